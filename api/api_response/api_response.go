@@ -35,3 +35,9 @@ func UnauthorizedError(c *gin.Context, message string) {
 		Message: message,
 	})
 }
+
+func TooManyRequestsError(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, ApiResponse{
+		Message: message,
+	})
+}
@@ -0,0 +1,32 @@
+package api_response
+
+import (
+	"ems/app/model/constant"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMErrorResponse is the error body of the SCIM endpoints (RFC 7644 section 3.12).
+type SCIMErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// SCIM writes a SCIM resource or list with the SCIM media type.
+func SCIM(c *gin.Context, status int, data interface{}) {
+	c.Header("Content-Type", "application/scim+json; charset=utf-8")
+	c.JSON(status, data)
+}
+
+func SCIMError(c *gin.Context, status int, scimType, detail string) {
+	c.Header("Content-Type", "application/scim+json; charset=utf-8")
+	c.AbortWithStatusJSON(status, SCIMErrorResponse{
+		Schemas:  []string{constant.SCIMErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
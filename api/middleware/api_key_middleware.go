@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"ems/app/model/constant"
+	"ems/utils"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authenticateAPIKey checks the API key sent in the Authorization header and that permission is
+// among its scopes, then stores the service account's claims on the context. It aborts the
+// request and returns false when either check fails.
+func (m *Middleware) authenticateAPIKey(c *gin.Context, prefix string, permission constant.Permission) bool {
+	key := strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+
+	apiKey, err := m.serviceAccountRepository.GetAPIKeyByPrefix(prefix)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if apiKey == nil || subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(utils.HashToken(key))) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return false
+	}
+
+	if apiKey.RevokedAt != nil || time.Now().After(apiKey.ExpiresAt) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key expired or revoked"})
+		return false
+	}
+
+	if !slices.Contains(strings.Split(apiKey.Scopes, ","), string(permission)) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing permission " + string(permission)})
+		return false
+	}
+
+	if apiKey.LastUsedAt == nil || time.Since(*apiKey.LastUsedAt) > constant.APIKeyTouchInterval {
+		if err := m.serviceAccountRepository.TouchAPIKey(apiKey.ID); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+
+	c.Set("user", &UserMiddleWareClaims{ServiceAccountID: &apiKey.ServiceAccountID})
+
+	return true
+}
@@ -1,72 +1,170 @@
 package middleware
 
 import (
+	"ems/app/model/constant"
 	"ems/domain"
+	"ems/utils"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Middleware struct {
-	userRepository domain.UserRepository
+	userRepository           domain.UserRepository
+	sessionRepository        domain.SessionRepository
+	roleRepository           domain.RoleRepository
+	serviceAccountRepository domain.ServiceAccountRepository
 }
 
-func NewMiddleware(userRepository domain.UserRepository) *Middleware {
-	return &Middleware{userRepository}
+func NewMiddleware(userRepository domain.UserRepository, sessionRepository domain.SessionRepository,
+	roleRepository domain.RoleRepository, serviceAccountRepository domain.ServiceAccountRepository) *Middleware {
+	return &Middleware{userRepository, sessionRepository, roleRepository, serviceAccountRepository}
 }
 
+// UserMiddleWareClaims identifies who made the request. Requests made with an API key carry
+// ServiceAccountID instead of a user. During impersonation the claims are the impersonated
+// user's and ImpersonatorID is the admin actually making the request.
 type UserMiddleWareClaims struct {
 	ID                 uint
 	RoleID             uint
+	SessionID          uint
 	DepartmentID       *uint
 	DepartmentMemberID *uint
+	ServiceAccountID   *uint
+	ImpersonatorID     *uint
 }
 
 func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.Request.Header.Get("Authorization")
+		user, ok := m.authenticate(c, false)
 
-		if token == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token missing"})
+		if !ok {
 			return
 		}
 
-		token = strings.TrimPrefix(token, "Bearer ")
+		defer m.recordImpersonatedRequest(c, user)
 
-		userID, err := ValidateToken(token)
+		c.Next()
+	}
+}
 
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+// PasswordChangeMiddleware authenticates like AuthMiddleware but also lets through users who
+// have to change their password, for the endpoints that let them do so.
+func (m *Middleware) PasswordChangeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := m.authenticate(c, true)
+
+		if !ok {
 			return
 		}
 
-		user, err := m.userRepository.GetUserByID(userID)
+		defer m.recordImpersonatedRequest(c, user)
+
+		c.Next()
+	}
+}
+
+// DenyImpersonation refuses requests made while impersonating. It goes after the middleware
+// that authenticates the route, on actions only the employee should take themselves.
+func (m *Middleware) DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := GetUserClaims(c)
 
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
 
-		if user == nil {
-			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		if user.ImpersonatorID != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Not allowed while impersonating"})
 			return
 		}
 
-		if user.Token == nil || *user.Token == "" || *user.Token != token {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			return
-		}
+		c.Next()
+	}
+}
 
-		userClaims := &UserMiddleWareClaims{
-			ID:                 user.ID,
-			RoleID:             user.RoleID,
-			DepartmentID:       user.DepartmentID,
-			DepartmentMemberID: user.DepartmentMemberID,
-		}
+// recordImpersonatedRequest records a finished request of an impersonation session with the
+// admin who made it, refused ones included. A failed insert is only logged, as the response
+// has already been written.
+func (m *Middleware) recordImpersonatedRequest(c *gin.Context, user *UserMiddleWareClaims) {
+	if user.ImpersonatorID == nil {
+		return
+	}
+
+	if err := m.sessionRepository.RecordImpersonatedRequest(user.SessionID, *user.ImpersonatorID, user.ID,
+		c.Request.Method, c.Request.URL.Path, c.Writer.Status()); err != nil {
+		log.Printf("impersonation: failed to record %s %s of session %d: %v", c.Request.Method,
+			c.Request.URL.Path, user.SessionID, err)
+	}
+}
 
-		c.Set("user", userClaims)
+// authenticate validates the bearer token and its session and stores the user's claims on
+// the context. Unless allowPasswordChange is set, password sessions of users whose password was
+// issued by HR or has expired are refused. It aborts the request and returns false when
+// authentication fails.
+func (m *Middleware) authenticate(c *gin.Context, allowPasswordChange bool) (*UserMiddleWareClaims, bool) {
+	token := c.Request.Header.Get("Authorization")
 
-		c.Next()
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token missing"})
+		return nil, false
+	}
+
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	userID, sessionID, err := ValidateToken(token)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	user, err := m.userRepository.GetUserByID(userID)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
 	}
+
+	session, err := m.sessionRepository.GetActiveSession(sessionID, user.ID)
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if session == nil || time.Now().After(session.ExpiresAt) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+		return nil, false
+	}
+
+	// 403 rather than 401 so that clients ask for a new password instead of refreshing the token.
+	// Single sign-on sessions never used the EMS password, so its policy does not apply to them.
+	if !allowPasswordChange && session.AuthMethod == string(constant.PasswordLogin) &&
+		(user.MustChangePassword || utils.IsPasswordExpired(user.PasswordChangedAt)) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Password change required"})
+		return nil, false
+	}
+
+	userClaims := &UserMiddleWareClaims{
+		ID:                 user.ID,
+		RoleID:             user.RoleID,
+		SessionID:          sessionID,
+		DepartmentID:       user.DepartmentID,
+		DepartmentMemberID: user.DepartmentMemberID,
+		ImpersonatorID:     session.ImpersonatorID,
+	}
+
+	c.Set("user", userClaims)
+
+	return userClaims, true
 }
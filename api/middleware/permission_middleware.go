@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"ems/app/model/constant"
+	"ems/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Require authenticates the request and lets it through only if the user's role has been
+// granted permission. Admin has every permission. Service accounts are let through when the
+// API key they sent has permission among its scopes.
+func (m *Middleware) Require(permission constant.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if prefix, ok := utils.ParseAPIKey(strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")); ok {
+			if m.authenticateAPIKey(c, prefix, permission) {
+				c.Next()
+			}
+
+			return
+		}
+
+		user, ok := m.authenticate(c, false)
+
+		if !ok {
+			return
+		}
+
+		defer m.recordImpersonatedRequest(c, user)
+
+		if user.RoleID != uint(constant.Admin) {
+			hasPermission, err := m.roleRepository.HasPermission(user.RoleID, string(permission))
+
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			if !hasPermission {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing permission " + string(permission)})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
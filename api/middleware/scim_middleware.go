@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"ems/api/api_response"
+	"ems/infrastructure/config"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMAuthMiddleware lets through requests bearing the configured SCIM token. The token is
+// hashed before the comparison so that its length does not leak through timing either.
+func (m *Middleware) SCIMAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+
+		if !found || token == "" {
+			api_response.SCIMError(c, http.StatusUnauthorized, "", "Token missing")
+			return
+		}
+
+		expected := sha256.Sum256([]byte(config.Config.SCIM.Token))
+		actual := sha256.Sum256([]byte(token))
+
+		if subtle.ConstantTimeCompare(expected[:], actual[:]) != 1 {
+			api_response.SCIMError(c, http.StatusUnauthorized, "", "Invalid token")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,39 +1,35 @@
 package middleware
 
 import (
-	"ems/infrastructure/config"
+	"ems/utils"
 	"errors"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 )
 
-func ValidateToken(tokenValue string) (uint, error) {
+// ValidateToken checks the access token's signature and expiry and returns the user and
+// session it was issued for.
+func ValidateToken(tokenValue string) (uint, uint, error) {
 
-	token, err := jwt.Parse(tokenValue, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
-		return []byte(config.Config.JwtSecretKey), nil
-	})
+	claims, err := utils.ParseToken(tokenValue)
 
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
+	userID, ok := claims["userID"].(float64)
 
-	if !ok || !token.Valid {
-		return 0, errors.New("invalid token")
+	if !ok || userID == 0 {
+		return 0, 0, errors.New("invalid token")
 	}
 
-	userID, ok := claims["userID"].(float64)
+	sessionID, ok := claims["sessionID"].(float64)
 
-	if !ok || userID == 0 {
-		return 0, errors.New("invalid token")
+	if !ok || sessionID == 0 {
+		return 0, 0, errors.New("invalid token")
 	}
 
-	return uint(userID), nil
+	return uint(userID), uint(sessionID), nil
 }
 
 func GetUserClaims(c *gin.Context) (*UserMiddleWareClaims, error) {
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuditRoutes(router *gin.RouterGroup, auditRepository domain.AuditRepository,
+	middleware *middleware.Middleware) {
+
+	auditService := service.NewAuditService(auditRepository)
+
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	auditRoute := router.Group("audit", middleware.Require(constant.AuditView))
+	{
+		auditRoute.GET("", auditHandler.FetchAuditLogs)
+		auditRoute.GET("verify", auditHandler.VerifyAuditLogs)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -10,15 +11,50 @@ import (
 )
 
 func RegisterAuthRoutes(router *gin.RouterGroup, userRepository domain.UserRepository,
+	sessionRepository domain.SessionRepository, roleRepository domain.RoleRepository,
+	twoFactorRepository domain.TwoFactorRepository, loginThrottleRepository domain.LoginThrottleRepository,
+	oidcRepository domain.OIDCRepository, identityProvider domain.IdentityProvider, directory domain.Directory,
 	middleware *middleware.Middleware) {
 
-	authService := service.NewAuthService(userRepository)
+	authService := service.NewAuthService(userRepository, sessionRepository, roleRepository, twoFactorRepository,
+		loginThrottleRepository, oidcRepository, identityProvider, directory)
 	authHandler := handler.NewAuthHandler(authService)
 
 	authRoute := router.Group("auth")
 	{
 		authRoute.POST("login", authHandler.Login)
-		authRoute.POST("logout", middleware.AuthMiddleware(), authHandler.Logout)
+		authRoute.POST("logout", middleware.PasswordChangeMiddleware(), authHandler.Logout)
+		authRoute.POST("refresh", authHandler.RefreshSession)
+		authRoute.GET("sessions", middleware.AuthMiddleware(), authHandler.FetchSessions)
+		authRoute.DELETE("sessions/:id", middleware.AuthMiddleware(), middleware.DenyImpersonation(), authHandler.RevokeSession)
+		authRoute.POST("2fa/verify", authHandler.VerifyTwoFactorLogin)
+		authRoute.POST("2fa/setup", authHandler.SetupTwoFactorForLogin)
+		authRoute.POST("2fa/enable", authHandler.EnableTwoFactorForLogin)
+		authRoute.GET("oidc/login", authHandler.StartOIDCLogin)
+		authRoute.POST("oidc/callback", authHandler.CompleteOIDCLogin)
+	}
+
+	twoFactorRoute := router.Group("user/2fa", middleware.AuthMiddleware(), middleware.DenyImpersonation())
+	{
+		twoFactorRoute.GET("", authHandler.FetchTwoFactorStatus)
+		twoFactorRoute.POST("setup", authHandler.SetupTwoFactor)
+		twoFactorRoute.POST("enable", authHandler.EnableTwoFactor)
+		twoFactorRoute.POST("disable", authHandler.DisableTwoFactor)
+		twoFactorRoute.POST("recoveryCodes", authHandler.RegenerateRecoveryCodes)
+	}
+
+	router.DELETE("hr/user/:id/sessions", middleware.Require(constant.SessionRevoke), authHandler.RevokeUserSessions)
+	router.DELETE("hr/user/:id/2fa", middleware.Require(constant.UserResetTwoFactor), authHandler.ResetTwoFactor)
+	router.GET("hr/user/:id/lockout", middleware.Require(constant.UserUnlock), authHandler.FetchLockoutStatus)
+	router.POST("hr/user/:id/unlock", middleware.Require(constant.UserUnlock), authHandler.UnlockUser)
+	router.POST("hr/user/:id/impersonate", middleware.Require(constant.UserImpersonate), middleware.DenyImpersonation(),
+		authHandler.StartImpersonation)
+
+	impersonationRoute := router.Group("hr/impersonation", middleware.Require(constant.UserImpersonate))
+	{
+		impersonationRoute.GET("", authHandler.FetchImpersonations)
+		impersonationRoute.GET(":id/requests", authHandler.FetchImpersonatedRequests)
+		impersonationRoute.DELETE(":id", middleware.DenyImpersonation(), authHandler.EndImpersonation)
 	}
 
 	forgotPasswordRoute := router.Group("forgotPassword")
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCustomFieldRoutes(router *gin.RouterGroup, customFieldRepository domain.CustomFieldRepository,
+	userRepository domain.UserRepository, middleware *middleware.Middleware) {
+
+	customFieldService := service.NewCustomFieldService(customFieldRepository, userRepository)
+
+	customFieldHandler := handler.NewCustomFieldHandler(customFieldService)
+
+	hrRoute := router.Group("hr/customField")
+	{
+		hrRoute.POST("", middleware.Require(constant.CustomFieldManage), customFieldHandler.CreateCustomField)
+		hrRoute.GET("", middleware.Require(constant.CustomFieldView), customFieldHandler.FetchCustomFields)
+		hrRoute.PATCH(":id", middleware.Require(constant.CustomFieldManage), customFieldHandler.UpdateCustomField)
+		hrRoute.DELETE(":id", middleware.Require(constant.CustomFieldManage), customFieldHandler.RemoveCustomField)
+		hrRoute.PUT("values", middleware.Require(constant.UserUpdate), customFieldHandler.UpdateUserCustomFieldValues)
+	}
+}
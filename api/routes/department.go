@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -17,15 +18,15 @@ func RegisterDepartmentRoutes(router *gin.RouterGroup,
 
 	departmentHandler := handler.NewDepartmentHandler(departmentService)
 
-	hrRoute := router.Group("department", middleware.HRAuthMiddleware())
+	hrRoute := router.Group("department")
 
 	{
-		hrRoute.POST("", departmentHandler.CreateDepartment)
-		hrRoute.GET("", departmentHandler.FetchDepartments)
-		hrRoute.PATCH(":id", departmentHandler.UpdateDepartment)
-		hrRoute.DELETE(":id", departmentHandler.RemoveDepartment)
-		hrRoute.POST(":id/mapUsers", departmentHandler.MappUsersToDepartment)
-		hrRoute.GET(":id/users", departmentHandler.FetchDepartmentMembers)
-		hrRoute.POST("unmapUser", departmentHandler.UnMapUser)
+		hrRoute.POST("", middleware.Require(constant.DepartmentManage), departmentHandler.CreateDepartment)
+		hrRoute.GET("", middleware.Require(constant.DepartmentView), departmentHandler.FetchDepartments)
+		hrRoute.PATCH(":id", middleware.Require(constant.DepartmentManage), departmentHandler.UpdateDepartment)
+		hrRoute.DELETE(":id", middleware.Require(constant.DepartmentManage), departmentHandler.RemoveDepartment)
+		hrRoute.POST(":id/mapUsers", middleware.Require(constant.DepartmentManage), departmentHandler.MappUsersToDepartment)
+		hrRoute.GET(":id/users", middleware.Require(constant.DepartmentView), departmentHandler.FetchDepartmentMembers)
+		hrRoute.POST("unmapUser", middleware.Require(constant.DepartmentManage), departmentHandler.UnMapUser)
 	}
 }
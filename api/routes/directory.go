@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDirectoryRoutes(router *gin.RouterGroup, directoryRepository domain.DirectoryRepository,
+	directory domain.Directory, middleware *middleware.Middleware) {
+
+	directorySyncService := service.NewDirectorySyncService(directoryRepository, directory)
+
+	directoryHandler := handler.NewDirectoryHandler(directorySyncService)
+
+	router.POST("hr/directory/sync", middleware.Require(constant.DirectorySync), directoryHandler.SyncDirectory)
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDocumentRoutes(router *gin.RouterGroup, documentRepository domain.DocumentRepository,
+	userRepository domain.UserRepository, roleRepository domain.RoleRepository, fileStorage domain.FileStorage,
+	fileScanner domain.FileScanner, middleware *middleware.Middleware) {
+
+	documentService := service.NewDocumentService(documentRepository, userRepository, roleRepository)
+
+	documentHandler := handler.NewDocumentHandler(documentService, fileStorage, fileScanner)
+
+	userRoute := router.Group("user/documents", middleware.AuthMiddleware())
+	{
+		userRoute.GET("", documentHandler.FetchOwnDocuments)
+	}
+
+	documentRoute := router.Group("documents", middleware.AuthMiddleware())
+	{
+		documentRoute.GET(":id", documentHandler.DownloadDocument)
+	}
+
+	categoryRoute := router.Group("hr/documentCategory")
+	{
+		categoryRoute.POST("", middleware.Require(constant.DocumentCategoryManage), documentHandler.CreateDocumentCategory)
+		categoryRoute.GET("", middleware.Require(constant.DocumentView), documentHandler.FetchDocumentCategories)
+		categoryRoute.PATCH(":id", middleware.Require(constant.DocumentCategoryManage), documentHandler.UpdateDocumentCategory)
+		categoryRoute.DELETE(":id", middleware.Require(constant.DocumentCategoryManage), documentHandler.RemoveDocumentCategory)
+	}
+
+	hrRoute := router.Group("hr/document")
+	{
+		hrRoute.POST("", middleware.Require(constant.DocumentUpload), documentHandler.UploadDocuments)
+		hrRoute.GET("", middleware.Require(constant.DocumentView), documentHandler.FetchUserDocuments)
+		hrRoute.GET("missing", middleware.Require(constant.DocumentView), documentHandler.FetchMissingDocuments)
+		hrRoute.GET("quarantine", middleware.Require(constant.DocumentQuarantine), documentHandler.FetchQuarantinedDocuments)
+		hrRoute.DELETE("quarantine/:id", middleware.Require(constant.DocumentQuarantine), documentHandler.RemoveQuarantinedDocument)
+		hrRoute.GET(":id/versions", middleware.Require(constant.DocumentView), documentHandler.FetchDocumentVersions)
+		hrRoute.DELETE(":id", middleware.Require(constant.DocumentDelete), documentHandler.RemoveDocument)
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"ems/app/handler"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterFileRoutes(router *gin.RouterGroup, fileStorage domain.FileStorage) {
+	fileHandler := handler.NewFileHandler(fileStorage)
+
+	router.GET("files", fileHandler.DownloadSignedFile)
+}
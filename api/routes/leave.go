@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -25,18 +26,18 @@ func RegisterLeaveRoute(router *gin.RouterGroup, leaveRepository domain.LeaveRep
 		userRoute.DELETE(":id", leaveHandler.RemoveLeaveRequest)
 	}
 
-	leadRoute := router.Group("lead/leave", middleware.DepartmentLeadMiddleware())
+	leadRoute := router.Group("lead/leave")
 	{
-		leadRoute.GET("", leaveHandler.FetchDepartmentMemberLeaves)
-		leadRoute.PATCH(":id", leaveHandler.UpdateLeaveStatus)
+		leadRoute.GET("", middleware.Require(constant.LeaveViewTeam), leaveHandler.FetchDepartmentMemberLeaves)
+		leadRoute.PATCH(":id", middleware.Require(constant.LeaveApprove), leaveHandler.UpdateLeaveStatus)
 	}
 
-	managerRoute := router.Group("manager/leave", middleware.ManagerMiddleware())
+	managerRoute := router.Group("manager/leave", middleware.Require(constant.LeaveViewLeads))
 	{
 		managerRoute.GET("", leaveHandler.FetchLeadAndHRLeaves)
 	}
 
-	hrRoute := router.Group("hr/leave", middleware.HRAuthMiddleware())
+	hrRoute := router.Group("hr/leave", middleware.Require(constant.LeaveViewAll))
 	{
 		hrRoute.GET("userLeave", leaveHandler.FetchUserLeaves)
 	}
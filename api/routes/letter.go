@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLetterRoutes(router *gin.RouterGroup, letterRepository domain.LetterRepository,
+	documentRepository domain.DocumentRepository, departmentRepository domain.DepartmentRepository,
+	fileStorage domain.FileStorage, middleware *middleware.Middleware) {
+
+	letterService := service.NewLetterService(letterRepository, documentRepository, departmentRepository)
+
+	letterHandler := handler.NewLetterHandler(letterService, fileStorage)
+
+	templateRoute := router.Group("hr/letterTemplate")
+	{
+		templateRoute.POST("", middleware.Require(constant.LetterTemplateManage), letterHandler.CreateLetterTemplate)
+		templateRoute.GET("", middleware.Require(constant.LetterTemplateView), letterHandler.FetchLetterTemplates)
+		templateRoute.PATCH(":id", middleware.Require(constant.LetterTemplateManage), letterHandler.UpdateLetterTemplate)
+		templateRoute.DELETE(":id", middleware.Require(constant.LetterTemplateManage), letterHandler.RemoveLetterTemplate)
+		templateRoute.GET(":id/versions", middleware.Require(constant.LetterTemplateView), letterHandler.FetchLetterTemplateVersions)
+	}
+
+	letterRoute := router.Group("hr/letter", middleware.Require(constant.LetterGenerate))
+	{
+		letterRoute.POST("", letterHandler.GenerateLetter)
+		letterRoute.POST("bulk", letterHandler.GenerateLetters)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -19,13 +20,13 @@ func RegisterNoticeRoutes(router *gin.RouterGroup, noticeRepository domain.Notic
 
 	userRoute := router.Group("notice", middleware.AuthMiddleware())
 	{
-		userRoute.POST("", noticeHandler.ApplyNotice)
+		userRoute.POST("", middleware.DenyImpersonation(), noticeHandler.ApplyNotice)
 		userRoute.GET("", noticeHandler.FetchNotice)
 	}
 
-	hrRoute := router.Group("hr/notice", middleware.HRAuthMiddleware())
+	hrRoute := router.Group("hr/notice")
 	{
-		hrRoute.GET("", noticeHandler.FetchActiveUserNotices)
-		hrRoute.POST("", noticeHandler.ApproveNotice)
+		hrRoute.GET("", middleware.Require(constant.NoticeView), noticeHandler.FetchActiveUserNotices)
+		hrRoute.POST("", middleware.Require(constant.NoticeApprove), noticeHandler.ApproveNotice)
 	}
 }
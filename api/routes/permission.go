@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -25,18 +26,18 @@ func RegisterPermissionRoutes(router *gin.RouterGroup, permissionRepository doma
 		userRoute.DELETE(":id", permissionHandler.RemovePermissionRequest)
 	}
 
-	leadRoute := router.Group("lead/permission", middleware.DepartmentLeadMiddleware())
+	leadRoute := router.Group("lead/permission")
 	{
-		leadRoute.GET("", permissionHandler.FetchDepartmentMemberPermissions)
-		leadRoute.PATCH(":id", permissionHandler.UpdatePermissionStatus)
+		leadRoute.GET("", middleware.Require(constant.PermissionViewTeam), permissionHandler.FetchDepartmentMemberPermissions)
+		leadRoute.PATCH(":id", middleware.Require(constant.PermissionApprove), permissionHandler.UpdatePermissionStatus)
 	}
 
-	managerRoute := router.Group("manager/permission", middleware.ManagerMiddleware())
+	managerRoute := router.Group("manager/permission", middleware.Require(constant.PermissionViewLeads))
 	{
 		managerRoute.GET("", permissionHandler.FetchLeadAndHRPermissions)
 	}
 
-	hrRoute := router.Group(`hr/permission`, middleware.HRAuthMiddleware())
+	hrRoute := router.Group("hr/permission", middleware.Require(constant.PermissionViewAll))
 	{
 		hrRoute.GET("userPermission", permissionHandler.FetchUserPermissions)
 	}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterProfileChangeRoutes(router *gin.RouterGroup, profileChangeRepository domain.ProfileChangeRepository,
+	userRepository domain.UserRepository, roleRepository domain.RoleRepository,
+	documentRepository domain.DocumentRepository, fileStorage domain.FileStorage, fileScanner domain.FileScanner,
+	middleware *middleware.Middleware) {
+
+	profileChangeService := service.NewProfileChangeService(profileChangeRepository, userRepository)
+	documentService := service.NewDocumentService(documentRepository, userRepository, roleRepository)
+
+	profileChangeHandler := handler.NewProfileChangeHandler(profileChangeService, documentService, fileStorage,
+		fileScanner)
+
+	userRoute := router.Group("user/profileChange", middleware.AuthMiddleware())
+	{
+		userRoute.POST("", middleware.DenyImpersonation(), profileChangeHandler.RequestProfileChange)
+		userRoute.GET("", profileChangeHandler.FetchOwnProfileChangeRequests)
+	}
+
+	hrRoute := router.Group("hr/profileChange")
+	{
+		hrRoute.GET("", middleware.Require(constant.ProfileChangeView), profileChangeHandler.FetchPendingProfileChangeRequests)
+		hrRoute.PATCH(":id", middleware.Require(constant.ProfileChangeApprove), profileChangeHandler.UpdateProfileChangeStatus)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -16,8 +17,13 @@ func RegisterRoleRoutes(router *gin.RouterGroup, roleRepository domain.RoleRepos
 
 	roleHandler := handler.NewRoleHandler(roleService)
 
-	roleRoute := router.Group("role", middleware.HRAuthMiddleware())
+	roleRoute := router.Group("role")
 	{
-		roleRoute.GET("", roleHandler.FetchRoles)
+		roleRoute.GET("", middleware.Require(constant.RoleView), roleHandler.FetchRoles)
+		roleRoute.GET("permissions", middleware.Require(constant.RoleView), roleHandler.FetchPermissions)
+		roleRoute.POST("", middleware.Require(constant.RoleManage), roleHandler.CreateRole)
+		roleRoute.PATCH(":id", middleware.Require(constant.RoleManage), roleHandler.UpdateRole)
+		roleRoute.PATCH(":id/twoFactor", middleware.Require(constant.RoleManage), roleHandler.UpdateRoleTwoFactor)
+		roleRoute.DELETE(":id", middleware.Require(constant.RoleManage), roleHandler.RemoveRole)
 	}
 }
@@ -2,7 +2,13 @@ package routes
 
 import (
 	"ems/api/middleware"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/infrastructure/ldap"
+	"ems/infrastructure/oidc"
 	"ems/infrastructure/repository"
+	"ems/infrastructure/scanner"
+	"ems/infrastructure/storage"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,17 +21,70 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 	leaveRepository := repository.NewLeaveRepository(db)
 	permissionRepository := repository.NewPermissionRepository(db)
 	noticeRepository := repository.NewNoticeRepository(db)
+	profileChangeRepository := repository.NewProfileChangeRepository(db)
+	userRelationRepository := repository.NewUserRelationRepository(db)
+	customFieldRepository := repository.NewCustomFieldRepository(db)
+	userQualificationRepository := repository.NewUserQualificationRepository(db)
+	documentRepository := repository.NewDocumentRepository(db)
+	letterRepository := repository.NewLetterRepository(db)
+	sessionRepository := repository.NewSessionRepository(db)
+	twoFactorRepository := repository.NewTwoFactorRepository(db)
+	loginThrottleRepository := repository.NewLoginThrottleRepository(db)
+	oidcRepository := repository.NewOIDCRepository(db)
+	directoryRepository := repository.NewDirectoryRepository(db)
+	scimRepository := repository.NewSCIMRepository(db)
+	serviceAccountRepository := repository.NewServiceAccountRepository(db)
+	signingKeyRepository := repository.NewSigningKeyRepository(db)
+	auditRepository := repository.NewAuditRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
 
-	middleware := middleware.NewMiddleware(userRepository)
+	fileStorage, err := storage.NewStorage()
+	if err != nil {
+		panic(err)
+	}
+
+	fileScanner, err := scanner.NewScanner()
+	if err != nil {
+		panic(err)
+	}
+
+	var identityProvider domain.IdentityProvider
+	if config.Config.OIDC.IssuerURL != "" {
+		identityProvider = oidc.NewIdentityProvider(config.Config.OIDC)
+	}
+
+	var directory domain.Directory
+	if config.Config.LDAP.URL != "" {
+		directory = ldap.NewDirectory(config.Config.LDAP)
+	}
+
+	middleware := middleware.NewMiddleware(userRepository, sessionRepository, roleRepository, serviceAccountRepository)
+
+	RegisterSigningKeyRoutes(router, signingKeyRepository, middleware)
 
 	apiRoute := router.Group("api")
 
-	RegisterAuthRoutes(apiRoute, userRepository, middleware)
-	RegisterUserRoutes(apiRoute, userRepository, departmentRepository, leaveRepository, permissionRepository, middleware)
+	RegisterAuthRoutes(apiRoute, userRepository, sessionRepository, roleRepository, twoFactorRepository, loginThrottleRepository, oidcRepository, identityProvider, directory, middleware)
+	RegisterUserRoutes(apiRoute, userRepository, departmentRepository, leaveRepository, permissionRepository, customFieldRepository, roleRepository, documentRepository, fileStorage, fileScanner, middleware)
 	RegisterDepartmentRoutes(apiRoute, departmentRepository, userRepository, middleware)
 	RegisterRoleRoutes(apiRoute, roleRepository, middleware)
 	RegisterLeaveRoute(apiRoute, leaveRepository, departmentRepository, userRepository, middleware)
 	RegisterPermissionRoutes(apiRoute, permissionRepository, departmentRepository, userRepository, middleware)
 	RegisterNoticeRoutes(apiRoute, noticeRepository, departmentRepository, middleware)
 	RegisterDashboardRoutes(apiRoute, userRepository, departmentRepository, leaveRepository, permissionRepository, noticeRepository, middleware)
+	RegisterProfileChangeRoutes(apiRoute, profileChangeRepository, userRepository, roleRepository, documentRepository, fileStorage, fileScanner, middleware)
+	RegisterUserRelationRoutes(apiRoute, userRelationRepository, userRepository, customFieldRepository, middleware)
+	RegisterCustomFieldRoutes(apiRoute, customFieldRepository, userRepository, middleware)
+	RegisterUserQualificationRoutes(apiRoute, userQualificationRepository, userRepository, middleware)
+	RegisterDocumentRoutes(apiRoute, documentRepository, userRepository, roleRepository, fileStorage, fileScanner, middleware)
+	RegisterLetterRoutes(apiRoute, letterRepository, documentRepository, departmentRepository, fileStorage, middleware)
+	RegisterFileRoutes(apiRoute, fileStorage)
+	RegisterDirectoryRoutes(apiRoute, directoryRepository, directory, middleware)
+	RegisterServiceAccountRoutes(apiRoute, serviceAccountRepository, middleware)
+	RegisterAuditRoutes(apiRoute, auditRepository, middleware)
+	RegisterWebhookRoutes(apiRoute, webhookRepository, middleware)
+
+	if config.Config.SCIM.Token != "" {
+		RegisterSCIMRoutes(apiRoute, scimRepository, userRepository, departmentRepository, leaveRepository, permissionRepository, customFieldRepository, roleRepository, middleware)
+	}
 }
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSCIMRoutes(router *gin.RouterGroup, scimRepository domain.SCIMRepository,
+	userRepository domain.UserRepository, departmentRepository domain.DepartmentRepository,
+	leaveRepository domain.LeaveRepository, permissionRepository domain.PermissionRepository,
+	customFieldRepository domain.CustomFieldRepository, roleRepository domain.RoleRepository,
+	middleware *middleware.Middleware) {
+
+	userService := service.NewUserService(userRepository, departmentRepository, leaveRepository, permissionRepository,
+		customFieldRepository, roleRepository)
+	departmentService := service.NewDepartmentService(departmentRepository, userRepository)
+	scimService := service.NewSCIMService(scimRepository, userRepository, departmentRepository, userService,
+		departmentService)
+
+	scimHandler := handler.NewSCIMHandler(scimService)
+
+	scimRoute := router.Group("scim/v2", middleware.SCIMAuthMiddleware())
+	{
+		scimRoute.GET("ServiceProviderConfig", scimHandler.FetchServiceProviderConfig)
+		scimRoute.GET("Users", scimHandler.FetchUsers)
+		scimRoute.POST("Users", scimHandler.CreateUser)
+		scimRoute.GET("Users/:id", scimHandler.FetchUser)
+		scimRoute.PUT("Users/:id", scimHandler.ReplaceUser)
+		scimRoute.PATCH("Users/:id", scimHandler.PatchUser)
+		scimRoute.DELETE("Users/:id", scimHandler.DeactivateUser)
+		scimRoute.GET("Groups", scimHandler.FetchGroups)
+		scimRoute.POST("Groups", scimHandler.CreateGroup)
+		scimRoute.GET("Groups/:id", scimHandler.FetchGroup)
+		scimRoute.PUT("Groups/:id", scimHandler.ReplaceGroup)
+		scimRoute.PATCH("Groups/:id", scimHandler.PatchGroup)
+		scimRoute.DELETE("Groups/:id", scimHandler.RemoveGroup)
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterServiceAccountRoutes(router *gin.RouterGroup, serviceAccountRepository domain.ServiceAccountRepository,
+	middleware *middleware.Middleware) {
+
+	serviceAccountService := service.NewServiceAccountService(serviceAccountRepository)
+
+	serviceAccountHandler := handler.NewServiceAccountHandler(serviceAccountService)
+
+	serviceAccountRoute := router.Group("serviceAccount", middleware.Require(constant.ServiceAccountManage))
+	{
+		serviceAccountRoute.GET("", serviceAccountHandler.FetchServiceAccounts)
+		serviceAccountRoute.GET("scopes", serviceAccountHandler.FetchAPIKeyScopes)
+		serviceAccountRoute.POST("", serviceAccountHandler.CreateServiceAccount)
+		serviceAccountRoute.DELETE(":id", serviceAccountHandler.RemoveServiceAccount)
+		serviceAccountRoute.POST(":id/apiKey", serviceAccountHandler.CreateAPIKey)
+		serviceAccountRoute.DELETE(":id/apiKey/:keyID", serviceAccountHandler.RevokeAPIKey)
+	}
+}
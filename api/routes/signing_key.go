@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterSigningKeyRoutes also makes sure a signing key exists before any token is issued.
+func RegisterSigningKeyRoutes(router *gin.Engine, signingKeyRepository domain.SigningKeyRepository,
+	middleware *middleware.Middleware) {
+
+	signingKeyService := service.NewSigningKeyService(signingKeyRepository)
+
+	if err := signingKeyService.RotateSigningKey(false); err != nil {
+		panic(err)
+	}
+
+	signingKeyHandler := handler.NewSigningKeyHandler(signingKeyService)
+
+	router.GET(".well-known/jwks.json", signingKeyHandler.FetchJWKS)
+	router.POST("api/signingKey/rotate", middleware.Require(constant.SigningKeyRotate), signingKeyHandler.RotateSigningKey)
+}
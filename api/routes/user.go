@@ -3,6 +3,7 @@ package routes
 import (
 	"ems/api/middleware"
 	"ems/app/handler"
+	"ems/app/model/constant"
 	"ems/app/service"
 	"ems/domain"
 
@@ -11,34 +12,38 @@ import (
 
 func RegisterUserRoutes(router *gin.RouterGroup, userRepository domain.UserRepository,
 	departmentRepository domain.DepartmentRepository, leaveRepository domain.LeaveRepository,
-	permissionRepository domain.PermissionRepository,
-	middleware *middleware.Middleware) {
+	permissionRepository domain.PermissionRepository, customFieldRepository domain.CustomFieldRepository,
+	roleRepository domain.RoleRepository, documentRepository domain.DocumentRepository,
+	fileStorage domain.FileStorage, fileScanner domain.FileScanner, middleware *middleware.Middleware) {
 
-	userService := service.NewUserService(userRepository, departmentRepository, leaveRepository, permissionRepository)
-	userHandler := handler.NewUserHandler(userService)
+	userService := service.NewUserService(userRepository, departmentRepository, leaveRepository, permissionRepository,
+		customFieldRepository, roleRepository)
+	documentService := service.NewDocumentService(documentRepository, userRepository, roleRepository)
+	userHandler := handler.NewUserHandler(userService, documentService, fileStorage, fileScanner)
 
-	hrRoute := router.Group("hr/user", middleware.HRAuthMiddleware())
+	hrRoute := router.Group("hr/user")
 	{
-		hrRoute.POST("", userHandler.CreateUser)
-		hrRoute.GET("", userHandler.FetchUsers)
-		hrRoute.PATCH(":id", userHandler.UpdateUser)
-		hrRoute.DELETE(":id", userHandler.RemoveUser)
-		hrRoute.POST("details", userHandler.UpdateUserDetails)
-		hrRoute.GET("lastUserCode", userHandler.FetchLastUserCode)
-		hrRoute.GET("unmappedLeads", userHandler.FetchUnmappedLeadUsers)
-		hrRoute.GET("unmappedHrs", userHandler.FetchUnmappedHRUsers)
-		hrRoute.POST("unmappedLeadsIncludeID", userHandler.FetchUnmappedLeadUserIncludeUserID)
-		hrRoute.POST("fetchUnmappedUsers", userHandler.FetchUnmappedUsers)
-		hrRoute.POST("uploadFiles", userHandler.UploadFiles)
-		hrRoute.GET("/files", userHandler.FetchFilePathsByUserID)
-		hrRoute.GET("/file", userHandler.FetchFile)
-		hrRoute.DELETE("/file", userHandler.DeleteFile)
+		hrRoute.POST("", middleware.Require(constant.UserCreate), userHandler.CreateUser)
+		hrRoute.GET("", middleware.Require(constant.UserView), userHandler.FetchUsers)
+		hrRoute.PATCH(":id", middleware.Require(constant.UserUpdate), userHandler.UpdateUser)
+		hrRoute.DELETE(":id", middleware.Require(constant.UserDelete), userHandler.RemoveUser)
+		hrRoute.GET(":id/history", middleware.Require(constant.UserView), userHandler.FetchUserFieldHistory)
+		hrRoute.POST("details", middleware.Require(constant.UserUpdate), userHandler.UpdateUserDetails)
+		hrRoute.POST("details/rotateKey", middleware.Require(constant.UserRotateKey), userHandler.RotatePIIEncryptionKey)
+		hrRoute.GET("lastUserCode", middleware.Require(constant.UserView), userHandler.FetchLastUserCode)
+		hrRoute.GET("unmappedLeads", middleware.Require(constant.UserView), userHandler.FetchUnmappedLeadUsers)
+		hrRoute.GET("unmappedHrs", middleware.Require(constant.UserView), userHandler.FetchUnmappedHRUsers)
+		hrRoute.POST("unmappedLeadsIncludeID", middleware.Require(constant.UserView), userHandler.FetchUnmappedLeadUserIncludeUserID)
+		hrRoute.POST("fetchUnmappedUsers", middleware.Require(constant.UserView), userHandler.FetchUnmappedUsers)
+		hrRoute.POST("uploadFiles", middleware.Require(constant.DocumentUpload), userHandler.UploadFiles)
 	}
 
-	userRoute := router.Group("user", middleware.AuthMiddleware())
+	userRoute := router.Group("user")
 	{
-		userRoute.GET("details", userHandler.FetchUserDetails)
-		userRoute.POST("resetPassword", userHandler.ResetPassword)
-		userRoute.POST("changePassword", userHandler.ChangePassword)
+		userRoute.GET("details", middleware.AuthMiddleware(), userHandler.FetchUserDetails)
+		userRoute.POST("resetPassword", middleware.PasswordChangeMiddleware(), middleware.DenyImpersonation(),
+			userHandler.ResetPassword)
+		userRoute.POST("changePassword", middleware.PasswordChangeMiddleware(), middleware.DenyImpersonation(),
+			userHandler.ChangePassword)
 	}
 }
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterUserQualificationRoutes(router *gin.RouterGroup, userQualificationRepository domain.UserQualificationRepository,
+	userRepository domain.UserRepository, middleware *middleware.Middleware) {
+
+	userQualificationService := service.NewUserQualificationService(userQualificationRepository, userRepository)
+
+	userQualificationHandler := handler.NewUserQualificationHandler(userQualificationService)
+
+	userRoute := router.Group("user/qualifications", middleware.AuthMiddleware())
+	{
+		userRoute.GET("", userQualificationHandler.FetchOwnQualifications)
+	}
+
+	hrRoute := router.Group("hr/userQualification")
+	{
+		hrRoute.GET("", middleware.Require(constant.QualificationView), userQualificationHandler.FetchUserQualifications)
+		hrRoute.GET("search", middleware.Require(constant.QualificationView), userQualificationHandler.SearchQualifications)
+		hrRoute.POST("education", middleware.Require(constant.QualificationManage), userQualificationHandler.CreateEducation)
+		hrRoute.PATCH("education/:id", middleware.Require(constant.QualificationManage), userQualificationHandler.UpdateEducation)
+		hrRoute.DELETE("education/:id", middleware.Require(constant.QualificationManage), userQualificationHandler.RemoveEducation)
+		hrRoute.POST("certification", middleware.Require(constant.QualificationManage), userQualificationHandler.CreateCertification)
+		hrRoute.PATCH("certification/:id", middleware.Require(constant.QualificationManage), userQualificationHandler.UpdateCertification)
+		hrRoute.DELETE("certification/:id", middleware.Require(constant.QualificationManage), userQualificationHandler.RemoveCertification)
+		hrRoute.POST("skill", middleware.Require(constant.QualificationManage), userQualificationHandler.CreateSkill)
+		hrRoute.PATCH("skill/:id", middleware.Require(constant.QualificationManage), userQualificationHandler.UpdateSkill)
+		hrRoute.DELETE("skill/:id", middleware.Require(constant.QualificationManage), userQualificationHandler.RemoveSkill)
+	}
+}
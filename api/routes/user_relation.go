@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterUserRelationRoutes(router *gin.RouterGroup, userRelationRepository domain.UserRelationRepository,
+	userRepository domain.UserRepository, customFieldRepository domain.CustomFieldRepository,
+	middleware *middleware.Middleware) {
+
+	userRelationService := service.NewUserRelationService(userRelationRepository, userRepository)
+	employeeDataService := service.NewEmployeeDataService(userRepository, userRelationRepository,
+		customFieldRepository)
+
+	userRelationHandler := handler.NewUserRelationHandler(userRelationService)
+	employeeDataHandler := handler.NewEmployeeDataHandler(employeeDataService)
+
+	userRoute := router.Group("user/relations", middleware.AuthMiddleware())
+	{
+		userRoute.GET("", userRelationHandler.FetchOwnRelations)
+	}
+
+	hrRoute := router.Group("hr/userRelation")
+	{
+		hrRoute.GET("", middleware.Require(constant.RelationView), userRelationHandler.FetchUserRelations)
+		hrRoute.POST("emergencyContact", middleware.Require(constant.RelationManage), userRelationHandler.CreateEmergencyContact)
+		hrRoute.PATCH("emergencyContact/:id", middleware.Require(constant.RelationManage), userRelationHandler.UpdateEmergencyContact)
+		hrRoute.DELETE("emergencyContact/:id", middleware.Require(constant.RelationManage), userRelationHandler.RemoveEmergencyContact)
+		hrRoute.POST("dependent", middleware.Require(constant.RelationManage), userRelationHandler.CreateDependent)
+		hrRoute.PATCH("dependent/:id", middleware.Require(constant.RelationManage), userRelationHandler.UpdateDependent)
+		hrRoute.DELETE("dependent/:id", middleware.Require(constant.RelationManage), userRelationHandler.RemoveDependent)
+		hrRoute.PUT("nominee", middleware.Require(constant.RelationManage), userRelationHandler.UpdateNominees)
+	}
+
+	exportRoute := router.Group("hr/employeeData")
+	{
+		exportRoute.GET("export", middleware.Require(constant.EmployeeDataExport), employeeDataHandler.ExportEmployees)
+		exportRoute.POST("import", middleware.Require(constant.EmployeeDataImport), employeeDataHandler.ImportEmployees)
+	}
+}
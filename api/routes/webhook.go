@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"ems/api/middleware"
+	"ems/app/handler"
+	"ems/app/model/constant"
+	"ems/app/service"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWebhookRoutes(router *gin.RouterGroup, webhookRepository domain.WebhookRepository,
+	middleware *middleware.Middleware) {
+
+	webhookService := service.NewWebhookService(webhookRepository)
+
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+
+	webhookRoute := router.Group("webhook", middleware.Require(constant.WebhookManage))
+	{
+		webhookRoute.GET("", webhookHandler.FetchWebhooks)
+		webhookRoute.GET("eventTypes", webhookHandler.FetchEventTypes)
+		webhookRoute.POST("", webhookHandler.CreateWebhook)
+		webhookRoute.PUT(":id", webhookHandler.UpdateWebhook)
+		webhookRoute.DELETE(":id", webhookHandler.RemoveWebhook)
+		webhookRoute.POST(":id/secret", webhookHandler.RotateWebhookSecret)
+		webhookRoute.GET(":id/deliveries", webhookHandler.FetchWebhookDeliveries)
+		webhookRoute.GET("deadLetter", webhookHandler.FetchDeadLetterDeliveries)
+		webhookRoute.GET("delivery/:deliveryID", webhookHandler.FetchWebhookDelivery)
+		webhookRoute.POST("delivery/:deliveryID/redeliver", webhookHandler.RedeliverWebhookDelivery)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService domain.AuditService
+}
+
+func NewAuditHandler(auditService domain.AuditService) *AuditHandler {
+	return &AuditHandler{auditService}
+}
+
+func (h *AuditHandler) FetchAuditLogs(c *gin.Context) {
+	var filters request.FetchAuditLogs
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	filters.Search = utils.SqlParamValidator(filters.Search)
+
+	data, err := h.auditService.FetchAuditLogs(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Audit logs fetched successfully", data)
+}
+
+func (h *AuditHandler) VerifyAuditLogs(c *gin.Context) {
+	data, err := h.auditService.VerifyAuditLogs()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Audit log verified successfully", data)
+}
+
+// auditActor identifies the signed in employee or the service account making a change, for
+// the audit log.
+func auditActor(c *gin.Context) (*request.AuditActor, error) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ServiceAccountID != nil {
+		return &request.AuditActor{
+			Type:             constant.ServiceAccountActor,
+			ServiceAccountID: user.ServiceAccountID,
+			IPAddress:        c.ClientIP(),
+		}, nil
+	}
+
+	return &request.AuditActor{
+		Type:           constant.UserActor,
+		UserID:         &user.ID,
+		ImpersonatorID: user.ImpersonatorID,
+		IPAddress:      c.ClientIP(),
+	}, nil
+}
@@ -3,9 +3,12 @@ package handler
 import (
 	"ems/api/api_response"
 	"ems/api/middleware"
+	apperror "ems/app/model/app_error"
 	"ems/app/model/request"
 	"ems/domain"
 	"ems/utils"
+	"errors"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,16 +32,207 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	req.Email = utils.SqlParamValidator(req.Email)
 	req.Password = utils.SqlParamValidator(req.Password)
 
-	data, err := h.authService.Login(&req)
+	data, challenge, err := h.authService.Login(&req, sessionDevice(c))
 
 	if err != nil {
+		if errors.Is(err, apperror.ErrTooManyAttempts) {
+			api_response.TooManyRequestsError(c, err.Error())
+			return
+		}
+
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
 
+	if challenge != nil {
+		api_response.Success(c, "Two-factor authentication required", challenge)
+		return
+	}
+
 	api_response.Success(c, "Login successful", data)
 }
 
+func (h *AuthHandler) VerifyTwoFactorLogin(c *gin.Context) {
+	var req request.VerifyTwoFactor
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.VerifyTwoFactorLogin(&req, sessionDevice(c))
+
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	api_response.Success(c, "Login successful", data)
+}
+
+func (h *AuthHandler) SetupTwoFactorForLogin(c *gin.Context) {
+	var req request.TwoFactorToken
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.SetupTwoFactorForLogin(&req)
+
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	api_response.Success(c, "Two-factor setup started", data)
+}
+
+func (h *AuthHandler) EnableTwoFactorForLogin(c *gin.Context) {
+	var req request.VerifyTwoFactor
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.EnableTwoFactorForLogin(&req, sessionDevice(c))
+
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	api_response.Success(c, "Two-factor authentication enabled, login successful", data)
+}
+
+func (h *AuthHandler) FetchTwoFactorStatus(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.FetchTwoFactorStatus(user.ID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Two-factor status fetched successfully", data)
+}
+
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.SetupTwoFactor(user.ID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Two-factor setup started", data)
+}
+
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	var req request.TwoFactorCode
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.EnableTwoFactor(user.ID, &req)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Two-factor authentication enabled successfully", data)
+}
+
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req request.TwoFactorCode
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(user.ID, &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Two-factor authentication disabled successfully", nil)
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req request.TwoFactorCode
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.RegenerateRecoveryCodes(user.ID, &req)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Recovery codes regenerated successfully", data)
+}
+
+func (h *AuthHandler) ResetTwoFactor(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.authService.ResetTwoFactor(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Two-factor authentication reset successfully", nil)
+}
+
 func (h *AuthHandler) Logout(c *gin.Context) {
 
 	user, err := middleware.GetUserClaims(c)
@@ -48,7 +242,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.Logout(user.ID); err != nil {
+	if err := h.authService.Logout(user.SessionID); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -65,6 +259,11 @@ func (h *AuthHandler) SendForgotPasswordOtp(c *gin.Context) {
 	}
 
 	if err := h.authService.SendForgotPasswordOtp(&req); err != nil {
+		if errors.Is(err, apperror.ErrDirectoryPassword) {
+			api_response.BadRequestError(c, err.Error())
+			return
+		}
+
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -83,12 +282,302 @@ func (h *AuthHandler) VerifyForgotPasswordOtp(c *gin.Context) {
 	req.Email = utils.SqlParamValidator(req.Email)
 	req.OTP = utils.SqlParamValidator(req.OTP)
 
-	data, err := h.authService.VerifyForgotPasswordOtp(&req)
+	data, challenge, err := h.authService.VerifyForgotPasswordOtp(&req, sessionDevice(c))
 
 	if err != nil {
+		if errors.Is(err, apperror.ErrTooManyAttempts) {
+			api_response.TooManyRequestsError(c, err.Error())
+			return
+		}
+
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
 
+	if challenge != nil {
+		api_response.Success(c, "Two-factor authentication required", challenge)
+		return
+	}
+
 	api_response.Success(c, "OTP verified successfully", data)
 }
+
+func (h *AuthHandler) RefreshSession(c *gin.Context) {
+	var req request.RefreshSession
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.RefreshSession(&req, sessionDevice(c))
+
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidSession) {
+			api_response.UnauthorizedError(c, err.Error())
+			return
+		}
+
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Session refreshed successfully", data)
+}
+
+func (h *AuthHandler) FetchSessions(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.FetchSessions(user.ID, user.SessionID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Sessions fetched successfully", data)
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.authService.RevokeSession(user.ID, uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Session revoked successfully", nil)
+}
+
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.authService.RevokeUserSessions(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User sessions revoked successfully", nil)
+}
+
+func (h *AuthHandler) FetchLockoutStatus(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.FetchLockoutStatus(uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Lockout status fetched successfully", data)
+}
+
+func (h *AuthHandler) UnlockUser(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.authService.UnlockUser(uint(id), user.ID, sessionDevice(c)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User unlocked successfully", nil)
+}
+
+func (h *AuthHandler) StartImpersonation(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	var req request.StartImpersonation
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.StartImpersonation(user.ID, uint(id), &req, sessionDevice(c))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Impersonation started successfully", data)
+}
+
+func (h *AuthHandler) FetchImpersonations(c *gin.Context) {
+	data, err := h.authService.FetchImpersonations()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Impersonations fetched successfully", data)
+}
+
+func (h *AuthHandler) FetchImpersonatedRequests(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.FetchImpersonatedRequests(uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Impersonated requests fetched successfully", data)
+}
+
+func (h *AuthHandler) EndImpersonation(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.authService.EndImpersonation(uint(id), user.ID, sessionDevice(c)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Impersonation ended successfully", nil)
+}
+
+// twoFactorError reports an expired interim token or a wrong code as unauthorized.
+func (h *AuthHandler) StartOIDCLogin(c *gin.Context) {
+	data, err := h.authService.StartOIDCLogin()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Single sign-on started", data)
+}
+
+func (h *AuthHandler) CompleteOIDCLogin(c *gin.Context) {
+	var req request.CompleteOIDCLogin
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.authService.CompleteOIDCLogin(&req, sessionDevice(c))
+
+	if err != nil {
+		if errors.Is(err, apperror.ErrTooManyAttempts) {
+			api_response.TooManyRequestsError(c, err.Error())
+			return
+		}
+
+		if errors.Is(err, apperror.ErrInvalidSession) || errors.Is(err, apperror.ErrSingleSignOnFailed) {
+			api_response.UnauthorizedError(c, err.Error())
+			return
+		}
+
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Login successful", data)
+}
+
+func twoFactorError(c *gin.Context, err error) {
+	if errors.Is(err, apperror.ErrTooManyAttempts) {
+		api_response.TooManyRequestsError(c, err.Error())
+		return
+	}
+
+	if errors.Is(err, apperror.ErrInvalidSession) || errors.Is(err, apperror.ErrInvalidTwoFactorCode) {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	api_response.InternalServerError(c, err.Error())
+}
+
+func sessionDevice(c *gin.Context) *request.SessionDevice {
+	return &request.SessionDevice{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
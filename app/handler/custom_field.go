@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CustomFieldHandler struct {
+	customFieldService domain.CustomFieldService
+}
+
+func NewCustomFieldHandler(customFieldService domain.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{customFieldService}
+}
+
+func (h *CustomFieldHandler) CreateCustomField(c *gin.Context) {
+	var req request.CreateCustomField
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	for i := range req.Options {
+		req.Options[i] = utils.SqlParamValidator(req.Options[i])
+	}
+
+	if err := h.customFieldService.CreateCustomField(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Custom field created successfully", nil)
+}
+
+func (h *CustomFieldHandler) FetchCustomFields(c *gin.Context) {
+	data, err := h.customFieldService.FetchCustomFields()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Custom fields fetched successfully", data)
+}
+
+func (h *CustomFieldHandler) UpdateCustomField(c *gin.Context) {
+	var req request.UpdateCustomField
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	for i := range req.Options {
+		req.Options[i] = utils.SqlParamValidator(req.Options[i])
+	}
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.customFieldService.UpdateCustomField(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Custom field updated successfully", nil)
+}
+
+func (h *CustomFieldHandler) RemoveCustomField(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.customFieldService.RemoveCustomField(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Custom field removed successfully", nil)
+}
+
+func (h *CustomFieldHandler) UpdateUserCustomFieldValues(c *gin.Context) {
+	var req request.UpdateUserCustomFieldValues
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	for i := range req.Values {
+		req.Values[i].Value = utils.SqlParamValidator(req.Values[i].Value)
+	}
+
+	if err := h.customFieldService.UpdateUserCustomFieldValues(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Custom field values updated successfully", nil)
+}
@@ -28,7 +28,14 @@ func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
 
 	req.Name = utils.SqlParamValidator(req.Name)
 
-	if err := h.departmentService.CreateDepartment(&req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.departmentService.CreateDepartment(actor, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -75,7 +82,14 @@ func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
 		return
 	}
 
-	if err := h.departmentService.UpdateDepartment(uint(id), &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.departmentService.UpdateDepartment(actor, uint(id), &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -93,7 +107,14 @@ func (h *DepartmentHandler) RemoveDepartment(c *gin.Context) {
 		return
 	}
 
-	if err := h.departmentService.RemoveDepartment(uint(id)); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.departmentService.RemoveDepartment(actor, uint(id)); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -118,7 +139,14 @@ func (h *DepartmentHandler) MappUsersToDepartment(c *gin.Context) {
 		return
 	}
 
-	if err := h.departmentService.MappUsersToDepartment(uint(id), &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.departmentService.MappUsersToDepartment(actor, uint(id), &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -163,7 +191,14 @@ func (h *DepartmentHandler) UnMapUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.departmentService.UnMapUser(&req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.departmentService.UnMapUser(actor, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/app/model/request"
+	"ems/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DirectoryHandler struct {
+	directorySyncService domain.DirectorySyncService
+}
+
+func NewDirectoryHandler(directorySyncService domain.DirectorySyncService) *DirectoryHandler {
+	return &DirectoryHandler{directorySyncService}
+}
+
+func (h *DirectoryHandler) SyncDirectory(c *gin.Context) {
+	var req request.SyncDirectory
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.directorySyncService.SyncDirectory(actor, req.DryRun)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Directory synced successfully", data)
+}
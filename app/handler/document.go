@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	apperror "ems/app/model/app_error"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentHandler struct {
+	documentService domain.DocumentService
+	fileStorage     domain.FileStorage
+	fileScanner     domain.FileScanner
+}
+
+func NewDocumentHandler(documentService domain.DocumentService, fileStorage domain.FileStorage,
+	fileScanner domain.FileScanner) *DocumentHandler {
+	return &DocumentHandler{documentService, fileStorage, fileScanner}
+}
+
+func (h *DocumentHandler) CreateDocumentCategory(c *gin.Context) {
+	var req request.CreateDocumentCategory
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Description = utils.SqlParamValidator(req.Description)
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.documentService.CreateDocumentCategory(actor, &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Document category created successfully", nil)
+}
+
+func (h *DocumentHandler) FetchDocumentCategories(c *gin.Context) {
+	data, err := h.documentService.FetchDocumentCategories()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Document categories fetched successfully", data)
+}
+
+func (h *DocumentHandler) UpdateDocumentCategory(c *gin.Context) {
+	var req request.UpdateDocumentCategory
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Description = utils.SqlParamValidator(req.Description)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.documentService.UpdateDocumentCategory(actor, uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Document category updated successfully", nil)
+}
+
+func (h *DocumentHandler) RemoveDocumentCategory(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.documentService.RemoveDocumentCategory(actor, uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Document category removed successfully", nil)
+}
+
+func (h *DocumentHandler) UploadDocuments(c *gin.Context) {
+	var req request.UploadDocument
+
+	if err := c.ShouldBind(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.ExpiryDate = utils.SqlParamValidator(req.ExpiryDate)
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		api_response.BadRequestError(c, "No files provided")
+		return
+	}
+
+	documents, err := inspectUploads(h.documentService, h.fileStorage, h.fileScanner, actor, user.ID, req.UserID,
+		&req.DocumentCategoryID, files)
+
+	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	if err := saveUploadedDocuments(h.fileStorage, files, req.UserID, documents); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	if err := h.documentService.UploadDocuments(actor, user.ID, &req, documents); err != nil {
+		removeUploadedDocuments(h.fileStorage, documents)
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User documents uploaded successfully", nil)
+}
+
+func (h *DocumentHandler) FetchOwnDocuments(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.documentService.FetchUserDocuments(&request.FetchUserDocuments{UserID: user.ID})
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User documents fetched successfully", data)
+}
+
+func (h *DocumentHandler) FetchUserDocuments(c *gin.Context) {
+	var req request.FetchUserDocuments
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.documentService.FetchUserDocuments(&req)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User documents fetched successfully", data)
+}
+
+func (h *DocumentHandler) FetchDocumentVersions(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.documentService.FetchDocumentVersions(uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Document versions fetched successfully", data)
+}
+
+func (h *DocumentHandler) DownloadDocument(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	document, err := h.documentService.FetchDocumentForDownload(uint(id), user.ID, user.RoleID)
+
+	if err != nil {
+		if errors.Is(err, apperror.ErrAccessDenied) {
+			api_response.UnauthorizedError(c, err.Error())
+			return
+		}
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	fileName := ""
+	if document.FileName != nil {
+		fileName = *document.FileName
+	}
+
+	signedURL, err := h.fileStorage.SignedURL(document.FilePath, fileName, config.Config.SignedURLValidity)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, signedURL)
+}
+
+func (h *DocumentHandler) RemoveDocument(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	document, err := h.documentService.RemoveDocument(actor, uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	if err := h.fileStorage.Delete(document.FilePath); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Document removed successfully", nil)
+}
+
+func (h *DocumentHandler) FetchQuarantinedDocuments(c *gin.Context) {
+	data, err := h.documentService.FetchQuarantinedDocuments()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Quarantined documents fetched successfully", data)
+}
+
+func (h *DocumentHandler) RemoveQuarantinedDocument(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	document, err := h.documentService.RemoveQuarantinedDocument(actor, uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	if err := h.fileStorage.Delete(document.FilePath); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Quarantined document removed successfully", nil)
+}
+
+func (h *DocumentHandler) FetchMissingDocuments(c *gin.Context) {
+	var filters request.FetchMissingDocuments
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	filters.Search = utils.SqlParamValidator(filters.Search)
+
+	data, err := h.documentService.FetchMissingDocuments(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Missing documents fetched successfully", data)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmployeeDataHandler struct {
+	employeeDataService domain.EmployeeDataService
+}
+
+func NewEmployeeDataHandler(employeeDataService domain.EmployeeDataService) *EmployeeDataHandler {
+	return &EmployeeDataHandler{employeeDataService}
+}
+
+func (h *EmployeeDataHandler) ExportEmployees(c *gin.Context) {
+	var filters request.ExportEmployees
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.employeeDataService.ExportEmployees(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="employees-%s.json"`,
+		time.Now().Format("20060102")))
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *EmployeeDataHandler) ImportEmployees(c *gin.Context) {
+	var req []request.ImportEmployee
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	for i := range req {
+		req[i].User.Code = utils.SqlParamValidator(req[i].User.Code)
+		for j := range req[i].CustomFields {
+			req[i].CustomFields[j].Key = utils.SqlParamValidator(req[i].CustomFields[j].Key)
+			req[i].CustomFields[j].Value = utils.SqlParamValidator(req[i].CustomFields[j].Value)
+		}
+	}
+
+	data, err := h.employeeDataService.ImportEmployees(req)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Employee data imported successfully", data)
+}
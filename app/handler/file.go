@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/domain"
+	"ems/utils"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FileHandler struct {
+	fileStorage domain.FileStorage
+}
+
+func NewFileHandler(fileStorage domain.FileStorage) *FileHandler {
+	return &FileHandler{fileStorage}
+}
+
+// DownloadSignedFile serves a file for a URL issued by SignFileURL. The signature is the
+// only authorisation, so links must stay short-lived.
+func (h *FileHandler) DownloadSignedFile(c *gin.Context) {
+	key := c.Query("key")
+	fileName := c.Query("name")
+
+	if !utils.VerifyFileURL(key, fileName, c.Query("expires"), c.Query("signature")) {
+		api_response.UnauthorizedError(c, "Invalid or expired file link")
+		return
+	}
+
+	file, err := h.fileStorage.Open(key)
+
+	if err != nil {
+		api_response.BadRequestError(c, "File not found")
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", utils.ContentType(key))
+	c.Header("Content-Disposition", utils.ContentDisposition(key, fileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, file)
+}
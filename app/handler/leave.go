@@ -39,7 +39,14 @@ func (h *LeaveHandler) RequestLeave(c *gin.Context) {
 		return
 	}
 
-	if err := h.leaveService.RequestLeave(*user.DepartmentMemberID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.leaveService.RequestLeave(actor, *user.DepartmentMemberID, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -125,7 +132,14 @@ func (h *LeaveHandler) UpdateLeaveStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.leaveService.UpdateLeaveStatus(uint(id), user.ID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.leaveService.UpdateLeaveStatus(actor, uint(id), user.ID, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -176,7 +190,14 @@ func (h *LeaveHandler) UpdateLeaveRequest(c *gin.Context) {
 		return
 	}
 
-	if err := h.leaveService.UpdateLeaveRequest(uint(id), &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.leaveService.UpdateLeaveRequest(actor, uint(id), &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -194,7 +215,14 @@ func (h *LeaveHandler) RemoveLeaveRequest(c *gin.Context) {
 		return
 	}
 
-	if err := h.leaveService.RemoveLeaveRequest(uint(id)); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.leaveService.RemoveLeaveRequest(actor, uint(id)); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LetterHandler struct {
+	letterService domain.LetterService
+	fileStorage   domain.FileStorage
+}
+
+func NewLetterHandler(letterService domain.LetterService, fileStorage domain.FileStorage) *LetterHandler {
+	return &LetterHandler{letterService, fileStorage}
+}
+
+func (h *LetterHandler) CreateLetterTemplate(c *gin.Context) {
+	var req request.CreateLetterTemplate
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.letterService.CreateLetterTemplate(user.ID, &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letter template created successfully", nil)
+}
+
+func (h *LetterHandler) FetchLetterTemplates(c *gin.Context) {
+	data, err := h.letterService.FetchLetterTemplates()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letter templates fetched successfully", data)
+}
+
+func (h *LetterHandler) UpdateLetterTemplate(c *gin.Context) {
+	var req request.UpdateLetterTemplate
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.letterService.UpdateLetterTemplate(uint(id), user.ID, &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letter template updated successfully", nil)
+}
+
+func (h *LetterHandler) RemoveLetterTemplate(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.letterService.RemoveLetterTemplate(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letter template removed successfully", nil)
+}
+
+func (h *LetterHandler) FetchLetterTemplateVersions(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.letterService.FetchLetterTemplateVersions(uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letter template versions fetched successfully", data)
+}
+
+func (h *LetterHandler) GenerateLetter(c *gin.Context) {
+	var req request.GenerateLetter
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	letter, err := h.letterService.RenderLetter(req.LetterTemplateID, req.UserID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	if err := h.storeLetters(actor, user.ID, []response.RenderedLetter{*letter}); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letter generated successfully", nil)
+}
+
+func (h *LetterHandler) GenerateLetters(c *gin.Context) {
+	var req request.GenerateLetters
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	letters, result, err := h.letterService.RenderLetters(&req)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	if err := h.storeLetters(actor, user.ID, letters); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Letters generated successfully", result)
+}
+
+// storeLetters writes the rendered PDFs to storage and records them as user documents,
+// removing the files again if recording fails.
+func (h *LetterHandler) storeLetters(actor *request.AuditActor, uploadedBy uint, letters []response.RenderedLetter) error {
+	documents := make([]response.UploadedDocument, 0, len(letters))
+
+	for i := range letters {
+		if err := saveGeneratedDocument(h.fileStorage, letters[i].Content, letters[i].UserID, &letters[i].Document); err != nil {
+			removeUploadedDocuments(h.fileStorage, documents)
+			return err
+		}
+
+		documents = append(documents, letters[i].Document)
+	}
+
+	if err := h.letterService.StoreLetters(actor, uploadedBy, letters); err != nil {
+		removeUploadedDocuments(h.fileStorage, documents)
+		return err
+	}
+
+	return nil
+}
@@ -40,7 +40,14 @@ func (h *NoticeHandler) ApplyNotice(c *gin.Context) {
 
 	req.Remarks = utils.SqlParamValidator(req.Remarks)
 
-	if err := h.noticeService.ApplyNotice(*user.DepartmentMemberID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.noticeService.ApplyNotice(actor, *user.DepartmentMemberID, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -114,7 +121,14 @@ func (h *NoticeHandler) ApproveNotice(c *gin.Context) {
 		return
 	}
 
-	if err := h.noticeService.ApproveNotice(user.ID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.noticeService.ApproveNotice(actor, user.ID, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
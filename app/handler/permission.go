@@ -39,7 +39,14 @@ func (h *PermissionHandler) RequestPermission(c *gin.Context) {
 		return
 	}
 
-	if err := h.permissionService.RequestPermission(*user.DepartmentMemberID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.permissionService.RequestPermission(actor, *user.DepartmentMemberID, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -125,7 +132,14 @@ func (h *PermissionHandler) UpdatePermissionStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.permissionService.UpdatePermissionStatus(uint(id), user.ID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.permissionService.UpdatePermissionStatus(actor, uint(id), user.ID, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -162,7 +176,14 @@ func (h *PermissionHandler) UpdatePermissionRequest(c *gin.Context) {
 		return
 	}
 
-	if err := h.permissionService.UpdatePermissionRequest(*user.DepartmentMemberID, uint(id), &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.permissionService.UpdatePermissionRequest(actor, *user.DepartmentMemberID, uint(id), &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -180,7 +201,14 @@ func (h *PermissionHandler) RemovePermissionRequest(c *gin.Context) {
 		return
 	}
 
-	if err := h.permissionService.RemovePermissionRequest(uint(id)); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.permissionService.RemovePermissionRequest(actor, uint(id)); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
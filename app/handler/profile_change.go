@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileChangeHandler struct {
+	profileChangeService domain.ProfileChangeService
+	documentService      domain.DocumentService
+	fileStorage          domain.FileStorage
+	fileScanner          domain.FileScanner
+}
+
+func NewProfileChangeHandler(profileChangeService domain.ProfileChangeService,
+	documentService domain.DocumentService, fileStorage domain.FileStorage,
+	fileScanner domain.FileScanner) *ProfileChangeHandler {
+	return &ProfileChangeHandler{profileChangeService, documentService, fileStorage, fileScanner}
+}
+
+func (h *ProfileChangeHandler) RequestProfileChange(c *gin.Context) {
+	var req request.RequestProfileChange
+
+	if err := c.ShouldBind(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	for _, field := range []*string{req.Address, req.City, req.Mobile, req.BankAccountNumber, req.IfscCode} {
+		if field != nil {
+			*field = utils.SqlParamValidator(*field)
+		}
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	var document *response.UploadedDocument
+
+	if file, err := c.FormFile("document"); err == nil {
+		files := []*multipart.FileHeader{file}
+
+		documents, err := inspectUploads(h.documentService, h.fileStorage, h.fileScanner, actor, user.ID, user.ID, nil, files)
+
+		if err != nil {
+			uploadError(c, err)
+			return
+		}
+
+		if err := saveUploadedDocuments(h.fileStorage, files, user.ID, documents); err != nil {
+			api_response.InternalServerError(c, err.Error())
+			return
+		}
+
+		document = &documents[0]
+	}
+
+	if err := h.profileChangeService.RequestProfileChange(user.ID, &req, document); err != nil {
+		if document != nil {
+			h.fileStorage.Delete(document.FilePath)
+		}
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Profile change requested successfully", nil)
+}
+
+func (h *ProfileChangeHandler) FetchOwnProfileChangeRequests(c *gin.Context) {
+	var filters request.CommonRequest
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.profileChangeService.FetchOwnProfileChangeRequests(user.ID, &filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Profile change requests fetched successfully", data)
+}
+
+func (h *ProfileChangeHandler) FetchPendingProfileChangeRequests(c *gin.Context) {
+	var filters request.CommonRequest
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.profileChangeService.FetchPendingProfileChangeRequests(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Pending profile change requests fetched successfully", data)
+}
+
+func (h *ProfileChangeHandler) UpdateProfileChangeStatus(c *gin.Context) {
+	var req request.UpdateProfileChangeStatus
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Remarks = utils.SqlParamValidator(req.Remarks)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.profileChangeService.UpdateProfileChangeStatus(actor, uint(id), user.ID, &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Profile change request status updated successfully", nil)
+}
@@ -2,7 +2,10 @@ package handler
 
 import (
 	"ems/api/api_response"
+	"ems/app/model/request"
 	"ems/domain"
+	"ems/utils"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,3 +29,102 @@ func (h *RoleHandler) FetchRoles(c *gin.Context) {
 
 	api_response.Success(c, "Roles fetched successfully", data)
 }
+
+func (h *RoleHandler) FetchPermissions(c *gin.Context) {
+	data, err := h.roleService.FetchPermissions()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Permissions fetched successfully", data)
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req request.CreateRole
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	if err := h.roleService.CreateRole(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Role created successfully", nil)
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	var req request.UpdateRole
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	if err := h.roleService.UpdateRole(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Role updated successfully", nil)
+}
+
+func (h *RoleHandler) UpdateRoleTwoFactor(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	var req request.UpdateRoleTwoFactor
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.roleService.UpdateRoleTwoFactor(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Role two-factor requirement updated successfully", nil)
+}
+
+func (h *RoleHandler) RemoveRole(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.roleService.RemoveRole(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Role removed successfully", nil)
+}
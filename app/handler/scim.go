@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SCIMHandler struct {
+	scimService domain.SCIMService
+}
+
+func NewSCIMHandler(scimService domain.SCIMService) *SCIMHandler {
+	return &SCIMHandler{scimService}
+}
+
+func (h *SCIMHandler) FetchServiceProviderConfig(c *gin.Context) {
+	api_response.SCIM(c, http.StatusOK, h.scimService.FetchServiceProviderConfig())
+}
+
+func (h *SCIMHandler) FetchUsers(c *gin.Context) {
+	var query request.SCIMListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+
+	data, err := h.scimService.FetchUsers(&query)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	users := data.Resources.([]response.SCIMUser)
+	for i := range users {
+		users[i].Meta.Location = resourceLocation(c, "Users", users[i].ID)
+	}
+
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) FetchUser(c *gin.Context) {
+	userID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	data, err := h.scimService.FetchUser(userID)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Users", data.ID)
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var req request.SCIMUser
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	data, err := h.scimService.CreateUser(scimActor(c), &req)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Users", data.ID)
+	c.Header("Location", data.Meta.Location)
+	api_response.SCIM(c, http.StatusCreated, data)
+}
+
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	userID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	var req request.SCIMUser
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	data, err := h.scimService.ReplaceUser(scimActor(c), userID, &req)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Users", data.ID)
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	userID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	var req request.SCIMPatch
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	data, err := h.scimService.PatchUser(scimActor(c), userID, &req)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Users", data.ID)
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) DeactivateUser(c *gin.Context) {
+	userID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.scimService.DeactivateUser(scimActor(c), userID); err != nil {
+		scimError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) FetchGroups(c *gin.Context) {
+	var query request.SCIMListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+
+	data, err := h.scimService.FetchGroups(&query)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	groups := data.Resources.([]response.SCIMGroup)
+	for i := range groups {
+		groups[i].Meta.Location = resourceLocation(c, "Groups", groups[i].ID)
+	}
+
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) FetchGroup(c *gin.Context) {
+	departmentID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	withMembers := !strings.Contains(strings.ToLower(c.Query("excludedAttributes")), "members")
+
+	data, err := h.scimService.FetchGroup(departmentID, withMembers)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Groups", data.ID)
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var req request.SCIMGroup
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	data, err := h.scimService.CreateGroup(scimActor(c), &req)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Groups", data.ID)
+	c.Header("Location", data.Meta.Location)
+	api_response.SCIM(c, http.StatusCreated, data)
+}
+
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	departmentID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	var req request.SCIMGroup
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	data, err := h.scimService.ReplaceGroup(scimActor(c), departmentID, &req)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Groups", data.ID)
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	departmentID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	var req request.SCIMPatch
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+
+	data, err := h.scimService.PatchGroup(scimActor(c), departmentID, &req)
+
+	if err != nil {
+		scimError(c, err)
+		return
+	}
+
+	data.Meta.Location = resourceLocation(c, "Groups", data.ID)
+	api_response.SCIM(c, http.StatusOK, data)
+}
+
+func (h *SCIMHandler) RemoveGroup(c *gin.Context) {
+	departmentID, ok := scimResourceID(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.scimService.RemoveGroup(scimActor(c), departmentID); err != nil {
+		scimError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// scimResourceID reads the resource ID from the path. IDs that are not numbers cannot exist,
+// so they get a 404.
+func scimResourceID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	if err != nil || id == 0 {
+		api_response.SCIMError(c, http.StatusNotFound, "", "resource not found")
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// scimError maps a service error to its SCIM status and error type. The remaining errors are
+// business rules of the user and department services, which a retry would not get past.
+// scimActor records provisioning writes against the identity provider, which signs in with a
+// shared token rather than as a user.
+func scimActor(c *gin.Context) *request.AuditActor {
+	return &request.AuditActor{Type: constant.SCIMActor, IPAddress: c.ClientIP()}
+}
+
+func scimError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrDataNotFound):
+		api_response.SCIMError(c, http.StatusNotFound, "", err.Error())
+	case errors.Is(err, apperror.ErrUniqueKey):
+		api_response.SCIMError(c, http.StatusConflict, "uniqueness", err.Error())
+	case errors.Is(err, apperror.ErrInvalidFilter):
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+	case errors.Is(err, apperror.ErrInvalidPath):
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidPath", err.Error())
+	case errors.Is(err, apperror.ErrInvalidValue):
+		api_response.SCIMError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	default:
+		api_response.SCIMError(c, http.StatusBadRequest, "", err.Error())
+	}
+}
+
+// resourceLocation builds the absolute URL of a resource from the URL the request came in on.
+func resourceLocation(c *gin.Context, resourceType, id string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	base, _, _ := strings.Cut(c.FullPath(), "/scim/v2/")
+
+	return scheme + "://" + c.Request.Host + base + "/scim/v2/" + resourceType + "/" + id
+}
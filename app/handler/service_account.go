@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceAccountHandler struct {
+	serviceAccountService domain.ServiceAccountService
+}
+
+func NewServiceAccountHandler(serviceAccountService domain.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{serviceAccountService}
+}
+
+func (h *ServiceAccountHandler) FetchServiceAccounts(c *gin.Context) {
+	data, err := h.serviceAccountService.FetchServiceAccounts()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Service accounts fetched successfully", data)
+}
+
+func (h *ServiceAccountHandler) FetchAPIKeyScopes(c *gin.Context) {
+	api_response.Success(c, "API key scopes fetched successfully", h.serviceAccountService.FetchAPIKeyScopes())
+}
+
+func (h *ServiceAccountHandler) CreateServiceAccount(c *gin.Context) {
+	var req request.CreateServiceAccount
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.serviceAccountService.CreateServiceAccount(&req, user.ID); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Service account created successfully", nil)
+}
+
+func (h *ServiceAccountHandler) RemoveServiceAccount(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.serviceAccountService.RemoveServiceAccount(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Service account removed successfully", nil)
+}
+
+func (h *ServiceAccountHandler) CreateAPIKey(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	var req request.CreateAPIKey
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.serviceAccountService.CreateAPIKey(uint(id), &req, user.ID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "API key created successfully, copy it now as it will not be shown again", data)
+}
+
+func (h *ServiceAccountHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	apiKeyID, err := strconv.Atoi(c.Param("keyID"))
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.serviceAccountService.RevokeAPIKey(uint(id), uint(apiKeyID)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "API key revoked successfully", nil)
+}
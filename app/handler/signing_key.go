@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SigningKeyHandler struct {
+	signingKeyService domain.SigningKeyService
+}
+
+func NewSigningKeyHandler(signingKeyService domain.SigningKeyService) *SigningKeyHandler {
+	return &SigningKeyHandler{signingKeyService}
+}
+
+// FetchJWKS is served as a bare key set, as verifiers expect, and not in the API envelope.
+func (h *SigningKeyHandler) FetchJWKS(c *gin.Context) {
+	data, err := h.signingKeyService.FetchJWKS()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *SigningKeyHandler) RotateSigningKey(c *gin.Context) {
+	if err := h.signingKeyService.RotateSigningKey(true); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Signing key rotation started successfully", nil)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"ems/api/api_response"
+	apperror "ems/app/model/app_error"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// inspectUploads runs every file through content sniffing, the size, type and quota limits
+// and the malware scanner before anything is stored. Infected files are moved to quarantine
+// and the whole upload is rejected.
+func inspectUploads(documentService domain.DocumentService, fileStorage domain.FileStorage,
+	fileScanner domain.FileScanner, actor *request.AuditActor, uploadedBy, userID uint, documentCategoryID *uint,
+	files []*multipart.FileHeader) ([]response.UploadedDocument, error) {
+	documents := make([]response.UploadedDocument, 0, len(files))
+
+	for _, file := range files {
+		document, err := sniffUpload(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, *document)
+	}
+
+	if err := documentService.ValidateUploads(userID, documentCategoryID, documents); err != nil {
+		return nil, err
+	}
+
+	for i, file := range files {
+		if err := scanUpload(documentService, fileStorage, fileScanner, actor, uploadedBy, userID, file, &documents[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return documents, nil
+}
+
+// sniffUpload detects the content type from the file's leading bytes, ignoring the
+// extension and the Content-Type header sent by the client.
+func sniffUpload(file *multipart.FileHeader) (*response.UploadedDocument, error) {
+	fileName := filepath.Base(file.Filename)
+
+	if file.Size > config.Config.MaxUploadFileSize {
+		return nil, apperror.UploadRejectedError(fileName,
+			fmt.Sprintf("exceeds the file size limit of %d MB", config.Config.MaxUploadFileSize>>20))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	detected, err := mimetype.DetectReader(src)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, _, err := mime.ParseMediaType(detected.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.UploadedDocument{
+		FileName:    fileName,
+		ContentType: contentType,
+		FileSize:    file.Size,
+	}, nil
+}
+
+func scanUpload(documentService domain.DocumentService, fileStorage domain.FileStorage,
+	fileScanner domain.FileScanner, actor *request.AuditActor, uploadedBy, userID uint, file *multipart.FileHeader,
+	document *response.UploadedDocument) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	infected, signature, err := fileScanner.Scan(src)
+	if err != nil {
+		return err
+	}
+
+	if !infected {
+		return nil
+	}
+
+	quarantined := *document
+
+	if err := saveUploadedDocument(fileStorage, file, "quarantine/"+userDirectory(userID), &quarantined); err != nil {
+		return err
+	}
+
+	if err := documentService.QuarantineDocument(actor, uploadedBy, userID, &quarantined, signature); err != nil {
+		fileStorage.Delete(quarantined.FilePath)
+		return err
+	}
+
+	return apperror.UploadRejectedError(document.FileName, "failed the malware scan and was quarantined")
+}
+
+// saveUploadedDocument stores the file under a server generated key, so client supplied names
+// never reach the storage path, and records its key and SHA-256 checksum on the document.
+// The key extension follows the sniffed content type rather than the client's file name.
+func saveUploadedDocument(fileStorage domain.FileStorage, file *multipart.FileHeader, directory string,
+	document *response.UploadedDocument) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return storeDocument(fileStorage, src, directory, document)
+}
+
+// saveGeneratedDocument stores content the server produced itself, such as rendered letters.
+func saveGeneratedDocument(fileStorage domain.FileStorage, content []byte, userID uint,
+	document *response.UploadedDocument) error {
+	return storeDocument(fileStorage, bytes.NewReader(content), userDirectory(userID), document)
+}
+
+func storeDocument(fileStorage domain.FileStorage, content io.Reader, directory string,
+	document *response.UploadedDocument) error {
+	extension := ""
+	if detected := mimetype.Lookup(document.ContentType); detected != nil {
+		extension = detected.Extension()
+	}
+
+	key := fmt.Sprintf("%s/%s%s", directory, uuid.NewString(), extension)
+
+	hash := sha256.New()
+	if err := fileStorage.Save(key, io.TeeReader(content, hash), document.ContentType); err != nil {
+		return err
+	}
+
+	document.FilePath = key
+	document.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	return nil
+}
+
+// saveUploadedDocuments stores inspected files, removing the ones already saved if any fails.
+func saveUploadedDocuments(fileStorage domain.FileStorage, files []*multipart.FileHeader, userID uint,
+	documents []response.UploadedDocument) error {
+	for i, file := range files {
+		if err := saveUploadedDocument(fileStorage, file, userDirectory(userID), &documents[i]); err != nil {
+			removeUploadedDocuments(fileStorage, documents[:i])
+			return fmt.Errorf("failed to upload %s", documents[i].FileName)
+		}
+	}
+
+	return nil
+}
+
+func removeUploadedDocuments(fileStorage domain.FileStorage, documents []response.UploadedDocument) {
+	for _, document := range documents {
+		fileStorage.Delete(document.FilePath)
+	}
+}
+
+func userDirectory(userID uint) string {
+	return fmt.Sprintf("user-%d", userID)
+}
+
+// uploadError reports rejected uploads as bad requests and anything else as a server error.
+func uploadError(c *gin.Context, err error) {
+	if errors.Is(err, apperror.ErrUploadRejected) {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	api_response.InternalServerError(c, err.Error())
+}
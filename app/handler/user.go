@@ -3,24 +3,26 @@ package handler
 import (
 	"ems/api/api_response"
 	"ems/api/middleware"
+	apperror "ems/app/model/app_error"
 	"ems/app/model/request"
 	"ems/domain"
 	"ems/utils"
-	"fmt"
-	"net/http"
-	"os"
-	"path/filepath"
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	userService domain.UserService
+	userService     domain.UserService
+	documentService domain.DocumentService
+	fileStorage     domain.FileStorage
+	fileScanner     domain.FileScanner
 }
 
-func NewUserHandler(userService domain.UserService) *UserHandler {
-	return &UserHandler{userService}
+func NewUserHandler(userService domain.UserService, documentService domain.DocumentService,
+	fileStorage domain.FileStorage, fileScanner domain.FileScanner) *UserHandler {
+	return &UserHandler{userService, documentService, fileStorage, fileScanner}
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
@@ -38,7 +40,19 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	req.Mobile = utils.SqlParamValidator(req.Mobile)
 	req.Password = utils.SqlParamValidator(req.Password)
 
-	if err := h.userService.CreateUser(&req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.userService.CreateUser(actor, &req); err != nil {
+		if errors.Is(err, apperror.ErrWeakPassword) {
+			api_response.BadRequestError(c, err.Error())
+			return
+		}
+
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -89,7 +103,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.UpdateUser(uint(id), &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.userService.UpdateUser(actor, uint(id), &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -166,7 +187,14 @@ func (h *UserHandler) RemoveUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.RemoveUser(uint(id)); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.userService.RemoveUser(actor, uint(id)); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -191,7 +219,19 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.ResetPassword(user.ID, &req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.userService.ResetPassword(actor, user.ID, &req); err != nil {
+		if errors.Is(err, apperror.ErrWeakPassword) || errors.Is(err, apperror.ErrDirectoryPassword) {
+			api_response.BadRequestError(c, err.Error())
+			return
+		}
+
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -216,7 +256,14 @@ func (h *UserHandler) UpdateUserDetails(c *gin.Context) {
 	req.Degree = utils.SqlParamValidator(req.Degree)
 	req.College = utils.SqlParamValidator(req.College)
 
-	if err := h.userService.UpdateUserDetails(&req); err != nil {
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.userService.UpdateUserDetails(actor, &req); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -232,7 +279,14 @@ func (h *UserHandler) FetchUserDetails(c *gin.Context) {
 		return
 	}
 
-	data, err := h.userService.FetchUserDetails(&req)
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.userService.FetchUserDetails(user.ID, user.RoleID, &req)
 	if err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
@@ -241,70 +295,47 @@ func (h *UserHandler) FetchUserDetails(c *gin.Context) {
 	api_response.Success(c, "User details fetched successfully", data)
 }
 
-func (h *UserHandler) UploadFiles(c *gin.Context) {
-	userIdForm := c.PostForm("userID")
-	if userIdForm == "" {
-		api_response.BadRequestError(c, "userId is required")
-		return
-	}
+func (h *UserHandler) FetchUserFieldHistory(c *gin.Context) {
+	var filters request.FetchUserFieldHistory
 
-	userID, err := strconv.Atoi(userIdForm)
-	if err != nil {
+	if err := c.ShouldBindQuery(&filters); err != nil {
 		api_response.BadRequestError(c, err.Error())
 		return
 	}
 
-	form, err := c.MultipartForm()
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
 	if err != nil {
 		api_response.BadRequestError(c, err.Error())
 		return
 	}
 
-	files := form.File["files"]
-	if len(files) == 0 {
-		api_response.BadRequestError(c, "No files provided")
-		return
-	}
+	filters.UserID = uint(id)
+	filters.Field = utils.SqlParamValidator(filters.Field)
 
-	baseDir := "./uploads/" + "user-" + userIdForm
+	user, err := middleware.GetUserClaims(c)
 
-	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(baseDir, os.ModePerm); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create directory"})
-			return
-		}
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
 	}
 
-	var filepaths []string
-
-	for _, file := range files {
-		if filepath.Ext(file.Filename) != ".pdf" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Only PDF files are allowed"})
-			return
-		}
-
-		filePath := filepath.Join(baseDir, file.Filename)
+	data, err := h.userService.FetchUserFieldHistory(user.ID, user.RoleID, &filters)
 
-		if err := c.SaveUploadedFile(file, filePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to upload %s", file.Filename)})
-			return
-		}
-
-		filepaths = append(filepaths, filePath)
-	}
-
-	if err := h.userService.UploadFiles(uint(userID), filepaths); err != nil {
+	if err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
 
-	api_response.Success(c, "User documents uploaded successfully", nil)
+	api_response.Success(c, "User field history fetched successfully", data)
 }
 
-func (h *UserHandler) FetchFilePathsByUserID(c *gin.Context) {
-	userIdForm := c.Query("userID")
+func (h *UserHandler) UploadFiles(c *gin.Context) {
+	userIdForm := c.PostForm("userID")
 	if userIdForm == "" {
-		api_response.BadRequestError(c, "userID is required")
+		api_response.BadRequestError(c, "userId is required")
 		return
 	}
 
@@ -314,46 +345,52 @@ func (h *UserHandler) FetchFilePathsByUserID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.userService.FetchFilePathsByUserID(uint(userID))
+	form, err := c.MultipartForm()
 	if err != nil {
-		api_response.InternalServerError(c, err.Error())
+		api_response.BadRequestError(c, err.Error())
 		return
 	}
 
-	api_response.Success(c, "File paths fetched successfully", data)
-}
+	files := form.File["files"]
+	if len(files) == 0 {
+		api_response.BadRequestError(c, "No files provided")
+		return
+	}
 
-func (h *UserHandler) FetchFile(c *gin.Context) {
-	filePath := c.Query("path")
+	user, err := middleware.GetUserClaims(c)
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		api_response.BadRequestError(c, "File not found")
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
 		return
 	}
 
-	c.File(filePath)
-}
-
-func (h *UserHandler) DeleteFile(c *gin.Context) {
-	filePath := c.Query("path")
+	actor, err := auditActor(c)
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		api_response.BadRequestError(c, "File not found")
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
 		return
 	}
 
-	err := os.Remove(filePath)
+	documents, err := inspectUploads(h.documentService, h.fileStorage, h.fileScanner, actor, user.ID, uint(userID), nil,
+		files)
+
 	if err != nil {
+		uploadError(c, err)
+		return
+	}
+
+	if err := saveUploadedDocuments(h.fileStorage, files, uint(userID), documents); err != nil {
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
 
-	if err := h.userService.RemoveFile(filePath); err != nil {
+	if err := h.userService.UploadFiles(actor, uint(userID), documents); err != nil {
+		removeUploadedDocuments(h.fileStorage, documents)
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
 
-	api_response.Success(c, "File deleted successfully", nil)
+	api_response.Success(c, "User documents uploaded successfully", nil)
 }
 
 func (h *UserHandler) ChangePassword(c *gin.Context) {
@@ -368,7 +405,26 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	req.OldPassword = utils.SqlParamValidator(req.OldPassword)
 	req.NewPassword = utils.SqlParamValidator(req.NewPassword)
 
-	if err := h.userService.ChangePassword(&req); err != nil {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	actor, err := auditActor(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	if err := h.userService.ChangePassword(actor, user.ID, &req); err != nil {
+		if errors.Is(err, apperror.ErrWeakPassword) || errors.Is(err, apperror.ErrDirectoryPassword) {
+			api_response.BadRequestError(c, err.Error())
+			return
+		}
+
 		api_response.InternalServerError(c, err.Error())
 		return
 	}
@@ -387,3 +443,14 @@ func (h *UserHandler) FetchUnmappedHRUsers(c *gin.Context) {
 
 	api_response.Success(c, "Unmapped hr users fetched successfully", data)
 }
+
+func (h *UserHandler) RotatePIIEncryptionKey(c *gin.Context) {
+	data, err := h.userService.RotatePIIEncryptionKey()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User details re-encrypted successfully", data)
+}
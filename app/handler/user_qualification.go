@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserQualificationHandler struct {
+	userQualificationService domain.UserQualificationService
+}
+
+func NewUserQualificationHandler(userQualificationService domain.UserQualificationService) *UserQualificationHandler {
+	return &UserQualificationHandler{userQualificationService}
+}
+
+func (h *UserQualificationHandler) FetchOwnQualifications(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.userQualificationService.FetchUserQualifications(user.ID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User qualifications fetched successfully", data)
+}
+
+func (h *UserQualificationHandler) FetchUserQualifications(c *gin.Context) {
+	var req request.FetchUserDetails
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.userQualificationService.FetchUserQualifications(req.UserID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User qualifications fetched successfully", data)
+}
+
+func (h *UserQualificationHandler) CreateEducation(c *gin.Context) {
+	var req request.CreateEducation
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Degree = utils.SqlParamValidator(req.Degree)
+	req.Institution = utils.SqlParamValidator(req.Institution)
+	req.FieldOfStudy = utils.SqlParamValidator(req.FieldOfStudy)
+	req.Grade = utils.SqlParamValidator(req.Grade)
+
+	if err := h.userQualificationService.CreateEducation(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Education created successfully", nil)
+}
+
+func (h *UserQualificationHandler) UpdateEducation(c *gin.Context) {
+	var req request.UpdateEducation
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Degree = utils.SqlParamValidator(req.Degree)
+	req.Institution = utils.SqlParamValidator(req.Institution)
+	req.FieldOfStudy = utils.SqlParamValidator(req.FieldOfStudy)
+	req.Grade = utils.SqlParamValidator(req.Grade)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userQualificationService.UpdateEducation(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Education updated successfully", nil)
+}
+
+func (h *UserQualificationHandler) RemoveEducation(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userQualificationService.RemoveEducation(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Education removed successfully", nil)
+}
+
+func (h *UserQualificationHandler) CreateCertification(c *gin.Context) {
+	var req request.CreateCertification
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Issuer = utils.SqlParamValidator(req.Issuer)
+	req.CredentialID = utils.SqlParamValidator(req.CredentialID)
+	req.IssuedDate = utils.SqlParamValidator(req.IssuedDate)
+	req.ExpiryDate = utils.SqlParamValidator(req.ExpiryDate)
+
+	if err := h.userQualificationService.CreateCertification(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Certification created successfully", nil)
+}
+
+func (h *UserQualificationHandler) UpdateCertification(c *gin.Context) {
+	var req request.UpdateCertification
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Issuer = utils.SqlParamValidator(req.Issuer)
+	req.CredentialID = utils.SqlParamValidator(req.CredentialID)
+	req.IssuedDate = utils.SqlParamValidator(req.IssuedDate)
+	req.ExpiryDate = utils.SqlParamValidator(req.ExpiryDate)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userQualificationService.UpdateCertification(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Certification updated successfully", nil)
+}
+
+func (h *UserQualificationHandler) RemoveCertification(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userQualificationService.RemoveCertification(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Certification removed successfully", nil)
+}
+
+func (h *UserQualificationHandler) CreateSkill(c *gin.Context) {
+	var req request.CreateSkill
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	if err := h.userQualificationService.CreateSkill(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Skill created successfully", nil)
+}
+
+func (h *UserQualificationHandler) UpdateSkill(c *gin.Context) {
+	var req request.UpdateSkill
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userQualificationService.UpdateSkill(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Skill updated successfully", nil)
+}
+
+func (h *UserQualificationHandler) RemoveSkill(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userQualificationService.RemoveSkill(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Skill removed successfully", nil)
+}
+
+func (h *UserQualificationHandler) SearchQualifications(c *gin.Context) {
+	var filters request.SearchQualifications
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	filters.Search = utils.SqlParamValidator(filters.Search)
+	filters.Skills = utils.SqlParamValidator(filters.Skills)
+	filters.Certifications = utils.SqlParamValidator(filters.Certifications)
+	filters.Degree = utils.SqlParamValidator(filters.Degree)
+
+	data, err := h.userQualificationService.SearchQualifications(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Employees fetched successfully", data)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserRelationHandler struct {
+	userRelationService domain.UserRelationService
+}
+
+func NewUserRelationHandler(userRelationService domain.UserRelationService) *UserRelationHandler {
+	return &UserRelationHandler{userRelationService}
+}
+
+func (h *UserRelationHandler) FetchOwnRelations(c *gin.Context) {
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.userRelationService.FetchUserRelations(user.ID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User relations fetched successfully", data)
+}
+
+func (h *UserRelationHandler) FetchUserRelations(c *gin.Context) {
+	var req request.FetchUserDetails
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.userRelationService.FetchUserRelations(req.UserID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "User relations fetched successfully", data)
+}
+
+func (h *UserRelationHandler) CreateEmergencyContact(c *gin.Context) {
+	var req request.CreateEmergencyContact
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Relationship = utils.SqlParamValidator(req.Relationship)
+	req.Mobile = utils.SqlParamValidator(req.Mobile)
+	req.Address = utils.SqlParamValidator(req.Address)
+
+	if err := h.userRelationService.CreateEmergencyContact(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Emergency contact created successfully", nil)
+}
+
+func (h *UserRelationHandler) UpdateEmergencyContact(c *gin.Context) {
+	var req request.UpdateEmergencyContact
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Relationship = utils.SqlParamValidator(req.Relationship)
+	req.Mobile = utils.SqlParamValidator(req.Mobile)
+	req.Address = utils.SqlParamValidator(req.Address)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userRelationService.UpdateEmergencyContact(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Emergency contact updated successfully", nil)
+}
+
+func (h *UserRelationHandler) RemoveEmergencyContact(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userRelationService.RemoveEmergencyContact(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Emergency contact removed successfully", nil)
+}
+
+func (h *UserRelationHandler) CreateDependent(c *gin.Context) {
+	var req request.CreateDependent
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Relationship = utils.SqlParamValidator(req.Relationship)
+	req.DOB = utils.SqlParamValidator(req.DOB)
+	req.Gender = utils.SqlParamValidator(req.Gender)
+
+	if err := h.userRelationService.CreateDependent(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Dependent created successfully", nil)
+}
+
+func (h *UserRelationHandler) UpdateDependent(c *gin.Context) {
+	var req request.UpdateDependent
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+	req.Relationship = utils.SqlParamValidator(req.Relationship)
+	req.DOB = utils.SqlParamValidator(req.DOB)
+	req.Gender = utils.SqlParamValidator(req.Gender)
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userRelationService.UpdateDependent(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Dependent updated successfully", nil)
+}
+
+func (h *UserRelationHandler) RemoveDependent(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.userRelationService.RemoveDependent(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Dependent removed successfully", nil)
+}
+
+func (h *UserRelationHandler) UpdateNominees(c *gin.Context) {
+	var req request.UpdateNominees
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	for i := range req.Nominees {
+		req.Nominees[i].Name = utils.SqlParamValidator(req.Nominees[i].Name)
+		req.Nominees[i].Relationship = utils.SqlParamValidator(req.Nominees[i].Relationship)
+		req.Nominees[i].Address = utils.SqlParamValidator(req.Nominees[i].Address)
+	}
+
+	if err := h.userRelationService.UpdateNominees(&req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Nominees updated successfully", nil)
+}
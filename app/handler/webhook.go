@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"ems/api/api_response"
+	"ems/api/middleware"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/domain"
+	"ems/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService domain.WebhookService
+}
+
+func NewWebhookHandler(webhookService domain.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService}
+}
+
+func (h *WebhookHandler) FetchWebhooks(c *gin.Context) {
+	data, err := h.webhookService.FetchWebhooks()
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhooks fetched successfully", data)
+}
+
+func (h *WebhookHandler) FetchEventTypes(c *gin.Context) {
+	api_response.Success(c, "Event types fetched successfully", h.webhookService.FetchEventTypes())
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req request.CreateWebhook
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	user, err := middleware.GetUserClaims(c)
+
+	if err != nil {
+		api_response.UnauthorizedError(c, err.Error())
+		return
+	}
+
+	data, err := h.webhookService.CreateWebhook(&req, user.ID)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook created successfully, copy the secret now as it will not be shown again", data)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	var req request.UpdateWebhook
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	req.Name = utils.SqlParamValidator(req.Name)
+
+	if err := h.webhookService.UpdateWebhook(uint(id), &req); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook updated successfully", nil)
+}
+
+func (h *WebhookHandler) RemoveWebhook(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	if err := h.webhookService.RemoveWebhook(uint(id)); err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook removed successfully", nil)
+}
+
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.webhookService.RotateWebhookSecret(uint(id))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook secret rotated successfully, copy it now as it will not be shown again", data)
+}
+
+func (h *WebhookHandler) FetchWebhookDeliveries(c *gin.Context) {
+	var filters request.FetchWebhookDeliveries
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	param := c.Param("id")
+
+	id, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	filters.WebhookID = uint(id)
+
+	data, err := h.webhookService.FetchWebhookDeliveries(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook deliveries fetched successfully", data)
+}
+
+// FetchDeadLetterDeliveries lists the deliveries of every webhook that ran out of attempts.
+func (h *WebhookHandler) FetchDeadLetterDeliveries(c *gin.Context) {
+	var filters request.FetchWebhookDeliveries
+
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	filters.Status = string(constant.WebhookDeliveryDeadLetter)
+
+	data, err := h.webhookService.FetchWebhookDeliveries(&filters)
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Dead letter deliveries fetched successfully", data)
+}
+
+func (h *WebhookHandler) FetchWebhookDelivery(c *gin.Context) {
+	param := c.Param("deliveryID")
+
+	deliveryID, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.webhookService.FetchWebhookDelivery(uint(deliveryID))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook delivery fetched successfully", data)
+}
+
+func (h *WebhookHandler) RedeliverWebhookDelivery(c *gin.Context) {
+	param := c.Param("deliveryID")
+
+	deliveryID, err := strconv.Atoi(param)
+
+	if err != nil {
+		api_response.BadRequestError(c, err.Error())
+		return
+	}
+
+	data, err := h.webhookService.RedeliverWebhookDelivery(uint(deliveryID))
+
+	if err != nil {
+		api_response.InternalServerError(c, err.Error())
+		return
+	}
+
+	api_response.Success(c, "Webhook delivery redelivered", data)
+}
@@ -1,11 +1,70 @@
 package apperror
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrAccessDenied   = errors.New("access denied")
+	ErrUploadRejected = errors.New("upload rejected")
+	ErrInvalidSession = errors.New("invalid session")
+
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrTooManyAttempts      = errors.New("too many failed attempts")
+	ErrWeakPassword         = errors.New("password does not meet the policy")
+	ErrSingleSignOnFailed   = errors.New("single sign-on failed")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrDirectoryPassword    = errors.New("the password is managed by the LDAP directory")
+
+	ErrDataNotFound  = errors.New("not found")
+	ErrUniqueKey     = errors.New("already exists")
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidPath   = errors.New("invalid path")
+	ErrInvalidValue  = errors.New("invalid value")
+)
 
 func UniqueKeyError(field string) error {
-	return fmt.Errorf("%s already exists", field)
+	return fmt.Errorf("%s %w", field, ErrUniqueKey)
 }
 
 func DataNotFoundError(field string) error {
-	return fmt.Errorf("%s not found", field)
+	return fmt.Errorf("%s %w", field, ErrDataNotFound)
+}
+
+func AccessDeniedError(field string) error {
+	return fmt.Errorf("%w to %s", ErrAccessDenied, field)
+}
+
+func UploadRejectedError(fileName, reason string) error {
+	return fmt.Errorf("%w: %s %s", ErrUploadRejected, fileName, reason)
+}
+
+func InvalidSessionError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSession, reason)
+}
+
+func TooManyAttemptsError(retryAfter time.Duration) error {
+	return fmt.Errorf("%w, try again in %s", ErrTooManyAttempts, retryAfter.Round(time.Second))
+}
+
+func WeakPasswordError(reason string) error {
+	return fmt.Errorf("%w, it %s", ErrWeakPassword, reason)
+}
+
+func SingleSignOnError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrSingleSignOnFailed, reason)
+}
+
+func InvalidFilterError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFilter, reason)
+}
+
+func InvalidPathError(path string) error {
+	return fmt.Errorf("%w %q", ErrInvalidPath, path)
+}
+
+func InvalidValueError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidValue, reason)
 }
@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 type Role int64
 
 const (
@@ -16,3 +18,235 @@ const (
 	Inactive Status = iota
 	Active
 )
+
+type CustomFieldType string
+
+const (
+	TextField   CustomFieldType = "text"
+	NumberField CustomFieldType = "number"
+	DateField   CustomFieldType = "date"
+	EnumField   CustomFieldType = "enum"
+)
+
+type Visibility uint
+
+const (
+	HROnly Visibility = iota + 1
+	OwnerAndHR
+	Everyone
+)
+
+type Proficiency uint
+
+const (
+	Beginner Proficiency = iota + 1
+	Intermediate
+	Advanced
+	Expert
+)
+
+// CertificationExpiryReminderDays is how long before expiry the certificate holder is reminded.
+const CertificationExpiryReminderDays = 30
+
+// DocumentExpiryReminderDays is how long before expiry the document owner is reminded.
+const DocumentExpiryReminderDays = 30
+
+// DefaultAllowedDocumentTypes applies to uploads without a category, or whose category
+// does not list its own allowed types.
+const DefaultAllowedDocumentTypes = "application/pdf"
+
+// Permission names an action that can be granted to a role, written as "<resource>.<action>".
+type Permission string
+
+const (
+	UserView               Permission = "user.view"
+	UserViewSensitive      Permission = "user.viewSensitive"
+	UserCreate             Permission = "user.create"
+	UserUpdate             Permission = "user.update"
+	UserDelete             Permission = "user.delete"
+	UserRotateKey          Permission = "user.rotateKey"
+	UserResetTwoFactor     Permission = "user.resetTwoFactor"
+	UserUnlock             Permission = "user.unlock"
+	DirectorySync          Permission = "directory.sync"
+	SessionRevoke          Permission = "session.revoke"
+	DepartmentView         Permission = "department.view"
+	DepartmentManage       Permission = "department.manage"
+	RoleView               Permission = "role.view"
+	RoleManage             Permission = "role.manage"
+	LeaveViewTeam          Permission = "leave.viewTeam"
+	LeaveApprove           Permission = "leave.approve"
+	LeaveViewLeads         Permission = "leave.viewLeads"
+	LeaveViewAll           Permission = "leave.viewAll"
+	PermissionViewTeam     Permission = "permissionRequest.viewTeam"
+	PermissionApprove      Permission = "permissionRequest.approve"
+	PermissionViewLeads    Permission = "permissionRequest.viewLeads"
+	PermissionViewAll      Permission = "permissionRequest.viewAll"
+	NoticeView             Permission = "notice.view"
+	NoticeApprove          Permission = "notice.approve"
+	ProfileChangeView      Permission = "profileChange.view"
+	ProfileChangeApprove   Permission = "profileChange.approve"
+	CustomFieldView        Permission = "customField.view"
+	CustomFieldManage      Permission = "customField.manage"
+	QualificationView      Permission = "qualification.view"
+	QualificationManage    Permission = "qualification.manage"
+	RelationView           Permission = "relation.view"
+	RelationManage         Permission = "relation.manage"
+	EmployeeDataExport     Permission = "employeeData.export"
+	EmployeeDataImport     Permission = "employeeData.import"
+	DocumentView           Permission = "document.view"
+	DocumentUpload         Permission = "document.upload"
+	DocumentDelete         Permission = "document.delete"
+	DocumentQuarantine     Permission = "document.quarantine"
+	DocumentCategoryManage Permission = "documentCategory.manage"
+	LetterTemplateView     Permission = "letterTemplate.view"
+	LetterTemplateManage   Permission = "letterTemplate.manage"
+	LetterGenerate         Permission = "letter.generate"
+	ServiceAccountManage   Permission = "serviceAccount.manage"
+	UserImpersonate        Permission = "user.impersonate"
+	SigningKeyRotate       Permission = "signingKey.rotate"
+	AuditView              Permission = "audit.view"
+	WebhookManage          Permission = "webhook.manage"
+)
+
+// APIKeyScopes are the permissions that can be granted to a service account's API key. The
+// others are left out because their handlers act as the signed in employee.
+var APIKeyScopes = []Permission{
+	UserView, UserCreate, UserUpdate, DepartmentView, RoleView, LeaveViewLeads, LeaveViewAll,
+	PermissionViewLeads, PermissionViewAll, CustomFieldView, QualificationView, RelationView,
+	EmployeeDataExport, EmployeeDataImport, DocumentView, LetterTemplateView,
+}
+
+// APIKeyPrefix starts every API key, so that the middleware can tell one from a JWT.
+const APIKeyPrefix = "ems_"
+
+// APIKeyTouchInterval is how stale an API key's last used time may get before a request
+// updates it, so that busy integrations do not write on every call.
+const APIKeyTouchInterval = time.Minute
+
+// AuthEvent is a sign in related event recorded for security review.
+type AuthEvent string
+
+const (
+	LoginSucceeded               AuthEvent = "login.succeeded"
+	LoginFailed                  AuthEvent = "login.failed"
+	LoginBlocked                 AuthEvent = "login.blocked"
+	TwoFactorFailed              AuthEvent = "twoFactor.failed"
+	ForgotPasswordFailed         AuthEvent = "forgotPassword.failed"
+	ForgotPasswordOtpInvalidated AuthEvent = "forgotPassword.otpInvalidated"
+	ForgotPasswordVerified       AuthEvent = "forgotPassword.verified"
+	AccountLocked                AuthEvent = "account.locked"
+	IPLocked                     AuthEvent = "ip.locked"
+	AccountUnlocked              AuthEvent = "account.unlocked"
+	SSOLoginFailed               AuthEvent = "sso.failed"
+	SSOIdentityLinked            AuthEvent = "sso.identityLinked"
+	SSOUserProvisioned           AuthEvent = "sso.userProvisioned"
+	ImpersonationStarted         AuthEvent = "impersonation.started"
+	ImpersonationEnded           AuthEvent = "impersonation.ended"
+)
+
+// AuthMethod is how a session was signed in.
+type AuthMethod string
+
+const (
+	PasswordLogin AuthMethod = "password"
+	SSOLogin      AuthMethod = "sso"
+	LDAPLogin     AuthMethod = "ldap"
+	Impersonation AuthMethod = "impersonation"
+)
+
+// AuditAction is the kind of change recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditEntity is the kind of record an audit log entry is about.
+type AuditEntity string
+
+const (
+	AuditUser                AuditEntity = "user"
+	AuditUserDetails         AuditEntity = "userDetails"
+	AuditDepartment          AuditEntity = "department"
+	AuditDepartmentMember    AuditEntity = "departmentMember"
+	AuditLeave               AuditEntity = "leave"
+	AuditPermission          AuditEntity = "permission"
+	AuditNotice              AuditEntity = "notice"
+	AuditDocumentCategory    AuditEntity = "documentCategory"
+	AuditDocument            AuditEntity = "document"
+	AuditQuarantinedDocument AuditEntity = "quarantinedDocument"
+)
+
+// AuditActorType is who made an audited change: a signed in employee, a service account's
+// API key, the SCIM client of the identity provider or the system itself, for the scheduler
+// and sign in jobs that run without a request user.
+type AuditActorType string
+
+const (
+	UserActor           AuditActorType = "user"
+	ServiceAccountActor AuditActorType = "serviceAccount"
+	SCIMActor           AuditActorType = "scim"
+	SystemActor         AuditActorType = "system"
+)
+
+// EventType names a domain event published to the outbox.
+type EventType string
+
+const (
+	LeaveRequested              EventType = "leave.requested"
+	LeaveStatusChanged          EventType = "leave.statusChanged"
+	PermissionRequested         EventType = "permission.requested"
+	PermissionStatusChanged     EventType = "permission.statusChanged"
+	NoticeApplied               EventType = "notice.applied"
+	NoticeApproved              EventType = "notice.approved"
+	UserCreated                 EventType = "user.created"
+	DepartmentMembershipChanged EventType = "department.membershipChanged"
+)
+
+// EventTypes are the domain events that can be subscribed to.
+var EventTypes = []EventType{
+	LeaveRequested, LeaveStatusChanged, PermissionRequested, PermissionStatusChanged, NoticeApplied,
+	NoticeApproved, UserCreated, DepartmentMembershipChanged,
+}
+
+// WebhookSubscriber is the event bus subscriber that queues deliveries for the webhooks.
+const WebhookSubscriber = "webhook"
+
+// WebhookSecretPrefix starts every webhook signing secret.
+const WebhookSecretPrefix = "whsec_"
+
+// WebhookDeliveryStatus is where a webhook delivery is. A delivery is dead lettered once it runs
+// out of attempts, and stays so until it is redelivered.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending    WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered  WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDeadLetter WebhookDeliveryStatus = "deadLetter"
+)
+
+// AuthSource is where a user's password is kept. Directory users sign in with their LDAP
+// password and cannot change or reset it in EMS.
+type AuthSource string
+
+const (
+	LocalAuth     AuthSource = "local"
+	DirectoryAuth AuthSource = "ldap"
+)
+
+// RecoveryCodeCount is how many single use recovery codes are issued when two-factor is enabled.
+const RecoveryCodeCount = 10
+
+// SCIM schema URNs (RFC 7643 and RFC 7644).
+const (
+	SCIMUserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMGroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMEnterpriseUserSchema        = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SCIMListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMPatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMResourceTypeSchema          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
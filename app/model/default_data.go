@@ -1,5 +1,160 @@
 package model
 
+import "ems/app/model/constant"
+
 var Roles = []string{"Admin", "Manager", "HR", "Department Lead", "Employee"}
 
 var Pages = []string{"Department", "Team", "User", "Attendance", "Permission", "Leave"}
+
+type DefaultPermission struct {
+	Name        constant.Permission
+	Description string
+}
+
+var Permissions = []DefaultPermission{
+	{constant.UserView, "View employees and their files"},
+	{constant.UserViewSensitive, "View employees' PII unmasked and their HR-only custom fields"},
+	{constant.UserCreate, "Create employees"},
+	{constant.UserUpdate, "Update employees, their details and custom field values"},
+	{constant.UserDelete, "Remove employees"},
+	{constant.UserRotateKey, "Re-encrypt employee details with the active key"},
+	{constant.UserResetTwoFactor, "Reset an employee's two-factor authentication"},
+	{constant.UserUnlock, "Unlock an employee account locked after failed sign in attempts"},
+	{constant.DirectorySync, "Sync roles and departments from the LDAP directory"},
+	{constant.SessionRevoke, "Sign an employee out of all devices"},
+	{constant.DepartmentView, "View departments and their members"},
+	{constant.DepartmentManage, "Create, update and remove departments and map members"},
+	{constant.RoleView, "View roles and the available permissions"},
+	{constant.RoleManage, "Create, update and remove roles"},
+	{constant.LeaveViewTeam, "View leave requests of department members"},
+	{constant.LeaveApprove, "Approve or reject leave requests"},
+	{constant.LeaveViewLeads, "View leave requests of department leads and HR"},
+	{constant.LeaveViewAll, "View leave requests of any employee"},
+	{constant.PermissionViewTeam, "View permission requests of department members"},
+	{constant.PermissionApprove, "Approve or reject permission requests"},
+	{constant.PermissionViewLeads, "View permission requests of department leads and HR"},
+	{constant.PermissionViewAll, "View permission requests of any employee"},
+	{constant.NoticeView, "View notices served by employees"},
+	{constant.NoticeApprove, "Approve notices"},
+	{constant.ProfileChangeView, "View pending profile change requests"},
+	{constant.ProfileChangeApprove, "Approve or reject profile change requests"},
+	{constant.CustomFieldView, "View custom field definitions"},
+	{constant.CustomFieldManage, "Create, update and remove custom fields"},
+	{constant.QualificationView, "View and search employee qualifications"},
+	{constant.QualificationManage, "Add, update and remove employee qualifications"},
+	{constant.RelationView, "View emergency contacts, dependents and nominees"},
+	{constant.RelationManage, "Add, update and remove emergency contacts, dependents and nominees"},
+	{constant.EmployeeDataExport, "Export employee data"},
+	{constant.EmployeeDataImport, "Import employee data"},
+	{constant.DocumentView, "View employee documents and the missing document report"},
+	{constant.DocumentUpload, "Upload employee documents"},
+	{constant.DocumentDelete, "Remove employee documents"},
+	{constant.DocumentQuarantine, "Review and purge quarantined uploads"},
+	{constant.DocumentCategoryManage, "Create, update and remove document categories"},
+	{constant.LetterTemplateView, "View letter templates and their versions"},
+	{constant.LetterTemplateManage, "Create, update and remove letter templates"},
+	{constant.LetterGenerate, "Generate letters for employees"},
+	{constant.ServiceAccountManage, "Create and remove service accounts and their API keys"},
+	{constant.UserImpersonate, "Sign in as an employee for a limited time to see what they see"},
+	{constant.SigningKeyRotate, "Rotate the keys that sign access tokens"},
+	{constant.AuditView, "Search the audit log and verify its integrity"},
+	{constant.WebhookManage, "Manage webhook subscriptions and redeliver their events"},
+}
+
+// hrPermissions were previously granted by the HR middleware, which let Admin, Manager and HR through.
+var hrPermissions = []constant.Permission{
+	constant.UserView, constant.UserCreate, constant.UserUpdate, constant.UserDelete, constant.SessionRevoke,
+	constant.UserResetTwoFactor, constant.UserUnlock,
+	constant.DepartmentView, constant.DepartmentManage, constant.RoleView, constant.LeaveViewAll,
+	constant.PermissionViewAll, constant.NoticeView, constant.NoticeApprove, constant.ProfileChangeView,
+	constant.ProfileChangeApprove, constant.CustomFieldView, constant.CustomFieldManage,
+	constant.QualificationView, constant.QualificationManage, constant.RelationView, constant.RelationManage,
+	constant.EmployeeDataExport, constant.EmployeeDataImport, constant.DocumentView, constant.DocumentUpload,
+	constant.DocumentDelete, constant.DocumentQuarantine, constant.DocumentCategoryManage,
+	constant.LetterTemplateView, constant.LetterTemplateManage, constant.LetterGenerate, constant.AuditView,
+}
+
+// leadPermissions were previously granted by the department lead middleware.
+var leadPermissions = []constant.Permission{
+	constant.LeaveViewTeam, constant.LeaveApprove, constant.PermissionViewTeam, constant.PermissionApprove,
+}
+
+// RolePermissions holds the default grants of the built-in roles. Admin is left out because
+// it always has every permission.
+var RolePermissions = map[constant.Role][]constant.Permission{
+	constant.Manager: append(append(append([]constant.Permission{}, hrPermissions...), leadPermissions...),
+		constant.LeaveViewLeads, constant.PermissionViewLeads),
+	constant.HR:             append(append([]constant.Permission{}, hrPermissions...), constant.UserViewSensitive),
+	constant.DepartmentLead: leadPermissions,
+}
+
+type DefaultDocumentCategory struct {
+	Name         string
+	Code         string
+	IsRequired   bool
+	HasExpiry    bool
+	AllowedTypes string
+}
+
+var DocumentCategories = []DefaultDocumentCategory{
+	{Name: "ID Proof", Code: "idProof", IsRequired: true, HasExpiry: true,
+		AllowedTypes: "application/pdf,image/jpeg,image/png"},
+	{Name: "Offer Letter", Code: "offerLetter", IsRequired: true, AllowedTypes: "application/pdf"},
+	{Name: "Payslip", Code: "payslip", AllowedTypes: "application/pdf"},
+	{Name: "Certificate", Code: "certificate", HasExpiry: true, AllowedTypes: "application/pdf,image/jpeg,image/png"},
+	{Name: "Appointment Letter", Code: "appointmentLetter", AllowedTypes: "application/pdf"},
+	{Name: "Experience Letter", Code: "experienceLetter", AllowedTypes: "application/pdf"},
+	{Name: "Relieving Letter", Code: "relievingLetter", AllowedTypes: "application/pdf"},
+}
+
+type DefaultLetterTemplate struct {
+	Name         string
+	CategoryCode string
+	Title        string
+	Body         string
+}
+
+var LetterTemplates = []DefaultLetterTemplate{
+	{Name: "Offer Letter", CategoryCode: "offerLetter", Title: "Offer of Employment",
+		Body: `Date: {{today}}
+
+Dear {{fullName}},
+
+We are pleased to offer you the position of {{designation}} in the {{department}} department. Your employment will begin on {{dateOfJoining}} and you will report to {{manager}}.
+
+Please confirm your acceptance by replying to this letter.
+
+Regards,
+Human Resources`},
+	{Name: "Appointment Letter", CategoryCode: "appointmentLetter", Title: "Letter of Appointment",
+		Body: `Date: {{today}}
+
+Dear {{fullName}},
+
+Further to your acceptance of our offer, you are appointed as {{designation}} in the {{department}} department with effect from {{dateOfJoining}}. Your employee code is {{code}}.
+
+We welcome you to the organisation.
+
+Regards,
+Human Resources`},
+	{Name: "Experience Letter", CategoryCode: "experienceLetter", Title: "Experience Certificate",
+		Body: `Date: {{today}}
+
+To whom it may concern,
+
+This is to certify that {{fullName}} (employee code {{code}}) worked with us as {{designation}} in the {{department}} department from {{dateOfJoining}} to {{lastWorkingDate}}.
+
+We wish them success in their future endeavours.
+
+Regards,
+Human Resources`},
+	{Name: "Relieving Letter", CategoryCode: "relievingLetter", Title: "Relieving Letter",
+		Body: `Date: {{today}}
+
+Dear {{fullName}},
+
+This is to confirm that you have been relieved from your duties as {{designation}} at the close of business on {{lastWorkingDate}}, and that all dues have been settled.
+
+Regards,
+Human Resources`},
+}
@@ -0,0 +1,77 @@
+package event
+
+import "time"
+
+// The payloads of the domain events. They are stored in the outbox as JSON and subscribers
+// decode them by the event type.
+
+type LeaveRequested struct {
+	LeaveID            uint        `json:"leaveID"`
+	DepartmentMemberID uint        `json:"departmentMemberID"`
+	UserID             uint        `json:"userID"`
+	Reason             string      `json:"reason"`
+	Dates              []LeaveDate `json:"dates" gorm:"-"`
+}
+
+type LeaveDate struct {
+	Date        string `json:"date"`
+	IsFullDay   bool   `json:"isFullDay"`
+	SessionType uint   `json:"sessionType"`
+}
+
+type LeaveStatusChanged struct {
+	LeaveID            uint `json:"leaveID"`
+	DepartmentMemberID uint `json:"departmentMemberID"`
+	UserID             uint `json:"userID"`
+	IsApproved         bool `json:"isApproved"`
+	ApprovedBy         uint `json:"approvedBy"`
+}
+
+type PermissionRequested struct {
+	PermissionID       uint   `json:"permissionID"`
+	DepartmentMemberID uint   `json:"departmentMemberID"`
+	UserID             uint   `json:"userID"`
+	Date               string `json:"date"`
+	FromTime           string `json:"fromTime"`
+	ToTime             string `json:"toTime"`
+	Reason             string `json:"reason"`
+}
+
+type PermissionStatusChanged struct {
+	PermissionID       uint `json:"permissionID"`
+	DepartmentMemberID uint `json:"departmentMemberID"`
+	UserID             uint `json:"userID"`
+	IsApproved         bool `json:"isApproved"`
+	ApprovedBy         uint `json:"approvedBy"`
+}
+
+type NoticeApplied struct {
+	NoticeID           uint   `json:"noticeID"`
+	DepartmentMemberID uint   `json:"departmentMemberID"`
+	UserID             uint   `json:"userID"`
+	Remarks            string `json:"remarks"`
+}
+
+type NoticeApproved struct {
+	NoticeID           uint      `json:"noticeID"`
+	DepartmentMemberID uint      `json:"departmentMemberID"`
+	UserID             uint      `json:"userID"`
+	NoticeEndDate      time.Time `json:"noticeEndDate"`
+	ApprovedBy         uint      `json:"approvedBy"`
+}
+
+type UserCreated struct {
+	UserID    uint   `json:"userID"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Code      string `json:"code"`
+	RoleID    uint   `json:"roleID"`
+}
+
+// DepartmentMembershipChanged is published for each user joining or leaving a department.
+type DepartmentMembershipChanged struct {
+	DepartmentID uint `json:"departmentID"`
+	UserID       uint `json:"userID"`
+	Joined       bool `json:"joined"`
+}
@@ -0,0 +1,22 @@
+package request
+
+import "ems/app/model/constant"
+
+// AuditActor is who made a change and where from, as recorded in the audit log.
+type AuditActor struct {
+	Type             constant.AuditActorType
+	UserID           *uint
+	ServiceAccountID *uint
+	ImpersonatorID   *uint
+	IPAddress        string
+}
+
+type FetchAuditLogs struct {
+	CommonRequest
+	UserID   uint   `form:"userID"`
+	Action   string `form:"action"`
+	Entity   string `form:"entity"`
+	EntityID uint   `form:"entityID"`
+	FromDate string `form:"fromDate"`
+	ToDate   string `form:"toDate"`
+}
@@ -23,3 +23,37 @@ type ChangePassword struct {
 	OldPassword string `json:"oldPassword" binding:"required"`
 	NewPassword string `json:"newPassword" binding:"required"`
 }
+
+type StartImpersonation struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type RefreshSession struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// SessionDevice describes the client a session is created or refreshed from.
+type SessionDevice struct {
+	UserAgent string
+	IPAddress string
+}
+
+type TwoFactorToken struct {
+	TwoFactorToken string `json:"twoFactorToken" binding:"required"`
+}
+
+type TwoFactorCode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// VerifyTwoFactor completes a login; Code is a TOTP code or an unused recovery code.
+type VerifyTwoFactor struct {
+	TwoFactorToken
+	TwoFactorCode
+}
+
+// CompleteOIDCLogin carries the query parameters the identity provider redirected back with.
+type CompleteOIDCLogin struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
@@ -0,0 +1,39 @@
+package request
+
+type CreateCustomField struct {
+	Name       string   `json:"name" binding:"required"`
+	Key        string   `json:"key" binding:"required,alphanum"`
+	FieldType  string   `json:"fieldType" binding:"required,oneof=text number date enum"`
+	Options    []string `json:"options"`
+	IsRequired bool     `json:"isRequired"`
+	Pattern    string   `json:"pattern"`
+	MinValue   *float64 `json:"minValue"`
+	MaxValue   *float64 `json:"maxValue"`
+	Visibility uint     `json:"visibility" binding:"required,oneof=1 2 3"`
+}
+
+type UpdateCustomField struct {
+	CreateCustomField
+}
+
+type UpdateUserCustomFieldValues struct {
+	UserID uint               `json:"userID" binding:"required"`
+	Values []CustomFieldValue `json:"values" binding:"required,dive"`
+}
+
+type CustomFieldValue struct {
+	CustomFieldID uint   `json:"customFieldID" binding:"required"`
+	Value         string `json:"value"`
+}
+
+type ImportEmployee struct {
+	User struct {
+		Code string `json:"code" binding:"required"`
+	} `json:"user"`
+	CustomFields []ImportCustomFieldValue `json:"customFields"`
+}
+
+type ImportCustomFieldValue struct {
+	Key   string `json:"key" binding:"required"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type SyncDirectory struct {
+	DryRun bool `form:"dryRun"`
+}
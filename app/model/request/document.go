@@ -0,0 +1,31 @@
+package request
+
+type CreateDocumentCategory struct {
+	Name        string `json:"name" binding:"required"`
+	Code        string `json:"code" binding:"required,alphanum"`
+	Description string `json:"description"`
+	IsRequired  bool   `json:"isRequired"`
+	HasExpiry   bool   `json:"hasExpiry"`
+	// AllowedTypes lists the MIME types accepted for this category, e.g. "image/png".
+	AllowedTypes []string `json:"allowedTypes"`
+}
+
+type UpdateDocumentCategory struct {
+	CreateDocumentCategory
+}
+
+type UploadDocument struct {
+	UserID             uint   `form:"userID" binding:"required"`
+	DocumentCategoryID uint   `form:"documentCategoryID" binding:"required"`
+	ExpiryDate         string `form:"expiryDate"`
+}
+
+type FetchUserDocuments struct {
+	UserID             uint `form:"userID" binding:"required"`
+	DocumentCategoryID uint `form:"documentCategoryID"`
+}
+
+type FetchMissingDocuments struct {
+	CommonRequest
+	DepartmentID uint `form:"departmentID"`
+}
@@ -0,0 +1,24 @@
+package request
+
+type CreateLetterTemplate struct {
+	Name               string `json:"name" binding:"required"`
+	DocumentCategoryID uint   `json:"documentCategoryID" binding:"required"`
+	Title              string `json:"title" binding:"required"`
+	Body               string `json:"body" binding:"required"`
+}
+
+type UpdateLetterTemplate struct {
+	CreateLetterTemplate
+}
+
+type GenerateLetter struct {
+	LetterTemplateID uint `json:"letterTemplateID" binding:"required"`
+	UserID           uint `json:"userID" binding:"required"`
+}
+
+// GenerateLetters targets either the listed users or every member of a department.
+type GenerateLetters struct {
+	LetterTemplateID uint   `json:"letterTemplateID" binding:"required"`
+	UserIDs          []uint `json:"userIDs"`
+	DepartmentID     uint   `json:"departmentID"`
+}
@@ -0,0 +1,22 @@
+package request
+
+type RequestProfileChange struct {
+	Address           *string `form:"address"`
+	City              *string `form:"city"`
+	Mobile            *string `form:"mobile"`
+	BankAccountNumber *string `form:"bankAccountNumber"`
+	IfscCode          *string `form:"ifscCode"`
+}
+
+type UpdateProfileChangeStatus struct {
+	IsApproved bool   `json:"isApproved"`
+	Remarks    string `json:"remarks"`
+}
+
+// ApplyProfileChange holds an approved request's changes, merged into the user's current
+// record. User is nil when the mobile number does not change, and Details when only it does.
+type ApplyProfileChange struct {
+	UserID  uint
+	User    *UpdateUser
+	Details *UpdateUserDetails
+}
@@ -0,0 +1,15 @@
+package request
+
+type CreateRole struct {
+	Name        string   `json:"name" binding:"required"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRole struct {
+	CreateRole
+}
+
+type UpdateRoleTwoFactor struct {
+	RequireTwoFactor *bool `json:"requireTwoFactor" binding:"required"`
+}
@@ -0,0 +1,70 @@
+package request
+
+// SCIMUser is a User resource as sent by an identity provider. Only the attributes EMS keeps
+// are read: userName is the user's email and the enterprise employeeNumber their code.
+type SCIMUser struct {
+	ExternalID   *string             `json:"externalId"`
+	UserName     string              `json:"userName"`
+	Name         *SCIMName           `json:"name"`
+	DisplayName  string              `json:"displayName"`
+	PhoneNumbers []SCIMMultiValue    `json:"phoneNumbers"`
+	Title        *string             `json:"title"`
+	Active       *bool               `json:"active"`
+	Enterprise   *SCIMEnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"`
+}
+
+type SCIMName struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Primary bool   `json:"primary"`
+}
+
+type SCIMEnterpriseUser struct {
+	EmployeeNumber string `json:"employeeNumber"`
+}
+
+// SCIMGroup is a Group resource, which EMS keeps as a department and its members.
+type SCIMGroup struct {
+	ExternalID  *string      `json:"externalId"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members"`
+}
+
+type SCIMMember struct {
+	Value string `json:"value"`
+}
+
+type SCIMPatch struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations" binding:"required,min=1"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string      `json:"op" binding:"required"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+type SCIMListQuery struct {
+	Filter             string `form:"filter"`
+	StartIndex         int    `form:"startIndex"`
+	Count              *int   `form:"count"`
+	ExcludedAttributes string `form:"excludedAttributes"`
+}
+
+// SaveSCIMUser holds the user columns a SCIM write sets. A nil Title leaves the designation
+// in the user's details as it is.
+type SaveSCIMUser struct {
+	FirstName  string
+	LastName   string
+	Email      string
+	Mobile     string
+	Code       string
+	ExternalID *string
+	Title      *string
+}
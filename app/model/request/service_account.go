@@ -0,0 +1,12 @@
+package request
+
+type CreateServiceAccount struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
+}
+
+type CreateAPIKey struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays uint     `json:"expiresInDays" binding:"required,min=1"`
+}
@@ -51,6 +51,25 @@ type FetchUnmappedUsersByDepartmentID struct {
 
 type FetchUsers struct {
 	CommonRequest
-	DepartmentID uint `form:"departmentID"`
-	RoleID       uint `form:"roleID"`
+	DepartmentID     uint   `form:"departmentID"`
+	RoleID           uint   `form:"roleID"`
+	CustomFieldID    uint   `form:"customFieldID"`
+	CustomFieldValue string `form:"customFieldValue"`
+}
+
+// UpdateDirectoryUser carries the fields the directory sync owns.
+type UpdateDirectoryUser struct {
+	FirstName string
+	LastName  string
+	RoleID    uint
+}
+
+// FetchUserFieldHistory filters a user's field changes. The value a field had on a date is the
+// new value of its latest change up to that date.
+type FetchUserFieldHistory struct {
+	UserID   uint   `form:"-"`
+	Page     uint   `form:"page"`
+	Field    string `form:"field"`
+	FromDate string `form:"fromDate"`
+	ToDate   string `form:"toDate"`
 }
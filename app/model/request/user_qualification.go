@@ -0,0 +1,47 @@
+package request
+
+type CreateEducation struct {
+	UserID uint `json:"userID" binding:"required"`
+	UpdateEducation
+}
+
+type UpdateEducation struct {
+	Degree       string `json:"degree" binding:"required"`
+	Institution  string `json:"institution" binding:"required"`
+	FieldOfStudy string `json:"fieldOfStudy"`
+	StartYear    uint   `json:"startYear" binding:"required,gte=1950"`
+	EndYear      *uint  `json:"endYear"`
+	Grade        string `json:"grade"`
+}
+
+type CreateCertification struct {
+	UserID uint `json:"userID" binding:"required"`
+	UpdateCertification
+}
+
+type UpdateCertification struct {
+	Name         string `json:"name" binding:"required"`
+	Issuer       string `json:"issuer" binding:"required"`
+	CredentialID string `json:"credentialID"`
+	IssuedDate   string `json:"issuedDate" binding:"required"`
+	ExpiryDate   string `json:"expiryDate"`
+}
+
+type CreateSkill struct {
+	UserID uint `json:"userID" binding:"required"`
+	UpdateSkill
+}
+
+type UpdateSkill struct {
+	Name        string `json:"name" binding:"required"`
+	Proficiency uint   `json:"proficiency" binding:"required,oneof=1 2 3 4"`
+}
+
+type SearchQualifications struct {
+	CommonRequest
+	Skills         string `form:"skills"`
+	Certifications string `form:"certifications"`
+	Degree         string `form:"degree"`
+	MinProficiency uint   `form:"minProficiency" binding:"omitempty,oneof=1 2 3 4"`
+	DepartmentID   uint   `form:"departmentID"`
+}
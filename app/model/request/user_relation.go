@@ -0,0 +1,46 @@
+package request
+
+type CreateEmergencyContact struct {
+	UserID       uint   `json:"userID" binding:"required"`
+	Name         string `json:"name" binding:"required"`
+	Relationship string `json:"relationship" binding:"required"`
+	Mobile       string `json:"mobile" binding:"required"`
+	Address      string `json:"address"`
+}
+
+type UpdateEmergencyContact struct {
+	Name         string `json:"name" binding:"required"`
+	Relationship string `json:"relationship" binding:"required"`
+	Mobile       string `json:"mobile" binding:"required"`
+	Address      string `json:"address"`
+}
+
+type CreateDependent struct {
+	UserID uint `json:"userID" binding:"required"`
+	UpdateDependent
+}
+
+type UpdateDependent struct {
+	Name         string `json:"name" binding:"required"`
+	Relationship string `json:"relationship" binding:"required"`
+	DOB          string `json:"dob" binding:"required"`
+	Gender       string `json:"gender" binding:"required"`
+	IsInsured    bool   `json:"isInsured"`
+}
+
+type UpdateNominees struct {
+	UserID   uint      `json:"userID" binding:"required"`
+	Nominees []Nominee `json:"nominees" binding:"required,dive"`
+}
+
+type Nominee struct {
+	Name            string  `json:"name" binding:"required"`
+	Relationship    string  `json:"relationship" binding:"required"`
+	SharePercentage float64 `json:"sharePercentage" binding:"required,gt=0,lte=100"`
+	Address         string  `json:"address"`
+}
+
+type ExportEmployees struct {
+	DepartmentID uint `form:"departmentID"`
+	RoleID       uint `form:"roleID"`
+}
@@ -0,0 +1,19 @@
+package request
+
+type CreateWebhook struct {
+	Name       string   `json:"name" binding:"required"`
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1"`
+}
+
+type UpdateWebhook struct {
+	CreateWebhook
+	IsEnabled bool `json:"isEnabled"`
+}
+
+type FetchWebhookDeliveries struct {
+	CommonRequest
+	WebhookID uint   `form:"-"`
+	Status    string `form:"status"`
+	EventType string `form:"eventType"`
+}
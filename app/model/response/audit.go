@@ -0,0 +1,36 @@
+package response
+
+import "time"
+
+type FetchAuditLogs struct {
+	ID               uint                   `json:"id"`
+	CreatedAt        time.Time              `json:"createdAt" gorm:"column:createdAt"`
+	ActorType        string                 `json:"actorType" gorm:"column:actorType"`
+	UserID           *uint                  `json:"userID" gorm:"column:userID"`
+	UserName         *string                `json:"userName" gorm:"column:userName"`
+	ServiceAccountID *uint                  `json:"serviceAccountID" gorm:"column:serviceAccountID"`
+	ImpersonatorID   *uint                  `json:"impersonatorID" gorm:"column:impersonatorID"`
+	IPAddress        *string                `json:"ipAddress" gorm:"column:ipAddress"`
+	Action           string                 `json:"action"`
+	Entity           string                 `json:"entity"`
+	EntityID         *uint                  `json:"entityID" gorm:"column:entityID"`
+	ChangeList       string                 `json:"-" gorm:"column:changes"`
+	Changes          map[string]AuditChange `json:"changes" gorm:"-"`
+	Count            uint                   `json:"-" gorm:"column:count"`
+}
+
+// AuditChange is a field's value before and after a change. PII is masked and passwords are
+// never included.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// VerifyAuditLog reports whether the hash chain is intact. LastHash can be kept outside EMS to
+// also detect rows removed from the end of the chain.
+type VerifyAuditLog struct {
+	Valid       bool   `json:"valid"`
+	CheckedRows int    `json:"checkedRows"`
+	BrokenAtID  *uint  `json:"brokenAtID"`
+	LastHash    string `json:"lastHash"`
+}
@@ -0,0 +1,105 @@
+package response
+
+import "time"
+
+type SessionTokens struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type UserSession struct {
+	ID                       uint
+	UserID                   uint       `gorm:"column:userID"`
+	RefreshTokenHash         string     `gorm:"column:refreshTokenHash"`
+	PreviousRefreshTokenHash *string    `gorm:"column:previousRefreshTokenHash"`
+	ExpiresAt                time.Time  `gorm:"column:expiresAt"`
+	RevokedAt                *time.Time `gorm:"column:revokedAt"`
+	AuthMethod               string     `gorm:"column:authMethod"`
+	ImpersonatorID           *uint      `gorm:"column:impersonatorID"`
+}
+
+type FetchUserSessions struct {
+	ID         uint      `json:"id"`
+	UserAgent  *string   `json:"userAgent" gorm:"column:userAgent"`
+	IPAddress  *string   `json:"ipAddress" gorm:"column:ipAddress"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"column:createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt" gorm:"column:lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt" gorm:"column:expiresAt"`
+	IsCurrent  bool      `json:"isCurrent" gorm:"-"`
+}
+
+// TwoFactorChallenge is returned instead of a session when the password was correct but a
+// second factor is still needed. SetupRequired means the role enforces two-factor and the
+// user has to enrol before signing in.
+type TwoFactorChallenge struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	SetupRequired  bool   `json:"setupRequired"`
+}
+
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningURI"`
+}
+
+type TwoFactorEnabled struct {
+	RecoveryCodes []string          `json:"recoveryCodes"`
+	User          *FetchUserByEmail `json:"user,omitempty"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RemainingRecoveryCodes int64 `json:"remainingRecoveryCodes"`
+}
+
+type LockoutStatus struct {
+	Locked         bool            `json:"locked"`
+	LockedUntil    *time.Time      `json:"lockedUntil"`
+	FailedAttempts int             `json:"failedAttempts"`
+	LockoutCount   int             `json:"lockoutCount"`
+	RecentEvents   []FetchAuthLogs `json:"recentEvents"`
+}
+
+type FetchAuthLogs struct {
+	ID        uint      `json:"id"`
+	Event     string    `json:"event"`
+	IPAddress string    `json:"ipAddress" gorm:"column:ipAddress"`
+	Detail    *string   `json:"detail"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorizationURL"`
+}
+
+// OIDCIdentity is the user described by a verified ID token.
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+type FetchImpersonations struct {
+	ID             uint       `json:"id"`
+	ImpersonatorID uint       `json:"impersonatorID" gorm:"column:impersonatorID"`
+	Impersonator   string     `json:"impersonator" gorm:"column:impersonator"`
+	UserID         uint       `json:"userID" gorm:"column:userID"`
+	User           string     `json:"user" gorm:"column:user"`
+	Reason         string     `json:"reason" gorm:"column:reason"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"column:createdAt"`
+	ExpiresAt      time.Time  `json:"expiresAt" gorm:"column:expiresAt"`
+	RevokedAt      *time.Time `json:"revokedAt" gorm:"column:revokedAt"`
+	RequestCount   int        `json:"requestCount" gorm:"column:requestCount"`
+}
+
+type FetchImpersonatedRequests struct {
+	ID         uint      `json:"id"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"statusCode" gorm:"column:statusCode"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
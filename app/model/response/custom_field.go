@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+type FetchCustomFields struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Key        string    `json:"key"`
+	FieldType  string    `json:"fieldType" gorm:"column:fieldType"`
+	Options    *string   `json:"options"`
+	IsRequired bool      `json:"isRequired" gorm:"column:isRequired"`
+	Pattern    *string   `json:"pattern"`
+	MinValue   *float64  `json:"minValue" gorm:"column:minValue"`
+	MaxValue   *float64  `json:"maxValue" gorm:"column:maxValue"`
+	Visibility uint      `json:"visibility"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type FetchUserCustomFieldValue struct {
+	CustomFieldID uint   `json:"customFieldID" gorm:"column:customFieldID"`
+	Key           string `json:"key"`
+	Name          string `json:"name"`
+	FieldType     string `json:"fieldType" gorm:"column:fieldType"`
+	Value         string `json:"value"`
+}
+
+type ImportEmployees struct {
+	UpdatedCount int      `json:"updatedCount"`
+	Errors       []string `json:"errors"`
+}
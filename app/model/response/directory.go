@@ -0,0 +1,40 @@
+package response
+
+// DirectoryUser is a user entry read from the directory. Groups holds the DNs of the groups
+// the user is a member of.
+type DirectoryUser struct {
+	DN        string
+	Email     string
+	FirstName string
+	LastName  string
+	Groups    []string
+}
+
+type DirectorySyncUser struct {
+	ID           uint
+	Email        string
+	FirstName    string `gorm:"column:firstName"`
+	LastName     string `gorm:"column:lastName"`
+	RoleID       uint   `gorm:"column:roleID"`
+	AuthSource   string `gorm:"column:authSource"`
+	DepartmentID *uint  `gorm:"column:departmentID"`
+}
+
+// DirectorySyncReport lists what a sync changed, or would change on a dry run.
+// NotInDirectory holds directory users of EMS that the directory no longer returns; they are
+// left for HR to review rather than removed.
+type DirectorySyncReport struct {
+	DryRun         bool                  `json:"dryRun"`
+	DirectoryUsers int                   `json:"directoryUsers"`
+	Created        []DirectorySyncChange `json:"created"`
+	Updated        []DirectorySyncChange `json:"updated"`
+	Skipped        []DirectorySyncChange `json:"skipped"`
+	Unchanged      int                   `json:"unchanged"`
+	NotInDirectory []string              `json:"notInDirectory"`
+}
+
+type DirectorySyncChange struct {
+	Email   string   `json:"email"`
+	Changes []string `json:"changes,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
@@ -0,0 +1,86 @@
+package response
+
+import "time"
+
+type FetchDocumentCategories struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Code         string    `json:"code"`
+	Description  *string   `json:"description"`
+	IsRequired   bool      `json:"isRequired" gorm:"column:isRequired"`
+	HasExpiry    bool      `json:"hasExpiry" gorm:"column:hasExpiry"`
+	AllowedTypes *string   `json:"allowedTypes" gorm:"column:allowedTypes"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type FetchUserDocument struct {
+	ID                 uint      `json:"id"`
+	UserID             uint      `json:"userID" gorm:"column:userID"`
+	DocumentCategoryID *uint     `json:"documentCategoryID" gorm:"column:documentCategoryID"`
+	Category           *string   `json:"category" gorm:"column:category"`
+	FileName           *string   `json:"fileName" gorm:"column:fileName"`
+	FilePath           string    `json:"filePath" gorm:"column:filePath"`
+	Checksum           *string   `json:"checksum"`
+	Version            uint      `json:"version"`
+	ExpiryDate         *string   `json:"expiryDate" gorm:"column:expiryDate"`
+	IsExpired          bool      `json:"isExpired" gorm:"column:isExpired"`
+	UploadedBy         *string   `json:"uploadedBy" gorm:"column:uploadedBy"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+type FetchMissingDocuments struct {
+	UserID            uint   `json:"userID" gorm:"column:userID"`
+	UserName          string `json:"userName" gorm:"column:userName"`
+	Code              string `json:"code"`
+	Department        string `json:"department"`
+	MissingCategories string `json:"missingCategories" gorm:"column:missingCategories"`
+	Count             uint   `json:"-"`
+}
+
+type ExpiringDocument struct {
+	ID         uint      `json:"id"`
+	Email      string    `json:"email"`
+	UserName   string    `json:"userName" gorm:"column:userName"`
+	Category   string    `json:"category" gorm:"column:category"`
+	ExpiryDate time.Time `json:"expiryDate" gorm:"column:expiryDate"`
+}
+
+type UploadedDocument struct {
+	FileName    string
+	FilePath    string
+	Checksum    string
+	ContentType string
+	FileSize    int64
+	// LetterTemplateVersionID is set when the document was generated from a letter template.
+	LetterTemplateVersionID *uint
+}
+
+type FetchQuarantinedDocument struct {
+	ID          uint      `json:"id"`
+	UserID      uint      `json:"userID" gorm:"column:userID"`
+	UserName    string    `json:"userName" gorm:"column:userName"`
+	FileName    string    `json:"fileName" gorm:"column:fileName"`
+	Checksum    string    `json:"checksum"`
+	ContentType string    `json:"contentType" gorm:"column:contentType"`
+	FileSize    int64     `json:"fileSize" gorm:"column:fileSize"`
+	Signature   string    `json:"signature"`
+	UploadedBy  string    `json:"uploadedBy" gorm:"column:uploadedBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type QuarantinedDocument struct {
+	ID       uint   `json:"id"`
+	FilePath string `json:"filePath" gorm:"column:filePath"`
+}
+
+type DownloadDocument struct {
+	ID       uint    `json:"id"`
+	UserID   uint    `json:"userID" gorm:"column:userID"`
+	FilePath string  `json:"filePath" gorm:"column:filePath"`
+	FileName *string `json:"fileName" gorm:"column:fileName"`
+}
+
+type StoredDocument struct {
+	ID       uint   `json:"id"`
+	FilePath string `json:"filePath" gorm:"column:filePath"`
+}
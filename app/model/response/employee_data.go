@@ -0,0 +1,9 @@
+package response
+
+type ExportEmployee struct {
+	User      FetchUsers         `json:"user"`
+	Details   *FetchUserDetails  `json:"details"`
+	Relations FetchUserRelations `json:"relations"`
+
+	CustomFields []FetchUserCustomFieldValue `json:"customFields"`
+}
@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+// OutboxEvent is a domain event handed to subscribers. Payload is the JSON of the event's
+// payload in the event model, chosen by EventType.
+type OutboxEvent struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	EventType string    `json:"eventType"`
+	Payload   string    `json:"payload"`
+	Attempts  int       `json:"attempts"`
+}
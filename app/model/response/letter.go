@@ -0,0 +1,64 @@
+package response
+
+import "time"
+
+type FetchLetterTemplates struct {
+	ID                 uint      `json:"id"`
+	Name               string    `json:"name"`
+	DocumentCategoryID uint      `json:"documentCategoryID" gorm:"column:documentCategoryID"`
+	Category           string    `json:"category" gorm:"column:category"`
+	Version            uint      `json:"version"`
+	Title              string    `json:"title"`
+	Body               string    `json:"body"`
+	UpdatedBy          string    `json:"updatedBy" gorm:"column:updatedBy"`
+	UpdatedAt          time.Time `json:"updatedAt" gorm:"column:updatedAt"`
+}
+
+type FetchLetterTemplateVersions struct {
+	ID        uint      `json:"id"`
+	Version   uint      `json:"version"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedBy string    `json:"createdBy" gorm:"column:createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type LetterTemplate struct {
+	ID                 uint   `json:"id"`
+	Name               string `json:"name"`
+	DocumentCategoryID uint   `json:"documentCategoryID" gorm:"column:documentCategoryID"`
+	VersionID          uint   `json:"versionID" gorm:"column:versionID"`
+	Title              string `json:"title"`
+	Body               string `json:"body"`
+}
+
+// LetterData holds the employee fields letter placeholders can refer to. Encrypted personal
+// details are deliberately left out.
+type LetterData struct {
+	UserID          uint    `json:"userID" gorm:"column:userID"`
+	FirstName       string  `json:"firstName" gorm:"column:firstName"`
+	LastName        string  `json:"lastName" gorm:"column:lastName"`
+	Code            string  `json:"code"`
+	Email           string  `json:"email"`
+	Mobile          string  `json:"mobile"`
+	Designation     *string `json:"designation"`
+	DateOfJoining   *string `json:"dateOfJoining" gorm:"column:dateOfJoining"`
+	Experience      *uint   `json:"experience"`
+	Address         *string `json:"address"`
+	City            *string `json:"city"`
+	Department      *string `json:"department"`
+	Manager         *string `json:"manager"`
+	LastWorkingDate *string `json:"lastWorkingDate" gorm:"column:lastWorkingDate"`
+}
+
+type RenderedLetter struct {
+	UserID             uint
+	DocumentCategoryID uint
+	Content            []byte
+	Document           UploadedDocument
+}
+
+type GenerateLetters struct {
+	GeneratedCount int      `json:"generatedCount"`
+	Errors         []string `json:"errors"`
+}
@@ -0,0 +1,24 @@
+package response
+
+import "time"
+
+type FetchProfileChangeRequests struct {
+	ID             uint                 `json:"id"`
+	UserID         uint                 `json:"userID" gorm:"column:userID"`
+	UserName       string               `json:"userName" gorm:"column:userName"`
+	Changes        string               `json:"-"`
+	Diff           []ProfileFieldChange `json:"diff" gorm:"-"`
+	UserDocumentID *uint                `json:"userDocumentID" gorm:"column:userDocumentID"`
+	IsApproved     *bool                `json:"isApproved" gorm:"column:isApproved"`
+	ApprovedAt     *time.Time           `json:"approvedAt" gorm:"column:approvedAt"`
+	ApprovedBy     *string              `json:"approvedBy" gorm:"column:approvedBy"`
+	Remarks        *string              `json:"remarks"`
+	CreatedAt      time.Time            `json:"createdAt"`
+	Count          uint                 `json:"-" gorm:"column:count"`
+}
+
+type ProfileFieldChange struct {
+	Field         string  `json:"field"`
+	CurrentValue  *string `json:"currentValue"`
+	ProposedValue string  `json:"proposedValue"`
+}
@@ -1,6 +1,16 @@
 package response
 
 type FetchRoles struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
+	ID               uint     `json:"id"`
+	Name             string   `json:"name"`
+	Description      *string  `json:"description"`
+	IsSystem         bool     `json:"isSystem" gorm:"column:isSystem"`
+	RequireTwoFactor bool     `json:"requireTwoFactor" gorm:"column:requireTwoFactor"`
+	Permissions      []string `json:"permissions" gorm:"-"`
+}
+
+type FetchPermissions struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
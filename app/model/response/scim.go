@@ -0,0 +1,144 @@
+package response
+
+import "time"
+
+type SCIMUser struct {
+	Schemas      []string           `json:"schemas"`
+	ID           string             `json:"id"`
+	ExternalID   *string            `json:"externalId,omitempty"`
+	UserName     string             `json:"userName"`
+	Name         SCIMName           `json:"name"`
+	DisplayName  string             `json:"displayName"`
+	Emails       []SCIMMultiValue   `json:"emails"`
+	PhoneNumbers []SCIMMultiValue   `json:"phoneNumbers,omitempty"`
+	Title        *string            `json:"title,omitempty"`
+	Active       bool               `json:"active"`
+	Groups       []SCIMMember       `json:"groups"`
+	Enterprise   SCIMEnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"`
+	Meta         SCIMMeta           `json:"meta"`
+}
+
+type SCIMName struct {
+	Formatted  string `json:"formatted"`
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Primary bool   `json:"primary"`
+}
+
+type SCIMEnterpriseUser struct {
+	EmployeeNumber string  `json:"employeeNumber"`
+	Department     *string `json:"department,omitempty"`
+}
+
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id"`
+	ExternalID  *string      `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members,omitempty"`
+	Meta        SCIMMeta     `json:"meta"`
+}
+
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display"`
+}
+
+// SCIMMeta describes a resource. Location is filled in by the handler, which knows the URL
+// the endpoints are served from.
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type SCIMUserRecord struct {
+	ID             uint
+	ExternalID     *string
+	FirstName      string
+	LastName       string
+	Email          string
+	Mobile         string
+	Code           string
+	RoleID         uint
+	IsActive       bool
+	Designation    *string
+	DepartmentID   *uint
+	DepartmentName *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	TotalCount     int
+}
+
+type SCIMGroupRecord struct {
+	ID         uint
+	ExternalID *string
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	TotalCount int
+}
+
+type SCIMGroupMember struct {
+	DepartmentID uint
+	UserID       uint
+	FirstName    string
+	LastName     string
+	RoleID       uint
+}
+
+// SCIMMembershipUser is a user a group write wants to add or remove, with their current
+// department if they have one.
+type SCIMMembershipUser struct {
+	ID           uint
+	RoleID       uint
+	DepartmentID *uint
+}
+
+// SCIMServiceProviderConfig tells identity providers which SCIM features are supported
+// (RFC 7643 section 5).
+type SCIMServiceProviderConfig struct {
+	Schemas               []string                   `json:"schemas"`
+	Patch                 SCIMSupported              `json:"patch"`
+	Bulk                  SCIMBulk                   `json:"bulk"`
+	Filter                SCIMFilter                 `json:"filter"`
+	ChangePassword        SCIMSupported              `json:"changePassword"`
+	Sort                  SCIMSupported              `json:"sort"`
+	Etag                  SCIMSupported              `json:"etag"`
+	AuthenticationSchemes []SCIMAuthenticationScheme `json:"authenticationSchemes"`
+}
+
+type SCIMSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type SCIMBulk struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type SCIMFilter struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type SCIMAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,43 @@
+package response
+
+import "time"
+
+type FetchServiceAccounts struct {
+	ID          uint           `json:"id"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description"`
+	CreatedAt   time.Time      `json:"createdAt" gorm:"column:createdAt"`
+	APIKeys     []FetchAPIKeys `json:"apiKeys" gorm:"-"`
+}
+
+type FetchAPIKeys struct {
+	ID               uint       `json:"id"`
+	ServiceAccountID uint       `json:"-" gorm:"column:serviceAccountID"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	ScopeList        string     `json:"-" gorm:"column:scopes"`
+	Scopes           []string   `json:"scopes" gorm:"-"`
+	CreatedAt        time.Time  `json:"createdAt" gorm:"column:createdAt"`
+	ExpiresAt        time.Time  `json:"expiresAt" gorm:"column:expiresAt"`
+	LastUsedAt       *time.Time `json:"lastUsedAt" gorm:"column:lastUsedAt"`
+	RevokedAt        *time.Time `json:"revokedAt" gorm:"column:revokedAt"`
+}
+
+// CreatedAPIKey is the only time the key itself is returned; it cannot be shown again.
+type CreatedAPIKey struct {
+	ID        uint      `json:"id"`
+	Key       string    `json:"key"`
+	Prefix    string    `json:"prefix"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// APIKey is what the middleware needs to authenticate a request made with an API key.
+type APIKey struct {
+	ID               uint
+	ServiceAccountID uint       `gorm:"column:serviceAccountID"`
+	KeyHash          string     `gorm:"column:keyHash"`
+	Scopes           string     `gorm:"column:scopes"`
+	ExpiresAt        time.Time  `gorm:"column:expiresAt"`
+	LastUsedAt       *time.Time `gorm:"column:lastUsedAt"`
+	RevokedAt        *time.Time `gorm:"column:revokedAt"`
+}
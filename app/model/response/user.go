@@ -3,33 +3,46 @@ package response
 import "time"
 
 type FetchUserByEmail struct {
-	ID                 uint      `json:"id"`
-	FirstName          string    `json:"firstName"`
-	LastName           string    `json:"lastName"`
-	Code               string    `json:"code"`
-	Email              string    `json:"email"`
-	Mobile             string    `json:"mobile"`
-	Token              string    `json:"token"`
-	Password           string    `json:"-"`
-	ManagerID          *uint     `json:"managerID,omitempty" gorm:"column:managerID"`
-	Manager            *string   `json:"manager,omitempty" gorm:"column:manager"`
-	RoleID             uint      `json:"roleID" gorm:"column:roleID"`
-	Role               string    `json:"role" gorm:"column:roleName"`
-	DepartmentID       *uint     `json:"departmentID,omitempty" gorm:"column:departmentID"`
-	Department         *string   `json:"department,omitempty" gorm:"column:department"`
-	DepartmentMemberID *uint     `json:"departmentMemberID,omitempty" gorm:"column:departmentMemberID"`
-	LeadID             *uint     `json:"leadID,omitempty" gorm:"column:leadID"`
-	Lead               *string   `json:"lead,omitempty" gorm:"column:lead"`
-	CreatedAt          time.Time `json:"createdAt"`
-	IsActive           bool      `json:"isActive"`
+	ID               uint     `json:"id"`
+	FirstName        string   `json:"firstName"`
+	LastName         string   `json:"lastName"`
+	Code             string   `json:"code"`
+	Email            string   `json:"email"`
+	Mobile           string   `json:"mobile"`
+	Token            string   `json:"token"`
+	RefreshToken     string   `json:"refreshToken"`
+	Permissions      []string `json:"permissions" gorm:"-"`
+	RequireTwoFactor bool     `json:"-" gorm:"column:requireTwoFactor"`
+	TwoFactorEnabled bool     `json:"twoFactorEnabled" gorm:"column:twoFactorEnabled"`
+	Password         string   `json:"-"`
+	// PasswordChangeRequired tells the client to ask for a new password; until then every
+	// other request is refused.
+	PasswordChangeRequired bool       `json:"passwordChangeRequired" gorm:"-"`
+	MustChangePassword     bool       `json:"-" gorm:"column:mustChangePassword"`
+	PasswordChangedAt      *time.Time `json:"-" gorm:"column:passwordChangedAt"`
+	AuthSource             string     `json:"authSource" gorm:"column:authSource"`
+	ManagerID              *uint      `json:"managerID,omitempty" gorm:"column:managerID"`
+	Manager                *string    `json:"manager,omitempty" gorm:"column:manager"`
+	RoleID                 uint       `json:"roleID" gorm:"column:roleID"`
+	Role                   string     `json:"role" gorm:"column:roleName"`
+	DepartmentID           *uint      `json:"departmentID,omitempty" gorm:"column:departmentID"`
+	Department             *string    `json:"department,omitempty" gorm:"column:department"`
+	DepartmentMemberID     *uint      `json:"departmentMemberID,omitempty" gorm:"column:departmentMemberID"`
+	LeadID                 *uint      `json:"leadID,omitempty" gorm:"column:leadID"`
+	Lead                   *string    `json:"lead,omitempty" gorm:"column:lead"`
+	CreatedAt              time.Time  `json:"createdAt"`
+	IsActive               bool       `json:"isActive"`
+	// ImpersonationExpiresAt is set when an admin started the session as this user.
+	ImpersonationExpiresAt *time.Time `json:"impersonationExpiresAt,omitempty" gorm:"-"`
 }
 
 type FetchUserByID struct {
-	ID                 uint    `json:"userID" gorm:"column:userID"`
-	Token              *string `json:"token" gorm:"column:token"`
-	RoleID             uint    `json:"roleID" gorm:"column:roleID"`
-	DepartmentID       *uint   `json:"departmentID" gorm:"column:departmentID"`
-	DepartmentMemberID *uint   `json:"departmentMemberID" gorm:"column:departmentMemberID"`
+	ID                 uint       `json:"userID" gorm:"column:userID"`
+	RoleID             uint       `json:"roleID" gorm:"column:roleID"`
+	DepartmentID       *uint      `json:"departmentID" gorm:"column:departmentID"`
+	DepartmentMemberID *uint      `json:"departmentMemberID" gorm:"column:departmentMemberID"`
+	MustChangePassword bool       `json:"-" gorm:"column:mustChangePassword"`
+	PasswordChangedAt  *time.Time `json:"-" gorm:"column:passwordChangedAt"`
 }
 
 type FetchUsers struct {
@@ -64,6 +77,11 @@ type FetchUnmappedUsers struct {
 
 type FetchUserDetails struct {
 	UserID            uint    `json:"userID" gorm:"column:userID"`
+	FirstName         string  `json:"firstName" gorm:"column:firstName"`
+	LastName          string  `json:"lastName" gorm:"column:lastName"`
+	Code              string  `json:"code"`
+	Email             string  `json:"email"`
+	Mobile            string  `json:"mobile"`
 	DateOfJoining     *string `json:"dateOfJoining"`
 	Experience        *string `json:"experience"`
 	Designation       *string `json:"designation"`
@@ -76,11 +94,8 @@ type FetchUserDetails struct {
 	Address           *string `json:"address"`
 	Degree            *string `json:"degree"`
 	College           *string `json:"college"`
-}
 
-type FetchUploadedDocumentPaths struct {
-	UserID    uint   `json:"userID"`
-	FilePaths string `json:"filePaths" gorm:"column:filePaths"`
+	CustomFields []FetchUserCustomFieldValue `json:"customFields" gorm:"-"`
 }
 
 type FetchDepartmentUserCountAndRoleID struct {
@@ -88,3 +103,21 @@ type FetchDepartmentUserCountAndRoleID struct {
 	RoleID       int `gorm:"column:roleID"`
 	DepartmentID int `gorm:"column:departmentID"`
 }
+
+type RotatePIIEncryptionKey struct {
+	ActiveKeyID     string `json:"activeKeyID"`
+	ReEncryptedRows int    `json:"reEncryptedRows"`
+}
+
+type FetchUserFieldHistory struct {
+	ID               uint      `json:"id"`
+	ChangedAt        time.Time `json:"changedAt" gorm:"column:changedAt"`
+	Field            string    `json:"field"`
+	OldValue         *string   `json:"oldValue" gorm:"column:oldValue"`
+	NewValue         *string   `json:"newValue" gorm:"column:newValue"`
+	ActorType        string    `json:"actorType" gorm:"column:actorType"`
+	ChangedBy        *uint     `json:"changedBy" gorm:"column:changedBy"`
+	ChangedByName    *string   `json:"changedByName" gorm:"column:changedByName"`
+	ServiceAccountID *uint     `json:"serviceAccountID" gorm:"column:serviceAccountID"`
+	Count            uint      `json:"-" gorm:"column:count"`
+}
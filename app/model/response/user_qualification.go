@@ -0,0 +1,57 @@
+package response
+
+import "time"
+
+type FetchUserQualifications struct {
+	Educations     []FetchEducation     `json:"educations"`
+	Certifications []FetchCertification `json:"certifications"`
+	Skills         []FetchSkill         `json:"skills"`
+}
+
+type FetchEducation struct {
+	ID           uint    `json:"id"`
+	Degree       string  `json:"degree"`
+	Institution  string  `json:"institution"`
+	FieldOfStudy *string `json:"fieldOfStudy" gorm:"column:fieldOfStudy"`
+	StartYear    uint    `json:"startYear" gorm:"column:startYear"`
+	EndYear      *uint   `json:"endYear" gorm:"column:endYear"`
+	Grade        *string `json:"grade"`
+}
+
+type FetchCertification struct {
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Issuer       string  `json:"issuer"`
+	CredentialID *string `json:"credentialID" gorm:"column:credentialID"`
+	IssuedDate   string  `json:"issuedDate" gorm:"column:issuedDate"`
+	ExpiryDate   *string `json:"expiryDate" gorm:"column:expiryDate"`
+	IsExpired    bool    `json:"isExpired" gorm:"column:isExpired"`
+}
+
+type FetchSkill struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Proficiency uint   `json:"proficiency"`
+}
+
+type SearchQualifications struct {
+	ID             uint    `json:"id"`
+	FirstName      string  `json:"firstName"`
+	LastName       string  `json:"lastName"`
+	Code           string  `json:"code"`
+	Email          string  `json:"email"`
+	Department     string  `json:"department"`
+	Designation    *string `json:"designation"`
+	Skills         *string `json:"skills"`
+	Certifications *string `json:"certifications"`
+	Count          uint    `json:"-"`
+}
+
+type ExpiringCertification struct {
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"userID" gorm:"column:userID"`
+	Email      string    `json:"email"`
+	UserName   string    `json:"userName" gorm:"column:userName"`
+	Name       string    `json:"name"`
+	ExpiryDate time.Time `json:"expiryDate" gorm:"column:expiryDate"`
+}
@@ -0,0 +1,32 @@
+package response
+
+type FetchUserRelations struct {
+	EmergencyContacts []FetchEmergencyContact `json:"emergencyContacts"`
+	Dependents        []FetchDependent        `json:"dependents"`
+	Nominees          []FetchNominee          `json:"nominees"`
+}
+
+type FetchEmergencyContact struct {
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Relationship string  `json:"relationship"`
+	Mobile       string  `json:"mobile"`
+	Address      *string `json:"address"`
+}
+
+type FetchDependent struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	DOB          string `json:"dob" gorm:"column:dob"`
+	Gender       string `json:"gender"`
+	IsInsured    bool   `json:"isInsured" gorm:"column:isInsured"`
+}
+
+type FetchNominee struct {
+	ID              uint    `json:"id"`
+	Name            string  `json:"name"`
+	Relationship    string  `json:"relationship"`
+	SharePercentage float64 `json:"sharePercentage" gorm:"column:sharePercentage"`
+	Address         *string `json:"address"`
+}
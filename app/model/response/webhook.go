@@ -0,0 +1,63 @@
+package response
+
+import "time"
+
+type FetchWebhooks struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	URL           string    `json:"url" gorm:"column:url"`
+	EventTypeList string    `json:"-" gorm:"column:eventTypes"`
+	EventTypes    []string  `json:"eventTypes" gorm:"-"`
+	IsEnabled     bool      `json:"isEnabled" gorm:"column:isEnabled"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+// CreatedWebhookSecret is the only time a webhook's signing secret is returned.
+type CreatedWebhookSecret struct {
+	ID     uint   `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type FetchWebhookDeliveries struct {
+	ID             uint       `json:"id"`
+	WebhookID      uint       `json:"webhookID" gorm:"column:webhookID"`
+	WebhookName    string     `json:"webhookName" gorm:"column:webhookName"`
+	OutboxEventID  uint       `json:"eventID" gorm:"column:outboxEventID"`
+	EventType      string     `json:"eventType" gorm:"column:eventType"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" gorm:"column:nextAttemptAt"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt" gorm:"column:lastAttemptAt"`
+	ResponseStatus *int       `json:"responseStatus" gorm:"column:responseStatus"`
+	LastError      *string    `json:"lastError" gorm:"column:lastError"`
+	DeliveredAt    *time.Time `json:"deliveredAt" gorm:"column:deliveredAt"`
+	CreatedAt      time.Time  `json:"createdAt" gorm:"column:createdAt"`
+	Count          uint       `json:"-" gorm:"column:count"`
+}
+
+type FetchWebhookDeliveryAttempts struct {
+	ID             uint      `json:"id"`
+	ResponseStatus *int      `json:"responseStatus" gorm:"column:responseStatus"`
+	Error          *string   `json:"error"`
+	DurationMs     int64     `json:"durationMs" gorm:"column:durationMs"`
+	CreatedAt      time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+type FetchWebhookDelivery struct {
+	FetchWebhookDeliveries
+	Payload    string                         `json:"payload"`
+	AttemptLog []FetchWebhookDeliveryAttempts `json:"attemptLog" gorm:"-"`
+}
+
+// WebhookDelivery is what the sender needs to post a delivery.
+type WebhookDelivery struct {
+	ID            uint
+	WebhookID     uint      `gorm:"column:webhookID"`
+	URL           string    `gorm:"column:url"`
+	Secret        string    `gorm:"column:secret"`
+	OutboxEventID uint      `gorm:"column:outboxEventID"`
+	EventType     string    `gorm:"column:eventType"`
+	Payload       string    `gorm:"column:payload"`
+	Attempts      int       `gorm:"column:attempts"`
+	EventAt       time.Time `gorm:"column:eventAt"`
+}
@@ -16,29 +16,64 @@ type BaseGorm struct {
 
 type Role struct {
 	BaseGorm
-	Name  string `gorm:"not null"`
-	Users []User
+	Name             string `gorm:"not null"`
+	Description      *string
+	IsSystem         bool `gorm:"default:false"`
+	RequireTwoFactor bool `gorm:"default:false"`
+	Users            []User
+	RolePermissions  []RolePermission
+}
+
+type Permission struct {
+	BaseGorm
+	Name            string `gorm:"not null;uniqueIndex"`
+	Description     string `gorm:"not null"`
+	RolePermissions []RolePermission
+}
+
+type RolePermission struct {
+	BaseGorm
+	RoleID       uint `gorm:"not null"`
+	Role         Role
+	PermissionID uint `gorm:"not null"`
+	Permission   Permission
 }
 type User struct {
 	BaseGorm
-	FirstName           string `gorm:"not null"`
-	LastName            string `gorm:"not null"`
-	Email               string `gorm:"not null"`
-	Mobile              string `gorm:"not null"`
-	Code                string `gorm:"not null"`
-	Password            string `gorm:"not null"`
-	Token               *string
-	RoleID              uint `gorm:"not null"`
-	Role                Role
-	ManagerID           *uint `gorm:"foreignKey:ManagerID"`
-	Manager             *User `gorm:"foreignKey:ManagerID"`
-	UserDetails         []UserDetails
-	UserDocuments       []UserDocument
-	ForgotPasswordOtps  []ForgotPasswordOtp
-	DepartmentMembers   []DepartmentMember
-	ApprovedLeaves      []DepartmentMemberLeaveRequest      `gorm:"foreignKey:ApprovedBy"`
-	ApprovedPermissions []DepartmentMemberPermissionRequest `gorm:"foreignKey:ApprovedBy"`
-	ApprovedNotices     []UserNotice                        `gorm:"foreignKey:ApprovedBy"`
+	FirstName             string `gorm:"not null"`
+	LastName              string `gorm:"not null"`
+	Email                 string `gorm:"not null"`
+	Mobile                string `gorm:"not null"`
+	Code                  string `gorm:"not null"`
+	Password              string `gorm:"not null"`
+	PasswordChangedAt     *time.Time
+	MustChangePassword    bool    `gorm:"default:false"`
+	AuthSource            string  `gorm:"not null;default:local"`
+	ExternalID            *string `gorm:"index"`
+	RoleID                uint    `gorm:"not null"`
+	Role                  Role
+	ManagerID             *uint `gorm:"foreignKey:ManagerID"`
+	Manager               *User `gorm:"foreignKey:ManagerID"`
+	UserDetails           []UserDetails
+	UserDocuments         []UserDocument
+	ForgotPasswordOtps    []ForgotPasswordOtp
+	PasswordHistories     []PasswordHistory
+	Sessions              []UserSession
+	TwoFactor             *UserTwoFactor
+	RecoveryCodes         []UserRecoveryCode
+	Identities            []UserIdentity
+	DepartmentMembers     []DepartmentMember
+	ApprovedLeaves        []DepartmentMemberLeaveRequest      `gorm:"foreignKey:ApprovedBy"`
+	ApprovedPermissions   []DepartmentMemberPermissionRequest `gorm:"foreignKey:ApprovedBy"`
+	ApprovedNotices       []UserNotice                        `gorm:"foreignKey:ApprovedBy"`
+	ProfileChangeRequests []ProfileChangeRequest
+	EmergencyContacts     []UserEmergencyContact
+	Dependents            []UserDependent
+	Nominees              []UserNominee
+	CustomFieldValues     []UserCustomFieldValue
+	Educations            []UserEducation
+	Certifications        []UserCertification
+	Skills                []UserSkill
 }
 
 type UserDetails struct {
@@ -48,9 +83,11 @@ type UserDetails struct {
 	DateOfJoining     time.Time `gorm:"not null;type:date"`
 	Designation       string    `gorm:"not null"`
 	Experience        uint      `gorm:"not null"`
-	DOB               time.Time `gorm:"not null;type:date"`
+	DOB               string    `gorm:"not null"`
 	AadharNumber      string    `gorm:"not null"`
+	AadharNumberIndex string    `gorm:"index"`
 	PanNumber         string    `gorm:"not null"`
+	PanNumberIndex    string    `gorm:"index"`
 	BankAccountNumber string    `gorm:"not null"`
 	IfscCode          string    `gorm:"not null"`
 	Address           string    `gorm:"not null"`
@@ -61,23 +98,317 @@ type UserDetails struct {
 
 type UserDocument struct {
 	BaseGorm
-	UserID   uint `gorm:"not null"`
-	User     User
-	FilePath string `gorm:"not null"`
+	UserID                  uint `gorm:"not null"`
+	User                    User
+	FilePath                string `gorm:"not null"`
+	DocumentCategoryID      *uint
+	DocumentCategory        *DocumentCategory
+	FileName                *string
+	Checksum                *string
+	Version                 uint       `gorm:"default:1"`
+	IsLatest                bool       `gorm:"default:true"`
+	ExpiryDate              *time.Time `gorm:"type:date"`
+	ReminderSentAt          *time.Time
+	UploadedBy              *uint
+	UploadedUser            *User  `gorm:"foreignKey:UploadedBy"`
+	StorageBackend          string `gorm:"default:local"`
+	ContentType             *string
+	FileSize                int64 `gorm:"default:0"`
+	LetterTemplateVersionID *uint
+	LetterTemplateVersion   *LetterTemplateVersion
+}
+
+type LetterTemplate struct {
+	BaseGorm
+	Name               string `gorm:"not null"`
+	DocumentCategoryID uint   `gorm:"not null"`
+	DocumentCategory   DocumentCategory
+	Version            uint `gorm:"default:1"`
+	Versions           []LetterTemplateVersion
+}
+
+// LetterTemplateVersion keeps every edit of a template, so generated letters can be traced
+// back to the exact text they were rendered from.
+type LetterTemplateVersion struct {
+	BaseGorm
+	LetterTemplateID uint `gorm:"not null"`
+	LetterTemplate   LetterTemplate
+	Version          uint   `gorm:"not null"`
+	Title            string `gorm:"not null"`
+	Body             string `gorm:"not null"`
+	CreatedBy        uint   `gorm:"not null"`
+	CreatedUser      User   `gorm:"foreignKey:CreatedBy"`
+}
+
+// QuarantinedDocument is an upload the malware scanner flagged. It is kept apart from
+// UserDocument so it never shows up in document listings.
+type QuarantinedDocument struct {
+	BaseGorm
+	UserID         uint `gorm:"not null"`
+	User           User
+	FileName       string `gorm:"not null"`
+	FilePath       string `gorm:"not null"`
+	Checksum       string `gorm:"not null"`
+	ContentType    string `gorm:"not null"`
+	FileSize       int64  `gorm:"not null"`
+	Signature      string `gorm:"not null"`
+	UploadedBy     uint   `gorm:"not null"`
+	UploadedUser   User   `gorm:"foreignKey:UploadedBy"`
+	StorageBackend string `gorm:"default:local"`
+}
+
+type DocumentCategory struct {
+	BaseGorm
+	Name          string `gorm:"not null"`
+	Code          string `gorm:"not null"`
+	Description   *string
+	IsRequired    bool `gorm:"default:false"`
+	HasExpiry     bool `gorm:"default:false"`
+	AllowedTypes  *string
+	UserDocuments []UserDocument
 }
 
 type ForgotPasswordOtp struct {
 	BaseGorm
-	UserID uint   `json:"userID" gorm:"not null"`
-	User   User   `json:"user"`
-	Email  string `json:"email" gorm:"not null"`
-	Otp    string `json:"otp" gorm:"not null"`
-	IsUsed bool   `json:"isUsed" gorm:"default:false"`
+	UserID         uint   `json:"userID" gorm:"not null"`
+	User           User   `json:"user"`
+	Email          string `json:"email" gorm:"not null"`
+	Otp            string `json:"otp" gorm:"not null"`
+	IsUsed         bool   `json:"isUsed" gorm:"default:false"`
+	FailedAttempts int    `json:"failedAttempts" gorm:"default:0"`
+}
+
+// PasswordHistory keeps the hashes of a user's recent passwords, including the current one,
+// so that they cannot be reused.
+type PasswordHistory struct {
+	BaseGorm
+	UserID       uint   `gorm:"not null;index"`
+	PasswordHash string `gorm:"not null"`
+}
+
+// UserSession is one signed in device. Only the SHA-256 hash of its refresh token is stored;
+// the previous hash is kept so that replaying a rotated token can be detected.
+type UserSession struct {
+	BaseGorm
+	UserID                   uint `gorm:"not null"`
+	User                     User
+	RefreshTokenHash         string  `gorm:"not null;index"`
+	PreviousRefreshTokenHash *string `gorm:"index"`
+	UserAgent                *string
+	IPAddress                *string
+	LastUsedAt               time.Time `gorm:"not null"`
+	ExpiresAt                time.Time `gorm:"not null"`
+	RevokedAt                *time.Time
+	AuthMethod               string `gorm:"not null;default:password"`
+	ImpersonatorID           *uint
+	ImpersonationReason      *string
+}
+
+// ImpersonatedRequest records a request made during an impersonation session, with the admin
+// who actually made it.
+type ImpersonatedRequest struct {
+	BaseGorm
+	SessionID      uint   `gorm:"not null;index"`
+	ImpersonatorID uint   `gorm:"not null"`
+	UserID         uint   `gorm:"not null"`
+	Method         string `gorm:"not null"`
+	Path           string `gorm:"not null"`
+	StatusCode     int    `gorm:"not null"`
+}
+
+// UserTwoFactor holds a user's TOTP secret, encrypted like other PII. The row exists from
+// setup onwards but two-factor is only enforced once EnabledAt is set by a confirmed code.
+type UserTwoFactor struct {
+	BaseGorm
+	UserID       uint   `gorm:"not null"`
+	Secret       string `gorm:"not null"`
+	EnabledAt    *time.Time
+	LastUsedStep int64
+}
+
+type UserRecoveryCode struct {
+	BaseGorm
+	UserID   uint   `gorm:"not null"`
+	CodeHash string `gorm:"not null;index"`
+	UsedAt   *time.Time
+}
+
+// LoginThrottle counts recent failed attempts for one account or client IP, identified by
+// Subject. LockoutCount is how many times in a row the subject has been locked and sets the
+// length of the next lockout.
+type LoginThrottle struct {
+	BaseGorm
+	Subject        string `gorm:"not null;uniqueIndex"`
+	FailedAttempts int
+	LockoutCount   int
+	LastFailedAt   *time.Time
+	LockedUntil    *time.Time
+}
+
+// AuthLog records every sign in, forgot password and lockout event. UserID is empty when the
+// email did not match an account.
+type AuthLog struct {
+	BaseGorm
+	UserID    *uint `gorm:"index"`
+	Email     *string
+	IPAddress string `gorm:"not null"`
+	Event     string `gorm:"not null;index"`
+	Detail    *string
+}
+
+// AuditLog records a change made to an entity, with the changed fields before and after. Each
+// row carries the hash of the previous row, so editing or removing a row breaks the chain
+// from that point on. PrevHash is unique, so two rows cannot continue from the same one.
+type AuditLog struct {
+	BaseGorm
+	ActorType        string `gorm:"not null"`
+	UserID           *uint  `gorm:"index"`
+	ServiceAccountID *uint
+	ImpersonatorID   *uint
+	IPAddress        *string
+	Action           string `gorm:"not null"`
+	Entity           string `gorm:"not null;index:idx_audit_log_entity"`
+	EntityID         *uint  `gorm:"index:idx_audit_log_entity"`
+	Changes          string `gorm:"not null"`
+	PrevHash         string `gorm:"not null;uniqueIndex"`
+	Hash             string `gorm:"not null"`
+}
+
+// UserFieldHistory is a user or user details field's value before and after a change, kept
+// alongside the audit log row of the change. PII values stay encrypted as in the user's record.
+type UserFieldHistory struct {
+	BaseGorm
+	AuditLogID       uint   `gorm:"not null;index"`
+	UserID           uint   `gorm:"not null;index:idx_user_field_history"`
+	Field            string `gorm:"not null;index:idx_user_field_history"`
+	OldValue         *string
+	NewValue         *string
+	ActorType        string `gorm:"not null"`
+	ChangedBy        *uint
+	ServiceAccountID *uint
+}
+
+// OutboxEvent is a domain event, written in the transaction of the change it describes. It is
+// done once every subscriber has handled it, or failed once the last attempt has been made.
+type OutboxEvent struct {
+	BaseGorm
+	EventType     string    `gorm:"not null"`
+	Payload       string    `gorm:"not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	DeliveredAt   *time.Time
+	FailedAt      *time.Time
+	LastError     *string
+}
+
+// OutboxDelivery is a subscriber that has handled an event, so that a retry only calls the
+// subscribers that failed.
+type OutboxDelivery struct {
+	BaseGorm
+	OutboxEventID uint   `gorm:"not null;uniqueIndex:idx_outbox_delivery"`
+	Subscriber    string `gorm:"not null;uniqueIndex:idx_outbox_delivery"`
+}
+
+// UserIdentity links a user to their account at an OpenID Connect provider, identified by
+// the issuer and the provider's subject ID rather than the email, which can change.
+type UserIdentity struct {
+	BaseGorm
+	UserID  uint   `gorm:"not null;index"`
+	Issuer  string `gorm:"not null;uniqueIndex:idx_user_identity_subject"`
+	Subject string `gorm:"not null;uniqueIndex:idx_user_identity_subject"`
+	Email   string `gorm:"not null"`
+}
+
+// OIDCLoginState is a single sign-on attempt waiting for the provider's callback. The PKCE
+// verifier and the nonce stay on the server; only State travels through the browser.
+type OIDCLoginState struct {
+	BaseGorm
+	State        string    `gorm:"not null;uniqueIndex"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	UsedAt       *time.Time
+}
+
+// SigningKey is a key pair that signs tokens, with the private key encrypted like other PII.
+// A key is published before ActivatesAt so that every instance can verify its tokens by then,
+// and stays published until ExpiresAt, after which no token it signed is still valid.
+type SigningKey struct {
+	BaseGorm
+	KeyID       string    `gorm:"not null;uniqueIndex"`
+	Algorithm   string    `gorm:"not null"`
+	PrivateKey  string    `gorm:"not null"`
+	PublicKey   string    `gorm:"not null"`
+	ActivatesAt time.Time `gorm:"not null"`
+	ExpiresAt   *time.Time
+}
+
+// Webhook is an admin managed subscription to domain events. Each event of its types is posted
+// to the URL, signed with the secret, which is kept encrypted.
+type Webhook struct {
+	BaseGorm
+	Name       string `gorm:"not null"`
+	URL        string `gorm:"not null"`
+	Secret     string `gorm:"not null"`
+	EventTypes string `gorm:"not null"`
+	IsEnabled  bool   `gorm:"not null;default:true"`
+	CreatedBy  uint   `gorm:"not null"`
+}
+
+// WebhookDelivery is an event queued for a webhook, retried with backoff until it is delivered
+// or dead lettered.
+type WebhookDelivery struct {
+	BaseGorm
+	WebhookID      uint      `gorm:"not null;uniqueIndex:idx_webhook_delivery"`
+	OutboxEventID  uint      `gorm:"not null;uniqueIndex:idx_webhook_delivery"`
+	EventType      string    `gorm:"not null"`
+	Status         string    `gorm:"not null;index"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"not null;index"`
+	LastAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      *string
+	DeliveredAt    *time.Time
+}
+
+// WebhookDeliveryAttempt is one POST of a delivery, kept for the delivery log.
+type WebhookDeliveryAttempt struct {
+	BaseGorm
+	WebhookDeliveryID uint `gorm:"not null;index"`
+	ResponseStatus    *int
+	Error             *string
+	DurationMs        int64 `gorm:"not null"`
+}
+
+// ServiceAccount is a non-human principal for integrations. It signs in only with its API keys.
+type ServiceAccount struct {
+	BaseGorm
+	Name        string `gorm:"not null"`
+	Description *string
+	CreatedBy   uint `gorm:"not null"`
+	APIKeys     []APIKey
+}
+
+// APIKey authenticates a service account. Only the SHA-256 hash of the key is stored; the
+// prefix is kept in clear to find the key and to tell keys apart. Scopes is a comma separated
+// list of permissions.
+type APIKey struct {
+	BaseGorm
+	ServiceAccountID uint      `gorm:"not null;index"`
+	Name             string    `gorm:"not null"`
+	Prefix           string    `gorm:"not null;uniqueIndex"`
+	KeyHash          string    `gorm:"not null"`
+	Scopes           string    `gorm:"not null"`
+	ExpiresAt        time.Time `gorm:"not null"`
+	LastUsedAt       *time.Time
+	RevokedAt        *time.Time
+	CreatedBy        uint `gorm:"not null"`
 }
 
 type Department struct {
 	BaseGorm
 	Name              string `gorm:"not null"`
+	ExternalID        *string
 	DepartmentMembers []DepartmentMember
 }
 
@@ -139,3 +470,103 @@ type UserNotice struct {
 	ApprovedBy         *uint `json:"approvedBy"`
 	ApprovedUser       *User `gorm:"foreignKey:ApprovedBy"`
 }
+
+type ProfileChangeRequest struct {
+	BaseGorm
+	UserID         uint `gorm:"not null"`
+	User           User
+	Changes        string `gorm:"not null"`
+	UserDocumentID *uint
+	UserDocument   *UserDocument
+	IsApproved     *bool
+	ApprovedAt     *time.Time
+	ApprovedBy     *uint
+	ApprovedUser   *User `gorm:"foreignKey:ApprovedBy"`
+	Remarks        *string
+}
+
+type UserEmergencyContact struct {
+	BaseGorm
+	UserID       uint `gorm:"not null"`
+	User         User
+	Name         string `gorm:"not null"`
+	Relationship string `gorm:"not null"`
+	Mobile       string `gorm:"not null"`
+	Address      *string
+}
+
+type UserDependent struct {
+	BaseGorm
+	UserID       uint `gorm:"not null"`
+	User         User
+	Name         string    `gorm:"not null"`
+	Relationship string    `gorm:"not null"`
+	DOB          time.Time `gorm:"not null;type:date"`
+	Gender       string    `gorm:"not null"`
+	IsInsured    bool      `gorm:"default:false"`
+}
+
+type UserNominee struct {
+	BaseGorm
+	UserID          uint `gorm:"not null"`
+	User            User
+	Name            string  `gorm:"not null"`
+	Relationship    string  `gorm:"not null"`
+	SharePercentage float64 `gorm:"not null"`
+	Address         *string
+}
+
+type CustomField struct {
+	BaseGorm
+	Name              string `gorm:"not null"`
+	Key               string `gorm:"not null"`
+	FieldType         string `gorm:"not null"`
+	Options           *string
+	IsRequired        bool `gorm:"default:false"`
+	Pattern           *string
+	MinValue          *float64
+	MaxValue          *float64
+	Visibility        uint `gorm:"not null"`
+	CustomFieldValues []UserCustomFieldValue
+}
+
+type UserCustomFieldValue struct {
+	BaseGorm
+	UserID        uint `gorm:"not null"`
+	User          User
+	CustomFieldID uint `gorm:"not null"`
+	CustomField   CustomField
+	Value         string `gorm:"not null"`
+}
+
+type UserEducation struct {
+	BaseGorm
+	UserID       uint `gorm:"not null"`
+	User         User
+	Degree       string `gorm:"not null"`
+	Institution  string `gorm:"not null"`
+	FieldOfStudy *string
+	StartYear    uint `gorm:"not null"`
+	EndYear      *uint
+	Grade        *string
+}
+
+type UserCertification struct {
+	BaseGorm
+	UserID         uint `gorm:"not null"`
+	User           User
+	Name           string `gorm:"not null"`
+	Issuer         string `gorm:"not null"`
+	CredentialID   *string
+	IssuedDate     time.Time  `gorm:"not null;type:date"`
+	ExpiryDate     *time.Time `gorm:"type:date"`
+	ReminderSentAt *time.Time
+}
+
+type UserSkill struct {
+	BaseGorm
+	UserID      uint `gorm:"not null"`
+	User        User
+	Name        string `gorm:"not null"`
+	Proficiency uint   `gorm:"not null"`
+}
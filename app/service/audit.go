@@ -0,0 +1,68 @@
+package service
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"fmt"
+)
+
+// auditVerifyBatchSize is how many audit log rows are read at a time while verifying the chain.
+const auditVerifyBatchSize = 500
+
+type auditService struct {
+	auditRepository domain.AuditRepository
+}
+
+func NewAuditService(auditRepository domain.AuditRepository) domain.AuditService {
+	return &auditService{auditRepository}
+}
+
+func (s *auditService) FetchAuditLogs(filters *request.FetchAuditLogs) (*utils.PaginationResponse, error) {
+	for _, date := range []string{filters.FromDate, filters.ToDate} {
+		if _, isValidDate := utils.IsValidDate(date); date != "" && !isValidDate {
+			return nil, fmt.Errorf("invalid date format: %s", date)
+		}
+	}
+
+	data, err := s.auditRepository.FetchAuditLogs(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// VerifyAuditLogs walks the chain from the first row and reports the first row that was edited,
+// or that follows a removed row.
+func (s *auditService) VerifyAuditLogs() (*response.VerifyAuditLog, error) {
+	data := &response.VerifyAuditLog{Valid: true}
+
+	var afterID uint
+
+	for {
+		logs, err := s.auditRepository.FetchAuditLogChain(afterID, auditVerifyBatchSize)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range logs {
+			if logs[i].PrevHash != data.LastHash || utils.AuditLogHash(&logs[i]) != logs[i].Hash {
+				data.Valid = false
+				data.BrokenAtID = &logs[i].ID
+				return data, nil
+			}
+
+			data.LastHash = logs[i].Hash
+			data.CheckedRows++
+			afterID = logs[i].ID
+		}
+
+		if len(logs) < auditVerifyBatchSize {
+			return data, nil
+		}
+	}
+}
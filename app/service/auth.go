@@ -9,67 +9,335 @@ import (
 	"ems/domain"
 	"ems/infrastructure/config"
 	"ems/utils"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type authService struct {
-	userRepository domain.UserRepository
+	userRepository          domain.UserRepository
+	sessionRepository       domain.SessionRepository
+	roleRepository          domain.RoleRepository
+	twoFactorRepository     domain.TwoFactorRepository
+	loginThrottleRepository domain.LoginThrottleRepository
+	oidcRepository          domain.OIDCRepository
+	identityProvider        domain.IdentityProvider
+	directory               domain.Directory
 }
 
-func NewAuthService(userRepository domain.UserRepository) domain.AuthService {
-	return &authService{userRepository}
+// NewAuthService builds the auth service. identityProvider and directory are nil when single
+// sign-on or LDAP sign in is not configured.
+func NewAuthService(userRepository domain.UserRepository, sessionRepository domain.SessionRepository,
+	roleRepository domain.RoleRepository, twoFactorRepository domain.TwoFactorRepository,
+	loginThrottleRepository domain.LoginThrottleRepository, oidcRepository domain.OIDCRepository,
+	identityProvider domain.IdentityProvider, directory domain.Directory) domain.AuthService {
+	return &authService{userRepository, sessionRepository, roleRepository, twoFactorRepository,
+		loginThrottleRepository, oidcRepository, identityProvider, directory}
 }
 
-func (s *authService) Login(req *request.Login) (*response.FetchUserByEmail, error) {
+// Login checks the password, against the directory for directory users, and starts a session,
+// unless the user has two-factor enabled or their role requires it, in which case a challenge
+// is returned for the second step.
+func (s *authService) Login(req *request.Login, device *request.SessionDevice) (*response.FetchUserByEmail,
+	*response.TwoFactorChallenge, error) {
+	if err := s.checkLockout(nil, req.Email, device); err != nil {
+		return nil, nil, err
+	}
+
 	user, err := s.userRepository.GetUserByEmail(req.Email)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if user == nil {
-		return nil, apperror.DataNotFoundError("email")
+		if err := s.recordFailedAttempt(constant.LoginFailed, nil, req.Email, device, "unknown email"); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, apperror.DataNotFoundError("email")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, fmt.Errorf("incorrect password")
+	if err := s.checkLockout(&user.ID, req.Email, device); err != nil {
+		return nil, nil, err
 	}
 
-	if (user.RoleID == uint(constant.Employee) || user.RoleID == uint(constant.DepartmentLead) ||
-		user.RoleID == uint(constant.HR)) && user.DepartmentID == nil {
-		return nil, fmt.Errorf("you are not assigned to any department, please contact HR")
+	if err := s.checkPassword(user, req.Password); err != nil {
+		if !errors.Is(err, apperror.ErrInvalidCredentials) {
+			return nil, nil, err
+		}
+
+		if err := s.recordFailedAttempt(constant.LoginFailed, &user.ID, req.Email, device, "incorrect password"); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, fmt.Errorf("incorrect password")
+	}
+
+	if err := checkDepartment(user); err != nil {
+		return nil, nil, err
 	}
 
-	token, err := utils.GenerateToken(int(user.ID))
+	if user.TwoFactorEnabled || user.RequireTwoFactor {
+		token, err := utils.GenerateTwoFactorToken(int(user.ID))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, &response.TwoFactorChallenge{TwoFactorToken: token, SetupRequired: !user.TwoFactorEnabled}, nil
+	}
+
+	if err := s.completeLogin(user, passwordAuthMethod(user), device); err != nil {
+		return nil, nil, err
+	}
+
+	return user, nil, nil
+}
+
+func (s *authService) VerifyTwoFactorLogin(req *request.VerifyTwoFactor, device *request.SessionDevice) (*response.FetchUserByEmail, error) {
+	user, err := s.getTwoFactorLoginUser(req.TwoFactorToken.TwoFactorToken)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.userRepository.UpdateToken(user.ID, token); err != nil {
+	if !user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	if err := s.checkLockout(&user.ID, user.Email, device); err != nil {
 		return nil, err
 	}
 
-	user.Token = token
+	if err := s.verifyTwoFactorCode(user.ID, req.Code); err != nil {
+		return nil, s.twoFactorLoginFailed(user, device, err)
+	}
+
+	if err := s.completeLogin(user, passwordAuthMethod(user), device); err != nil {
+		return nil, err
+	}
 
 	return user, nil
 }
 
-func (s *authService) Logout(userID uint) error {
-	isUserExistsByID, err := s.userRepository.GetUserByID(userID)
+// SetupTwoFactorForLogin lets a user whose role requires two-factor enrol with the interim
+// token, before they have a session.
+func (s *authService) SetupTwoFactorForLogin(req *request.TwoFactorToken) (*response.TwoFactorSetup, error) {
+	user, err := s.getTwoFactorLoginUser(req.TwoFactorToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SetupTwoFactor(user.ID)
+}
+
+func (s *authService) EnableTwoFactorForLogin(req *request.VerifyTwoFactor, device *request.SessionDevice) (*response.TwoFactorEnabled, error) {
+	user, err := s.getTwoFactorLoginUser(req.TwoFactorToken.TwoFactorToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLockout(&user.ID, user.Email, device); err != nil {
+		return nil, err
+	}
+
+	data, err := s.EnableTwoFactor(user.ID, &req.TwoFactorCode)
+
+	if err != nil {
+		return nil, s.twoFactorLoginFailed(user, device, err)
+	}
+
+	user.TwoFactorEnabled = true
+
+	if err := s.completeLogin(user, passwordAuthMethod(user), device); err != nil {
+		return nil, err
+	}
+
+	data.User = user
+
+	return data, nil
+}
+
+func (s *authService) FetchTwoFactorStatus(userID uint) (*response.TwoFactorStatus, error) {
+	user, err := s.userRepository.GetLoginUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	remainingRecoveryCodes, err := s.twoFactorRepository.CountRecoveryCodes(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.TwoFactorStatus{
+		Enabled:                user.TwoFactorEnabled,
+		Required:               user.RequireTwoFactor,
+		RemainingRecoveryCodes: remainingRecoveryCodes,
+	}, nil
+}
+
+// SetupTwoFactor generates a new secret. Two-factor stays off until EnableTwoFactor confirms
+// that the authenticator app produces matching codes.
+func (s *authService) SetupTwoFactor(userID uint) (*response.TwoFactorSetup, error) {
+	user, err := s.userRepository.GetLoginUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedSecret, err := utils.EncryptPII(secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.SaveTwoFactorSecret(userID, encryptedSecret); err != nil {
+		return nil, err
+	}
+
+	return &response.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(config.Config.TwoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *authService) EnableTwoFactor(userID uint, req *request.TwoFactorCode) (*response.TwoFactorEnabled, error) {
+	twoFactor, err := s.twoFactorRepository.GetTwoFactor(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor == nil {
+		return nil, fmt.Errorf("two-factor setup has not been started")
+	}
+
+	if twoFactor.EnabledAt != nil {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.DecryptPII(twoFactor.Secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	step, isValid := utils.ValidateTOTP(secret, strings.TrimSpace(req.Code), time.Now())
+
+	if !isValid {
+		return nil, apperror.ErrInvalidTwoFactorCode
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := generateRecoveryCodes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.EnableTwoFactor(userID, step, recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+
+	return &response.TwoFactorEnabled{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *authService) DisableTwoFactor(userID uint, req *request.TwoFactorCode) error {
+	user, err := s.userRepository.GetLoginUserByID(userID)
 
 	if err != nil {
 		return err
 	}
 
-	if isUserExistsByID == nil {
+	if user == nil {
 		return apperror.DataNotFoundError("user")
 	}
 
-	if err := s.userRepository.UpdateTokenStatus(userID); err != nil {
+	if user.RequireTwoFactor {
+		return fmt.Errorf("your role requires two-factor authentication")
+	}
+
+	if err := s.verifyTwoFactorCode(userID, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepository.RemoveTwoFactor(userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authService) RegenerateRecoveryCodes(userID uint, req *request.TwoFactorCode) (*response.TwoFactorEnabled, error) {
+	if err := s.verifyTwoFactorCode(userID, req.Code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := generateRecoveryCodes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.ReplaceRecoveryCodes(userID, recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+
+	return &response.TwoFactorEnabled{RecoveryCodes: recoveryCodes}, nil
+}
+
+// ResetTwoFactor is used by HR when an employee loses their authenticator. Their sessions are
+// revoked as well, and they enrol again on their next login if their role requires it.
+func (s *authService) ResetTwoFactor(userID uint) error {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := s.twoFactorRepository.RemoveTwoFactor(userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepository.RevokeUserSessions(userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Logout revokes only the session the request was made with; other devices stay signed in.
+func (s *authService) Logout(sessionID uint) error {
+	if err := s.sessionRepository.RevokeSession(sessionID); err != nil {
 		return err
 	}
 
@@ -87,6 +355,10 @@ func (s *authService) SendForgotPasswordOtp(req *request.SendForgotPasswordOtp)
 		return apperror.DataNotFoundError("email")
 	}
 
+	if isUserExistsByEmail.AuthSource == string(constant.DirectoryAuth) {
+		return apperror.ErrDirectoryPassword
+	}
+
 	otp := utils.GenerateOTP()
 
 	userOtp := &schema.ForgotPasswordOtp{
@@ -109,52 +381,433 @@ func (s *authService) SendForgotPasswordOtp(req *request.SendForgotPasswordOtp)
 	return nil
 }
 
-func (s *authService) VerifyForgotPasswordOtp(req *request.VerifyForgotPasswordOtp) (interface{}, error) {
-	var response struct {
-		Token string `json:"token"`
+// VerifyForgotPasswordOtp signs the user in with the emailed OTP in place of their password, so
+// that they can set a new one. The OTP only stands in for the password: a user with two-factor
+// gets the same challenge as at login before any session is created.
+func (s *authService) VerifyForgotPasswordOtp(req *request.VerifyForgotPasswordOtp,
+	device *request.SessionDevice) (*response.SessionTokens, *response.TwoFactorChallenge, error) {
+	if err := s.checkLockout(nil, req.Email, device); err != nil {
+		return nil, nil, err
 	}
 
 	isUserExists, err := s.userRepository.GetUserByEmail(req.Email)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if isUserExists == nil || isUserExists.Email == "" {
-		return nil, apperror.DataNotFoundError("email")
+		if err := s.recordFailedAttempt(constant.ForgotPasswordFailed, nil, req.Email, device, "unknown email"); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, apperror.DataNotFoundError("email")
+	}
+
+	if err := s.checkLockout(&isUserExists.ID, req.Email, device); err != nil {
+		return nil, nil, err
 	}
 
 	otpData, err := s.userRepository.GetOTPStatusByUserID(isUserExists.ID)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if otpData.Otp != req.OTP || otpData.IsUsed {
-		return nil, fmt.Errorf("incorrect otp")
+	if otpData == nil || otpData.Otp != req.OTP || otpData.IsUsed {
+		if err := s.forgotPasswordOtpFailed(isUserExists.ID, req.Email, otpData, device); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, fmt.Errorf("incorrect otp")
 	}
 
 	expiresAt := otpData.CreatedAt.Add(time.Duration(config.Config.ForgotPasswordOTPValidity) * time.Minute)
 
 	if time.Now().After(expiresAt) {
-		return nil, fmt.Errorf("otp expired")
+		return nil, nil, fmt.Errorf("otp expired")
+	}
+
+	if err := checkDepartment(isUserExists); err != nil {
+		return nil, nil, err
 	}
 
 	if err := s.userRepository.UpdateOTPStatus(isUserExists.ID); err != nil {
+		return nil, nil, err
+	}
+
+	s.logAuthEvent(constant.ForgotPasswordVerified, &isUserExists.ID, req.Email, device, "")
+
+	if isUserExists.TwoFactorEnabled || isUserExists.RequireTwoFactor {
+		token, err := utils.GenerateTwoFactorToken(int(isUserExists.ID))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, &response.TwoFactorChallenge{TwoFactorToken: token, SetupRequired: !isUserExists.TwoFactorEnabled}, nil
+	}
+
+	tokens, err := s.createSession(isUserExists.ID, constant.PasswordLogin, device)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.loginThrottleRepository.ResetLoginThrottle(accountSubject(isUserExists.ID)); err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, nil, nil
+}
+
+// forgotPasswordOtpFailed counts a wrong OTP against the account and IP, and invalidates the
+// OTP once it has been guessed wrong too many times so that a new one has to be requested.
+func (s *authService) forgotPasswordOtpFailed(userID uint, email string, otpData *schema.ForgotPasswordOtp,
+	device *request.SessionDevice) error {
+	if err := s.recordFailedAttempt(constant.ForgotPasswordFailed, &userID, email, device, "incorrect otp"); err != nil {
+		return err
+	}
+
+	if otpData == nil || otpData.IsUsed {
+		return nil
+	}
+
+	if err := s.userRepository.IncrementOTPFailedAttempts(otpData.ID); err != nil {
+		return err
+	}
+
+	if otpData.FailedAttempts+1 < config.Config.MaxForgotPasswordAttempts {
+		return nil
+	}
+
+	if err := s.userRepository.UpdateOTPStatus(userID); err != nil {
+		return err
+	}
+
+	s.logAuthEvent(constant.ForgotPasswordOtpInvalidated, &userID, email, device,
+		fmt.Sprintf("invalidated after %d incorrect attempts", otpData.FailedAttempts+1))
+
+	return nil
+}
+
+// RefreshSession exchanges a refresh token for a new access token and rotates the refresh
+// token. Presenting a token that was already rotated means it has leaked, so the session is
+// revoked and both holders have to sign in again.
+func (s *authService) RefreshSession(req *request.RefreshSession, device *request.SessionDevice) (*response.SessionTokens, error) {
+	refreshTokenHash := utils.HashToken(req.RefreshToken)
+
+	session, err := s.sessionRepository.GetSessionByRefreshTokenHash(refreshTokenHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil || session.RevokedAt != nil {
+		return nil, apperror.InvalidSessionError("refresh token is invalid")
+	}
+
+	if session.RefreshTokenHash != refreshTokenHash {
+		if err := s.sessionRepository.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, apperror.InvalidSessionError("refresh token was already used, the session has been revoked")
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, apperror.InvalidSessionError("refresh token expired")
+	}
+
+	user, err := s.userRepository.GetUserByID(session.UserID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, apperror.InvalidSessionError("user not found")
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	isRotated, err := s.sessionRepository.RotateRefreshToken(session.ID, refreshTokenHash,
+		utils.HashToken(refreshToken), device)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isRotated {
+		return nil, apperror.InvalidSessionError("refresh token is invalid")
+	}
+
+	token, err := utils.GenerateToken(int(session.UserID), session.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SessionTokens{Token: token, RefreshToken: refreshToken}, nil
+}
+
+func (s *authService) FetchSessions(userID, currentSessionID uint) ([]response.FetchUserSessions, error) {
+	data, err := s.sessionRepository.FetchUserSessions(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range data {
+		data[i].IsCurrent = data[i].ID == currentSessionID
+	}
+
+	return data, nil
+}
+
+func (s *authService) RevokeSession(userID, sessionID uint) error {
+	session, err := s.sessionRepository.GetSessionByID(sessionID)
+
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserID != userID {
+		return apperror.DataNotFoundError("session")
+	}
+
+	if err := s.sessionRepository.RevokeSession(sessionID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authService) RevokeUserSessions(userID uint) error {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := s.sessionRepository.RevokeUserSessions(userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// completeLogin starts the session once every check has passed and clears the account's
+// failed attempts.
+func (s *authService) completeLogin(user *response.FetchUserByEmail, authMethod constant.AuthMethod,
+	device *request.SessionDevice) error {
+	tokens, err := s.createSession(user.ID, authMethod, device)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.loginThrottleRepository.ResetLoginThrottle(accountSubject(user.ID)); err != nil {
+		return err
+	}
+
+	s.logAuthEvent(constant.LoginSucceeded, &user.ID, user.Email, device, "")
+
+	user.Token = tokens.Token
+	user.RefreshToken = tokens.RefreshToken
+	user.PasswordChangeRequired = authMethod == constant.PasswordLogin &&
+		(user.MustChangePassword || utils.IsPasswordExpired(user.PasswordChangedAt))
+
+	if user.Permissions, err = s.rolePermissions(user.RoleID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkPassword verifies a local user's password hash or binds to the directory as a directory
+// user. A wrong password is reported as apperror.ErrInvalidCredentials.
+func (s *authService) checkPassword(user *response.FetchUserByEmail, password string) error {
+	if user.AuthSource != string(constant.DirectoryAuth) {
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+			return apperror.ErrInvalidCredentials
+		}
+
+		return nil
+	}
+
+	if s.directory == nil {
+		return fmt.Errorf("directory sign in is not configured")
+	}
+
+	_, err := s.directory.Authenticate(user.Email, password)
+
+	return err
+}
+
+// passwordAuthMethod is the session type for a user who signed in with their password. The
+// EMS password policy only applies to local passwords.
+func passwordAuthMethod(user *response.FetchUserByEmail) constant.AuthMethod {
+	if user.AuthSource == string(constant.DirectoryAuth) {
+		return constant.LDAPLogin
+	}
+
+	return constant.PasswordLogin
+}
+
+// checkDepartment refuses roles that work within a department until HR has assigned one.
+func checkDepartment(user *response.FetchUserByEmail) error {
+	if (user.RoleID == uint(constant.Employee) || user.RoleID == uint(constant.DepartmentLead) ||
+		user.RoleID == uint(constant.HR)) && user.DepartmentID == nil {
+		return fmt.Errorf("you are not assigned to any department, please contact HR")
+	}
+
+	return nil
+}
+
+func (s *authService) getTwoFactorLoginUser(twoFactorToken string) (*response.FetchUserByEmail, error) {
+	userID, err := utils.ValidateTwoFactorToken(twoFactorToken)
+
+	if err != nil {
+		return nil, apperror.InvalidSessionError(err.Error())
+	}
+
+	user, err := s.userRepository.GetLoginUserByID(userID)
+
+	if err != nil {
 		return nil, err
 	}
 
-	token, err := utils.GenerateToken(int(isUserExists.ID))
+	if user == nil {
+		return nil, apperror.InvalidSessionError("user not found")
+	}
+
+	return user, nil
+}
+
+// twoFactorLoginFailed counts a wrong code at the second login step like a wrong password.
+func (s *authService) twoFactorLoginFailed(user *response.FetchUserByEmail, device *request.SessionDevice, err error) error {
+	if !errors.Is(err, apperror.ErrInvalidTwoFactorCode) {
+		return err
+	}
+
+	if err := s.recordFailedAttempt(constant.TwoFactorFailed, &user.ID, user.Email, device, err.Error()); err != nil {
+		return err
+	}
+
+	return err
+}
+
+// verifyTwoFactorCode accepts a current TOTP code or an unused recovery code.
+func (s *authService) verifyTwoFactorCode(userID uint, code string) error {
+	twoFactor, err := s.twoFactorRepository.GetTwoFactor(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	code = strings.TrimSpace(code)
+
+	secret, err := utils.DecryptPII(twoFactor.Secret)
+
+	if err != nil {
+		return err
+	}
+
+	if step, isValid := utils.ValidateTOTP(secret, code, time.Now()); isValid {
+		isUnused, err := s.twoFactorRepository.UseTwoFactorStep(userID, step)
+
+		if err != nil {
+			return err
+		}
+
+		if !isUnused {
+			return fmt.Errorf("%w, the code was already used", apperror.ErrInvalidTwoFactorCode)
+		}
+
+		return nil
+	}
+
+	isUnused, err := s.twoFactorRepository.UseRecoveryCode(userID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+
+	if err != nil {
+		return err
+	}
+
+	if !isUnused {
+		return apperror.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes, err := utils.GenerateRecoveryCodes(constant.RecoveryCodeCount)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveryCodeHashes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		recoveryCodeHashes[i] = utils.HashToken(recoveryCode)
+	}
+
+	return recoveryCodes, recoveryCodeHashes, nil
+}
+
+func (s *authService) createSession(userID uint, authMethod constant.AuthMethod,
+	device *request.SessionDevice) (*response.SessionTokens, error) {
+	refreshToken, err := utils.GenerateRefreshToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := s.sessionRepository.CreateSession(userID, utils.HashToken(refreshToken), authMethod, device,
+		time.Now().Add(config.Config.RefreshTokenDuration))
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.userRepository.UpdateToken(isUserExists.ID, token); err != nil {
+	token, err := utils.GenerateToken(int(userID), sessionID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SessionTokens{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// rolePermissions lists the permissions granted to a role so clients can tailor their menus.
+func (s *authService) rolePermissions(roleID uint) ([]string, error) {
+	if roleID != uint(constant.Admin) {
+		return s.roleRepository.FetchRolePermissions(roleID)
+	}
+
+	permissions, err := s.roleRepository.FetchPermissions()
+
+	if err != nil {
 		return nil, err
 	}
 
-	response.Token = token
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = permission.Name
+	}
 
-	return response, nil
+	return names, nil
 }
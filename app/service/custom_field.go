@@ -0,0 +1,237 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/utils"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type customFieldService struct {
+	customFieldRepository domain.CustomFieldRepository
+	userRepository        domain.UserRepository
+}
+
+func NewCustomFieldService(customFieldRepository domain.CustomFieldRepository,
+	userRepository domain.UserRepository) domain.CustomFieldService {
+	return &customFieldService{customFieldRepository, userRepository}
+}
+
+func (s *customFieldService) CreateCustomField(req *request.CreateCustomField) error {
+	isKeyExists, err := s.customFieldRepository.IsCustomFieldKeyExists(req.Key)
+
+	if err != nil {
+		return err
+	}
+
+	if isKeyExists {
+		return apperror.UniqueKeyError("custom field key")
+	}
+
+	if err := validateCustomFieldDefinition(req); err != nil {
+		return err
+	}
+
+	if err := s.customFieldRepository.CreateCustomField(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *customFieldService) FetchCustomFields() ([]response.FetchCustomFields, error) {
+	data, err := s.customFieldRepository.FetchCustomFields()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *customFieldService) UpdateCustomField(customFieldID uint, req *request.UpdateCustomField) error {
+	customField, err := s.customFieldRepository.GetCustomFieldByID(customFieldID)
+
+	if err != nil {
+		return err
+	}
+
+	if customField == nil {
+		return apperror.DataNotFoundError("custom field")
+	}
+
+	isKeyExists, err := s.customFieldRepository.IsCustomFieldKeyExistsExceptID(customFieldID, req.Key)
+
+	if err != nil {
+		return err
+	}
+
+	if isKeyExists {
+		return apperror.UniqueKeyError("custom field key")
+	}
+
+	if err := validateCustomFieldDefinition(&req.CreateCustomField); err != nil {
+		return err
+	}
+
+	if err := s.customFieldRepository.UpdateCustomField(customFieldID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *customFieldService) RemoveCustomField(customFieldID uint) error {
+	customField, err := s.customFieldRepository.GetCustomFieldByID(customFieldID)
+
+	if err != nil {
+		return err
+	}
+
+	if customField == nil {
+		return apperror.DataNotFoundError("custom field")
+	}
+
+	if err := s.customFieldRepository.RemoveCustomField(customFieldID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *customFieldService) UpdateUserCustomFieldValues(req *request.UpdateUserCustomFieldValues) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	for _, value := range req.Values {
+		customField, err := s.customFieldRepository.GetCustomFieldByID(value.CustomFieldID)
+
+		if err != nil {
+			return err
+		}
+
+		if customField == nil {
+			return apperror.DataNotFoundError("custom field")
+		}
+
+		if err := validateCustomFieldValue(customField, value.Value); err != nil {
+			return err
+		}
+	}
+
+	if err := s.customFieldRepository.UpdateUserCustomFieldValues(req.UserID, req.Values); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// customFieldVisibilities returns the visibility levels the viewer may read for the owner's fields.
+// HR-only fields are read by roles granted user.viewSensitive.
+func customFieldVisibilities(viewerID, ownerID uint, canViewSensitive bool) []uint {
+	if canViewSensitive {
+		return []uint{uint(constant.HROnly), uint(constant.OwnerAndHR), uint(constant.Everyone)}
+	}
+
+	if viewerID == ownerID {
+		return []uint{uint(constant.OwnerAndHR), uint(constant.Everyone)}
+	}
+
+	return []uint{uint(constant.Everyone)}
+}
+
+func validateCustomFieldDefinition(req *request.CreateCustomField) error {
+	if constant.CustomFieldType(req.FieldType) == constant.EnumField && len(req.Options) == 0 {
+		return fmt.Errorf("options are required for enum fields")
+	}
+
+	for _, option := range req.Options {
+		if strings.Contains(option, ",") {
+			return fmt.Errorf("enum option %q must not contain a comma", option)
+		}
+	}
+
+	if req.Pattern != "" {
+		if _, err := regexp.Compile(req.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+
+	if req.MinValue != nil && req.MaxValue != nil && *req.MinValue > *req.MaxValue {
+		return fmt.Errorf("minValue must not be greater than maxValue")
+	}
+
+	return nil
+}
+
+func validateCustomFieldValue(customField *schema.CustomField, value string) error {
+	if value == "" {
+		if customField.IsRequired {
+			return fmt.Errorf("%s is required", customField.Name)
+		}
+		return nil
+	}
+
+	switch constant.CustomFieldType(customField.FieldType) {
+	case constant.NumberField:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", customField.Name)
+		}
+
+		if customField.MinValue != nil && number < *customField.MinValue {
+			return fmt.Errorf("%s must be at least %v", customField.Name, *customField.MinValue)
+		}
+
+		if customField.MaxValue != nil && number > *customField.MaxValue {
+			return fmt.Errorf("%s must be at most %v", customField.Name, *customField.MaxValue)
+		}
+	case constant.DateField:
+		if _, isValidDate := utils.IsValidDate(value); !isValidDate {
+			return fmt.Errorf("%s must be a date in YYYY-MM-DD format", customField.Name)
+		}
+	case constant.EnumField:
+		if customField.Options == nil {
+			return fmt.Errorf("%s has no options configured", customField.Name)
+		}
+
+		isValidOption := false
+		for _, option := range strings.Split(*customField.Options, ",") {
+			if option == value {
+				isValidOption = true
+				break
+			}
+		}
+
+		if !isValidOption {
+			return fmt.Errorf("%s must be one of %s", customField.Name, *customField.Options)
+		}
+	}
+
+	if customField.Pattern != nil {
+		isMatch, err := regexp.MatchString(*customField.Pattern, value)
+		if err != nil {
+			return err
+		}
+
+		if !isMatch {
+			return fmt.Errorf("%s does not match the expected format", customField.Name)
+		}
+	}
+
+	return nil
+}
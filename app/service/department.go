@@ -18,7 +18,7 @@ func NewDepartmentService(departmentRepository domain.DepartmentRepository, user
 	return &departmentService{departmentRepository, userRepository}
 }
 
-func (s *departmentService) CreateDepartment(req *request.CreateDepartment) error {
+func (s *departmentService) CreateDepartment(actor *request.AuditActor, req *request.CreateDepartment) error {
 
 	isDepartmentNameExists, err := s.departmentRepository.IsDepartmentNameExists(req.Name)
 
@@ -40,7 +40,7 @@ func (s *departmentService) CreateDepartment(req *request.CreateDepartment) erro
 		return apperror.DataNotFoundError("department lead user")
 	}
 
-	if err := s.departmentRepository.CreateDepartment(req); err != nil {
+	if err := s.departmentRepository.CreateDepartment(actor, req); err != nil {
 		return err
 	}
 
@@ -57,7 +57,7 @@ func (s *departmentService) FetchDepartments(filters *request.CommonRequest) (*u
 	return data, nil
 }
 
-func (s *departmentService) UpdateDepartment(departmentID uint, req *request.UpdateDepartment) error {
+func (s *departmentService) UpdateDepartment(actor *request.AuditActor, departmentID uint, req *request.UpdateDepartment) error {
 	isDepartmentExists, err := s.departmentRepository.IsDepartmentExists(departmentID)
 
 	if err != nil {
@@ -100,14 +100,14 @@ func (s *departmentService) UpdateDepartment(departmentID uint, req *request.Upd
 		}
 	}
 
-	if err := s.departmentRepository.UpdateDepartment(departmentID, req); err != nil {
+	if err := s.departmentRepository.UpdateDepartment(actor, departmentID, req); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *departmentService) RemoveDepartment(departmentID uint) error {
+func (s *departmentService) RemoveDepartment(actor *request.AuditActor, departmentID uint) error {
 	isDepartmentExists, err := s.departmentRepository.IsDepartmentExists(departmentID)
 
 	if err != nil {
@@ -122,14 +122,14 @@ func (s *departmentService) RemoveDepartment(departmentID uint) error {
 		return fmt.Errorf("hr department cannot be removed")
 	}
 
-	if err := s.departmentRepository.RemoveDepartment(departmentID); err != nil {
+	if err := s.departmentRepository.RemoveDepartment(actor, departmentID); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *departmentService) MappUsersToDepartment(departmentID uint, req *request.MappUsersToDepartment) error {
+func (s *departmentService) MappUsersToDepartment(actor *request.AuditActor, departmentID uint, req *request.MappUsersToDepartment) error {
 	isDepartmentExists, err := s.departmentRepository.IsDepartmentExists(departmentID)
 
 	if err != nil {
@@ -162,7 +162,7 @@ func (s *departmentService) MappUsersToDepartment(departmentID uint, req *reques
 		}
 	}
 
-	if err := s.departmentRepository.MappUsersToDepartment(departmentID, req); err != nil {
+	if err := s.departmentRepository.MappUsersToDepartment(actor, departmentID, req); err != nil {
 		return err
 	}
 
@@ -189,7 +189,7 @@ func (s *departmentService) FetchDepartmentMembers(departmentID uint, filters *r
 	return data, nil
 }
 
-func (s *departmentService) UnMapUser(req *request.UnMapUser) error {
+func (s *departmentService) UnMapUser(actor *request.AuditActor, req *request.UnMapUser) error {
 	isDepartmentUserExists, err := s.userRepository.IsDepartmentUserExists(req.UserID)
 
 	if err != nil {
@@ -214,11 +214,11 @@ func (s *departmentService) UnMapUser(req *request.UnMapUser) error {
 				return apperror.DataNotFoundError("department lead user")
 			}
 
-			if err := s.departmentRepository.UnMapUser(req); err != nil {
+			if err := s.departmentRepository.UnMapUser(actor, req); err != nil {
 				return err
 			}
 
-			if err := s.departmentRepository.MapLeadToDepartment(uint(isDepartmentUserExists.DepartmentID),
+			if err := s.mapLeadToDepartment(actor, uint(isDepartmentUserExists.DepartmentID),
 				*req.LeadID); err != nil {
 				return err
 			}
@@ -233,20 +233,31 @@ func (s *departmentService) UnMapUser(req *request.UnMapUser) error {
 			return fmt.Errorf("please provide another hr to unmap this hr")
 		}
 
-		if err := s.departmentRepository.UnMapUser(req); err != nil {
+		if err := s.departmentRepository.UnMapUser(actor, req); err != nil {
 			return err
 		}
 
 		if departmentMemberCount == 1 && req.LeadID != nil {
-			if err := s.departmentRepository.MapLeadToDepartment(uint(isDepartmentUserExists.DepartmentID),
+			if err := s.mapLeadToDepartment(actor, uint(isDepartmentUserExists.DepartmentID),
 				*req.LeadID); err != nil {
 				return err
 			}
 		}
 	} else {
-		if err := s.departmentRepository.UnMapUser(req); err != nil {
+		if err := s.departmentRepository.UnMapUser(actor, req); err != nil {
 			return err
 		}
+
+	}
+
+	return nil
+}
+
+// mapLeadToDepartment maps the lead that takes over from an unmapped lead or HR.
+func (s *departmentService) mapLeadToDepartment(actor *request.AuditActor, departmentID, leadID uint) error {
+	if err := s.departmentRepository.MapLeadToDepartment(actor, departmentID, leadID); err != nil {
+		return err
 	}
+
 	return nil
 }
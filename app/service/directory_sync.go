@@ -0,0 +1,260 @@
+package service
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+var errDirectoryNotConfigured = errors.New("LDAP directory is not configured")
+
+type directorySyncService struct {
+	directoryRepository domain.DirectoryRepository
+	directory           domain.Directory
+}
+
+// NewDirectorySyncService builds the sync service. directory is nil when LDAP is not configured.
+func NewDirectorySyncService(directoryRepository domain.DirectoryRepository,
+	directory domain.Directory) domain.DirectorySyncService {
+	return &directorySyncService{directoryRepository, directory}
+}
+
+// SyncDirectory matches directory users to EMS users by email and applies the roles and
+// departments their groups map to. Users without a mapped role are neither created nor moved
+// to directory sign in, so that they keep their local password, and the role of Admin users
+// is never changed, so that the directory cannot lock everyone out. On a dry run the report
+// is built without changing anything.
+func (s *directorySyncService) SyncDirectory(actor *request.AuditActor, dryRun bool) (*response.DirectorySyncReport, error) {
+	if s.directory == nil {
+		return nil, errDirectoryNotConfigured
+	}
+
+	directoryUsers, err := s.directory.FetchUsers()
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.directoryRepository.FetchDirectorySyncUsers()
+
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames, err := s.directoryRepository.FetchRoleNames()
+
+	if err != nil {
+		return nil, err
+	}
+
+	departmentNames, err := s.directoryRepository.FetchDepartmentNames()
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &response.DirectorySyncReport{
+		DryRun:         dryRun,
+		DirectoryUsers: len(directoryUsers),
+		Created:        []response.DirectorySyncChange{},
+		Updated:        []response.DirectorySyncChange{},
+		Skipped:        []response.DirectorySyncChange{},
+		NotInDirectory: []string{},
+	}
+
+	usersByEmail := make(map[string]*response.DirectorySyncUser, len(users))
+	for i := range users {
+		usersByEmail[strings.ToLower(users[i].Email)] = &users[i]
+	}
+
+	synced := make(map[uint]bool)
+
+	for _, directoryUser := range directoryUsers {
+		ldapConfig := config.Config.LDAP
+		roleID := matchGroup(ldapConfig.GroupRoles, directoryUser.Groups)
+		departmentID := matchGroup(ldapConfig.GroupDepartments, directoryUser.Groups)
+
+		if _, ok := roleNames[roleID]; roleID != 0 && !ok {
+			report.Skipped = append(report.Skipped, response.DirectorySyncChange{Email: directoryUser.Email,
+				Reason: fmt.Sprintf("mapped role %d does not exist", roleID)})
+			continue
+		}
+
+		if _, ok := departmentNames[departmentID]; departmentID != 0 && !ok {
+			report.Skipped = append(report.Skipped, response.DirectorySyncChange{Email: directoryUser.Email,
+				Reason: fmt.Sprintf("mapped department %d does not exist", departmentID)})
+			continue
+		}
+
+		user := usersByEmail[strings.ToLower(directoryUser.Email)]
+
+		if user != nil {
+			synced[user.ID] = true
+		}
+
+		if roleID == 0 {
+			report.Skipped = append(report.Skipped, response.DirectorySyncChange{Email: directoryUser.Email,
+				Reason: "not a member of any group mapped to a role"})
+			continue
+		}
+
+		if user == nil {
+			change := response.DirectorySyncChange{Email: directoryUser.Email,
+				Changes: []string{"role " + roleNames[roleID]}}
+
+			if departmentID != 0 {
+				change.Changes = append(change.Changes, "department "+departmentNames[departmentID])
+			}
+
+			if !dryRun {
+				if err := s.createUser(actor, &directoryUser, roleID, departmentID); err != nil {
+					report.Skipped = append(report.Skipped, response.DirectorySyncChange{Email: directoryUser.Email,
+						Reason: err.Error()})
+					continue
+				}
+			}
+
+			report.Created = append(report.Created, change)
+			continue
+		}
+
+		if user.RoleID == uint(constant.Admin) {
+			roleID = 0
+		}
+
+		req := &request.UpdateDirectoryUser{FirstName: user.FirstName, LastName: user.LastName, RoleID: user.RoleID}
+		var changes []string
+
+		if user.AuthSource != string(constant.DirectoryAuth) {
+			changes = append(changes, "sign in moved to the directory")
+		}
+
+		if directoryUser.FirstName != "" && directoryUser.FirstName != user.FirstName {
+			changes = append(changes, fmt.Sprintf("first name %s -> %s", user.FirstName, directoryUser.FirstName))
+			req.FirstName = directoryUser.FirstName
+		}
+
+		if directoryUser.LastName != "" && directoryUser.LastName != user.LastName {
+			changes = append(changes, fmt.Sprintf("last name %s -> %s", user.LastName, directoryUser.LastName))
+			req.LastName = directoryUser.LastName
+		}
+
+		if roleID != 0 && roleID != user.RoleID {
+			changes = append(changes, fmt.Sprintf("role %s -> %s", roleNames[user.RoleID], roleNames[roleID]))
+			req.RoleID = roleID
+		}
+
+		departmentChanged := departmentID != 0 && (user.DepartmentID == nil || *user.DepartmentID != departmentID)
+
+		if departmentChanged {
+			currentDepartment := "none"
+			if user.DepartmentID != nil {
+				currentDepartment = departmentNames[*user.DepartmentID]
+			}
+
+			changes = append(changes, fmt.Sprintf("department %s -> %s", currentDepartment,
+				departmentNames[departmentID]))
+		}
+
+		if len(changes) == 0 {
+			report.Unchanged++
+			continue
+		}
+
+		if !dryRun {
+			if err := s.updateUser(actor, user, req, departmentChanged, departmentID); err != nil {
+				report.Skipped = append(report.Skipped, response.DirectorySyncChange{Email: directoryUser.Email,
+					Reason: err.Error()})
+				continue
+			}
+		}
+
+		report.Updated = append(report.Updated, response.DirectorySyncChange{Email: directoryUser.Email,
+			Changes: changes})
+	}
+
+	for _, user := range users {
+		if user.AuthSource == string(constant.DirectoryAuth) && !synced[user.ID] {
+			report.NotInDirectory = append(report.NotInDirectory, user.Email)
+		}
+	}
+
+	log.Printf("directory sync (dry run %t): %d created, %d updated, %d unchanged, %d skipped, %d not in directory",
+		dryRun, len(report.Created), len(report.Updated), report.Unchanged, len(report.Skipped),
+		len(report.NotInDirectory))
+
+	return report, nil
+}
+
+func (s *directorySyncService) createUser(actor *request.AuditActor, directoryUser *response.DirectoryUser,
+	roleID, departmentID uint) error {
+	// The directory checks the password, so the stored one is random and never used.
+	password, err := utils.GenerateRefreshToken()
+
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+
+	if err != nil {
+		return err
+	}
+
+	firstName := directoryUser.FirstName
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(directoryUser.Email, "@")
+	}
+
+	userID, err := s.directoryRepository.CreateDirectoryUser(actor, &request.CreateUser{
+		RoleID:    roleID,
+		FirstName: firstName,
+		LastName:  directoryUser.LastName,
+		Code:      "LDAP-" + strings.ToUpper(utils.HashToken(strings.ToLower(directoryUser.DN))[:8]),
+		Email:     directoryUser.Email,
+	}, hashedPassword)
+
+	if err != nil {
+		return err
+	}
+
+	if departmentID != 0 {
+		return s.directoryRepository.SetUserDepartment(actor, userID, nil, departmentID)
+	}
+
+	return nil
+}
+
+func (s *directorySyncService) updateUser(actor *request.AuditActor, user *response.DirectorySyncUser,
+	req *request.UpdateDirectoryUser, departmentChanged bool, departmentID uint) error {
+	if err := s.directoryRepository.UpdateDirectoryUser(actor, user.ID, req); err != nil {
+		return err
+	}
+
+	if departmentChanged {
+		return s.directoryRepository.SetUserDepartment(actor, user.ID, user.DepartmentID, departmentID)
+	}
+
+	return nil
+}
+
+// matchGroup returns the ID of the first mapping whose group the user belongs to, or 0.
+// Group DNs are compared case insensitively, as directories do.
+func matchGroup(mappings []config.GroupMapping, groups []string) uint {
+	for _, mapping := range mappings {
+		for _, group := range groups {
+			if strings.EqualFold(strings.TrimSpace(group), mapping.GroupDN) {
+				return mapping.ID
+			}
+		}
+	}
+
+	return 0
+}
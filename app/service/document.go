@@ -0,0 +1,372 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+type documentService struct {
+	documentRepository domain.DocumentRepository
+	userRepository     domain.UserRepository
+	roleRepository     domain.RoleRepository
+}
+
+func NewDocumentService(documentRepository domain.DocumentRepository,
+	userRepository domain.UserRepository, roleRepository domain.RoleRepository) domain.DocumentService {
+	return &documentService{documentRepository, userRepository, roleRepository}
+}
+
+func (s *documentService) CreateDocumentCategory(actor *request.AuditActor, req *request.CreateDocumentCategory) error {
+	isCodeExists, err := s.documentRepository.IsDocumentCategoryCodeExists(req.Code)
+
+	if err != nil {
+		return err
+	}
+
+	if isCodeExists {
+		return apperror.UniqueKeyError("document category code")
+	}
+
+	if req.AllowedTypes, err = normalizeAllowedTypes(req.AllowedTypes); err != nil {
+		return err
+	}
+
+	if err := s.documentRepository.CreateDocumentCategory(actor, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *documentService) FetchDocumentCategories() ([]response.FetchDocumentCategories, error) {
+	data, err := s.documentRepository.FetchDocumentCategories()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *documentService) UpdateDocumentCategory(actor *request.AuditActor, categoryID uint, req *request.UpdateDocumentCategory) error {
+	category, err := s.documentRepository.GetDocumentCategoryByID(categoryID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return apperror.DataNotFoundError("document category")
+	}
+
+	isCodeExists, err := s.documentRepository.IsDocumentCategoryCodeExistsExceptID(categoryID, req.Code)
+
+	if err != nil {
+		return err
+	}
+
+	if isCodeExists {
+		return apperror.UniqueKeyError("document category code")
+	}
+
+	if req.AllowedTypes, err = normalizeAllowedTypes(req.AllowedTypes); err != nil {
+		return err
+	}
+
+	if err := s.documentRepository.UpdateDocumentCategory(actor, categoryID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *documentService) RemoveDocumentCategory(actor *request.AuditActor, categoryID uint) error {
+	category, err := s.documentRepository.GetDocumentCategoryByID(categoryID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return apperror.DataNotFoundError("document category")
+	}
+
+	if err := s.documentRepository.RemoveDocumentCategory(actor, categoryID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *documentService) UploadDocuments(actor *request.AuditActor, uploadedBy uint, req *request.UploadDocument, documents []response.UploadedDocument) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	category, err := s.documentRepository.GetDocumentCategoryByID(req.DocumentCategoryID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return apperror.DataNotFoundError("document category")
+	}
+
+	if category.HasExpiry && req.ExpiryDate == "" {
+		return fmt.Errorf("expiry date is required for %s", category.Name)
+	}
+
+	if req.ExpiryDate != "" {
+		expiryDate, isValidDate := utils.IsValidDate(req.ExpiryDate)
+
+		if !isValidDate {
+			return fmt.Errorf("invalid date format: %s", req.ExpiryDate)
+		}
+
+		if expiryDate.Before(time.Now().Truncate(24 * time.Hour)) {
+			return fmt.Errorf("expiry date cannot be in the past")
+		}
+	}
+
+	for _, document := range documents {
+		checksum, err := s.documentRepository.GetLatestDocumentChecksum(req.UserID, req.DocumentCategoryID, document.FileName)
+
+		if err != nil {
+			return err
+		}
+
+		if checksum != nil && *checksum == document.Checksum {
+			return fmt.Errorf("%s is identical to the latest uploaded version", document.FileName)
+		}
+	}
+
+	if err := s.documentRepository.CreateDocumentVersions(actor, uploadedBy, req, documents); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *documentService) FetchUserDocuments(req *request.FetchUserDocuments) ([]response.FetchUserDocument, error) {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isUserExists {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	data, err := s.documentRepository.FetchUserDocuments(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *documentService) FetchDocumentVersions(documentID uint) ([]response.FetchUserDocument, error) {
+	isDocumentExists, err := s.documentRepository.IsDocumentExists(documentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isDocumentExists {
+		return nil, apperror.DataNotFoundError("document")
+	}
+
+	data, err := s.documentRepository.FetchDocumentVersions(documentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *documentService) FetchMissingDocuments(filters *request.FetchMissingDocuments) (*utils.PaginationResponse, error) {
+	data, totalCount, err := s.documentRepository.FetchMissingDocuments(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
+}
+
+func (s *documentService) FetchDocumentForDownload(documentID, viewerID, viewerRoleID uint) (*response.DownloadDocument, error) {
+	document, err := s.documentRepository.GetDocumentByID(documentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		return nil, apperror.DataNotFoundError("document")
+	}
+
+	canAccessDocument, err := s.canAccessDocument(viewerID, viewerRoleID, document.UserID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !canAccessDocument {
+		return nil, apperror.AccessDeniedError("document")
+	}
+
+	return document, nil
+}
+
+func (s *documentService) RemoveDocument(actor *request.AuditActor, documentID uint) (*response.DownloadDocument, error) {
+	document, err := s.documentRepository.GetDocumentByID(documentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		return nil, apperror.DataNotFoundError("document")
+	}
+
+	if err := s.documentRepository.RemoveDocument(actor, documentID); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// ValidateUploads checks the sniffed content types against the category's allowed types and
+// keeps the owner within their storage quota. Without a category the default types apply.
+func (s *documentService) ValidateUploads(userID uint, documentCategoryID *uint,
+	documents []response.UploadedDocument) error {
+	allowedTypes := constant.DefaultAllowedDocumentTypes
+
+	if documentCategoryID != nil {
+		category, err := s.documentRepository.GetDocumentCategoryByID(*documentCategoryID)
+
+		if err != nil {
+			return err
+		}
+
+		if category == nil {
+			return apperror.DataNotFoundError("document category")
+		}
+
+		if category.AllowedTypes != nil {
+			allowedTypes = *category.AllowedTypes
+		}
+	}
+
+	var totalSize int64
+
+	for _, document := range documents {
+		if !slices.Contains(strings.Split(allowedTypes, ","), document.ContentType) {
+			return apperror.UploadRejectedError(document.FileName,
+				fmt.Sprintf("has type %s, allowed types are %s", document.ContentType, allowedTypes))
+		}
+
+		totalSize += document.FileSize
+	}
+
+	usage, err := s.documentRepository.FetchUserStorageUsage(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if usage+totalSize > config.Config.UserStorageQuota {
+		return apperror.UploadRejectedError(documents[len(documents)-1].FileName,
+			fmt.Sprintf("exceeds the storage quota of %d MB", config.Config.UserStorageQuota>>20))
+	}
+
+	return nil
+}
+
+func (s *documentService) QuarantineDocument(actor *request.AuditActor, uploadedBy, userID uint, document *response.UploadedDocument,
+	signature string) error {
+	if err := s.documentRepository.CreateQuarantinedDocument(actor, uploadedBy, userID, document, signature); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *documentService) FetchQuarantinedDocuments() ([]response.FetchQuarantinedDocument, error) {
+	data, err := s.documentRepository.FetchQuarantinedDocuments()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *documentService) RemoveQuarantinedDocument(actor *request.AuditActor, quarantineID uint) (*response.QuarantinedDocument, error) {
+	document, err := s.documentRepository.GetQuarantinedDocumentByID(quarantineID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if document == nil {
+		return nil, apperror.DataNotFoundError("quarantined document")
+	}
+
+	if err := s.documentRepository.RemoveQuarantinedDocument(actor, quarantineID); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// normalizeAllowedTypes maps aliases such as application/x-pdf to the canonical MIME type and rejects
+// types the content sniffer cannot detect, since uploads of such a type could never match.
+func normalizeAllowedTypes(allowedTypes []string) ([]string, error) {
+	normalized := make([]string, 0, len(allowedTypes))
+
+	for _, allowedType := range allowedTypes {
+		detected := mimetype.Lookup(strings.ToLower(strings.TrimSpace(allowedType)))
+
+		if detected == nil {
+			return nil, fmt.Errorf("unsupported file type %s", allowedType)
+		}
+
+		if !slices.Contains(normalized, detected.String()) {
+			normalized = append(normalized, detected.String())
+		}
+	}
+
+	return normalized, nil
+}
+
+// canAccessDocument lets employees read their own documents and roles granted document.view
+// read anyone's.
+func (s *documentService) canAccessDocument(viewerID, viewerRoleID, ownerID uint) (bool, error) {
+	if viewerID == ownerID {
+		return true, nil
+	}
+
+	return hasPermission(s.roleRepository, viewerRoleID, constant.DocumentView)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"fmt"
+)
+
+type employeeDataService struct {
+	userRepository        domain.UserRepository
+	customFieldRepository domain.CustomFieldRepository
+	userRelationService   domain.UserRelationService
+}
+
+func NewEmployeeDataService(userRepository domain.UserRepository,
+	userRelationRepository domain.UserRelationRepository,
+	customFieldRepository domain.CustomFieldRepository) domain.EmployeeDataService {
+	return &employeeDataService{userRepository, customFieldRepository,
+		NewUserRelationService(userRelationRepository, userRepository)}
+}
+
+func (s *employeeDataService) ExportEmployees(filters *request.ExportEmployees) ([]response.ExportEmployee, error) {
+	users, err := s.userRepository.FetchUsers(&request.FetchUsers{
+		DepartmentID: filters.DepartmentID,
+		RoleID:       filters.RoleID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ := users.Data.([]response.FetchUsers)
+	data := make([]response.ExportEmployee, 0, len(rows))
+	visibilities := customFieldVisibilities(0, 0, true)
+
+	for _, user := range rows {
+		details, err := s.userRepository.FetchUserDetails(&request.FetchUserDetails{UserID: user.ID})
+
+		if err != nil {
+			return nil, err
+		}
+
+		relations, err := s.userRelationService.FetchUserRelations(user.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		customFields, err := s.customFieldRepository.FetchUserCustomFieldValues(user.ID, visibilities)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, response.ExportEmployee{
+			User:         user,
+			Details:      details,
+			Relations:    *relations,
+			CustomFields: customFields,
+		})
+	}
+
+	return data, nil
+}
+
+// ImportEmployees applies custom field values from an export file, matching users by code
+// and fields by key. Rows that fail validation are reported and skipped.
+func (s *employeeDataService) ImportEmployees(req []request.ImportEmployee) (*response.ImportEmployees, error) {
+	result := &response.ImportEmployees{Errors: []string{}}
+
+	for i, employee := range req {
+		userID, err := s.userRepository.GetUserIDByCode(employee.User.Code)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if userID == 0 {
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: user %s not found", i+1, employee.User.Code))
+			continue
+		}
+
+		values, problem, err := s.resolveImportCustomFields(employee.CustomFields)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if problem != "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %s", i+1, problem))
+			continue
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := s.customFieldRepository.UpdateUserCustomFieldValues(userID, values); err != nil {
+			return nil, err
+		}
+
+		result.UpdatedCount++
+	}
+
+	return result, nil
+}
+
+// resolveImportCustomFields maps a row's fields to their IDs. A row that cannot be imported is
+// reported by the problem, and the error is left for failures that should stop the import.
+func (s *employeeDataService) resolveImportCustomFields(fields []request.ImportCustomFieldValue) ([]request.CustomFieldValue, string, error) {
+	values := make([]request.CustomFieldValue, 0, len(fields))
+
+	for _, field := range fields {
+		customField, err := s.customFieldRepository.GetCustomFieldByKey(field.Key)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if customField == nil {
+			return nil, fmt.Sprintf("custom field %s not found", field.Key), nil
+		}
+
+		if err := validateCustomFieldValue(customField, field.Value); err != nil {
+			return nil, err.Error(), nil
+		}
+
+		values = append(values, request.CustomFieldValue{CustomFieldID: customField.ID, Value: field.Value})
+	}
+
+	return values, "", nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/response"
+	"ems/domain"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// eventBatchSize is how many events a dispatch claims at a time.
+	eventBatchSize = 100
+	// eventLease is how long claimed events are held before another dispatch may retry them.
+	eventLease = 5 * time.Minute
+	// eventMaxAttempts is how many times an event is dispatched before it is marked as failed.
+	eventMaxAttempts = 10
+	// eventRetryDelay is the delay before the first retry, doubled on each later one.
+	eventRetryDelay = 30 * time.Second
+	// eventMaxRetryDelay caps the delay between retries.
+	eventMaxRetryDelay = 6 * time.Hour
+)
+
+type eventSubscriber struct {
+	name    string
+	handler domain.EventHandler
+}
+
+type eventBus struct {
+	eventRepository domain.EventRepository
+	mutex           sync.RWMutex
+	subscribers     map[constant.EventType][]eventSubscriber
+}
+
+func NewEventBus(eventRepository domain.EventRepository) domain.EventBus {
+	return &eventBus{
+		eventRepository: eventRepository,
+		subscribers:     make(map[constant.EventType][]eventSubscriber),
+	}
+}
+
+// Subscribe registers the handler for the event types. The subscriber name identifies the handler
+// across restarts, so an event it has handled is not delivered to it again on a retry.
+func (b *eventBus) Subscribe(subscriber string, handler domain.EventHandler, eventTypes ...constant.EventType) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, eventType := range eventTypes {
+		b.subscribers[eventType] = append(b.subscribers[eventType], eventSubscriber{subscriber, handler})
+	}
+}
+
+// DispatchEvents delivers the pending events to their subscribers. An event is completed once
+// every subscriber has handled it; otherwise it is retried with backoff, only for the subscribers
+// that failed, until it runs out of attempts.
+func (b *eventBus) DispatchEvents() error {
+	for {
+		events, err := b.eventRepository.ClaimPendingEvents(eventBatchSize, eventLease)
+
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			if err := b.dispatchEvent(&events[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < eventBatchSize {
+			return nil
+		}
+	}
+}
+
+func (b *eventBus) dispatchEvent(event *response.OutboxEvent) error {
+	b.mutex.RLock()
+	subscribers := b.subscribers[constant.EventType(event.EventType)]
+	b.mutex.RUnlock()
+
+	delivered, err := b.eventRepository.FetchEventSubscribers(event.ID)
+
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+
+	for _, subscriber := range subscribers {
+		if slices.Contains(delivered, subscriber.name) {
+			continue
+		}
+
+		if err := callEventHandler(subscriber.handler, event); err != nil {
+			log.Printf("Event %d (%s) failed for %s: %v", event.ID, event.EventType, subscriber.name, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", subscriber.name, err))
+			continue
+		}
+
+		if err := b.eventRepository.CreateEventDelivery(event.ID, subscriber.name); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) == 0 {
+		return b.eventRepository.CompleteEvent(event.ID)
+	}
+
+	attempts := event.Attempts + 1
+	lastError := strings.Join(failures, "; ")
+
+	if attempts >= eventMaxAttempts {
+		return b.eventRepository.FailEvent(event.ID, attempts, lastError)
+	}
+
+	return b.eventRepository.RetryEvent(event.ID, attempts, time.Now().Add(eventBackoff(attempts)), lastError)
+}
+
+// callEventHandler calls the handler, turning a panic into an error so that one subscriber
+// cannot stop the dispatch.
+func callEventHandler(handler domain.EventHandler, event *response.OutboxEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.New(fmt.Sprint("panic: ", recovered))
+		}
+	}()
+
+	return handler(event)
+}
+
+// eventBackoff returns the delay before the given retry attempt.
+func eventBackoff(attempts int) time.Duration {
+	delay := eventRetryDelay
+
+	for i := 1; i < attempts && delay < eventMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > eventMaxRetryDelay {
+		delay = eventMaxRetryDelay
+	}
+
+	return delay
+}
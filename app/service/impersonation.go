@@ -0,0 +1,184 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"fmt"
+	"log"
+	"time"
+)
+
+// StartImpersonation signs the impersonator in as the user for a fixed time. The session
+// cannot be refreshed and its requests are recorded against the impersonator. Admins, and
+// users with a permission the impersonator does not have, cannot be impersonated, so that the
+// permission cannot be used to gain more than the impersonator already has.
+func (s *authService) StartImpersonation(impersonatorID, userID uint, req *request.StartImpersonation,
+	device *request.SessionDevice) (*response.FetchUserByEmail, error) {
+	if impersonatorID == userID {
+		return nil, fmt.Errorf("you cannot impersonate yourself")
+	}
+
+	user, err := s.userRepository.GetLoginUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	if user.RoleID == uint(constant.Admin) {
+		return nil, fmt.Errorf("admins cannot be impersonated")
+	}
+
+	impersonator, err := s.userRepository.GetLoginUserByID(impersonatorID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if impersonator == nil {
+		return nil, apperror.DataNotFoundError("impersonator")
+	}
+
+	if err := s.checkImpersonationPermissions(impersonator.RoleID, user.RoleID); err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(config.Config.ImpersonationDuration)
+
+	sessionID, err := s.sessionRepository.CreateImpersonationSession(userID, impersonatorID, req.Reason,
+		utils.HashToken(refreshToken), device, expiresAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateTokenUntil(int(userID), sessionID, expiresAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAuthEvent(constant.ImpersonationStarted, &user.ID, user.Email, device,
+		fmt.Sprintf("by user %d: %s", impersonatorID, req.Reason))
+
+	// The session and the auth log already record the impersonation, so a mail server that is
+	// down does not hold up support.
+	if err := utils.SendImpersonationMail(user.Email, user.FirstName,
+		impersonator.FirstName+" "+impersonator.LastName, req.Reason, expiresAt); err != nil {
+		log.Printf("impersonation: failed to notify user %d: %v", user.ID, err)
+	}
+
+	user.Token = token
+	user.ImpersonationExpiresAt = &expiresAt
+
+	if user.Permissions, err = s.rolePermissions(user.RoleID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// checkImpersonationPermissions refuses a user whose role grants a permission that the
+// impersonator's role does not.
+func (s *authService) checkImpersonationPermissions(impersonatorRoleID, roleID uint) error {
+	if impersonatorRoleID == uint(constant.Admin) || impersonatorRoleID == roleID {
+		return nil
+	}
+
+	impersonatorPermissions, err := s.rolePermissions(impersonatorRoleID)
+
+	if err != nil {
+		return err
+	}
+
+	permissions, err := s.rolePermissions(roleID)
+
+	if err != nil {
+		return err
+	}
+
+	granted := make(map[string]bool, len(impersonatorPermissions))
+	for _, permission := range impersonatorPermissions {
+		granted[permission] = true
+	}
+
+	for _, permission := range permissions {
+		if !granted[permission] {
+			return fmt.Errorf("you cannot impersonate a user with the %s permission, which you do not have", permission)
+		}
+	}
+
+	return nil
+}
+
+func (s *authService) FetchImpersonations() ([]response.FetchImpersonations, error) {
+	data, err := s.sessionRepository.FetchImpersonations()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *authService) FetchImpersonatedRequests(sessionID uint) ([]response.FetchImpersonatedRequests, error) {
+	if _, err := s.getImpersonationSession(sessionID); err != nil {
+		return nil, err
+	}
+
+	data, err := s.sessionRepository.FetchImpersonatedRequests(sessionID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *authService) EndImpersonation(sessionID, endedBy uint, device *request.SessionDevice) error {
+	session, err := s.getImpersonationSession(sessionID)
+
+	if err != nil {
+		return err
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return fmt.Errorf("impersonation session has already ended")
+	}
+
+	if err := s.sessionRepository.RevokeSession(sessionID); err != nil {
+		return err
+	}
+
+	s.logAuthEvent(constant.ImpersonationEnded, &session.UserID, "", device,
+		fmt.Sprintf("by user %d", endedBy))
+
+	return nil
+}
+
+func (s *authService) getImpersonationSession(sessionID uint) (*response.UserSession, error) {
+	session, err := s.sessionRepository.GetSessionByID(sessionID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil || session.ImpersonatorID == nil {
+		return nil, apperror.DataNotFoundError("impersonation session")
+	}
+
+	return session, nil
+}
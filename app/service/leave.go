@@ -19,7 +19,7 @@ func NewLeaveService(leaveRepository domain.LeaveRepository, departmentRepositor
 	return &leaveService{leaveRepository, departmentRepository, userRepository}
 }
 
-func (s *leaveService) RequestLeave(departmentMemberID uint, req *request.RequestLeave) error {
+func (s *leaveService) RequestLeave(actor *request.AuditActor, departmentMemberID uint, req *request.RequestLeave) error {
 	isDepartmentMemberExists, err := s.departmentRepository.IsDepartmentMemberExists(departmentMemberID)
 
 	if err != nil {
@@ -49,7 +49,7 @@ func (s *leaveService) RequestLeave(departmentMemberID uint, req *request.Reques
 		return fmt.Errorf(`last leave request is in the pending state. please contact the Manager`)
 	}
 
-	if err := s.leaveRepository.RequestLeave(departmentMemberID, req); err != nil {
+	if err := s.leaveRepository.RequestLeave(actor, departmentMemberID, req); err != nil {
 		return err
 	}
 
@@ -76,8 +76,8 @@ func (s *leaveService) FetchDepartmentMemberLeaves(departmentID uint, filters *r
 	return data, nil
 }
 
-func (s *leaveService) UpdateLeaveStatus(leaveID uint, approvedBy uint, req *request.UpdateLeaveStatus) error {
-	if err := s.leaveRepository.UpdateLeaveStatus(leaveID, approvedBy, req); err != nil {
+func (s *leaveService) UpdateLeaveStatus(actor *request.AuditActor, leaveID uint, approvedBy uint, req *request.UpdateLeaveStatus) error {
+	if err := s.leaveRepository.UpdateLeaveStatus(actor, leaveID, approvedBy, req); err != nil {
 		return err
 	}
 
@@ -94,7 +94,7 @@ func (s *leaveService) FetchLeadAndHRLeaves(filters *request.CommonRequestWithDa
 	return data, nil
 }
 
-func (s *leaveService) UpdateLeaveRequest(leaveID uint, req *request.RequestLeave) error {
+func (s *leaveService) UpdateLeaveRequest(actor *request.AuditActor, leaveID uint, req *request.RequestLeave) error {
 	isLeaveRequestExists, err := s.leaveRepository.IsLeaveExistsWithID(leaveID)
 
 	if err != nil {
@@ -112,14 +112,14 @@ func (s *leaveService) UpdateLeaveRequest(leaveID uint, req *request.RequestLeav
 		}
 	}
 
-	if err := s.leaveRepository.UpdateLeaveRequest(leaveID, req); err != nil {
+	if err := s.leaveRepository.UpdateLeaveRequest(actor, leaveID, req); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *leaveService) RemoveLeaveRequest(leaveID uint) error {
+func (s *leaveService) RemoveLeaveRequest(actor *request.AuditActor, leaveID uint) error {
 	isLeaveRequestExists, err := s.leaveRepository.IsLeaveExistsWithID(leaveID)
 
 	if err != nil {
@@ -140,7 +140,7 @@ func (s *leaveService) RemoveLeaveRequest(leaveID uint) error {
 		return fmt.Errorf("approved leave request cannot be removed")
 	}
 
-	if err := s.leaveRepository.RemoveLeaveRequest(leaveID); err != nil {
+	if err := s.leaveRepository.RemoveLeaveRequest(actor, leaveID); err != nil {
 		return err
 	}
 
@@ -0,0 +1,344 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// letterPlaceholderPattern matches placeholders such as {{fullName}} in letter templates.
+var letterPlaceholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// letterPlaceholders lists the names templates may use. See letterValues for their sources.
+var letterPlaceholders = []string{"firstName", "lastName", "fullName", "code", "email", "mobile",
+	"designation", "dateOfJoining", "experience", "address", "city", "department", "manager",
+	"lastWorkingDate", "today"}
+
+const letterDateLayout = "02 January 2006"
+
+type letterService struct {
+	letterRepository     domain.LetterRepository
+	documentRepository   domain.DocumentRepository
+	departmentRepository domain.DepartmentRepository
+}
+
+func NewLetterService(letterRepository domain.LetterRepository, documentRepository domain.DocumentRepository,
+	departmentRepository domain.DepartmentRepository) domain.LetterService {
+	return &letterService{letterRepository, documentRepository, departmentRepository}
+}
+
+func (s *letterService) CreateLetterTemplate(createdBy uint, req *request.CreateLetterTemplate) error {
+	isNameExists, err := s.letterRepository.IsLetterTemplateNameExists(req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isNameExists {
+		return apperror.UniqueKeyError("letter template name")
+	}
+
+	if err := s.validateLetterTemplate(req); err != nil {
+		return err
+	}
+
+	if err := s.letterRepository.CreateLetterTemplate(createdBy, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *letterService) FetchLetterTemplates() ([]response.FetchLetterTemplates, error) {
+	data, err := s.letterRepository.FetchLetterTemplates()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *letterService) UpdateLetterTemplate(letterTemplateID, updatedBy uint, req *request.UpdateLetterTemplate) error {
+	letterTemplate, err := s.letterRepository.GetLetterTemplateByID(letterTemplateID)
+
+	if err != nil {
+		return err
+	}
+
+	if letterTemplate == nil {
+		return apperror.DataNotFoundError("letter template")
+	}
+
+	isNameExists, err := s.letterRepository.IsLetterTemplateNameExistsExceptID(letterTemplateID, req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isNameExists {
+		return apperror.UniqueKeyError("letter template name")
+	}
+
+	if err := s.validateLetterTemplate(&req.CreateLetterTemplate); err != nil {
+		return err
+	}
+
+	if err := s.letterRepository.UpdateLetterTemplate(letterTemplateID, updatedBy, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *letterService) RemoveLetterTemplate(letterTemplateID uint) error {
+	letterTemplate, err := s.letterRepository.GetLetterTemplateByID(letterTemplateID)
+
+	if err != nil {
+		return err
+	}
+
+	if letterTemplate == nil {
+		return apperror.DataNotFoundError("letter template")
+	}
+
+	if err := s.letterRepository.RemoveLetterTemplate(letterTemplateID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *letterService) FetchLetterTemplateVersions(letterTemplateID uint) ([]response.FetchLetterTemplateVersions, error) {
+	letterTemplate, err := s.letterRepository.GetLetterTemplateByID(letterTemplateID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if letterTemplate == nil {
+		return nil, apperror.DataNotFoundError("letter template")
+	}
+
+	data, err := s.letterRepository.FetchLetterTemplateVersions(letterTemplateID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *letterService) RenderLetter(letterTemplateID, userID uint) (*response.RenderedLetter, error) {
+	letterTemplate, err := s.letterRepository.GetLetterTemplateByID(letterTemplateID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if letterTemplate == nil {
+		return nil, apperror.DataNotFoundError("letter template")
+	}
+
+	return s.renderLetter(letterTemplate, userID)
+}
+
+// RenderLetters renders the template for every recipient. Employees whose data cannot fill
+// the template are reported in the result instead of failing the whole run.
+func (s *letterService) RenderLetters(req *request.GenerateLetters) ([]response.RenderedLetter, *response.GenerateLetters, error) {
+	letterTemplate, err := s.letterRepository.GetLetterTemplateByID(req.LetterTemplateID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if letterTemplate == nil {
+		return nil, nil, apperror.DataNotFoundError("letter template")
+	}
+
+	userIDs := req.UserIDs
+
+	if req.DepartmentID != 0 {
+		isDepartmentExists, err := s.departmentRepository.IsDepartmentExists(req.DepartmentID)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !isDepartmentExists {
+			return nil, nil, apperror.DataNotFoundError("department")
+		}
+
+		if userIDs, err = s.letterRepository.FetchDepartmentUserIDs(req.DepartmentID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil, fmt.Errorf("userIDs or departmentID is required")
+	}
+
+	var letters []response.RenderedLetter
+	result := &response.GenerateLetters{Errors: []string{}}
+
+	for _, userID := range userIDs {
+		letter, err := s.renderLetter(letterTemplate, userID)
+
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("user %d: %v", userID, err))
+			continue
+		}
+
+		letters = append(letters, *letter)
+	}
+
+	result.GeneratedCount = len(letters)
+
+	return letters, result, nil
+}
+
+func (s *letterService) StoreLetters(actor *request.AuditActor, uploadedBy uint, letters []response.RenderedLetter) error {
+	if len(letters) == 0 {
+		return nil
+	}
+
+	return s.documentRepository.CreateGeneratedLetters(actor, uploadedBy, letters)
+}
+
+func (s *letterService) renderLetter(letterTemplate *response.LetterTemplate, userID uint) (*response.RenderedLetter, error) {
+	data, err := s.letterRepository.GetLetterData(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	values := letterValues(data)
+
+	title, err := fillLetterPlaceholders(letterTemplate.Title, values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := fillLetterPlaceholders(letterTemplate.Body, values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content := utils.RenderPDF(title, body)
+
+	return &response.RenderedLetter{
+		UserID:             userID,
+		DocumentCategoryID: letterTemplate.DocumentCategoryID,
+		Content:            content,
+		Document: response.UploadedDocument{
+			FileName:                letterTemplate.Name + ".pdf",
+			ContentType:             "application/pdf",
+			FileSize:                int64(len(content)),
+			LetterTemplateVersionID: &letterTemplate.VersionID,
+		},
+	}, nil
+}
+
+func (s *letterService) validateLetterTemplate(req *request.CreateLetterTemplate) error {
+	category, err := s.documentRepository.GetDocumentCategoryByID(req.DocumentCategoryID)
+
+	if err != nil {
+		return err
+	}
+
+	if category == nil {
+		return apperror.DataNotFoundError("document category")
+	}
+
+	for _, match := range letterPlaceholderPattern.FindAllStringSubmatch(req.Title+"\n"+req.Body, -1) {
+		if !slices.Contains(letterPlaceholders, match[1]) {
+			return fmt.Errorf("unknown placeholder %s, available placeholders are %s", match[0],
+				strings.Join(letterPlaceholders, ", "))
+		}
+	}
+
+	return nil
+}
+
+func letterValues(data *response.LetterData) map[string]*string {
+	fullName := data.FirstName + " " + data.LastName
+	today := time.Now().Format(letterDateLayout)
+
+	var experience *string
+	if data.Experience != nil {
+		years := strconv.Itoa(int(*data.Experience))
+		experience = &years
+	}
+
+	return map[string]*string{
+		"firstName":       &data.FirstName,
+		"lastName":        &data.LastName,
+		"fullName":        &fullName,
+		"code":            &data.Code,
+		"email":           &data.Email,
+		"mobile":          &data.Mobile,
+		"designation":     data.Designation,
+		"dateOfJoining":   formatLetterDate(data.DateOfJoining),
+		"experience":      experience,
+		"address":         data.Address,
+		"city":            data.City,
+		"department":      data.Department,
+		"manager":         data.Manager,
+		"lastWorkingDate": formatLetterDate(data.LastWorkingDate),
+		"today":           &today,
+	}
+}
+
+// fillLetterPlaceholders replaces every placeholder and fails listing the ones the employee
+// has no value for, rather than issuing a letter with blanks.
+func fillLetterPlaceholders(text string, values map[string]*string) (string, error) {
+	var missing []string
+
+	filled := letterPlaceholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := letterPlaceholderPattern.FindStringSubmatch(placeholder)[1]
+
+		value := values[name]
+		if value == nil || *value == "" {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return placeholder
+		}
+
+		return *value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for %s", strings.Join(missing, ", "))
+	}
+
+	return filled, nil
+}
+
+func formatLetterDate(date *string) *string {
+	if date == nil {
+		return nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", *date)
+	if err != nil {
+		return date
+	}
+
+	formatted := parsed.Format(letterDateLayout)
+	return &formatted
+}
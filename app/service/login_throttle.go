@@ -0,0 +1,197 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/infrastructure/config"
+	"fmt"
+	"log"
+	"time"
+)
+
+// recentAuthLogCount is how many events FetchLockoutStatus returns.
+const recentAuthLogCount = 20
+
+func (s *authService) FetchLockoutStatus(userID uint) (*response.LockoutStatus, error) {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isUserExists {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	throttle, err := s.loginThrottleRepository.GetLoginThrottle(accountSubject(userID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	recentEvents, err := s.loginThrottleRepository.FetchUserAuthLogs(userID, recentAuthLogCount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := &response.LockoutStatus{RecentEvents: recentEvents}
+
+	if throttle != nil {
+		data.Locked = isLocked(throttle)
+		data.FailedAttempts = throttle.FailedAttempts
+		data.LockoutCount = throttle.LockoutCount
+		if data.Locked {
+			data.LockedUntil = throttle.LockedUntil
+		}
+	}
+
+	return data, nil
+}
+
+// UnlockUser clears the account's lockout and failed attempts. IP lockouts are left alone as
+// they are not tied to one employee and expire by themselves.
+func (s *authService) UnlockUser(userID, unlockedBy uint, device *request.SessionDevice) error {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := s.loginThrottleRepository.ResetLoginThrottle(accountSubject(userID)); err != nil {
+		return err
+	}
+
+	s.logAuthEvent(constant.AccountUnlocked, &userID, "", device, fmt.Sprintf("unlocked by user %d", unlockedBy))
+
+	return nil
+}
+
+// checkLockout fails while the client IP or, when known, the account is locked.
+func (s *authService) checkLockout(userID *uint, email string, device *request.SessionDevice) error {
+	subjects := []string{ipSubject(device.IPAddress)}
+	if userID != nil {
+		subjects = append(subjects, accountSubject(*userID))
+	}
+
+	for _, subject := range subjects {
+		throttle, err := s.loginThrottleRepository.GetLoginThrottle(subject)
+
+		if err != nil {
+			return err
+		}
+
+		if throttle != nil && isLocked(throttle) {
+			s.logAuthEvent(constant.LoginBlocked, userID, email, device, subject+" is locked")
+			return apperror.TooManyAttemptsError(time.Until(*throttle.LockedUntil))
+		}
+	}
+
+	return nil
+}
+
+// recordFailedAttempt logs the failure and counts it against the client IP and, when known,
+// the account, locking either once it reaches its limit.
+func (s *authService) recordFailedAttempt(event constant.AuthEvent, userID *uint, email string,
+	device *request.SessionDevice, detail string) error {
+	s.logAuthEvent(event, userID, email, device, detail)
+
+	if userID != nil {
+		if err := s.countFailedAttempt(accountSubject(*userID), config.Config.Lockout.MaxAccountAttempts,
+			constant.AccountLocked, userID, email, device); err != nil {
+			return err
+		}
+	}
+
+	return s.countFailedAttempt(ipSubject(device.IPAddress), config.Config.Lockout.MaxIPAttempts,
+		constant.IPLocked, userID, email, device)
+}
+
+func (s *authService) countFailedAttempt(subject string, maxAttempts int, lockEvent constant.AuthEvent,
+	userID *uint, email string, device *request.SessionDevice) error {
+	lockout := config.Config.Lockout
+	now := time.Now()
+
+	throttle, err := s.loginThrottleRepository.RecordFailedAttempt(subject, now.Add(-lockout.AttemptWindow),
+		now.Add(-lockout.MaxDuration))
+
+	if err != nil {
+		return err
+	}
+
+	if throttle.FailedAttempts < maxAttempts {
+		return nil
+	}
+
+	duration := lockoutDuration(throttle.LockoutCount)
+
+	if err := s.loginThrottleRepository.LockSubject(throttle.ID, now.Add(duration)); err != nil {
+		return err
+	}
+
+	s.logAuthEvent(lockEvent, userID, email, device,
+		fmt.Sprintf("%s locked for %s after %d failed attempts", subject, duration, throttle.FailedAttempts))
+
+	return nil
+}
+
+// logAuthEvent writes the event to the application log and the AuthLog table. A failed
+// insert is only logged so that it cannot block signing in.
+func (s *authService) logAuthEvent(event constant.AuthEvent, userID *uint, email string,
+	device *request.SessionDevice, detail string) {
+	user := "-"
+	if userID != nil {
+		user = fmt.Sprint(*userID)
+	}
+
+	log.Printf("auth: %s user=%s email=%q ip=%s %s", event, user, email, device.IPAddress, detail)
+
+	data := &schema.AuthLog{
+		UserID:    userID,
+		IPAddress: device.IPAddress,
+		Event:     string(event),
+	}
+
+	if email != "" {
+		data.Email = &email
+	}
+
+	if detail != "" {
+		data.Detail = &detail
+	}
+
+	if err := s.loginThrottleRepository.CreateAuthLog(data); err != nil {
+		log.Printf("auth: failed to record %s event: %v", event, err)
+	}
+}
+
+// lockoutDuration doubles the base lockout for every earlier lockout, up to the maximum.
+func lockoutDuration(lockoutCount int) time.Duration {
+	lockout := config.Config.Lockout
+	duration := lockout.BaseDuration
+
+	for i := 0; i < lockoutCount && duration < lockout.MaxDuration; i++ {
+		duration *= 2
+	}
+
+	return min(duration, lockout.MaxDuration)
+}
+
+func isLocked(throttle *schema.LoginThrottle) bool {
+	return throttle.LockedUntil != nil && throttle.LockedUntil.After(time.Now())
+}
+
+func accountSubject(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+func ipSubject(ipAddress string) string {
+	return "ip:" + ipAddress
+}
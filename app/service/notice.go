@@ -19,7 +19,7 @@ func NewNoticeService(noticeRepository domain.NoticeRepository,
 	return &noticeService{noticeRepository, departmentRepository}
 }
 
-func (s *noticeService) ApplyNotice(departmentMemberID uint, req *request.ApplyNotice) error {
+func (s *noticeService) ApplyNotice(actor *request.AuditActor, departmentMemberID uint, req *request.ApplyNotice) error {
 	isDepartmentMemberExists, err := s.departmentRepository.IsDepartmentMemberExists(departmentMemberID)
 
 	if err != nil {
@@ -30,7 +30,7 @@ func (s *noticeService) ApplyNotice(departmentMemberID uint, req *request.ApplyN
 		return apperror.DataNotFoundError("user")
 	}
 
-	if err := s.noticeRepository.ApplyNotice(departmentMemberID, req); err != nil {
+	if err := s.noticeRepository.ApplyNotice(actor, departmentMemberID, req); err != nil {
 		return err
 	}
 
@@ -67,7 +67,7 @@ func (s *noticeService) FetchNotice(departmentMemberID uint) (*response.FetchAct
 	return data, nil
 }
 
-func (s *noticeService) ApproveNotice(approvedBy uint, req *request.ApproveNotice) error {
+func (s *noticeService) ApproveNotice(actor *request.AuditActor, approvedBy uint, req *request.ApproveNotice) error {
 	isDepartmentMemberExists, err := s.departmentRepository.IsDepartmentMemberExists(uint(req.DepartmentMemberID))
 
 	if err != nil {
@@ -88,7 +88,7 @@ func (s *noticeService) ApproveNotice(approvedBy uint, req *request.ApproveNotic
 		return fmt.Errorf("notice not found for the user")
 	}
 
-	if err := s.noticeRepository.ApproveNotice(uint(req.DepartmentMemberID), approvedBy, req); err != nil {
+	if err := s.noticeRepository.ApproveNotice(actor, uint(req.DepartmentMemberID), approvedBy, req); err != nil {
 		return err
 	}
 
@@ -0,0 +1,251 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errSingleSignOnNotConfigured = errors.New("single sign-on is not configured")
+
+// StartOIDCLogin stores a new login state and returns the provider URL the client should open.
+func (s *authService) StartOIDCLogin() (*response.OIDCAuthorization, error) {
+	if s.identityProvider == nil {
+		return nil, errSingleSignOnNotConfigured
+	}
+
+	var loginState schema.OIDCLoginState
+
+	for _, value := range []*string{&loginState.State, &loginState.Nonce, &loginState.CodeVerifier} {
+		token, err := utils.GenerateRefreshToken()
+
+		if err != nil {
+			return nil, err
+		}
+
+		*value = token
+	}
+
+	loginState.ExpiresAt = time.Now().Add(config.Config.OIDC.StateValidity)
+
+	authorizationURL, err := s.identityProvider.AuthorizationURL(loginState.State, loginState.Nonce,
+		utils.PKCEChallenge(loginState.CodeVerifier))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.oidcRepository.CreateOIDCLoginState(&loginState); err != nil {
+		return nil, err
+	}
+
+	return &response.OIDCAuthorization{AuthorizationURL: authorizationURL}, nil
+}
+
+// CompleteOIDCLogin exchanges the code from the provider's callback and starts a session for
+// the matching user. Users are matched by their linked identity, then by verified email, and
+// are created when just-in-time provisioning is on. EMS two-factor is skipped, as the
+// provider is responsible for the second factor.
+func (s *authService) CompleteOIDCLogin(req *request.CompleteOIDCLogin,
+	device *request.SessionDevice) (*response.FetchUserByEmail, error) {
+	if s.identityProvider == nil {
+		return nil, errSingleSignOnNotConfigured
+	}
+
+	if err := s.checkLockout(nil, "", device); err != nil {
+		return nil, err
+	}
+
+	loginState, err := s.oidcRepository.UseOIDCLoginState(req.State)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loginState == nil {
+		return nil, apperror.InvalidSessionError("the sign in request is unknown, expired or already used")
+	}
+
+	identity, err := s.identityProvider.Exchange(req.Code, loginState.CodeVerifier, loginState.Nonce)
+
+	if err != nil {
+		return nil, s.singleSignOnFailed(nil, "", device, err.Error())
+	}
+
+	identity.Email = strings.TrimSpace(identity.Email)
+
+	userID, err := s.oidcRepository.GetUserIDByIdentity(identity.Issuer, identity.Subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userID == 0 {
+		if userID, err = s.linkOIDCIdentity(identity, device); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.userRepository.GetLoginUserByID(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	if err := s.checkLockout(&user.ID, user.Email, device); err != nil {
+		return nil, err
+	}
+
+	if err := checkDepartment(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.completeLogin(user, constant.SSOLogin, device); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// linkOIDCIdentity links a first time single sign-on identity to the user with the same
+// verified email, or provisions a new user, and returns the user's ID.
+func (s *authService) linkOIDCIdentity(identity *response.OIDCIdentity, device *request.SessionDevice) (uint, error) {
+	if identity.Email == "" {
+		return 0, s.singleSignOnFailed(nil, "", device, "the identity provider did not return an email")
+	}
+
+	// An unverified address could belong to anyone, so it must not take over an account.
+	if !identity.EmailVerified {
+		return 0, s.singleSignOnFailed(nil, identity.Email, device, "the email is not verified by the identity provider")
+	}
+
+	userIdentity := &schema.UserIdentity{Issuer: identity.Issuer, Subject: identity.Subject, Email: identity.Email}
+
+	user, err := s.userRepository.GetUserByEmail(identity.Email)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if user == nil {
+		return s.provisionOIDCUser(identity, userIdentity, device)
+	}
+
+	linkedIdentity, err := s.oidcRepository.GetUserIdentity(user.ID, identity.Issuer)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if linkedIdentity != nil {
+		return 0, s.singleSignOnFailed(nil, identity.Email, device,
+			"the account is already linked to another identity")
+	}
+
+	userIdentity.UserID = user.ID
+
+	if err := s.oidcRepository.CreateUserIdentity(userIdentity); err != nil {
+		return 0, err
+	}
+
+	s.logAuthEvent(constant.SSOIdentityLinked, &user.ID, identity.Email, device,
+		fmt.Sprintf("linked %s subject %s", identity.Issuer, identity.Subject))
+
+	return user.ID, nil
+}
+
+func (s *authService) provisionOIDCUser(identity *response.OIDCIdentity, userIdentity *schema.UserIdentity,
+	device *request.SessionDevice) (uint, error) {
+	oidcConfig := config.Config.OIDC
+
+	if !oidcConfig.JITProvisioning {
+		return 0, s.singleSignOnFailed(nil, identity.Email, device, "no account exists for this email, please contact HR")
+	}
+
+	isRoleExists, err := s.userRepository.IsRoleExists(oidcConfig.DefaultRoleID)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !isRoleExists {
+		return 0, apperror.DataNotFoundError("role")
+	}
+
+	// The password is random and never shown, the user can set one through forgot password.
+	password, err := utils.GenerateRefreshToken()
+
+	if err != nil {
+		return 0, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+
+	if err != nil {
+		return 0, err
+	}
+
+	firstName, lastName := oidcUserName(identity)
+
+	req := &request.CreateUser{
+		RoleID:    oidcConfig.DefaultRoleID,
+		FirstName: firstName,
+		LastName:  lastName,
+		Code:      "SSO-" + strings.ToUpper(utils.HashToken(identity.Issuer + " " + identity.Subject)[:8]),
+		Email:     identity.Email,
+	}
+
+	// No one is signed in yet, so the account is recorded as created by the system.
+	actor := &request.AuditActor{Type: constant.SystemActor, IPAddress: device.IPAddress}
+
+	userID, err := s.oidcRepository.CreateProvisionedUser(actor, req, hashedPassword, userIdentity)
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.logAuthEvent(constant.SSOUserProvisioned, &userID, identity.Email, device,
+		fmt.Sprintf("provisioned from %s subject %s", identity.Issuer, identity.Subject))
+
+	return userID, nil
+}
+
+// singleSignOnFailed logs the failure and counts it against the client IP.
+func (s *authService) singleSignOnFailed(userID *uint, email string, device *request.SessionDevice, reason string) error {
+	if err := s.recordFailedAttempt(constant.SSOLoginFailed, userID, email, device, reason); err != nil {
+		return err
+	}
+
+	return apperror.SingleSignOnError(reason)
+}
+
+// oidcUserName prefers the given and family name claims and falls back to the full name, then
+// to the email's local part.
+func oidcUserName(identity *response.OIDCIdentity) (string, string) {
+	firstName, lastName := identity.GivenName, identity.FamilyName
+
+	if firstName == "" {
+		names := strings.Fields(identity.Name)
+		if len(names) > 0 {
+			firstName, lastName = names[0], strings.Join(names[1:], " ")
+		}
+	}
+
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	return firstName, lastName
+}
@@ -19,7 +19,7 @@ func NewPermissionService(permissionRepository domain.PermissionRepository, depa
 	return &permissionService{permissionRepository, departmentRepository, userRepository}
 }
 
-func (s *permissionService) RequestPermission(departmentMemberID uint, req *request.RequestPermission) error {
+func (s *permissionService) RequestPermission(actor *request.AuditActor, departmentMemberID uint, req *request.RequestPermission) error {
 	var dateFilters request.DateFilters
 
 	isDepartmentMemberExists, err := s.departmentRepository.IsDepartmentMemberExists(departmentMemberID)
@@ -75,7 +75,7 @@ func (s *permissionService) RequestPermission(departmentMemberID uint, req *requ
 		return fmt.Errorf(`last permission request is in the pending state. please contact the Manager`)
 	}
 
-	if err := s.permissionRepository.RequestPermission(departmentMemberID, req); err != nil {
+	if err := s.permissionRepository.RequestPermission(actor, departmentMemberID, req); err != nil {
 		return err
 	}
 
@@ -102,15 +102,15 @@ func (s *permissionService) FetchDepartmentMemberPermissions(departmentID uint,
 	return data, nil
 }
 
-func (s *permissionService) UpdatePermissionStatus(permissionID uint, approvedBy uint, req *request.UpdatePermissionStatus) error {
-	if err := s.permissionRepository.UpdatePermissionStatus(permissionID, approvedBy, req); err != nil {
+func (s *permissionService) UpdatePermissionStatus(actor *request.AuditActor, permissionID uint, approvedBy uint, req *request.UpdatePermissionStatus) error {
+	if err := s.permissionRepository.UpdatePermissionStatus(actor, permissionID, approvedBy, req); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *permissionService) UpdatePermissionRequest(departmentMemberID uint, permissionID uint, req *request.RequestPermission) error {
+func (s *permissionService) UpdatePermissionRequest(actor *request.AuditActor, departmentMemberID uint, permissionID uint, req *request.RequestPermission) error {
 	isPermissionExists, err := s.permissionRepository.IsPermissionExistWithID(permissionID)
 
 	if err != nil {
@@ -157,14 +157,14 @@ func (s *permissionService) UpdatePermissionRequest(departmentMemberID uint, per
 		return err
 	}
 
-	if err := s.permissionRepository.UpdatePermissionRequest(permissionID, req); err != nil {
+	if err := s.permissionRepository.UpdatePermissionRequest(actor, permissionID, req); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *permissionService) RemovePermissionRequest(permissionID uint) error {
+func (s *permissionService) RemovePermissionRequest(actor *request.AuditActor, permissionID uint) error {
 	isPermissionExists, err := s.permissionRepository.IsPermissionExistWithID(permissionID)
 
 	if err != nil {
@@ -185,7 +185,7 @@ func (s *permissionService) RemovePermissionRequest(permissionID uint) error {
 		return fmt.Errorf("approved permission request cannot be removed")
 	}
 
-	if err := s.permissionRepository.RemovePermissionRequest(permissionID); err != nil {
+	if err := s.permissionRepository.RemovePermissionRequest(actor, permissionID); err != nil {
 		return err
 	}
 
@@ -0,0 +1,343 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/utils"
+	"encoding/json"
+	"fmt"
+)
+
+// Profile fields employees may change themselves. Sensitive fields need a supporting document.
+var (
+	profileChangeFields          = []string{"address", "city", "mobile", "bankAccountNumber", "ifscCode"}
+	profileChangeSensitiveFields = map[string]bool{"bankAccountNumber": true, "ifscCode": true}
+)
+
+type profileChangeService struct {
+	profileChangeRepository domain.ProfileChangeRepository
+	userRepository          domain.UserRepository
+}
+
+func NewProfileChangeService(profileChangeRepository domain.ProfileChangeRepository,
+	userRepository domain.UserRepository) domain.ProfileChangeService {
+	return &profileChangeService{profileChangeRepository, userRepository}
+}
+
+func (s *profileChangeService) RequestProfileChange(userID uint, req *request.RequestProfileChange, document *response.UploadedDocument) error {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	changes := profileChangesFromRequest(req)
+
+	if len(changes) == 0 {
+		return fmt.Errorf("no profile changes provided")
+	}
+
+	for field := range changes {
+		if profileChangeSensitiveFields[field] && document == nil {
+			return fmt.Errorf("supporting document is required to change %s", field)
+		}
+	}
+
+	isPendingExists, err := s.profileChangeRepository.IsProfileChangeExistsWithoutApproval(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if isPendingExists {
+		return fmt.Errorf("last profile change request is in the pending state, please contact HR")
+	}
+
+	payload, err := json.Marshal(changes)
+
+	if err != nil {
+		return err
+	}
+
+	// Proposed values may hold bank details, so they are sealed like the UserDetails columns.
+	encryptedChanges, err := utils.EncryptPII(string(payload))
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.profileChangeRepository.RequestProfileChange(userID, encryptedChanges, document); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *profileChangeService) FetchOwnProfileChangeRequests(userID uint, filters *request.CommonRequest) (*utils.PaginationResponse, error) {
+	data, totalCount, err := s.profileChangeRepository.FetchOwnProfileChangeRequests(userID, filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.buildProfileChangeDiffs(data); err != nil {
+		return nil, err
+	}
+
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
+}
+
+func (s *profileChangeService) FetchPendingProfileChangeRequests(filters *request.CommonRequest) (*utils.PaginationResponse, error) {
+	data, totalCount, err := s.profileChangeRepository.FetchPendingProfileChangeRequests(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.buildProfileChangeDiffs(data); err != nil {
+		return nil, err
+	}
+
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
+}
+
+func (s *profileChangeService) UpdateProfileChangeStatus(actor *request.AuditActor, requestID, approvedBy uint,
+	req *request.UpdateProfileChangeStatus) error {
+	changeRequest, err := s.profileChangeRepository.GetProfileChangeRequestByID(requestID)
+
+	if err != nil {
+		return err
+	}
+
+	if changeRequest == nil {
+		return apperror.DataNotFoundError("profile change request")
+	}
+
+	if changeRequest.IsApproved != nil {
+		return fmt.Errorf("profile change request is already reviewed")
+	}
+
+	var changes *request.ApplyProfileChange
+
+	if req.IsApproved {
+		if changes, err = s.profileChangeApplication(changeRequest); err != nil {
+			return err
+		}
+	}
+
+	// The request is claimed and its changes applied together, so that it is applied once however
+	// many reviewers approve it at the same time, and not at all if saving the changes fails.
+	isUpdated, err := s.profileChangeRepository.UpdateProfileChangeStatus(actor, requestID, approvedBy, req, changes)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUpdated {
+		return fmt.Errorf("profile change request is already reviewed")
+	}
+
+	return nil
+}
+
+// profileChangeApplication merges the approved values into the user's current record. The
+// uniqueness check HR's edits go through still applies to the mobile number.
+func (s *profileChangeService) profileChangeApplication(changeRequest *schema.ProfileChangeRequest) (*request.ApplyProfileChange, error) {
+	changes, err := decodeProfileChanges(changeRequest.Changes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.userRepository.FetchUserDetails(&request.FetchUserDetails{UserID: changeRequest.UserID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if current == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	details, err := profileChangeDetails(current, changes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	application := &request.ApplyProfileChange{UserID: changeRequest.UserID, Details: details}
+
+	if mobile, ok := changes["mobile"]; ok {
+		isMobileExists, err := s.userRepository.IsMobileNumberExistsExceptID(changeRequest.UserID, mobile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isMobileExists {
+			return nil, apperror.UniqueKeyError("mobile")
+		}
+
+		application.User = &request.UpdateUser{
+			FirstName: current.FirstName,
+			LastName:  current.LastName,
+			Code:      current.Code,
+			Email:     current.Email,
+			Mobile:    mobile,
+		}
+	}
+
+	return application, nil
+}
+
+// profileChangeDetails returns the user's details with the changes merged in, or nil when only
+// the mobile number changes.
+func profileChangeDetails(current *response.FetchUserDetails, changes map[string]string) (*request.UpdateUserDetails, error) {
+	hasDetailChanges := false
+	for field := range changes {
+		if field != "mobile" {
+			hasDetailChanges = true
+		}
+	}
+
+	if !hasDetailChanges {
+		return nil, nil
+	}
+
+	if current.DOB == nil || current.DateOfJoining == nil {
+		return nil, fmt.Errorf("user details are not filled yet, please update them before approving")
+	}
+
+	dob, isValidDate := utils.IsValidDate(*current.DOB)
+	if !isValidDate {
+		return nil, fmt.Errorf("invalid date of birth: %s", *current.DOB)
+	}
+
+	dateOfJoining, isValidDate := utils.IsValidDate(*current.DateOfJoining)
+	if !isValidDate {
+		return nil, fmt.Errorf("invalid date of joining: %s", *current.DateOfJoining)
+	}
+
+	return &request.UpdateUserDetails{
+		UserID:            current.UserID,
+		DateOfJoining:     *dateOfJoining,
+		DOB:               *dob,
+		Experience:        valueOrEmpty(current.Experience),
+		Designation:       valueOrEmpty(current.Designation),
+		PanNumber:         valueOrEmpty(current.PanNumber),
+		AadharNumber:      valueOrEmpty(current.AadharNumber),
+		BankAccountNumber: valueOrDefault(changes, "bankAccountNumber", current.BankAccountNumber),
+		IfscCode:          valueOrDefault(changes, "ifscCode", current.IfscCode),
+		City:              valueOrDefault(changes, "city", current.City),
+		Address:           valueOrDefault(changes, "address", current.Address),
+		Degree:            valueOrEmpty(current.Degree),
+		College:           valueOrEmpty(current.College),
+	}, nil
+}
+
+func (s *profileChangeService) buildProfileChangeDiffs(data []response.FetchProfileChangeRequests) error {
+	for i := range data {
+		changes, err := decodeProfileChanges(data[i].Changes)
+
+		if err != nil {
+			return err
+		}
+
+		current, err := s.userRepository.FetchUserDetails(&request.FetchUserDetails{UserID: data[i].UserID})
+
+		if err != nil {
+			return err
+		}
+
+		for _, field := range profileChangeFields {
+			proposed, ok := changes[field]
+			if !ok {
+				continue
+			}
+
+			data[i].Diff = append(data[i].Diff, response.ProfileFieldChange{
+				Field:         field,
+				CurrentValue:  currentProfileValue(current, field),
+				ProposedValue: proposed,
+			})
+		}
+	}
+
+	return nil
+}
+
+func profileChangesFromRequest(req *request.RequestProfileChange) map[string]string {
+	changes := make(map[string]string)
+
+	for field, value := range map[string]*string{
+		"address":           req.Address,
+		"city":              req.City,
+		"mobile":            req.Mobile,
+		"bankAccountNumber": req.BankAccountNumber,
+		"ifscCode":          req.IfscCode,
+	} {
+		if value != nil && *value != "" {
+			changes[field] = *value
+		}
+	}
+
+	return changes
+}
+
+func decodeProfileChanges(encrypted string) (map[string]string, error) {
+	payload, err := utils.DecryptPII(encrypted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var changes map[string]string
+
+	if err := json.Unmarshal([]byte(payload), &changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func currentProfileValue(current *response.FetchUserDetails, field string) *string {
+	if current == nil {
+		return nil
+	}
+
+	switch field {
+	case "address":
+		return current.Address
+	case "city":
+		return current.City
+	case "mobile":
+		return &current.Mobile
+	case "bankAccountNumber":
+		return current.BankAccountNumber
+	case "ifscCode":
+		return current.IfscCode
+	}
+
+	return nil
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func valueOrDefault(changes map[string]string, field string, current *string) string {
+	if value, ok := changes[field]; ok {
+		return value
+	}
+	return valueOrEmpty(current)
+}
@@ -1,8 +1,14 @@
 package service
 
 import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
 	"ems/app/model/response"
+	"ems/app/model/schema"
 	"ems/domain"
+	"fmt"
+	"slices"
 )
 
 type roleService struct {
@@ -22,3 +28,176 @@ func (s *roleService) FetchRoles() ([]response.FetchRoles, error) {
 
 	return data, nil
 }
+
+func (s *roleService) FetchPermissions() ([]response.FetchPermissions, error) {
+	data, err := s.roleRepository.FetchPermissions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *roleService) CreateRole(req *request.CreateRole) error {
+	isNameExists, err := s.roleRepository.IsRoleNameExists(req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isNameExists {
+		return apperror.UniqueKeyError("role name")
+	}
+
+	permissionIDs, err := s.permissionIDs(req.Permissions)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleRepository.CreateRole(req, permissionIDs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRole replaces the role's permissions. Built-in roles keep their names because the
+// leave, permission and notice workflows are tied to them; Admin cannot be changed at all.
+func (s *roleService) UpdateRole(roleID uint, req *request.UpdateRole) error {
+	role, err := s.getEditableRole(roleID)
+
+	if err != nil {
+		return err
+	}
+
+	if role.IsSystem && role.Name != req.Name {
+		return fmt.Errorf("built-in roles cannot be renamed")
+	}
+
+	isNameExists, err := s.roleRepository.IsRoleNameExistsExceptID(roleID, req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isNameExists {
+		return apperror.UniqueKeyError("role name")
+	}
+
+	permissionIDs, err := s.permissionIDs(req.Permissions)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleRepository.UpdateRole(roleID, req, permissionIDs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRoleTwoFactor is allowed for every role, Admin included. Users of a role that
+// requires two-factor and have not enrolled yet are asked to enrol on their next login.
+func (s *roleService) UpdateRoleTwoFactor(roleID uint, req *request.UpdateRoleTwoFactor) error {
+	role, err := s.roleRepository.GetRoleByID(roleID)
+
+	if err != nil {
+		return err
+	}
+
+	if role == nil {
+		return apperror.DataNotFoundError("role")
+	}
+
+	if err := s.roleRepository.UpdateRoleTwoFactor(roleID, *req.RequireTwoFactor); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *roleService) RemoveRole(roleID uint) error {
+	role, err := s.getEditableRole(roleID)
+
+	if err != nil {
+		return err
+	}
+
+	if role.IsSystem {
+		return fmt.Errorf("built-in roles cannot be removed")
+	}
+
+	isRoleAssigned, err := s.roleRepository.IsRoleAssigned(roleID)
+
+	if err != nil {
+		return err
+	}
+
+	if isRoleAssigned {
+		return fmt.Errorf("role is assigned to users, reassign them before removing it")
+	}
+
+	if err := s.roleRepository.RemoveRole(roleID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *roleService) getEditableRole(roleID uint) (*schema.Role, error) {
+	role, err := s.roleRepository.GetRoleByID(roleID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return nil, apperror.DataNotFoundError("role")
+	}
+
+	if role.ID == uint(constant.Admin) {
+		return nil, fmt.Errorf("the admin role always has every permission and cannot be changed")
+	}
+
+	return role, nil
+}
+
+// permissionIDs resolves permission names, rejecting any that do not exist.
+func (s *roleService) permissionIDs(names []string) ([]uint, error) {
+	permissions, err := s.roleRepository.FetchPermissions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var permissionIDs []uint
+
+	for _, name := range names {
+		index := slices.IndexFunc(permissions, func(permission response.FetchPermissions) bool {
+			return permission.Name == name
+		})
+
+		if index == -1 {
+			return nil, apperror.DataNotFoundError("permission " + name)
+		}
+
+		if !slices.Contains(permissionIDs, permissions[index].ID) {
+			permissionIDs = append(permissionIDs, permissions[index].ID)
+		}
+	}
+
+	return permissionIDs, nil
+}
+
+// hasPermission reports whether the role has been granted the permission, as the Require
+// middleware does. Admin has every permission.
+func hasPermission(roleRepository domain.RoleRepository, roleID uint, permission constant.Permission) (bool, error) {
+	if roleID == uint(constant.Admin) {
+		return true, nil
+	}
+
+	return roleRepository.HasPermission(roleID, string(permission))
+}
@@ -0,0 +1,831 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/infrastructure/scim"
+	"ems/utils"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+type scimService struct {
+	scimRepository       domain.SCIMRepository
+	userRepository       domain.UserRepository
+	departmentRepository domain.DepartmentRepository
+	userService          domain.UserService
+	departmentService    domain.DepartmentService
+}
+
+// NewSCIMService builds the provisioning service. Removals go through the user and department
+// services so that SCIM follows the same rules as HR.
+func NewSCIMService(scimRepository domain.SCIMRepository, userRepository domain.UserRepository,
+	departmentRepository domain.DepartmentRepository, userService domain.UserService,
+	departmentService domain.DepartmentService) domain.SCIMService {
+	return &scimService{scimRepository, userRepository, departmentRepository, userService, departmentService}
+}
+
+func (s *scimService) FetchServiceProviderConfig() *response.SCIMServiceProviderConfig {
+	return &response.SCIMServiceProviderConfig{
+		Schemas:        []string{constant.SCIMServiceProviderConfigSchema},
+		Patch:          response.SCIMSupported{Supported: true},
+		Filter:         response.SCIMFilter{Supported: true, MaxResults: config.Config.SCIM.MaxResults},
+		ChangePassword: response.SCIMSupported{Supported: false},
+		AuthenticationSchemes: []response.SCIMAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer token",
+			Description: "The SCIM token configured in EMS, sent as a bearer token",
+		}},
+	}
+}
+
+func (s *scimService) FetchUsers(query *request.SCIMListQuery) (*response.SCIMListResponse, error) {
+	startIndex, count := listWindow(query)
+
+	records, totalCount, err := s.scimRepository.FetchSCIMUsers(query.Filter, startIndex-1, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]response.SCIMUser, 0, len(records))
+	for i := range records {
+		users = append(users, toSCIMUser(&records[i]))
+	}
+
+	return &response.SCIMListResponse{
+		Schemas:      []string{constant.SCIMListResponseSchema},
+		TotalResults: totalCount,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(users),
+		Resources:    users,
+	}, nil
+}
+
+func (s *scimService) FetchUser(userID uint) (*response.SCIMUser, error) {
+	record, err := s.fetchUserRecord(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user := toSCIMUser(record)
+
+	return &user, nil
+}
+
+// CreateUser provisions a user with the configured default role. A provider that sends a
+// removed user's email gets a conflict and is expected to reactivate that user instead.
+func (s *scimService) CreateUser(actor *request.AuditActor, req *request.SCIMUser) (*response.SCIMUser, error) {
+	user, err := s.userFields(nil, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Nobody knows the stored password. The user signs in with single sign-on or sets one
+	// through forgot password.
+	password, err := utils.GenerateRefreshToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := s.scimRepository.CreateSCIMUser(actor, user, config.Config.SCIM.DefaultRoleID, hashedPassword)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Active != nil && !*req.Active {
+		if err := s.userService.RemoveUser(actor, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.FetchUser(userID)
+}
+
+func (s *scimService) ReplaceUser(actor *request.AuditActor, userID uint, req *request.SCIMUser) (*response.SCIMUser, error) {
+	record, err := s.fetchUserRecord(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveUser(actor, record, req); err != nil {
+		return nil, err
+	}
+
+	return s.FetchUser(userID)
+}
+
+// PatchUser applies the operations to the user's current representation and saves the
+// result as a replace would.
+func (s *scimService) PatchUser(actor *request.AuditActor, userID uint, req *request.SCIMPatch) (*response.SCIMUser, error) {
+	record, err := s.fetchUserRecord(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var user request.SCIMUser
+
+	if err := applyPatch(toSCIMUser(record), req, &user); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveUser(actor, record, &user); err != nil {
+		return nil, err
+	}
+
+	return s.FetchUser(userID)
+}
+
+// DeactivateUser removes the user as HR would. The record stays readable, with active false,
+// as employee records are never deleted.
+func (s *scimService) DeactivateUser(actor *request.AuditActor, userID uint) error {
+	record, err := s.fetchUserRecord(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !record.IsActive {
+		return nil
+	}
+
+	return s.deactivateUser(actor, record)
+}
+
+func (s *scimService) fetchUserRecord(userID uint) (*response.SCIMUserRecord, error) {
+	record, err := s.scimRepository.FetchSCIMUser(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	return record, nil
+}
+
+func (s *scimService) saveUser(actor *request.AuditActor, record *response.SCIMUserRecord, req *request.SCIMUser) error {
+	user, err := s.userFields(record, req)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.scimRepository.UpdateSCIMUser(actor, record.ID, user); err != nil {
+		return err
+	}
+
+	if req.Active == nil || *req.Active == record.IsActive {
+		return nil
+	}
+
+	if !*req.Active {
+		return s.deactivateUser(actor, record)
+	}
+
+	isEmailExists, err := s.userRepository.IsEmailExistsExceptID(record.ID, user.Email)
+
+	if err != nil {
+		return err
+	}
+
+	if isEmailExists {
+		return apperror.UniqueKeyError("email")
+	}
+
+	if err := s.scimRepository.ReactivateUser(actor, record.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deactivateUser goes through RemoveUser, so mapped leads and HR have to be unmapped first.
+// Admin users are never deactivated, so that the provider cannot lock everyone out.
+func (s *scimService) deactivateUser(actor *request.AuditActor, record *response.SCIMUserRecord) error {
+	if record.RoleID == uint(constant.Admin) {
+		return apperror.InvalidValueError("admin users cannot be deactivated through SCIM")
+	}
+
+	return s.userService.RemoveUser(actor, record.ID)
+}
+
+// userFields maps a SCIM user onto the user's columns and checks that the email, mobile and
+// code stay unique. current is nil for a new user.
+func (s *scimService) userFields(current *response.SCIMUserRecord, req *request.SCIMUser) (*request.SaveSCIMUser, error) {
+	var userID uint
+	if current != nil {
+		userID = current.ID
+	}
+
+	email := strings.TrimSpace(req.UserName)
+
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, apperror.InvalidValueError("userName must be the user's email")
+	}
+
+	isEmailTaken, err := s.scimRepository.IsSCIMEmailTaken(userID, email)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isEmailTaken {
+		return nil, apperror.UniqueKeyError("userName")
+	}
+
+	user := &request.SaveSCIMUser{Email: email, ExternalID: req.ExternalID, Title: req.Title}
+
+	if req.Name != nil {
+		user.FirstName, user.LastName = strings.TrimSpace(req.Name.GivenName), strings.TrimSpace(req.Name.FamilyName)
+	}
+
+	if user.FirstName == "" {
+		user.FirstName, user.LastName, _ = strings.Cut(strings.TrimSpace(req.DisplayName), " ")
+	}
+
+	if user.FirstName == "" {
+		user.FirstName, _, _ = strings.Cut(email, "@")
+	}
+
+	user.Mobile = primaryValue(req.PhoneNumbers, "mobile")
+
+	if user.Mobile != "" && (current == nil || user.Mobile != current.Mobile) {
+		isMobileExists, err := s.userRepository.IsMobileNumberExistsExceptID(userID, user.Mobile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isMobileExists {
+			return nil, apperror.UniqueKeyError("phone number")
+		}
+	}
+
+	switch {
+	case req.Enterprise != nil && strings.TrimSpace(req.Enterprise.EmployeeNumber) != "":
+		user.Code = strings.TrimSpace(req.Enterprise.EmployeeNumber)
+	case current != nil:
+		user.Code = current.Code
+	default:
+		user.Code = "SCIM-" + strings.ToUpper(utils.HashToken(strings.ToLower(email))[:8])
+	}
+
+	if current == nil || user.Code != current.Code {
+		isUserCodeExists, err := s.userRepository.IsUserCodeExistsExceptID(userID, user.Code)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isUserCodeExists {
+			return nil, apperror.UniqueKeyError("employeeNumber")
+		}
+	}
+
+	return user, nil
+}
+
+func (s *scimService) FetchGroups(query *request.SCIMListQuery) (*response.SCIMListResponse, error) {
+	startIndex, count := listWindow(query)
+
+	records, totalCount, err := s.scimRepository.FetchSCIMGroups(query.Filter, startIndex-1, count)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var members []response.SCIMGroupMember
+
+	if !strings.Contains(strings.ToLower(query.ExcludedAttributes), "members") {
+		departmentIDs := make([]uint, 0, len(records))
+		for _, record := range records {
+			departmentIDs = append(departmentIDs, record.ID)
+		}
+
+		if members, err = s.scimRepository.FetchSCIMGroupMembers(departmentIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := make([]response.SCIMGroup, 0, len(records))
+	for i := range records {
+		groups = append(groups, toSCIMGroup(&records[i], members))
+	}
+
+	return &response.SCIMListResponse{
+		Schemas:      []string{constant.SCIMListResponseSchema},
+		TotalResults: totalCount,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(groups),
+		Resources:    groups,
+	}, nil
+}
+
+func (s *scimService) FetchGroup(departmentID uint, withMembers bool) (*response.SCIMGroup, error) {
+	record, members, err := s.fetchGroupRecord(departmentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !withMembers {
+		members = nil
+	}
+
+	group := toSCIMGroup(record, members)
+
+	return &group, nil
+}
+
+// CreateGroup creates a department. Like one HR creates, it needs a department lead, which
+// has to be among the members; the other members have to be employees without a department.
+func (s *scimService) CreateGroup(actor *request.AuditActor, req *request.SCIMGroup) (*response.SCIMGroup, error) {
+	name := strings.TrimSpace(req.DisplayName)
+
+	if name == "" {
+		return nil, apperror.InvalidValueError("displayName is required")
+	}
+
+	isDepartmentNameExists, err := s.departmentRepository.IsDepartmentNameExists(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isDepartmentNameExists {
+		return nil, apperror.UniqueKeyError("displayName")
+	}
+
+	memberIDs, err := memberUserIDs(req.Members)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.membershipUsers(memberIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		leadID    uint
+		employees []uint
+	)
+
+	for _, user := range users {
+		if user.RoleID == uint(constant.DepartmentLead) {
+			if leadID != 0 {
+				return nil, apperror.InvalidValueError("a department has a single department lead")
+			}
+
+			if user.DepartmentID != nil {
+				return nil, apperror.InvalidValueError(fmt.Sprintf("user %d already belongs to a department", user.ID))
+			}
+
+			leadID = user.ID
+			continue
+		}
+
+		if err := checkNewMember(0, &user); err != nil {
+			return nil, err
+		}
+
+		employees = append(employees, user.ID)
+	}
+
+	if leadID == 0 {
+		return nil, apperror.InvalidValueError("the members must include a department lead")
+	}
+
+	if err := s.departmentService.CreateDepartment(actor, &request.CreateDepartment{Name: name, LeadID: leadID}); err != nil {
+		return nil, err
+	}
+
+	departmentID, err := s.scimRepository.GetDepartmentIDByName(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(employees) > 0 {
+		if err := s.departmentService.MappUsersToDepartment(actor, departmentID,
+			&request.MappUsersToDepartment{UserIDs: employees}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.updateGroup(actor, departmentID, name, req.ExternalID); err != nil {
+		return nil, err
+	}
+
+	return s.FetchGroup(departmentID, true)
+}
+
+func (s *scimService) ReplaceGroup(actor *request.AuditActor, departmentID uint, req *request.SCIMGroup) (*response.SCIMGroup, error) {
+	record, members, err := s.fetchGroupRecord(departmentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveGroup(actor, record, members, req); err != nil {
+		return nil, err
+	}
+
+	return s.FetchGroup(departmentID, true)
+}
+
+// PatchGroup applies the operations to the department's current representation, members
+// included, and saves the result as a replace would.
+func (s *scimService) PatchGroup(actor *request.AuditActor, departmentID uint, req *request.SCIMPatch) (*response.SCIMGroup, error) {
+	record, members, err := s.fetchGroupRecord(departmentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var group request.SCIMGroup
+
+	if err := applyPatch(toSCIMGroup(record, members), req, &group); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveGroup(actor, record, members, &group); err != nil {
+		return nil, err
+	}
+
+	return s.FetchGroup(departmentID, true)
+}
+
+func (s *scimService) RemoveGroup(actor *request.AuditActor, departmentID uint) error {
+	return s.departmentService.RemoveDepartment(actor, departmentID)
+}
+
+func (s *scimService) fetchGroupRecord(departmentID uint) (*response.SCIMGroupRecord, []response.SCIMGroupMember, error) {
+	record, err := s.scimRepository.FetchSCIMGroup(departmentID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if record == nil {
+		return nil, nil, apperror.DataNotFoundError("group")
+	}
+
+	members, err := s.scimRepository.FetchSCIMGroupMembers([]uint{departmentID})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return record, members, nil
+}
+
+func (s *scimService) saveGroup(actor *request.AuditActor, record *response.SCIMGroupRecord, members []response.SCIMGroupMember,
+	req *request.SCIMGroup) error {
+	name := strings.TrimSpace(req.DisplayName)
+
+	if name == "" {
+		return apperror.InvalidValueError("displayName is required")
+	}
+
+	if name != record.Name {
+		isDepartmentNameExists, err := s.departmentRepository.IsDepartmentNameExistsExceptID(record.ID, name)
+
+		if err != nil {
+			return err
+		}
+
+		if isDepartmentNameExists {
+			return apperror.UniqueKeyError("displayName")
+		}
+	}
+
+	memberIDs, err := memberUserIDs(req.Members)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.updateMembers(actor, record.ID, members, memberIDs); err != nil {
+		return err
+	}
+
+	return s.updateGroup(actor, record.ID, name, req.ExternalID)
+}
+
+func (s *scimService) updateGroup(actor *request.AuditActor, departmentID uint, name string, externalID *string) error {
+	if err := s.scimRepository.UpdateSCIMGroup(actor, departmentID, name, externalID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateMembers moves the department to the wanted members with the department service's
+// unmap and map rules. A department lead, or the last HR of the HR department, can only be
+// removed when the same request adds their replacement. Additions are checked before
+// anything changes.
+func (s *scimService) updateMembers(actor *request.AuditActor, departmentID uint, current []response.SCIMGroupMember, wanted []uint) error {
+	isWanted := make(map[uint]bool, len(wanted))
+	for _, userID := range wanted {
+		isWanted[userID] = true
+	}
+
+	isCurrent := make(map[uint]bool, len(current))
+	var removed []response.SCIMGroupMember
+
+	for _, member := range current {
+		isCurrent[member.UserID] = true
+
+		if !isWanted[member.UserID] {
+			removed = append(removed, member)
+		}
+	}
+
+	var added []uint
+	for _, userID := range wanted {
+		if !isCurrent[userID] {
+			added = append(added, userID)
+		}
+	}
+
+	users, err := s.membershipUsers(added)
+
+	if err != nil {
+		return err
+	}
+
+	replacements := make(map[uint]*uint)
+	var employees []uint
+
+	for i := range users {
+		user := users[i]
+
+		// A lead is only added as the replacement of the one being removed.
+		if departmentID != 1 && user.RoleID == uint(constant.DepartmentLead) {
+			replaced := false
+
+			for _, member := range removed {
+				if member.RoleID == uint(constant.DepartmentLead) && replacements[member.UserID] == nil {
+					replacements[member.UserID] = &user.ID
+					replaced = true
+					break
+				}
+			}
+
+			if !replaced {
+				return apperror.InvalidValueError("a department has a single department lead")
+			}
+
+			if user.DepartmentID != nil {
+				return apperror.InvalidValueError(fmt.Sprintf("user %d already belongs to a department", user.ID))
+			}
+
+			continue
+		}
+
+		if err := checkNewMember(departmentID, &user); err != nil {
+			return err
+		}
+
+		employees = append(employees, user.ID)
+	}
+
+	// The last HR of the HR department is replaced by one of the HRs being added.
+	if departmentID == 1 && len(removed) == len(current) && len(removed) > 0 && len(employees) > 0 {
+		replacements[removed[len(removed)-1].UserID] = &employees[0]
+		employees = employees[1:]
+	}
+
+	for _, member := range removed {
+		if err := s.departmentService.UnMapUser(actor, &request.UnMapUser{UserID: member.UserID,
+			LeadID: replacements[member.UserID]}); err != nil {
+			return err
+		}
+	}
+
+	if len(employees) == 0 {
+		return nil
+	}
+
+	return s.departmentService.MappUsersToDepartment(actor, departmentID,
+		&request.MappUsersToDepartment{UserIDs: employees})
+}
+
+// membershipUsers fetches the users a group write refers to, all of which have to be active.
+func (s *scimService) membershipUsers(userIDs []uint) ([]response.SCIMMembershipUser, error) {
+	users, err := s.scimRepository.FetchSCIMMembershipUsers(userIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) != len(userIDs) {
+		return nil, apperror.InvalidValueError("members must be active users")
+	}
+
+	return users, nil
+}
+
+// checkNewMember checks that the user can join the department: the HR department takes HRs
+// and the others employees, none of whom may belong to a department yet.
+func checkNewMember(departmentID uint, user *response.SCIMMembershipUser) error {
+	if user.DepartmentID != nil {
+		return apperror.InvalidValueError(fmt.Sprintf("user %d already belongs to a department", user.ID))
+	}
+
+	if departmentID == 1 && user.RoleID != uint(constant.HR) {
+		return apperror.InvalidValueError(fmt.Sprintf("user %d is not an HR", user.ID))
+	}
+
+	if departmentID != 1 && user.RoleID != uint(constant.Employee) {
+		return apperror.InvalidValueError(fmt.Sprintf("user %d is not an employee", user.ID))
+	}
+
+	return nil
+}
+
+func memberUserIDs(members []request.SCIMMember) ([]uint, error) {
+	seen := make(map[uint]bool, len(members))
+	userIDs := make([]uint, 0, len(members))
+
+	for _, member := range members {
+		userID, err := strconv.ParseUint(member.Value, 10, 64)
+
+		if err != nil || userID == 0 {
+			return nil, apperror.InvalidValueError(fmt.Sprintf("member %q is not a user ID", member.Value))
+		}
+
+		if !seen[uint(userID)] {
+			seen[uint(userID)] = true
+			userIDs = append(userIDs, uint(userID))
+		}
+	}
+
+	return userIDs, nil
+}
+
+// applyPatch applies the patch operations to the resource's JSON representation and decodes
+// the result into target.
+func applyPatch(resource interface{}, req *request.SCIMPatch, target interface{}) error {
+	encoded, err := json.Marshal(resource)
+
+	if err != nil {
+		return err
+	}
+
+	var document map[string]interface{}
+
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+
+	for _, operation := range req.Operations {
+		if err := scim.ApplyOperation(document, operation.Op, operation.Path, operation.Value); err != nil {
+			return err
+		}
+	}
+
+	// Some providers send active as the string "True" or "False".
+	for key, value := range document {
+		if text, ok := value.(string); ok && strings.EqualFold(key, "active") {
+			active, err := strconv.ParseBool(strings.ToLower(text))
+
+			if err != nil {
+				return apperror.InvalidValueError("active must be a boolean")
+			}
+
+			document[key] = active
+		}
+	}
+
+	if encoded, err = json.Marshal(document); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(encoded, target); err != nil {
+		return apperror.InvalidValueError(err.Error())
+	}
+
+	return nil
+}
+
+// listWindow returns the 1-based start index and page size of a list request, capped at the
+// configured maximum.
+func listWindow(query *request.SCIMListQuery) (int, int) {
+	startIndex, count := query.StartIndex, config.Config.SCIM.MaxResults
+
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	if query.Count != nil && *query.Count < count {
+		count = max(*query.Count, 0)
+	}
+
+	return startIndex, count
+}
+
+// primaryValue picks the primary value of a multi-valued attribute, else the one of the
+// preferred type, else the first.
+func primaryValue(values []request.SCIMMultiValue, preferredType string) string {
+	for _, value := range values {
+		if value.Primary {
+			return strings.TrimSpace(value.Value)
+		}
+	}
+
+	for _, value := range values {
+		if strings.EqualFold(value.Type, preferredType) {
+			return strings.TrimSpace(value.Value)
+		}
+	}
+
+	if len(values) > 0 {
+		return strings.TrimSpace(values[0].Value)
+	}
+
+	return ""
+}
+
+func toSCIMUser(record *response.SCIMUserRecord) response.SCIMUser {
+	user := response.SCIMUser{
+		Schemas:    []string{constant.SCIMUserSchema, constant.SCIMEnterpriseUserSchema},
+		ID:         strconv.FormatUint(uint64(record.ID), 10),
+		ExternalID: record.ExternalID,
+		UserName:   record.Email,
+		Name: response.SCIMName{
+			Formatted:  strings.TrimSpace(record.FirstName + " " + record.LastName),
+			GivenName:  record.FirstName,
+			FamilyName: record.LastName,
+		},
+		DisplayName: strings.TrimSpace(record.FirstName + " " + record.LastName),
+		Emails:      []response.SCIMMultiValue{{Value: record.Email, Type: "work", Primary: true}},
+		Title:       record.Designation,
+		Active:      record.IsActive,
+		Groups:      []response.SCIMMember{},
+		Enterprise: response.SCIMEnterpriseUser{
+			EmployeeNumber: record.Code,
+			Department:     record.DepartmentName,
+		},
+		Meta: response.SCIMMeta{ResourceType: "User", Created: record.CreatedAt, LastModified: record.UpdatedAt},
+	}
+
+	if record.Mobile != "" {
+		user.PhoneNumbers = []response.SCIMMultiValue{{Value: record.Mobile, Type: "mobile", Primary: true}}
+	}
+
+	if record.DepartmentID != nil && record.DepartmentName != nil {
+		user.Groups = append(user.Groups, response.SCIMMember{
+			Value:   strconv.FormatUint(uint64(*record.DepartmentID), 10),
+			Display: *record.DepartmentName,
+		})
+	}
+
+	return user
+}
+
+func toSCIMGroup(record *response.SCIMGroupRecord, members []response.SCIMGroupMember) response.SCIMGroup {
+	group := response.SCIMGroup{
+		Schemas:     []string{constant.SCIMGroupSchema},
+		ID:          strconv.FormatUint(uint64(record.ID), 10),
+		ExternalID:  record.ExternalID,
+		DisplayName: record.Name,
+		Meta:        response.SCIMMeta{ResourceType: "Group", Created: record.CreatedAt, LastModified: record.UpdatedAt},
+	}
+
+	for _, member := range members {
+		if member.DepartmentID == record.ID {
+			group.Members = append(group.Members, response.SCIMMember{
+				Value:   strconv.FormatUint(uint64(member.UserID), 10),
+				Display: strings.TrimSpace(member.FirstName + " " + member.LastName),
+			})
+		}
+	}
+
+	return group
+}
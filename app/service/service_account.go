@@ -0,0 +1,155 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type serviceAccountService struct {
+	serviceAccountRepository domain.ServiceAccountRepository
+}
+
+func NewServiceAccountService(serviceAccountRepository domain.ServiceAccountRepository) domain.ServiceAccountService {
+	return &serviceAccountService{serviceAccountRepository}
+}
+
+func (s *serviceAccountService) FetchServiceAccounts() ([]response.FetchServiceAccounts, error) {
+	data, err := s.serviceAccountRepository.FetchServiceAccounts()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *serviceAccountService) FetchAPIKeyScopes() []string {
+	scopes := make([]string, 0, len(constant.APIKeyScopes))
+	for _, scope := range constant.APIKeyScopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	return scopes
+}
+
+func (s *serviceAccountService) CreateServiceAccount(req *request.CreateServiceAccount, createdBy uint) error {
+	isNameExists, err := s.serviceAccountRepository.IsServiceAccountNameExists(req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isNameExists {
+		return apperror.UniqueKeyError("service account name")
+	}
+
+	if err := s.serviceAccountRepository.CreateServiceAccount(req, createdBy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *serviceAccountService) RemoveServiceAccount(serviceAccountID uint) error {
+	serviceAccount, err := s.serviceAccountRepository.GetServiceAccountByID(serviceAccountID)
+
+	if err != nil {
+		return err
+	}
+
+	if serviceAccount == nil {
+		return apperror.DataNotFoundError("service account")
+	}
+
+	if err := s.serviceAccountRepository.RemoveServiceAccount(serviceAccountID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateAPIKey issues a key limited to scopes that can be granted to API keys. The key is
+// returned once; only its hash is kept.
+func (s *serviceAccountService) CreateAPIKey(serviceAccountID uint, req *request.CreateAPIKey,
+	createdBy uint) (*response.CreatedAPIKey, error) {
+	serviceAccount, err := s.serviceAccountRepository.GetServiceAccountByID(serviceAccountID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if serviceAccount == nil {
+		return nil, apperror.DataNotFoundError("service account")
+	}
+
+	var scopes []string
+
+	for _, scope := range req.Scopes {
+		if !slices.Contains(constant.APIKeyScopes, constant.Permission(scope)) {
+			return nil, fmt.Errorf("scope %s cannot be granted to an API key", scope)
+		}
+
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	validity := time.Hour * 24 * time.Duration(req.ExpiresInDays)
+
+	if validity > config.Config.APIKeyMaxValidity {
+		return nil, fmt.Errorf("API keys can be valid for at most %d days",
+			int(config.Config.APIKeyMaxValidity.Hours()/24))
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(validity)
+
+	apiKeyID, err := s.serviceAccountRepository.CreateAPIKey(serviceAccountID, req.Name, prefix, utils.HashToken(key),
+		scopes, expiresAt, createdBy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.CreatedAPIKey{
+		ID:        apiKeyID,
+		Key:       key,
+		Prefix:    prefix,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *serviceAccountService) RevokeAPIKey(serviceAccountID, apiKeyID uint) error {
+	apiKey, err := s.serviceAccountRepository.GetAPIKeyByID(serviceAccountID, apiKeyID)
+
+	if err != nil {
+		return err
+	}
+
+	if apiKey == nil {
+		return apperror.DataNotFoundError("API key")
+	}
+
+	if apiKey.RevokedAt != nil {
+		return fmt.Errorf("API key is already revoked")
+	}
+
+	if err := s.serviceAccountRepository.RevokeAPIKey(apiKeyID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+type signingKeyService struct {
+	signingKeyRepository domain.SigningKeyRepository
+}
+
+func NewSigningKeyService(signingKeyRepository domain.SigningKeyRepository) domain.SigningKeyService {
+	return &signingKeyService{signingKeyRepository}
+}
+
+// LoadSigningKeys replaces the keyring of this instance with the keys in the database, so that
+// keys rotated by another instance are picked up.
+func (s *signingKeyService) LoadSigningKeys() error {
+	data, err := s.signingKeyRepository.FetchSigningKeys()
+
+	if err != nil {
+		return err
+	}
+
+	keys := make([]utils.SigningKey, 0, len(data))
+
+	for _, key := range data {
+		privatePEM, err := utils.DecryptPII(key.PrivateKey)
+
+		if err != nil {
+			return err
+		}
+
+		privateKey, publicKey, err := utils.ParseSigningKey(privatePEM, key.PublicKey)
+
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", key.KeyID, err)
+		}
+
+		keys = append(keys, utils.SigningKey{
+			ID:          key.KeyID,
+			Algorithm:   key.Algorithm,
+			PrivateKey:  privateKey,
+			PublicKey:   publicKey,
+			ActivatesAt: key.ActivatesAt,
+			ExpiresAt:   key.ExpiresAt,
+		})
+	}
+
+	utils.SetSigningKeys(keys)
+
+	return nil
+}
+
+// RotateSigningKey adds a key when there is none, when the current key is older than the
+// rotation interval or uses another algorithm than the configured one, or when forced. The new
+// key only starts signing after the propagation delay, once every instance has reloaded it,
+// and the keys it replaces stay valid until the last token they signed has expired.
+func (s *signingKeyService) RotateSigningKey(force bool) error {
+	data, err := s.signingKeyRepository.FetchSigningKeys()
+
+	if err != nil {
+		return err
+	}
+
+	var current *schema.SigningKey
+
+	for i, key := range data {
+		if key.ActivatesAt.After(time.Now()) {
+			if force {
+				return errors.New("a key rotation is already in progress")
+			}
+
+			return s.LoadSigningKeys()
+		}
+
+		if key.ExpiresAt == nil {
+			current = &data[i]
+		}
+	}
+
+	if !force && current != nil && current.Algorithm == config.Config.JwtSigningAlgorithm &&
+		time.Since(current.ActivatesAt) < config.Config.JwtKeyRotationInterval {
+		return s.LoadSigningKeys()
+	}
+
+	activatesAt := time.Now()
+	if current != nil {
+		activatesAt = activatesAt.Add(config.Config.JwtKeyPropagationDelay)
+	}
+
+	keyID, privatePEM, publicPEM, err := utils.GenerateSigningKey(config.Config.JwtSigningAlgorithm)
+
+	if err != nil {
+		return err
+	}
+
+	encryptedPrivateKey, err := utils.EncryptPII(privatePEM)
+
+	if err != nil {
+		return err
+	}
+
+	key := &schema.SigningKey{
+		KeyID:       keyID,
+		Algorithm:   config.Config.JwtSigningAlgorithm,
+		PrivateKey:  encryptedPrivateKey,
+		PublicKey:   publicPEM,
+		ActivatesAt: activatesAt,
+	}
+
+	if err := s.signingKeyRepository.RotateSigningKey(key, activatesAt.Add(maxTokenLifetime())); err != nil {
+		return err
+	}
+
+	return s.LoadSigningKeys()
+}
+
+// maxTokenLifetime is how long a token can be valid after it is signed.
+func maxTokenLifetime() time.Duration {
+	return max(config.Config.AccessTokenDuration, config.Config.TwoFactorTokenDuration,
+		config.Config.ImpersonationDuration)
+}
+
+// FetchJWKS publishes the public keys tokens can be verified with, including keys that do not
+// sign yet, so that other services can cache the set until the next rotation.
+func (s *signingKeyService) FetchJWKS() (*response.JWKS, error) {
+	data := &response.JWKS{Keys: []response.JWK{}}
+
+	for _, key := range utils.SigningKeys() {
+		jwk := response.JWK{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			return nil, fmt.Errorf("signing key %s has an unsupported type", key.ID)
+		}
+
+		data.Keys = append(data.Keys, jwk)
+	}
+
+	return data, nil
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
 	"ems/app/model/request"
 	"ems/app/model/response"
 	"ems/domain"
+	"ems/infrastructure/config"
 	"ems/utils"
 	"fmt"
 
@@ -12,19 +14,37 @@ import (
 )
 
 type userService struct {
-	userRepository       domain.UserRepository
-	departmentRepository domain.DepartmentRepository
-	leaveRepository      domain.LeaveRepository
-	permissionRepository domain.PermissionRepository
+	userRepository        domain.UserRepository
+	departmentRepository  domain.DepartmentRepository
+	leaveRepository       domain.LeaveRepository
+	permissionRepository  domain.PermissionRepository
+	customFieldRepository domain.CustomFieldRepository
+	roleRepository        domain.RoleRepository
 }
 
 func NewUserService(userRepository domain.UserRepository,
-	departmentRepository domain.DepartmentRepository, leaveRepository domain.LeaveRepository, permissionRepository domain.PermissionRepository) domain.UserService {
+	departmentRepository domain.DepartmentRepository, leaveRepository domain.LeaveRepository, permissionRepository domain.PermissionRepository,
+	customFieldRepository domain.CustomFieldRepository, roleRepository domain.RoleRepository) domain.UserService {
 
-	return &userService{userRepository, departmentRepository, leaveRepository, permissionRepository}
+	return &userService{userRepository, departmentRepository, leaveRepository, permissionRepository,
+		customFieldRepository, roleRepository}
 }
 
-func (s *userService) CreateUser(req *request.CreateUser) error {
+func (s *userService) CreateUser(actor *request.AuditActor, req *request.CreateUser) error {
+	if err := utils.ValidatePassword(req.Password, req.FirstName, req.LastName, req.Email, req.Mobile); err != nil {
+		return err
+	}
+
+	isRoleExists, err := s.userRepository.IsRoleExists(req.RoleID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isRoleExists {
+		return apperror.DataNotFoundError("role")
+	}
+
 	isUserCodeExists, err := s.userRepository.IsUserCodeExists(req.Code)
 
 	if err != nil {
@@ -61,8 +81,8 @@ func (s *userService) CreateUser(req *request.CreateUser) error {
 		return err
 	}
 
-	if err := s.userRepository.CreateUser(req, hashedPassword); err != nil {
-		return nil
+	if err := s.userRepository.CreateUser(actor, req, hashedPassword); err != nil {
+		return err
 	}
 
 	return nil
@@ -78,7 +98,7 @@ func (s *userService) FetchUsers(filters *request.FetchUsers) (*utils.Pagination
 	return data, err
 }
 
-func (s *userService) UpdateUser(userID uint, req *request.UpdateUser) error {
+func (s *userService) UpdateUser(actor *request.AuditActor, userID uint, req *request.UpdateUser) error {
 	isUserExists, err := s.userRepository.IsUserExists(userID)
 
 	if err != nil {
@@ -119,7 +139,7 @@ func (s *userService) UpdateUser(userID uint, req *request.UpdateUser) error {
 		return apperror.UniqueKeyError("mobile")
 	}
 
-	if err := s.userRepository.UpdateUser(userID, req); err != nil {
+	if err := s.userRepository.UpdateUser(actor, userID, req); err != nil {
 		return err
 	}
 
@@ -196,7 +216,7 @@ func (s *userService) FetchUnmappedUsers(req *request.FetchUnmappedUsersByDepart
 	}
 }
 
-func (s *userService) RemoveUser(userID uint) error {
+func (s *userService) RemoveUser(actor *request.AuditActor, userID uint) error {
 	isMappedLeadUser, err := s.userRepository.IsMappedLeadUser(userID)
 
 	if err != nil {
@@ -207,38 +227,32 @@ func (s *userService) RemoveUser(userID uint) error {
 		return fmt.Errorf(`user mapped to department. Kindly unmap from department`)
 	}
 
-	if err := s.userRepository.RemoveUser(userID); err != nil {
+	if err := s.userRepository.RemoveUser(actor, userID); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *userService) ResetPassword(userID uint, req *request.ResetPassword) error {
-	isUserExists, err := s.userRepository.IsUserExists(userID)
+func (s *userService) ResetPassword(actor *request.AuditActor, userID uint, req *request.ResetPassword) error {
+	user, err := s.userRepository.GetLoginUserByID(userID)
 
 	if err != nil {
 		return err
 	}
 
-	if !isUserExists {
+	if user == nil {
 		return apperror.DataNotFoundError("user")
 	}
 
-	hashedPassword, err := utils.HashPassword(req.Password)
-
-	if err != nil {
-		return err
-	}
-
-	if err := s.userRepository.UpdatePassword(userID, hashedPassword); err != nil {
+	if err := s.setPassword(actor, user, req.Password); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *userService) UpdateUserDetails(req *request.UpdateUserDetails) error {
+func (s *userService) UpdateUserDetails(actor *request.AuditActor, req *request.UpdateUserDetails) error {
 	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
 
 	if err != nil {
@@ -269,14 +283,14 @@ func (s *userService) UpdateUserDetails(req *request.UpdateUserDetails) error {
 		return apperror.UniqueKeyError("pan Number")
 	}
 
-	if err := s.userRepository.UpdateUserDetails(req); err != nil {
+	if err := s.userRepository.UpdateUserDetails(actor, req); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *userService) FetchUserDetails(req *request.FetchUserDetails) (*response.FetchUserDetails, error) {
+func (s *userService) FetchUserDetails(viewerID, viewerRoleID uint, req *request.FetchUserDetails) (*response.FetchUserDetails, error) {
 	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
 
 	if err != nil {
@@ -293,29 +307,35 @@ func (s *userService) FetchUserDetails(req *request.FetchUserDetails) (*response
 		return nil, err
 	}
 
-	return data, err
-}
+	if data == nil {
+		return nil, nil
+	}
 
-func (s *userService) UploadFiles(userID uint, filepaths []string) error {
-	isUserExists, err := s.userRepository.IsUserExists(userID)
+	canViewSensitive, err := hasPermission(s.roleRepository, viewerRoleID, constant.UserViewSensitive)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if !isUserExists {
-		return apperror.DataNotFoundError("user")
+	if !canViewSensitive && viewerID != req.UserID {
+		maskUserDetails(data)
 	}
 
-	if err := s.userRepository.UploadFiles(userID, filepaths); err != nil {
-		return err
+	data.CustomFields, err = s.customFieldRepository.FetchUserCustomFieldValues(req.UserID,
+		customFieldVisibilities(viewerID, req.UserID, canViewSensitive))
+
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return data, err
 }
 
-func (s *userService) FetchFilePathsByUserID(userID uint) ([]response.FetchUploadedDocumentPaths, error) {
-	isUserExists, err := s.userRepository.IsUserExists(userID)
+// FetchUserFieldHistory lists the changes to a user's fields, newest first, with PII masked as
+// in the user's details.
+func (s *userService) FetchUserFieldHistory(viewerID, viewerRoleID uint,
+	filters *request.FetchUserFieldHistory) (*utils.PaginationResponse, error) {
+	isUserExists, err := s.userRepository.IsUserExists(filters.UserID)
 
 	if err != nil {
 		return nil, err
@@ -325,23 +345,87 @@ func (s *userService) FetchFilePathsByUserID(userID uint) ([]response.FetchUploa
 		return nil, apperror.DataNotFoundError("user")
 	}
 
-	data, err := s.userRepository.FetchFilePathsByUserID(userID)
+	for _, date := range []string{filters.FromDate, filters.ToDate} {
+		if _, isValidDate := utils.IsValidDate(date); date != "" && !isValidDate {
+			return nil, fmt.Errorf("invalid date format: %s", date)
+		}
+	}
+
+	data, err := s.userRepository.FetchUserFieldHistory(filters)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return data, err
+	canViewSensitive, err := hasPermission(s.roleRepository, viewerRoleID, constant.UserViewSensitive)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// PII is shown in clear to its owner and to roles granted user.viewSensitive.
+	if !canViewSensitive && viewerID != filters.UserID {
+		history, _ := data.Data.([]response.FetchUserFieldHistory)
+
+		for i := range history {
+			for _, value := range []*string{history[i].OldValue, history[i].NewValue} {
+				if value != nil {
+					*value = maskUserDetailsField(history[i].Field, *value)
+				}
+			}
+		}
+	}
+
+	return data, nil
+}
+
+func maskUserDetails(data *response.FetchUserDetails) {
+	fields := map[string]*string{
+		"AadharNumber": data.AadharNumber, "PanNumber": data.PanNumber,
+		"BankAccountNumber": data.BankAccountNumber, "DOB": data.DOB,
+	}
+
+	for field, value := range fields {
+		if value != nil {
+			*value = maskUserDetailsField(field, *value)
+		}
+	}
+}
+
+// maskUserDetailsField masks the value of a PII column of the user's details. Other columns are
+// returned unchanged.
+func maskUserDetailsField(field, value string) string {
+	switch field {
+	case "AadharNumber":
+		return utils.MaskAadharNumber(value)
+	case "PanNumber", "BankAccountNumber":
+		return utils.MaskValue(value, 4)
+	case "DOB":
+		return "XXXX-XX-XX"
+	}
+
+	return value
 }
 
-func (s *userService) RemoveFile(filePath string) error {
-	if err := s.userRepository.RemoveFile(filePath); err != nil {
+func (s *userService) UploadFiles(actor *request.AuditActor, userID uint, documents []response.UploadedDocument) error {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := s.userRepository.UploadFiles(actor, userID, documents); err != nil {
 		return err
 	}
+
 	return nil
 }
 
-func (s *userService) ChangePassword(req *request.ChangePassword) error {
+func (s *userService) ChangePassword(actor *request.AuditActor, userID uint, req *request.ChangePassword) error {
 	user, err := s.userRepository.GetUserByEmail(req.Email)
 
 	if err != nil {
@@ -352,17 +436,59 @@ func (s *userService) ChangePassword(req *request.ChangePassword) error {
 		return apperror.DataNotFoundError("email")
 	}
 
+	if user.ID != userID {
+		return apperror.AccessDeniedError("change another user's password")
+	}
+
+	if user.AuthSource == string(constant.DirectoryAuth) {
+		return apperror.ErrDirectoryPassword
+	}
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
 		return fmt.Errorf("incorrect old password")
 	}
 
-	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err := s.setPassword(actor, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setPassword checks the new password against the policy and the user's recent passwords
+// before storing it. Directory users change their password in the directory instead.
+func (s *userService) setPassword(actor *request.AuditActor, user *response.FetchUserByEmail, password string) error {
+	if user.AuthSource == string(constant.DirectoryAuth) {
+		return apperror.ErrDirectoryPassword
+	}
+
+	if err := utils.ValidatePassword(password, user.FirstName, user.LastName, user.Email, user.Mobile); err != nil {
+		return err
+	}
+
+	historyCount := config.Config.PasswordPolicy.HistoryCount
+
+	recentPasswords, err := s.userRepository.FetchPasswordHistory(user.ID, historyCount)
+
+	if err != nil {
+		return err
+	}
+
+	// The current password is always checked, even for users with no history yet.
+	for _, hashedPassword := range append(recentPasswords, user.Password) {
+		if bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil {
+			return apperror.WeakPasswordError(fmt.Sprintf("must not match any of your last %d passwords",
+				max(historyCount, 1)))
+		}
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
 
 	if err != nil {
 		return err
 	}
 
-	if err := s.userRepository.UpdatePassword(user.ID, hashedPassword); err != nil {
+	if err := s.userRepository.UpdatePassword(actor, user.ID, hashedPassword); err != nil {
 		return err
 	}
 
@@ -379,3 +505,28 @@ func (s *userService) FetchUnmappedHRUsers() ([]response.FetchUnmappedUsers, err
 
 	return data, err
 }
+
+func (s *userService) RotatePIIEncryptionKey() (*response.RotatePIIEncryptionKey, error) {
+	count, err := s.userRepository.ReEncryptUserDetails()
+
+	if err != nil {
+		return nil, err
+	}
+
+	historyCount, err := s.userRepository.ReEncryptUserFieldHistory()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sealedCount, err := s.userRepository.ReEncryptSealedColumns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.RotatePIIEncryptionKey{
+		ActiveKeyID:     config.Config.PiiActiveKeyID,
+		ReEncryptedRows: count + historyCount + sealedCount,
+	}, nil
+}
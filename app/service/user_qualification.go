@@ -0,0 +1,301 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"fmt"
+	"time"
+)
+
+type userQualificationService struct {
+	userQualificationRepository domain.UserQualificationRepository
+	userRepository              domain.UserRepository
+}
+
+func NewUserQualificationService(userQualificationRepository domain.UserQualificationRepository,
+	userRepository domain.UserRepository) domain.UserQualificationService {
+	return &userQualificationService{userQualificationRepository, userRepository}
+}
+
+func (s *userQualificationService) FetchUserQualifications(userID uint) (*response.FetchUserQualifications, error) {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isUserExists {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	educations, err := s.userQualificationRepository.FetchEducations(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	certifications, err := s.userQualificationRepository.FetchCertifications(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	skills, err := s.userQualificationRepository.FetchSkills(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.FetchUserQualifications{
+		Educations:     educations,
+		Certifications: certifications,
+		Skills:         skills,
+	}, nil
+}
+
+func (s *userQualificationService) CreateEducation(req *request.CreateEducation) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := validateEducation(&req.UpdateEducation); err != nil {
+		return err
+	}
+
+	if err := s.userQualificationRepository.CreateEducation(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) UpdateEducation(educationID uint, req *request.UpdateEducation) error {
+	isEducationExists, err := s.userQualificationRepository.IsEducationExists(educationID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isEducationExists {
+		return apperror.DataNotFoundError("education")
+	}
+
+	if err := validateEducation(req); err != nil {
+		return err
+	}
+
+	if err := s.userQualificationRepository.UpdateEducation(educationID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) RemoveEducation(educationID uint) error {
+	isEducationExists, err := s.userQualificationRepository.IsEducationExists(educationID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isEducationExists {
+		return apperror.DataNotFoundError("education")
+	}
+
+	if err := s.userQualificationRepository.RemoveEducation(educationID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) CreateCertification(req *request.CreateCertification) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := validateCertification(&req.UpdateCertification); err != nil {
+		return err
+	}
+
+	if err := s.userQualificationRepository.CreateCertification(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) UpdateCertification(certificationID uint, req *request.UpdateCertification) error {
+	isCertificationExists, err := s.userQualificationRepository.IsCertificationExists(certificationID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isCertificationExists {
+		return apperror.DataNotFoundError("certification")
+	}
+
+	if err := validateCertification(req); err != nil {
+		return err
+	}
+
+	if err := s.userQualificationRepository.UpdateCertification(certificationID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) RemoveCertification(certificationID uint) error {
+	isCertificationExists, err := s.userQualificationRepository.IsCertificationExists(certificationID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isCertificationExists {
+		return apperror.DataNotFoundError("certification")
+	}
+
+	if err := s.userQualificationRepository.RemoveCertification(certificationID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) CreateSkill(req *request.CreateSkill) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	isSkillNameExists, err := s.userQualificationRepository.IsSkillNameExists(req.UserID, req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isSkillNameExists {
+		return apperror.UniqueKeyError("skill")
+	}
+
+	if err := s.userQualificationRepository.CreateSkill(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) UpdateSkill(skillID uint, req *request.UpdateSkill) error {
+	isSkillExists, err := s.userQualificationRepository.IsSkillExists(skillID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isSkillExists {
+		return apperror.DataNotFoundError("skill")
+	}
+
+	isSkillNameExists, err := s.userQualificationRepository.IsSkillNameExistsExceptID(skillID, req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	if isSkillNameExists {
+		return apperror.UniqueKeyError("skill")
+	}
+
+	if err := s.userQualificationRepository.UpdateSkill(skillID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) RemoveSkill(skillID uint) error {
+	isSkillExists, err := s.userQualificationRepository.IsSkillExists(skillID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isSkillExists {
+		return apperror.DataNotFoundError("skill")
+	}
+
+	if err := s.userQualificationRepository.RemoveSkill(skillID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userQualificationService) SearchQualifications(filters *request.SearchQualifications) (*utils.PaginationResponse, error) {
+	data, totalCount, err := s.userQualificationRepository.SearchQualifications(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
+}
+
+func validateEducation(req *request.UpdateEducation) error {
+	if req.StartYear > uint(time.Now().Year()) {
+		return fmt.Errorf("start year cannot be in the future")
+	}
+
+	if req.EndYear != nil && *req.EndYear < req.StartYear {
+		return fmt.Errorf("end year cannot be before start year")
+	}
+
+	return nil
+}
+
+func validateCertification(req *request.UpdateCertification) error {
+	issuedDate, isValidDate := utils.IsValidDate(req.IssuedDate)
+
+	if !isValidDate {
+		return fmt.Errorf("invalid date format: %s", req.IssuedDate)
+	}
+
+	if req.ExpiryDate == "" {
+		return nil
+	}
+
+	expiryDate, isValidDate := utils.IsValidDate(req.ExpiryDate)
+
+	if !isValidDate {
+		return fmt.Errorf("invalid date format: %s", req.ExpiryDate)
+	}
+
+	if !expiryDate.After(*issuedDate) {
+		return fmt.Errorf("expiry date must be after issued date")
+	}
+
+	return nil
+}
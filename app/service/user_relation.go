@@ -0,0 +1,201 @@
+package service
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"fmt"
+	"math"
+)
+
+type userRelationService struct {
+	userRelationRepository domain.UserRelationRepository
+	userRepository         domain.UserRepository
+}
+
+func NewUserRelationService(userRelationRepository domain.UserRelationRepository,
+	userRepository domain.UserRepository) domain.UserRelationService {
+	return &userRelationService{userRelationRepository, userRepository}
+}
+
+func (s *userRelationService) FetchUserRelations(userID uint) (*response.FetchUserRelations, error) {
+	isUserExists, err := s.userRepository.IsUserExists(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isUserExists {
+		return nil, apperror.DataNotFoundError("user")
+	}
+
+	emergencyContacts, err := s.userRelationRepository.FetchEmergencyContacts(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dependents, err := s.userRelationRepository.FetchDependents(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nominees, err := s.userRelationRepository.FetchNominees(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.FetchUserRelations{
+		EmergencyContacts: emergencyContacts,
+		Dependents:        dependents,
+		Nominees:          nominees,
+	}, nil
+}
+
+func (s *userRelationService) CreateEmergencyContact(req *request.CreateEmergencyContact) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if err := s.userRelationRepository.CreateEmergencyContact(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userRelationService) UpdateEmergencyContact(contactID uint, req *request.UpdateEmergencyContact) error {
+	isEmergencyContactExists, err := s.userRelationRepository.IsEmergencyContactExists(contactID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isEmergencyContactExists {
+		return apperror.DataNotFoundError("emergency contact")
+	}
+
+	if err := s.userRelationRepository.UpdateEmergencyContact(contactID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userRelationService) RemoveEmergencyContact(contactID uint) error {
+	isEmergencyContactExists, err := s.userRelationRepository.IsEmergencyContactExists(contactID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isEmergencyContactExists {
+		return apperror.DataNotFoundError("emergency contact")
+	}
+
+	if err := s.userRelationRepository.RemoveEmergencyContact(contactID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userRelationService) CreateDependent(req *request.CreateDependent) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	if _, isValidDate := utils.IsValidDate(req.DOB); !isValidDate {
+		return fmt.Errorf("invalid date format: %s", req.DOB)
+	}
+
+	if err := s.userRelationRepository.CreateDependent(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userRelationService) UpdateDependent(dependentID uint, req *request.UpdateDependent) error {
+	isDependentExists, err := s.userRelationRepository.IsDependentExists(dependentID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isDependentExists {
+		return apperror.DataNotFoundError("dependent")
+	}
+
+	if _, isValidDate := utils.IsValidDate(req.DOB); !isValidDate {
+		return fmt.Errorf("invalid date format: %s", req.DOB)
+	}
+
+	if err := s.userRelationRepository.UpdateDependent(dependentID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userRelationService) RemoveDependent(dependentID uint) error {
+	isDependentExists, err := s.userRelationRepository.IsDependentExists(dependentID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isDependentExists {
+		return apperror.DataNotFoundError("dependent")
+	}
+
+	if err := s.userRelationRepository.RemoveDependent(dependentID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *userRelationService) UpdateNominees(req *request.UpdateNominees) error {
+	isUserExists, err := s.userRepository.IsUserExists(req.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	if !isUserExists {
+		return apperror.DataNotFoundError("user")
+	}
+
+	var totalShare float64
+	for _, nominee := range req.Nominees {
+		totalShare += nominee.SharePercentage
+	}
+
+	// Shares are entered with up to two decimals, allow for float rounding.
+	if len(req.Nominees) > 0 && math.Abs(totalShare-100) > 0.001 {
+		return fmt.Errorf("nominee shares must add up to 100%%, got %.2f%%", totalShare)
+	}
+
+	if err := s.userRelationRepository.ReplaceNominees(req); err != nil {
+		return err
+	}
+
+	return nil
+}
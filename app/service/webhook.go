@@ -0,0 +1,378 @@
+package service
+
+import (
+	"bytes"
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// webhookBatchSize is how many deliveries a run claims at a time.
+	webhookBatchSize = 50
+	// webhookLease is how long claimed deliveries are held before another run may send them.
+	webhookLease = 5 * time.Minute
+	// webhookMaxAttempts is how many times a delivery is sent before it is dead lettered.
+	webhookMaxAttempts = 8
+	// webhookRetryDelay is the delay before the first retry, doubled on each later one.
+	webhookRetryDelay = time.Minute
+	// webhookMaxRetryDelay caps the delay between retries.
+	webhookMaxRetryDelay = 12 * time.Hour
+	// webhookTimeout bounds each POST, so that a slow receiver cannot hold up the others.
+	webhookTimeout = 10 * time.Second
+)
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+type webhookService struct {
+	webhookRepository domain.WebhookRepository
+}
+
+func NewWebhookService(webhookRepository domain.WebhookRepository) domain.WebhookService {
+	return &webhookService{webhookRepository}
+}
+
+func (s *webhookService) FetchWebhooks() ([]response.FetchWebhooks, error) {
+	data, err := s.webhookRepository.FetchWebhooks()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *webhookService) FetchEventTypes() []string {
+	eventTypes := make([]string, 0, len(constant.EventTypes))
+	for _, eventType := range constant.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	return eventTypes
+}
+
+// CreateWebhook subscribes the URL to the event types. The signing secret is returned once; it
+// can be rotated but not shown again.
+func (s *webhookService) CreateWebhook(req *request.CreateWebhook, createdBy uint) (*response.CreatedWebhookSecret, error) {
+	if err := s.validateWebhook(0, req); err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateWebhookSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	webhookID, err := s.webhookRepository.CreateWebhook(req, secret, createdBy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.CreatedWebhookSecret{ID: webhookID, Secret: secret}, nil
+}
+
+func (s *webhookService) UpdateWebhook(webhookID uint, req *request.UpdateWebhook) error {
+	webhook, err := s.webhookRepository.GetWebhookByID(webhookID)
+
+	if err != nil {
+		return err
+	}
+
+	if webhook == nil {
+		return apperror.DataNotFoundError("webhook")
+	}
+
+	if err := s.validateWebhook(webhookID, &req.CreateWebhook); err != nil {
+		return err
+	}
+
+	if err := s.webhookRepository.UpdateWebhook(webhookID, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *webhookService) RemoveWebhook(webhookID uint) error {
+	webhook, err := s.webhookRepository.GetWebhookByID(webhookID)
+
+	if err != nil {
+		return err
+	}
+
+	if webhook == nil {
+		return apperror.DataNotFoundError("webhook")
+	}
+
+	if err := s.webhookRepository.RemoveWebhook(webhookID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RotateWebhookSecret replaces the signing secret. Deliveries are signed with the new secret
+// from their next attempt.
+func (s *webhookService) RotateWebhookSecret(webhookID uint) (*response.CreatedWebhookSecret, error) {
+	webhook, err := s.webhookRepository.GetWebhookByID(webhookID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook == nil {
+		return nil, apperror.DataNotFoundError("webhook")
+	}
+
+	secret, err := utils.GenerateWebhookSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepository.UpdateWebhookSecret(webhookID, secret); err != nil {
+		return nil, err
+	}
+
+	return &response.CreatedWebhookSecret{ID: webhookID, Secret: secret}, nil
+}
+
+func (s *webhookService) FetchWebhookDeliveries(filters *request.FetchWebhookDeliveries) (*utils.PaginationResponse, error) {
+	if filters.WebhookID > 0 {
+		webhook, err := s.webhookRepository.GetWebhookByID(filters.WebhookID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if webhook == nil {
+			return nil, apperror.DataNotFoundError("webhook")
+		}
+	}
+
+	if filters.Status != "" && !slices.Contains([]constant.WebhookDeliveryStatus{constant.WebhookDeliveryPending,
+		constant.WebhookDeliveryDelivered, constant.WebhookDeliveryDeadLetter},
+		constant.WebhookDeliveryStatus(filters.Status)) {
+		return nil, fmt.Errorf("invalid delivery status: %s", filters.Status)
+	}
+
+	data, err := s.webhookRepository.FetchWebhookDeliveries(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *webhookService) FetchWebhookDelivery(deliveryID uint) (*response.FetchWebhookDelivery, error) {
+	data, err := s.webhookRepository.FetchWebhookDelivery(deliveryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, apperror.DataNotFoundError("webhook delivery")
+	}
+
+	return data, nil
+}
+
+// RedeliverWebhookDelivery sends a delivery again right away, whatever its state, with a fresh
+// set of attempts. If this attempt fails it is retried as a new delivery would be.
+func (s *webhookService) RedeliverWebhookDelivery(deliveryID uint) (*response.FetchWebhookDelivery, error) {
+	data, err := s.webhookRepository.FetchWebhookDelivery(deliveryID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, apperror.DataNotFoundError("webhook delivery")
+	}
+
+	delivery, err := s.webhookRepository.ClaimWebhookDelivery(deliveryID, webhookLease)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deliverWebhook(delivery); err != nil {
+		return nil, err
+	}
+
+	return s.FetchWebhookDelivery(deliveryID)
+}
+
+// QueueWebhookDeliveries is the event bus handler that queues an event for its webhooks. The
+// deliveries are sent by DeliverWebhooks, so that a failing receiver is retried on its own.
+func (s *webhookService) QueueWebhookDeliveries(event *response.OutboxEvent) error {
+	return s.webhookRepository.CreateWebhookDeliveries(event)
+}
+
+// DeliverWebhooks sends the deliveries that are due.
+func (s *webhookService) DeliverWebhooks() error {
+	for {
+		deliveries, err := s.webhookRepository.ClaimDueWebhookDeliveries(webhookBatchSize, webhookLease)
+
+		if err != nil {
+			return err
+		}
+
+		for i := range deliveries {
+			if err := s.deliverWebhook(&deliveries[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliverWebhook posts the delivery once and records the outcome. A failed attempt is retried
+// with backoff until the delivery runs out of attempts and is dead lettered.
+func (s *webhookService) deliverWebhook(delivery *response.WebhookDelivery) error {
+	startedAt := time.Now()
+	responseStatus, err := postWebhook(delivery)
+	duration := time.Since(startedAt)
+
+	attempts := delivery.Attempts + 1
+	status := constant.WebhookDeliveryDelivered
+	nextAttemptAt := time.Now()
+
+	var lastError *string
+
+	if err != nil {
+		message := err.Error()
+		lastError = &message
+
+		log.Printf("Webhook delivery %d to %s failed: %v", delivery.ID, delivery.URL, err)
+
+		if attempts >= webhookMaxAttempts {
+			status = constant.WebhookDeliveryDeadLetter
+		} else {
+			status = constant.WebhookDeliveryPending
+			nextAttemptAt = nextAttemptAt.Add(webhookBackoff(attempts))
+		}
+	}
+
+	return s.webhookRepository.RecordWebhookDeliveryAttempt(delivery.ID, attempts, status, nextAttemptAt,
+		responseStatus, lastError, duration)
+}
+
+// validateWebhook checks the URL and the event types, and drops repeated event types.
+func (s *webhookService) validateWebhook(webhookID uint, req *request.CreateWebhook) error {
+	isNameExists, err := s.webhookRepository.IsWebhookNameExists(req.Name, webhookID)
+
+	if err != nil {
+		return err
+	}
+
+	if isNameExists {
+		return apperror.UniqueKeyError("webhook name")
+	}
+
+	target, err := url.Parse(req.URL)
+
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("invalid webhook URL: %s", req.URL)
+	}
+
+	var eventTypes []string
+
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(constant.EventTypes, constant.EventType(eventType)) {
+			return fmt.Errorf("invalid event type: %s", eventType)
+		}
+
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	req.EventTypes = eventTypes
+
+	return nil
+}
+
+// webhookBody is what is posted to a webhook. The ID is the event's, so that a receiver can
+// ignore an event it has already handled when it is redelivered.
+type webhookBody struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// postWebhook posts the event to the webhook, signed with its secret, and returns the response
+// status. Any status other than 2xx is an error.
+func postWebhook(delivery *response.WebhookDelivery) (*int, error) {
+	body, err := json.Marshal(webhookBody{
+		ID:        delivery.OutboxEventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.EventAt,
+		Data:      json.RawMessage(delivery.Payload),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "EMS-Webhook")
+	req.Header.Set("X-EMS-Event", delivery.EventType)
+	req.Header.Set("X-EMS-Event-ID", strconv.FormatUint(uint64(delivery.OutboxEventID), 10))
+	req.Header.Set("X-EMS-Delivery-ID", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-EMS-Signature", utils.SignWebhookPayload(delivery.Secret, time.Now(), body))
+
+	res, err := webhookClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	// Drain a little of the body so that the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &res.StatusCode, fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+
+	return &res.StatusCode, nil
+}
+
+// webhookBackoff returns the delay before the given retry attempt.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookRetryDelay
+
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, webhookMaxRetryDelay)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/infrastructure/repository"
+	"ems/internal/testenv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// webhookReceiver records the deliveries it is sent and answers with the next status in line,
+// repeating the last one. A delivery with a bad signature is answered with 401.
+type webhookReceiver struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	secret   string
+	statuses []int
+	bodies   []map[string]interface{}
+	headers  []http.Header
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+
+	timestamp, signature, _ := strings.Cut(req.Header.Get("X-EMS-Signature"), ",")
+	mac := hmac.New(sha256.New, []byte(r.secret))
+	mac.Write([]byte(strings.TrimPrefix(timestamp, "t=") + "."))
+	mac.Write(body)
+
+	if signature != "v1="+hex.EncodeToString(mac.Sum(nil)) {
+		r.t.Errorf("delivery %s has a bad signature", req.Header.Get("X-EMS-Delivery-ID"))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var data map[string]interface{}
+	json.Unmarshal(body, &data)
+
+	r.bodies = append(r.bodies, data)
+	r.headers = append(r.headers, req.Header)
+
+	status := r.statuses[0]
+	if len(r.statuses) > 1 {
+		r.statuses = r.statuses[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+func (r *webhookReceiver) received() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.bodies)
+}
+
+func (r *webhookReceiver) answer(statuses ...int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.statuses = statuses
+}
+
+type webhookTest struct {
+	t        *testing.T
+	db       *gorm.DB
+	service  domain.WebhookService
+	receiver *webhookReceiver
+}
+
+// newWebhookTest starts a receiver and queues one user.created delivery for it through the
+// event bus, as a change to a user would.
+func newWebhookTest(t *testing.T, statuses ...int) *webhookTest {
+	db := testenv.Setup(t, nil)
+
+	receiver := &webhookReceiver{t: t, statuses: statuses}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	webhookService := NewWebhookService(repository.NewWebhookRepository(db))
+
+	created, err := webhookService.CreateWebhook(&request.CreateWebhook{
+		Name:       "Payroll",
+		URL:        server.URL + "/hooks/ems",
+		EventTypes: []string{string(constant.UserCreated)},
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver.secret = created.Secret
+
+	event := &schema.OutboxEvent{EventType: string(constant.UserCreated), Payload: `{"email":"new.joiner@ems.com"}`, NextAttemptAt: time.Now()}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	eventBus := NewEventBus(repository.NewEventRepository(db))
+	eventBus.Subscribe(constant.WebhookSubscriber, webhookService.QueueWebhookDeliveries, constant.EventTypes...)
+	if err := eventBus.DispatchEvents(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &webhookTest{t: t, db: db, service: webhookService, receiver: receiver}
+}
+
+// deliverDue makes the queued delivery due, as if its backoff had passed, and runs the sender.
+func (w *webhookTest) deliverDue() {
+	if err := w.db.Exec(`UPDATE WebhookDelivery SET NextAttemptAt = ?`, time.Now().Add(-time.Second)).Error; err != nil {
+		w.t.Fatal(err)
+	}
+
+	if err := w.service.DeliverWebhooks(); err != nil {
+		w.t.Fatal(err)
+	}
+}
+
+func (w *webhookTest) delivery() *schema.WebhookDelivery {
+	var deliveries []schema.WebhookDelivery
+	if err := w.db.Find(&deliveries).Error; err != nil {
+		w.t.Fatal(err)
+	}
+
+	if len(deliveries) != 1 {
+		w.t.Fatalf("deliveries = %d", len(deliveries))
+	}
+
+	return &deliveries[0]
+}
+
+func TestWebhookRetriedWithBackoff(t *testing.T) {
+	test := newWebhookTest(t, http.StatusInternalServerError, http.StatusOK)
+
+	if err := test.service.DeliverWebhooks(); err != nil {
+		t.Fatal(err)
+	}
+
+	delivery := test.delivery()
+	if delivery.Status != string(constant.WebhookDeliveryPending) || delivery.Attempts != 1 {
+		t.Fatalf("after a failed attempt: status %s, attempts %d", delivery.Status, delivery.Attempts)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < webhookRetryDelay-time.Minute/2 || wait > webhookRetryDelay {
+		t.Fatalf("retry in %s", wait)
+	}
+
+	// The retry is not sent before it is due.
+	if err := test.service.DeliverWebhooks(); err != nil {
+		t.Fatal(err)
+	}
+	if received := test.receiver.received(); received != 1 {
+		t.Fatalf("sent %d times before the retry was due", received)
+	}
+
+	test.deliverDue()
+
+	if delivery := test.delivery(); delivery.Status != string(constant.WebhookDeliveryDelivered) || delivery.Attempts != 2 {
+		t.Fatalf("after the retry: status %s, attempts %d", delivery.Status, delivery.Attempts)
+	}
+
+	first, retry := test.receiver.bodies[0], test.receiver.bodies[1]
+	if first["id"] != retry["id"] || test.receiver.headers[0].Get("X-EMS-Event-ID") != test.receiver.headers[1].Get("X-EMS-Event-ID") {
+		t.Fatalf("the retry carries event %v, the first attempt %v", retry["id"], first["id"])
+	}
+	if retry["type"] != string(constant.UserCreated) || retry["data"].(map[string]interface{})["email"] != "new.joiner@ems.com" {
+		t.Fatalf("body = %v", retry)
+	}
+}
+
+func TestWebhookDeadLetteredAndRedelivered(t *testing.T) {
+	test := newWebhookTest(t, http.StatusServiceUnavailable)
+
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		test.deliverDue()
+
+		status := constant.WebhookDeliveryPending
+		if attempt == webhookMaxAttempts {
+			status = constant.WebhookDeliveryDeadLetter
+		}
+
+		if delivery := test.delivery(); delivery.Status != string(status) || delivery.Attempts != attempt {
+			t.Fatalf("after attempt %d: status %s, attempts %d", attempt, delivery.Status, delivery.Attempts)
+		}
+	}
+
+	// A dead lettered delivery is not sent again on its own.
+	test.deliverDue()
+	if received := test.receiver.received(); received != webhookMaxAttempts {
+		t.Fatalf("sent %d times", received)
+	}
+
+	deliveryID := test.delivery().ID
+
+	data, err := test.service.FetchWebhookDelivery(deliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.AttemptLog) != webhookMaxAttempts || data.ResponseStatus == nil || *data.ResponseStatus != http.StatusServiceUnavailable {
+		t.Fatalf("attempt log %d, response status %v", len(data.AttemptLog), data.ResponseStatus)
+	}
+
+	test.receiver.answer(http.StatusNoContent)
+
+	data, err = test.service.RedeliverWebhookDelivery(deliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Status != string(constant.WebhookDeliveryDelivered) || data.Attempts != 1 || data.DeliveredAt == nil {
+		t.Fatalf("after redelivery: status %s, attempts %d", data.Status, data.Attempts)
+	}
+	if received := test.receiver.received(); received != webhookMaxAttempts+1 {
+		t.Fatalf("sent %d times", received)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	for attempts, expected := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		10: 512 * time.Minute,
+		11: webhookMaxRetryDelay,
+		50: webhookMaxRetryDelay,
+	} {
+		if delay := webhookBackoff(attempts); delay != expected {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", attempts, delay, expected)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"ems/infrastructure/ldap/ber"
+	"encoding/json"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Protocol operations and result codes used by the mock, see RFC 4511.
+const (
+	opBindRequest       byte = 0
+	opBindResponse      byte = 1
+	opUnbindRequest     byte = 2
+	opSearchRequest     byte = 3
+	opSearchResultEntry byte = 4
+	opSearchResultDone  byte = 5
+	opExtendedRequest   byte = 23
+	opExtendedResponse  byte = 24
+
+	resultSuccess            = 0
+	resultProtocolError      = 2
+	resultInvalidCredentials = 49
+
+	pagedResultsOID = "1.2.840.113556.1.4.319"
+)
+
+type entry struct {
+	DN         string              `json:"dn"`
+	Password   string              `json:"password"`
+	Attributes map[string][]string `json:"attributes"`
+}
+
+// sampleEntries are served when no data file is given. The service account is
+// cn=admin,dc=ems,dc=com with password admin.
+var sampleEntries = []entry{
+	{DN: "cn=admin,dc=ems,dc=com", Password: "admin", Attributes: map[string][]string{
+		"objectClass": {"organizationalRole"}, "cn": {"admin"}}},
+	{DN: "uid=hr,ou=people,dc=ems,dc=com", Password: "Directory123", Attributes: map[string][]string{
+		"objectClass": {"person"}, "uid": {"hr"}, "mail": {"hr@ems.com"}, "givenName": {"Hema"},
+		"sn": {"Raman"}, "memberOf": {"cn=hr,ou=groups,dc=ems,dc=com"}}},
+	{DN: "uid=dev,ou=people,dc=ems,dc=com", Password: "Directory123", Attributes: map[string][]string{
+		"objectClass": {"person"}, "uid": {"dev"}, "mail": {"dev@ems.com"}, "givenName": {"Dev"},
+		"sn": {"Kumar"}, "memberOf": {"cn=staff,ou=groups,dc=ems,dc=com", "cn=engineering,ou=groups,dc=ems,dc=com"}}},
+}
+
+/**
+ * @Function: main
+ * @Description: Runs a small in-memory LDAP server for developing and testing directory sign in
+ * and group sync. It supports simple binds, subtree searches with the paged results control
+ * and unbind. Usage: go run ./cmd/mock-ldap -addr 127.0.0.1:3389 -data entries.json, where the
+ * file holds a JSON list of {"dn", "password", "attributes"} entries.
+ *
+ * @Params:
+ *    - None
+ *
+ * @Returns:
+ *    - None
+ */
+func main() {
+	addr := flag.String("addr", "127.0.0.1:3389", "address to listen on")
+	dataFile := flag.String("data", "", "JSON file with the directory entries, defaults to a sample directory")
+	flag.Parse()
+
+	entries := sampleEntries
+
+	if *dataFile != "" {
+		data, err := os.ReadFile(*dataFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := json.Unmarshal(data, &entries); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("mock LDAP server with %d entries listening on %s", len(entries), *addr)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		go serve(conn, entries)
+	}
+}
+
+func serve(conn net.Conn, entries []entry) {
+	defer conn.Close()
+
+	for {
+		message, err := ber.ReadPacket(conn)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+
+		messageID := message.Children[0].Int()
+		op := message.Children[1]
+
+		var controls *ber.Packet
+		if len(message.Children) > 2 {
+			controls = message.Children[2]
+		}
+
+		var responses []*ber.Packet
+		var responseControls *ber.Packet
+
+		switch {
+		case op.Is(ber.ClassApplication, opBindRequest):
+			responses = []*ber.Packet{bind(op, entries)}
+		case op.Is(ber.ClassApplication, opSearchRequest):
+			responses, responseControls = search(op, controls, entries)
+		case op.Is(ber.ClassApplication, opUnbindRequest):
+			return
+		case op.Is(ber.ClassApplication, opExtendedRequest):
+			responses = []*ber.Packet{ldapResult(opExtendedResponse, resultProtocolError, "extended operations are not supported")}
+		default:
+			return
+		}
+
+		for i, response := range responses {
+			reply := ber.NewSequence(ber.NewInteger(ber.ClassUniversal, ber.TagInteger, messageID), response)
+
+			// The last response of a paged search carries the cookie for the next page.
+			if i == len(responses)-1 && responseControls != nil {
+				reply.Append(responseControls)
+			}
+
+			if _, err := conn.Write(reply.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func bind(op *ber.Packet, entries []entry) *ber.Packet {
+	if len(op.Children) < 3 {
+		return ldapResult(opBindResponse, resultProtocolError, "malformed bind request")
+	}
+
+	dn, password := op.Children[1].String(), op.Children[2].String()
+
+	if dn == "" && password == "" {
+		return ldapResult(opBindResponse, resultSuccess, "")
+	}
+
+	for _, entry := range entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password {
+			return ldapResult(opBindResponse, resultSuccess, "")
+		}
+	}
+
+	return ldapResult(opBindResponse, resultInvalidCredentials, "invalid credentials")
+}
+
+// search returns the matching entries followed by the done message, and the controls to send
+// with the done message.
+func search(op *ber.Packet, controls *ber.Packet, entries []entry) ([]*ber.Packet, *ber.Packet) {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{ldapResult(opSearchResultDone, resultProtocolError, "malformed search request")}, nil
+	}
+
+	baseDN := strings.ToLower(op.Children[0].String())
+	filter := op.Children[6]
+
+	var requested []string
+	for _, attribute := range op.Children[7].Children {
+		requested = append(requested, attribute.String())
+	}
+
+	var matches []entry
+	for _, entry := range entries {
+		if strings.HasSuffix(strings.ToLower(entry.DN), baseDN) && matchFilter(filter, entry) {
+			matches = append(matches, entry)
+		}
+	}
+
+	pageSize, offset := pagedResults(controls)
+	end := len(matches)
+
+	if pageSize > 0 && offset+pageSize < end {
+		end = offset + pageSize
+	}
+
+	var responses []*ber.Packet
+
+	for _, entry := range matches[min(offset, len(matches)):end] {
+		attributes := ber.NewSequence()
+
+		for name, values := range entry.Attributes {
+			if !isRequested(name, requested) {
+				continue
+			}
+
+			set := ber.NewSet()
+			for _, value := range values {
+				set.Append(ber.NewOctetString(value))
+			}
+
+			attributes.Append(ber.NewSequence(ber.NewOctetString(name), set))
+		}
+
+		responses = append(responses, ber.NewConstructed(ber.ClassApplication, opSearchResultEntry,
+			ber.NewOctetString(entry.DN), attributes))
+	}
+
+	responses = append(responses, ldapResult(opSearchResultDone, resultSuccess, ""))
+
+	if pageSize == 0 {
+		return responses, nil
+	}
+
+	cookie := ""
+	if end < len(matches) {
+		cookie = strconv.Itoa(end)
+	}
+
+	value := ber.NewSequence(ber.NewInteger(ber.ClassUniversal, ber.TagInteger, int64(len(matches))),
+		ber.NewOctetString(cookie))
+
+	return responses, ber.NewConstructed(ber.ClassContext, 0, ber.NewSequence(ber.NewOctetString(pagedResultsOID),
+		ber.NewPrimitive(ber.ClassUniversal, ber.TagOctetString, value.Bytes())))
+}
+
+// pagedResults returns the page size and the offset encoded in the cookie of a paged results
+// control, or 0 and 0 without one.
+func pagedResults(controls *ber.Packet) (int, int) {
+	if controls == nil {
+		return 0, 0
+	}
+
+	for _, control := range controls.Children {
+		if len(control.Children) < 2 || control.Children[0].String() != pagedResultsOID {
+			continue
+		}
+
+		value, err := ber.Decode(control.Children[len(control.Children)-1].Value)
+		if err != nil || len(value.Children) < 2 {
+			return 0, 0
+		}
+
+		offset, _ := strconv.Atoi(value.Children[1].String())
+
+		return int(value.Children[0].Int()), offset
+	}
+
+	return 0, 0
+}
+
+func matchFilter(filter *ber.Packet, entry entry) bool {
+	if filter.Class != ber.ClassContext {
+		return false
+	}
+
+	switch filter.Tag {
+	case 0: // and
+		for _, child := range filter.Children {
+			if !matchFilter(child, entry) {
+				return false
+			}
+		}
+		return true
+	case 1: // or
+		for _, child := range filter.Children {
+			if matchFilter(child, entry) {
+				return true
+			}
+		}
+		return false
+	case 2: // not
+		return len(filter.Children) == 1 && !matchFilter(filter.Children[0], entry)
+	case 3, 5, 6, 8: // equalityMatch, greaterOrEqual, lessOrEqual, approxMatch
+		if len(filter.Children) < 2 {
+			return false
+		}
+
+		assertion := strings.ToLower(filter.Children[1].String())
+
+		for _, value := range attributeValues(entry, filter.Children[0].String()) {
+			value = strings.ToLower(value)
+
+			if (filter.Tag == 5 && value >= assertion) || (filter.Tag == 6 && value <= assertion) ||
+				((filter.Tag == 3 || filter.Tag == 8) && value == assertion) {
+				return true
+			}
+		}
+		return false
+	case 4: // substrings
+		if len(filter.Children) < 2 {
+			return false
+		}
+
+		for _, value := range attributeValues(entry, filter.Children[0].String()) {
+			if matchSubstrings(strings.ToLower(value), filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	case 7: // present
+		return len(attributeValues(entry, filter.String())) > 0
+	}
+
+	return false
+}
+
+func matchSubstrings(value string, substrings []*ber.Packet) bool {
+	for _, substring := range substrings {
+		part := strings.ToLower(substring.String())
+
+		switch substring.Tag {
+		case 0:
+			if !strings.HasPrefix(value, part) {
+				return false
+			}
+			value = value[len(part):]
+		case 1:
+			index := strings.Index(value, part)
+			if index < 0 {
+				return false
+			}
+			value = value[index+len(part):]
+		case 2:
+			if !strings.HasSuffix(value, part) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func attributeValues(entry entry, name string) []string {
+	for attribute, values := range entry.Attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func isRequested(name string, requested []string) bool {
+	if len(requested) == 0 {
+		return true
+	}
+
+	for _, attribute := range requested {
+		if attribute == "*" || strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ldapResult(op byte, code int64, message string) *ber.Packet {
+	return ber.NewConstructed(ber.ClassApplication, op,
+		ber.NewInteger(ber.ClassUniversal, ber.TagEnumerated, code),
+		ber.NewOctetString(""),
+		ber.NewOctetString(message))
+}
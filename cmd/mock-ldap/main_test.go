@@ -0,0 +1,222 @@
+package main
+
+import (
+	"ems/api/routes"
+	"ems/internal/testenv"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	adminEmail    = "guhandhakshanamurthy@gmail.com"
+	adminPassword = "8438379027"
+)
+
+// testEntries holds the service account, four people who already have EMS accounts or will get
+// one, and enough people without a name to take the sync past the first page. Only HR and the
+// new developer are in groups mapped to a role.
+func testEntries() []entry {
+	entries := []entry{
+		{DN: "cn=admin,dc=ems,dc=com", Password: "admin", Attributes: map[string][]string{
+			"objectClass": {"organizationalRole"}}},
+		{DN: "uid=hr,ou=people,dc=ems,dc=com", Password: "Directory123", Attributes: map[string][]string{
+			"objectClass": {"person"}, "mail": {"hr@ems.com"}, "givenName": {"Hema"}, "sn": {"Raman"},
+			"memberOf": {"cn=hr,ou=groups,dc=ems,dc=com"}}},
+		{DN: "uid=dev,ou=people,dc=ems,dc=com", Password: "Directory123", Attributes: map[string][]string{
+			"objectClass": {"person"}, "mail": {"dev@ems.com"}, "givenName": {"Dev"}, "sn": {"Kumar"},
+			"memberOf": {"CN=Staff,ou=groups,dc=ems,dc=com", "cn=eng,ou=groups,dc=ems,dc=com"}}},
+		{DN: "uid=manager,ou=people,dc=ems,dc=com", Password: "Directory123", Attributes: map[string][]string{
+			"objectClass": {"person"}, "mail": {"manager@ems.com"}, "memberOf": {"cn=contractors,ou=groups,dc=ems,dc=com"}}},
+		{DN: "uid=admin,ou=people,dc=ems,dc=com", Password: "Directory123", Attributes: map[string][]string{
+			"objectClass": {"person"}, "mail": {adminEmail}}},
+	}
+
+	for i := 0; i < 600; i++ {
+		entries = append(entries, entry{DN: fmt.Sprintf("uid=user%d,ou=people,dc=ems,dc=com", i), Attributes: map[string][]string{
+			"objectClass": {"person"}, "mail": {fmt.Sprintf("user%d@ems.com", i)}}})
+	}
+
+	return entries
+}
+
+type testApp struct {
+	t      *testing.T
+	db     *gorm.DB
+	router *gin.Engine
+}
+
+// newTestApp starts the mock directory and an EMS instance with a fresh database that signs in
+// and syncs against it.
+func newTestApp(t *testing.T) *testApp {
+	gin.SetMode(gin.TestMode)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	entries := testEntries()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serve(conn, entries)
+		}
+	}()
+
+	db := testenv.Setup(t, map[string]string{
+		"LDAP_URL":                   "ldap://" + listener.Addr().String(),
+		"LDAP_BASE_DN":               "dc=ems,dc=com",
+		"LDAP_BIND_DN":               "cn=admin,dc=ems,dc=com",
+		"LDAP_BIND_PASSWORD":         "admin",
+		"LDAP_GROUP_ROLES":           "cn=hr,ou=groups,dc=ems,dc=com=>3; cn=staff,ou=groups,dc=ems,dc=com=>2",
+		"LDAP_GROUP_DEPARTMENTS":     "cn=eng,ou=groups,dc=ems,dc=com=>1",
+		"LDAP_SYNC_INTERVAL_MINUTES": "0",
+	})
+
+	router := gin.New()
+	routes.SetupRoutes(router, db)
+
+	return &testApp{t: t, db: db, router: router}
+}
+
+func (a *testApp) call(method, path, token string, body interface{}) (int, map[string]interface{}) {
+	return testenv.Call(a.router, method, path, token, body)
+}
+
+// login returns the access token, or an empty string when the login fails.
+func (a *testApp) login(email, password string) string {
+	_, data := a.call(http.MethodPost, "/api/auth/login", "", map[string]string{"email": email, "password": password})
+
+	token, _ := data["token"].(string)
+
+	return token
+}
+
+func (a *testApp) scan(out interface{}, query string, args ...interface{}) {
+	if err := a.db.Raw(query, args...).Scan(out).Error; err != nil {
+		a.t.Fatal(err)
+	}
+}
+
+func emails(changes interface{}) []string {
+	var emails []string
+
+	list, _ := changes.([]interface{})
+	for _, change := range list {
+		email, _ := change.(map[string]interface{})["email"].(string)
+		emails = append(emails, email)
+	}
+
+	return emails
+}
+
+func TestSyncDryRunChangesNothing(t *testing.T) {
+	app := newTestApp(t)
+	admin := app.login(adminEmail, adminPassword)
+
+	if code, _ := app.call(http.MethodPost, "/api/hr/directory/sync?dryRun=true", app.login("manager@ems.com", "9999999999"), nil); code != http.StatusUnauthorized {
+		t.Fatalf("sync by a manager = %d", code)
+	}
+
+	code, data := app.call(http.MethodPost, "/api/hr/directory/sync?dryRun=true", admin, nil)
+	if code != http.StatusOK || data["dryRun"] != true {
+		t.Fatalf("dry run = %d %v", code, data["dryRun"])
+	}
+
+	if data["directoryUsers"] != 604.0 {
+		t.Errorf("directory users = %v", data["directoryUsers"])
+	}
+	if created := emails(data["created"]); fmt.Sprint(created) != "[dev@ems.com]" {
+		t.Errorf("created = %v", created)
+	}
+	if updated := emails(data["updated"]); fmt.Sprint(updated) != "[hr@ems.com]" {
+		t.Errorf("updated = %v", updated)
+	}
+
+	var count int64
+	app.scan(&count, `SELECT COUNT(*) FROM User WHERE Email = 'dev@ems.com' OR AuthSource <> 'local'`)
+	if count != 0 {
+		t.Fatalf("users changed by the dry run = %d", count)
+	}
+
+	if app.login("hr@ems.com", "8888888888") == "" {
+		t.Fatal("local password stopped working after a dry run")
+	}
+}
+
+func TestSyncAndAuthenticate(t *testing.T) {
+	app := newTestApp(t)
+	admin := app.login(adminEmail, adminPassword)
+
+	if code, _ := app.call(http.MethodPost, "/api/hr/directory/sync", admin, nil); code != http.StatusOK {
+		t.Fatalf("sync = %d", code)
+	}
+
+	var roles []uint
+	app.scan(&roles, `SELECT RoleID FROM User WHERE Email IN (?, 'hr@ems.com', 'dev@ems.com') ORDER BY ID`, adminEmail)
+	if fmt.Sprint(roles) != "[1 3 2]" {
+		t.Fatalf("roles after the sync = %v", roles)
+	}
+
+	var departmentID uint
+	app.scan(&departmentID, `SELECT dm.DepartmentID FROM DepartmentMember dm JOIN User u ON u.ID = dm.UserID
+		WHERE u.Email = 'dev@ems.com' AND dm.IsActive = 1`)
+	if departmentID != 1 {
+		t.Fatalf("department of the synced user = %d", departmentID)
+	}
+
+	var audited []string
+	app.scan(&audited, `SELECT Action || ' ' || Entity FROM AuditLog WHERE ActorType = 'user' ORDER BY ID`)
+	if fmt.Sprint(audited) != "[update user create user create departmentMember]" {
+		t.Fatalf("audit log of the sync = %v", audited)
+	}
+
+	if app.login("hr@ems.com", "8888888888") != "" {
+		t.Fatal("the local password still works for a directory user")
+	}
+
+	// Users in no group mapped to a role are left to sign in with their local password.
+	if app.login("manager@ems.com", "9999999999") == "" || app.login(adminEmail, adminPassword) == "" {
+		t.Fatal("the local password stopped working for a user without a mapped group")
+	}
+	if app.login("hr@ems.com", "Wrong123") != "" {
+		t.Fatal("a wrong directory password was accepted")
+	}
+
+	token := app.login("hr@ems.com", "Directory123")
+	if token == "" {
+		t.Fatal("directory login failed")
+	}
+	if code, _ := app.call(http.MethodGet, "/api/auth/sessions", token, nil); code != http.StatusOK {
+		t.Fatalf("sessions with the directory token = %d", code)
+	}
+
+	var authMethod string
+	app.scan(&authMethod, `SELECT AuthMethod FROM UserSession ORDER BY ID DESC LIMIT 1`)
+	if authMethod != "ldap" {
+		t.Fatalf("session auth method = %s", authMethod)
+	}
+
+	if app.login("dev@ems.com", "Directory123") == "" {
+		t.Fatal("login of the provisioned user failed")
+	}
+
+	if code, _ := app.call(http.MethodPost, "/api/forgotPassword/sendOtp", "", map[string]string{"email": "hr@ems.com"}); code != http.StatusBadRequest {
+		t.Fatalf("forgot password for a directory user = %d", code)
+	}
+
+	_, data := app.call(http.MethodPost, "/api/hr/directory/sync", admin, nil)
+	if data["unchanged"] != 2.0 || len(emails(data["created"])) != 0 || len(emails(data["updated"])) != 0 {
+		t.Fatalf("second sync = %v", data)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"flag"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+const keyID = "mock"
+
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	expiresAt     time.Time
+}
+
+type mockProvider struct {
+	issuer        string
+	clientID      string
+	clientSecret  string
+	email         string
+	givenName     string
+	familyName    string
+	emailVerified bool
+	key           *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]*authorization
+}
+
+/**
+ * @Function: main
+ * @Description: Runs a local OpenID Connect provider for developing and testing single sign-on.
+ * It approves every authorization request for the configured user, or for login_hint when
+ * given. Usage: go run ./cmd/mock-oidc -addr :9000, then set OIDC_ISSUER_URL=http://localhost:9000
+ *
+ * @Params:
+ *    - None
+ *
+ * @Returns:
+ *    - None
+ */
+func main() {
+	addr := flag.String("addr", "127.0.0.1:9000", "address to listen on")
+	issuer := flag.String("issuer", "", "issuer URL, defaults to http://<addr>")
+	clientID := flag.String("client-id", "ems", "client ID accepted by the provider")
+	clientSecret := flag.String("client-secret", "secret", "client secret accepted by the provider")
+	email := flag.String("email", "guhandhakshanamurthy@gmail.com", "email of the signed in user")
+	givenName := flag.String("given-name", "Mock", "given name of the signed in user")
+	familyName := flag.String("family-name", "User", "family name of the signed in user")
+	emailVerified := flag.Bool("email-verified", true, "whether the email is reported as verified")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *issuer == "" {
+		*issuer = "http://" + *addr
+	}
+
+	provider := &mockProvider{
+		issuer:        *issuer,
+		clientID:      *clientID,
+		clientSecret:  *clientSecret,
+		email:         *email,
+		givenName:     *givenName,
+		familyName:    *familyName,
+		emailVerified: *emailVerified,
+		key:           key,
+		codes:         make(map[string]*authorization),
+	}
+
+	router := gin.Default()
+	provider.register(router)
+
+	log.Printf("mock OpenID Connect provider %s listening on %s", provider.issuer, *addr)
+
+	if err := router.Run(*addr); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// register adds the discovery document, the keys and the code flow endpoints to the router.
+func (p *mockProvider) register(router *gin.Engine) {
+	router.GET("/.well-known/openid-configuration", p.discovery)
+	router.GET("/jwks", p.jwks)
+	router.GET("/authorize", p.authorize)
+	router.POST("/token", p.token)
+}
+
+func (p *mockProvider) discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *mockProvider) jwks(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"keys": []gin.H{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize approves the request straight away and redirects back with a code.
+func (p *mockProvider) authorize(c *gin.Context) {
+	if c.Query("response_type") != "code" || c.Query("client_id") != p.clientID {
+		c.String(http.StatusBadRequest, "unsupported response type or unknown client")
+		return
+	}
+
+	if c.Query("code_challenge_method") != "S256" || c.Query("code_challenge") == "" {
+		c.String(http.StatusBadRequest, "PKCE with S256 is required")
+		return
+	}
+
+	redirectURI, err := url.Parse(c.Query("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		c.String(http.StatusBadRequest, "invalid redirect_uri")
+		return
+	}
+
+	email := p.email
+	if loginHint := c.Query("login_hint"); loginHint != "" {
+		email = loginHint
+	}
+
+	code := randomString()
+
+	p.mu.Lock()
+	p.codes[code] = &authorization{
+		clientID:      p.clientID,
+		redirectURI:   redirectURI.String(),
+		nonce:         c.Query("nonce"),
+		codeChallenge: c.Query("code_challenge"),
+		email:         email,
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	query := redirectURI.Query()
+	query.Set("code", code)
+	query.Set("state", c.Query("state"))
+	redirectURI.RawQuery = query.Encode()
+
+	c.Redirect(http.StatusFound, redirectURI.String())
+}
+
+// token redeems a code once, checking the client credentials and the PKCE verifier.
+func (p *mockProvider) token(c *gin.Context) {
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+
+	if !ok || clientID != p.clientID || clientSecret != p.clientSecret {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	authorization := p.codes[c.PostForm("code")]
+	delete(p.codes, c.PostForm("code"))
+	p.mu.Unlock()
+
+	if c.PostForm("grant_type") != "authorization_code" || authorization == nil ||
+		time.Now().After(authorization.expiresAt) || authorization.redirectURI != c.PostForm("redirect_uri") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(c.PostForm("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.codeChallenge {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	subject := sha256.Sum256([]byte(authorization.email))
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            base64.RawURLEncoding.EncodeToString(subject[:12]),
+		"aud":            authorization.clientID,
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          authorization.nonce,
+		"email":          authorization.email,
+		"email_verified": p.emailVerified,
+		"given_name":     p.givenName,
+		"family_name":    p.familyName,
+	})
+	idToken.Header["kid"] = keyID
+
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func randomString() string {
+	value := make([]byte, 24)
+	if _, err := rand.Read(value); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(value)
+}
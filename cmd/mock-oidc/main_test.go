@@ -0,0 +1,189 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"ems/api/routes"
+	"ems/internal/testenv"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const adminEmail = "guhandhakshanamurthy@gmail.com"
+
+type testApp struct {
+	t        *testing.T
+	db       *gorm.DB
+	router   *gin.Engine
+	provider *mockProvider
+}
+
+// newTestApp starts the mock provider and an EMS instance with a fresh database that signs in
+// through it.
+func newTestApp(t *testing.T, jitProvisioning bool) *testApp {
+	gin.SetMode(gin.TestMode)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &mockProvider{
+		clientID:      "ems",
+		clientSecret:  "secret",
+		email:         adminEmail,
+		givenName:     "Mock",
+		familyName:    "User",
+		emailVerified: true,
+		key:           key,
+		codes:         make(map[string]*authorization),
+	}
+
+	providerRouter := gin.New()
+	provider.register(providerRouter)
+
+	server := httptest.NewServer(providerRouter)
+	t.Cleanup(server.Close)
+	provider.issuer = server.URL
+
+	db := testenv.Setup(t, map[string]string{
+		"OIDC_ISSUER_URL":       server.URL,
+		"OIDC_CLIENT_ID":        "ems",
+		"OIDC_CLIENT_SECRET":    "secret",
+		"OIDC_REDIRECT_URL":     "http://localhost:3000/sso",
+		"OIDC_JIT_PROVISIONING": map[bool]string{true: "true", false: "false"}[jitProvisioning],
+	})
+
+	router := gin.New()
+	routes.SetupRoutes(router, db)
+
+	return &testApp{t: t, db: db, router: router, provider: provider}
+}
+
+func (a *testApp) call(method, path, token string, body interface{}) (int, map[string]interface{}) {
+	return testenv.Call(a.router, method, path, token, body)
+}
+
+// signIn starts a login, lets the provider approve it for loginHint (its default user when
+// empty) and posts the code and state from the redirect back to the callback.
+func (a *testApp) signIn(loginHint string) (string, int, map[string]interface{}) {
+	code, data := a.call(http.MethodGet, "/api/auth/oidc/login", "", nil)
+	if code != http.StatusOK {
+		a.t.Fatalf("start login = %d", code)
+	}
+
+	authorizationURL, _ := data["authorizationURL"].(string)
+	if loginHint != "" {
+		authorizationURL += "&login_hint=" + url.QueryEscape(loginHint)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Get(authorizationURL)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	res.Body.Close()
+
+	redirect, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || res.StatusCode != http.StatusFound {
+		a.t.Fatalf("authorize = %d %s", res.StatusCode, res.Header.Get("Location"))
+	}
+	if redirect.Host != "localhost:3000" || redirect.Path != "/sso" {
+		a.t.Fatalf("redirected to %s", redirect)
+	}
+
+	state := redirect.Query().Get("state")
+	code, data = a.call(http.MethodPost, "/api/auth/oidc/callback", "", map[string]string{
+		"code":  redirect.Query().Get("code"),
+		"state": state,
+	})
+
+	return state, code, data
+}
+
+func (a *testApp) count(query string, args ...interface{}) int64 {
+	var count int64
+	if err := a.db.Raw(query, args...).Scan(&count).Error; err != nil {
+		a.t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestCallbackLinksExistingUser(t *testing.T) {
+	app := newTestApp(t, false)
+
+	state, code, data := app.signIn("")
+	if code != http.StatusOK || data["token"] == nil {
+		t.Fatalf("callback = %d %v", code, data)
+	}
+
+	if code, _ := app.call(http.MethodGet, "/api/auth/sessions", data["token"].(string), nil); code != http.StatusOK {
+		t.Fatalf("sessions with the single sign-on token = %d", code)
+	}
+
+	if n := app.count(`SELECT COUNT(*) FROM UserIdentity WHERE Email = ?`, adminEmail); n != 1 {
+		t.Fatalf("linked identities = %d", n)
+	}
+
+	if code, _ := app.call(http.MethodPost, "/api/auth/oidc/callback", "", map[string]string{"code": "replayed", "state": state}); code != http.StatusUnauthorized {
+		t.Fatalf("callback with a used state = %d", code)
+	}
+
+	if code, _ := app.call(http.MethodPost, "/api/auth/oidc/callback", "", map[string]string{"code": "forged", "state": "forged"}); code != http.StatusUnauthorized {
+		t.Fatalf("callback with an unknown state = %d", code)
+	}
+
+	// The second sign in finds the user by the linked identity.
+	if _, code, _ := app.signIn(""); code != http.StatusOK {
+		t.Fatalf("second callback = %d", code)
+	}
+
+	if n := app.count(`SELECT COUNT(*) FROM UserIdentity`); n != 1 {
+		t.Fatalf("identities after the second sign in = %d", n)
+	}
+}
+
+func TestCallbackProvisionsUser(t *testing.T) {
+	// Employees need a department to sign in, which a provisioned user does not have yet.
+	t.Setenv("OIDC_DEFAULT_ROLE_ID", "2")
+	app := newTestApp(t, true)
+
+	if _, code, data := app.signIn("new.joiner@ems.com"); code != http.StatusOK || data["token"] == nil {
+		t.Fatalf("callback = %d %v", code, data)
+	}
+
+	if n := app.count(`SELECT COUNT(*) FROM User WHERE Email = ? AND RoleID = 2`, "new.joiner@ems.com"); n != 1 {
+		t.Fatalf("provisioned users = %d", n)
+	}
+}
+
+func TestCallbackRejectsUnknownUserWithoutProvisioning(t *testing.T) {
+	app := newTestApp(t, false)
+
+	if _, code, _ := app.signIn("stranger@ems.com"); code != http.StatusUnauthorized {
+		t.Fatalf("callback = %d", code)
+	}
+
+	if n := app.count(`SELECT COUNT(*) FROM User WHERE Email = ?`, "stranger@ems.com"); n != 0 {
+		t.Fatalf("users created = %d", n)
+	}
+}
+
+func TestCallbackRejectsUnverifiedEmail(t *testing.T) {
+	app := newTestApp(t, false)
+	app.provider.emailVerified = false
+
+	if _, code, _ := app.signIn(""); code != http.StatusUnauthorized {
+		t.Fatalf("callback = %d", code)
+	}
+
+	if n := app.count(`SELECT COUNT(*) FROM UserIdentity`); n != 0 {
+		t.Fatalf("linked identities = %d", n)
+	}
+}
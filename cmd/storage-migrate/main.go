@@ -0,0 +1,104 @@
+package main
+
+import (
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/infrastructure/database"
+	"ems/infrastructure/repository"
+	"ems/infrastructure/storage"
+	"flag"
+	"log"
+	"mime"
+	"path"
+)
+
+/**
+ * @Function: main
+ * @Description: Copies every UserDocument file from one storage backend to another and
+ * points the rows at the new backend. Usage: go run ./cmd/storage-migrate -from local -to s3
+ *
+ * @Params:
+ *    - None
+ *
+ * @Returns:
+ *    - None
+ */
+func main() {
+	from := flag.String("from", storage.LocalBackend, "storage backend to migrate from")
+	to := flag.String("to", storage.S3Backend, "storage backend to migrate to")
+	deleteSource := flag.Bool("delete-source", false, "delete files from the source backend after copying")
+	flag.Parse()
+
+	if *from == *to {
+		log.Fatal("source and destination backends must differ")
+	}
+
+	if err := config.Load(); err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := database.InitDB()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := storage.NewStorageForBackend(*from)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	destination, err := storage.NewStorageForBackend(*to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	documentRepository := repository.NewDocumentRepository(db)
+
+	documents, err := documentRepository.FetchDocumentsByStorageBackend(*from)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var migrated, failed int
+
+	for _, document := range documents {
+		key, err := storage.NormalizeKey(document.FilePath)
+		if err != nil {
+			log.Printf("document %d: %v", document.ID, err)
+			failed++
+			continue
+		}
+
+		if err := copyFile(source, destination, key); err != nil {
+			log.Printf("document %d: %v", document.ID, err)
+			failed++
+			continue
+		}
+
+		if err := documentRepository.UpdateDocumentStorage(document.ID, key, *to); err != nil {
+			log.Printf("document %d: %v", document.ID, err)
+			failed++
+			continue
+		}
+
+		if *deleteSource {
+			if err := source.Delete(key); err != nil {
+				log.Printf("document %d: failed to delete source file: %v", document.ID, err)
+			}
+		}
+
+		migrated++
+	}
+
+	log.Printf("Migrated %d documents from %s to %s, %d failed", migrated, *from, *to, failed)
+}
+
+func copyFile(source, destination domain.FileStorage, key string) error {
+	file, err := source.Open(key)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return destination.Save(key, file, mime.TypeByExtension(path.Ext(key)))
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/utils"
+)
+
+type AuditService interface {
+	FetchAuditLogs(filters *request.FetchAuditLogs) (*utils.PaginationResponse, error)
+	VerifyAuditLogs() (*response.VerifyAuditLog, error)
+}
+
+type AuditRepository interface {
+	FetchAuditLogs(filters *request.FetchAuditLogs) (*utils.PaginationResponse, error)
+	FetchAuditLogChain(afterID uint, limit int) ([]schema.AuditLog, error)
+}
@@ -1,13 +1,90 @@
 package domain
 
 import (
+	"ems/app/model/constant"
 	"ems/app/model/request"
 	"ems/app/model/response"
+	"ems/app/model/schema"
+	"time"
 )
 
 type AuthService interface {
-	Login(req *request.Login) (*response.FetchUserByEmail, error)
-	Logout(userID uint) error
+	Login(req *request.Login, device *request.SessionDevice) (*response.FetchUserByEmail, *response.TwoFactorChallenge, error)
+	VerifyTwoFactorLogin(req *request.VerifyTwoFactor, device *request.SessionDevice) (*response.FetchUserByEmail, error)
+	SetupTwoFactorForLogin(req *request.TwoFactorToken) (*response.TwoFactorSetup, error)
+	EnableTwoFactorForLogin(req *request.VerifyTwoFactor, device *request.SessionDevice) (*response.TwoFactorEnabled, error)
+	FetchTwoFactorStatus(userID uint) (*response.TwoFactorStatus, error)
+	SetupTwoFactor(userID uint) (*response.TwoFactorSetup, error)
+	EnableTwoFactor(userID uint, req *request.TwoFactorCode) (*response.TwoFactorEnabled, error)
+	DisableTwoFactor(userID uint, req *request.TwoFactorCode) error
+	RegenerateRecoveryCodes(userID uint, req *request.TwoFactorCode) (*response.TwoFactorEnabled, error)
+	ResetTwoFactor(userID uint) error
+	Logout(sessionID uint) error
 	SendForgotPasswordOtp(req *request.SendForgotPasswordOtp) error
-	VerifyForgotPasswordOtp(req *request.VerifyForgotPasswordOtp) (interface{}, error)
+	VerifyForgotPasswordOtp(req *request.VerifyForgotPasswordOtp, device *request.SessionDevice) (*response.SessionTokens, *response.TwoFactorChallenge, error)
+	RefreshSession(req *request.RefreshSession, device *request.SessionDevice) (*response.SessionTokens, error)
+	FetchSessions(userID, currentSessionID uint) ([]response.FetchUserSessions, error)
+	RevokeSession(userID, sessionID uint) error
+	RevokeUserSessions(userID uint) error
+	FetchLockoutStatus(userID uint) (*response.LockoutStatus, error)
+	UnlockUser(userID, unlockedBy uint, device *request.SessionDevice) error
+	StartOIDCLogin() (*response.OIDCAuthorization, error)
+	CompleteOIDCLogin(req *request.CompleteOIDCLogin, device *request.SessionDevice) (*response.FetchUserByEmail, error)
+	StartImpersonation(impersonatorID, userID uint, req *request.StartImpersonation,
+		device *request.SessionDevice) (*response.FetchUserByEmail, error)
+	FetchImpersonations() ([]response.FetchImpersonations, error)
+	FetchImpersonatedRequests(sessionID uint) ([]response.FetchImpersonatedRequests, error)
+	EndImpersonation(sessionID, endedBy uint, device *request.SessionDevice) error
+}
+
+type SessionRepository interface {
+	CreateSession(userID uint, refreshTokenHash string, authMethod constant.AuthMethod, device *request.SessionDevice,
+		expiresAt time.Time) (uint, error)
+	GetSessionByRefreshTokenHash(refreshTokenHash string) (*response.UserSession, error)
+	RotateRefreshToken(sessionID uint, refreshTokenHash, newRefreshTokenHash string, device *request.SessionDevice) (bool, error)
+	GetActiveSession(sessionID, userID uint) (*response.UserSession, error)
+	FetchUserSessions(userID uint) ([]response.FetchUserSessions, error)
+	GetSessionByID(sessionID uint) (*response.UserSession, error)
+	RevokeSession(sessionID uint) error
+	RevokeUserSessions(userID uint) error
+	CreateImpersonationSession(userID, impersonatorID uint, reason, refreshTokenHash string, device *request.SessionDevice,
+		expiresAt time.Time) (uint, error)
+	RecordImpersonatedRequest(sessionID, impersonatorID, userID uint, method, path string, statusCode int) error
+	FetchImpersonations() ([]response.FetchImpersonations, error)
+	FetchImpersonatedRequests(sessionID uint) ([]response.FetchImpersonatedRequests, error)
+}
+
+type TwoFactorRepository interface {
+	GetTwoFactor(userID uint) (*schema.UserTwoFactor, error)
+	SaveTwoFactorSecret(userID uint, secret string) error
+	EnableTwoFactor(userID uint, step int64, recoveryCodeHashes []string) error
+	UseTwoFactorStep(userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uint, recoveryCodeHashes []string) error
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uint) (int64, error)
+	RemoveTwoFactor(userID uint) error
+}
+
+type LoginThrottleRepository interface {
+	GetLoginThrottle(subject string) (*schema.LoginThrottle, error)
+	RecordFailedAttempt(subject string, windowStart, lockoutResetBefore time.Time) (*schema.LoginThrottle, error)
+	LockSubject(throttleID uint, lockedUntil time.Time) error
+	ResetLoginThrottle(subject string) error
+	CreateAuthLog(data *schema.AuthLog) error
+	FetchUserAuthLogs(userID uint, limit int) ([]response.FetchAuthLogs, error)
+}
+
+type OIDCRepository interface {
+	CreateOIDCLoginState(data *schema.OIDCLoginState) error
+	UseOIDCLoginState(state string) (*schema.OIDCLoginState, error)
+	GetUserIDByIdentity(issuer, subject string) (uint, error)
+	GetUserIdentity(userID uint, issuer string) (*schema.UserIdentity, error)
+	CreateUserIdentity(data *schema.UserIdentity) error
+	CreateProvisionedUser(actor *request.AuditActor, req *request.CreateUser, hashedPassword string, identity *schema.UserIdentity) (uint, error)
+}
+
+// IdentityProvider is an OpenID Connect provider used for single sign-on.
+type IdentityProvider interface {
+	AuthorizationURL(state, nonce, codeChallenge string) (string, error)
+	Exchange(code, codeVerifier, nonce string) (*response.OIDCIdentity, error)
 }
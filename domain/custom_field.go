@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+)
+
+type CustomFieldService interface {
+	CreateCustomField(req *request.CreateCustomField) error
+	FetchCustomFields() ([]response.FetchCustomFields, error)
+	UpdateCustomField(customFieldID uint, req *request.UpdateCustomField) error
+	RemoveCustomField(customFieldID uint) error
+	UpdateUserCustomFieldValues(req *request.UpdateUserCustomFieldValues) error
+}
+
+type CustomFieldRepository interface {
+	CreateCustomField(req *request.CreateCustomField) error
+	IsCustomFieldKeyExists(key string) (bool, error)
+	IsCustomFieldKeyExistsExceptID(id uint, key string) (bool, error)
+	FetchCustomFields() ([]response.FetchCustomFields, error)
+	GetCustomFieldByID(id uint) (*schema.CustomField, error)
+	GetCustomFieldByKey(key string) (*schema.CustomField, error)
+	UpdateCustomField(id uint, req *request.UpdateCustomField) error
+	RemoveCustomField(id uint) error
+	FetchUserCustomFieldValues(userID uint, visibilities []uint) ([]response.FetchUserCustomFieldValue, error)
+	UpdateUserCustomFieldValues(userID uint, values []request.CustomFieldValue) error
+}
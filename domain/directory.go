@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+)
+
+// Directory is an LDAP or Active Directory server that users sign in against and that
+// assigns roles and departments through group membership.
+type Directory interface {
+	Authenticate(email, password string) (*response.DirectoryUser, error)
+	FetchUsers() ([]response.DirectoryUser, error)
+}
+
+type DirectorySyncService interface {
+	SyncDirectory(actor *request.AuditActor, dryRun bool) (*response.DirectorySyncReport, error)
+}
+
+type DirectoryRepository interface {
+	FetchDirectorySyncUsers() ([]response.DirectorySyncUser, error)
+	FetchRoleNames() (map[uint]string, error)
+	FetchDepartmentNames() (map[uint]string, error)
+	CreateDirectoryUser(actor *request.AuditActor, req *request.CreateUser, hashedPassword string) (uint, error)
+	UpdateDirectoryUser(actor *request.AuditActor, userID uint, req *request.UpdateDirectoryUser) error
+	SetUserDepartment(actor *request.AuditActor, userID uint, currentDepartmentID *uint, departmentID uint) error
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/utils"
+)
+
+type DocumentService interface {
+	CreateDocumentCategory(actor *request.AuditActor, req *request.CreateDocumentCategory) error
+	FetchDocumentCategories() ([]response.FetchDocumentCategories, error)
+	UpdateDocumentCategory(actor *request.AuditActor, categoryID uint, req *request.UpdateDocumentCategory) error
+	RemoveDocumentCategory(actor *request.AuditActor, categoryID uint) error
+	UploadDocuments(actor *request.AuditActor, uploadedBy uint, req *request.UploadDocument, documents []response.UploadedDocument) error
+	FetchUserDocuments(req *request.FetchUserDocuments) ([]response.FetchUserDocument, error)
+	FetchDocumentVersions(documentID uint) ([]response.FetchUserDocument, error)
+	FetchMissingDocuments(filters *request.FetchMissingDocuments) (*utils.PaginationResponse, error)
+	FetchDocumentForDownload(documentID, viewerID, viewerRoleID uint) (*response.DownloadDocument, error)
+	RemoveDocument(actor *request.AuditActor, documentID uint) (*response.DownloadDocument, error)
+	ValidateUploads(userID uint, documentCategoryID *uint, documents []response.UploadedDocument) error
+	QuarantineDocument(actor *request.AuditActor, uploadedBy, userID uint, document *response.UploadedDocument, signature string) error
+	FetchQuarantinedDocuments() ([]response.FetchQuarantinedDocument, error)
+	RemoveQuarantinedDocument(actor *request.AuditActor, quarantineID uint) (*response.QuarantinedDocument, error)
+}
+
+type DocumentRepository interface {
+	CreateDocumentCategory(actor *request.AuditActor, req *request.CreateDocumentCategory) error
+	FetchDocumentCategories() ([]response.FetchDocumentCategories, error)
+	GetDocumentCategoryByID(categoryID uint) (*schema.DocumentCategory, error)
+	IsDocumentCategoryCodeExists(code string) (bool, error)
+	IsDocumentCategoryCodeExistsExceptID(categoryID uint, code string) (bool, error)
+	UpdateDocumentCategory(actor *request.AuditActor, categoryID uint, req *request.UpdateDocumentCategory) error
+	RemoveDocumentCategory(actor *request.AuditActor, categoryID uint) error
+	GetLatestDocumentChecksum(userID, categoryID uint, fileName string) (*string, error)
+	CreateDocumentVersions(actor *request.AuditActor, uploadedBy uint, req *request.UploadDocument, documents []response.UploadedDocument) error
+	CreateGeneratedLetters(actor *request.AuditActor, uploadedBy uint, letters []response.RenderedLetter) error
+	FetchUserDocuments(req *request.FetchUserDocuments) ([]response.FetchUserDocument, error)
+	IsDocumentExists(documentID uint) (bool, error)
+	GetDocumentByID(documentID uint) (*response.DownloadDocument, error)
+	RemoveDocument(actor *request.AuditActor, documentID uint) error
+	FetchDocumentVersions(documentID uint) ([]response.FetchUserDocument, error)
+	FetchMissingDocuments(filters *request.FetchMissingDocuments) ([]response.FetchMissingDocuments, uint, error)
+	FetchExpiringDocuments(days uint) ([]response.ExpiringDocument, error)
+	MarkDocumentReminderSent(documentID uint) error
+	FetchDocumentsByStorageBackend(backend string) ([]response.StoredDocument, error)
+	UpdateDocumentStorage(documentID uint, filePath, backend string) error
+	FetchUserStorageUsage(userID uint) (int64, error)
+	CreateQuarantinedDocument(actor *request.AuditActor, uploadedBy, userID uint, document *response.UploadedDocument, signature string) error
+	FetchQuarantinedDocuments() ([]response.FetchQuarantinedDocument, error)
+	GetQuarantinedDocumentByID(quarantineID uint) (*response.QuarantinedDocument, error)
+	RemoveQuarantinedDocument(actor *request.AuditActor, quarantineID uint) error
+}
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+)
+
+type EmployeeDataService interface {
+	ExportEmployees(filters *request.ExportEmployees) ([]response.ExportEmployee, error)
+	ImportEmployees(req []request.ImportEmployee) (*response.ImportEmployees, error)
+}
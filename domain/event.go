@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/response"
+	"time"
+)
+
+// EventHandler reacts to a domain event. An event is handed to it again when it fails, so it
+// has to be safe to repeat.
+type EventHandler func(event *response.OutboxEvent) error
+
+// EventBus delivers the events in the outbox to the in-process subscribers of their type.
+type EventBus interface {
+	Subscribe(subscriber string, handler EventHandler, eventTypes ...constant.EventType)
+	DispatchEvents() error
+}
+
+type EventRepository interface {
+	ClaimPendingEvents(limit int, lease time.Duration) ([]response.OutboxEvent, error)
+	FetchEventSubscribers(eventID uint) ([]string, error)
+	CreateEventDelivery(eventID uint, subscriber string) error
+	CompleteEvent(eventID uint) error
+	RetryEvent(eventID uint, attempts int, nextAttemptAt time.Time, lastError string) error
+	FailEvent(eventID uint, attempts int, lastError string) error
+}
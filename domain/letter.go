@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+)
+
+type LetterService interface {
+	CreateLetterTemplate(createdBy uint, req *request.CreateLetterTemplate) error
+	FetchLetterTemplates() ([]response.FetchLetterTemplates, error)
+	UpdateLetterTemplate(letterTemplateID, updatedBy uint, req *request.UpdateLetterTemplate) error
+	RemoveLetterTemplate(letterTemplateID uint) error
+	FetchLetterTemplateVersions(letterTemplateID uint) ([]response.FetchLetterTemplateVersions, error)
+	RenderLetter(letterTemplateID, userID uint) (*response.RenderedLetter, error)
+	RenderLetters(req *request.GenerateLetters) ([]response.RenderedLetter, *response.GenerateLetters, error)
+	StoreLetters(actor *request.AuditActor, uploadedBy uint, letters []response.RenderedLetter) error
+}
+
+type LetterRepository interface {
+	CreateLetterTemplate(createdBy uint, req *request.CreateLetterTemplate) error
+	FetchLetterTemplates() ([]response.FetchLetterTemplates, error)
+	GetLetterTemplateByID(letterTemplateID uint) (*response.LetterTemplate, error)
+	IsLetterTemplateNameExists(name string) (bool, error)
+	IsLetterTemplateNameExistsExceptID(letterTemplateID uint, name string) (bool, error)
+	UpdateLetterTemplate(letterTemplateID, updatedBy uint, req *request.UpdateLetterTemplate) error
+	RemoveLetterTemplate(letterTemplateID uint) error
+	FetchLetterTemplateVersions(letterTemplateID uint) ([]response.FetchLetterTemplateVersions, error)
+	GetLetterData(userID uint) (*response.LetterData, error)
+	FetchDepartmentUserIDs(departmentID uint) ([]uint, error)
+}
@@ -4,21 +4,23 @@ import (
 	"ems/app/model/request"
 	"ems/app/model/response"
 	"ems/utils"
+	"time"
 )
 
 type NoticeService interface {
-	ApplyNotice(departmentMemberID uint, req *request.ApplyNotice) error
+	ApplyNotice(actor *request.AuditActor, departmentMemberID uint, req *request.ApplyNotice) error
 	FetchActiveUserNotices(roleID uint, filters *request.CommonRequest) (*utils.PaginationResponse, error)
 	FetchNotice(departmentMemberID uint) (*response.FetchActiveUserNotices, error)
-	ApproveNotice(approvedBy uint, req *request.ApproveNotice) error
+	ApproveNotice(actor *request.AuditActor, approvedBy uint, req *request.ApproveNotice) error
 }
 
 type NoticeRepository interface {
-	ApplyNotice(departmentMemberID uint, req *request.ApplyNotice) error
+	ApplyNotice(actor *request.AuditActor, departmentMemberID uint, req *request.ApplyNotice) error
 	FetchActiveUserNotices(roleID uint, filters *request.CommonRequest) (*utils.PaginationResponse, error)
 	GetNoticeUserCount() (int, error)
 	GetNoticeUserCountByDepartment(departmentID uint) (int, error)
 	FetchNotice(departmentMemberID uint) (*response.FetchActiveUserNotices, error)
-	ApproveNotice(departmentMemberID, approvedBy uint, req *request.ApproveNotice) error
+	ApproveNotice(actor *request.AuditActor, departmentMemberID, approvedBy uint, req *request.ApproveNotice) error
 	IsApproveExistsByUser(departmentMemberID uint) (bool, error)
+	RemoveServedNoticeUsers(actor *request.AuditActor, now time.Time) error
 }
@@ -6,24 +6,24 @@ import (
 )
 
 type PermissionService interface {
-	RequestPermission(departmentMemberID uint, req *request.RequestPermission) error
+	RequestPermission(actor *request.AuditActor, departmentMemberID uint, req *request.RequestPermission) error
 	FetchOwnPermissions(departmentMemberID uint, filters *request.CommonRequestWithDateFilter) (*utils.PaginationResponse, error)
 	FetchDepartmentMemberPermissions(departmentID uint, filters *request.CommonRequestWithDateFilter) (*utils.PaginationResponse, error)
-	UpdatePermissionStatus(permissionID, approvedBy uint, req *request.UpdatePermissionStatus) error
-	UpdatePermissionRequest(departmentMemberID uint, permissionID uint, req *request.RequestPermission) error
-	RemovePermissionRequest(permissionID uint) error
+	UpdatePermissionStatus(actor *request.AuditActor, permissionID, approvedBy uint, req *request.UpdatePermissionStatus) error
+	UpdatePermissionRequest(actor *request.AuditActor, departmentMemberID uint, permissionID uint, req *request.RequestPermission) error
+	RemovePermissionRequest(actor *request.AuditActor, permissionID uint) error
 	FetchLeadAndHRPermissions(filters *request.CommonRequestWithDateFilter) (*utils.PaginationResponse, error)
 }
 
 type PermissionRepository interface {
-	RequestPermission(departmentMemberID uint, req *request.RequestPermission) error
+	RequestPermission(actor *request.AuditActor, departmentMemberID uint, req *request.RequestPermission) error
 	FetchOwnPermissions(departmentMemberID uint, filters *request.CommonRequestWithDateFilter) (*utils.PaginationResponse, error)
 	FetchDepartmentMemberPermissions(departmentID uint, filters *request.CommonRequestWithDateFilter) (*utils.PaginationResponse, error)
-	UpdatePermissionStatus(permissionID, approvedBy uint, req *request.UpdatePermissionStatus) error
+	UpdatePermissionStatus(actor *request.AuditActor, permissionID, approvedBy uint, req *request.UpdatePermissionStatus) error
 	IsPermissionExistWithoutApproval(departmentMemberID uint) (bool, error)
 	IsPermissionExistWithID(id uint) (bool, error)
-	UpdatePermissionRequest(permissionID uint, req *request.RequestPermission) error
-	RemovePermissionRequest(permissionID uint) error
+	UpdatePermissionRequest(actor *request.AuditActor, permissionID uint, req *request.RequestPermission) error
+	RemovePermissionRequest(actor *request.AuditActor, permissionID uint) error
 	IsPermissionExistsWithApproval(permissionID uint) (bool, error)
 	GetPermissionCount(dateFilters *request.DateFilters) (int, error)
 	GetPermissionCountByUser(departmentMemberID uint, dateFilters *request.DateFilters) (int, error)
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/utils"
+)
+
+type ProfileChangeService interface {
+	RequestProfileChange(userID uint, req *request.RequestProfileChange, document *response.UploadedDocument) error
+	FetchOwnProfileChangeRequests(userID uint, filters *request.CommonRequest) (*utils.PaginationResponse, error)
+	FetchPendingProfileChangeRequests(filters *request.CommonRequest) (*utils.PaginationResponse, error)
+	UpdateProfileChangeStatus(actor *request.AuditActor, requestID, approvedBy uint, req *request.UpdateProfileChangeStatus) error
+}
+
+type ProfileChangeRepository interface {
+	RequestProfileChange(userID uint, changes string, document *response.UploadedDocument) error
+	IsProfileChangeExistsWithoutApproval(userID uint) (bool, error)
+	GetProfileChangeRequestByID(requestID uint) (*schema.ProfileChangeRequest, error)
+	FetchOwnProfileChangeRequests(userID uint, filters *request.CommonRequest) ([]response.FetchProfileChangeRequests, uint, error)
+	FetchPendingProfileChangeRequests(filters *request.CommonRequest) ([]response.FetchProfileChangeRequests, uint, error)
+	UpdateProfileChangeStatus(actor *request.AuditActor, requestID, approvedBy uint, req *request.UpdateProfileChangeStatus, changes *request.ApplyProfileChange) (bool, error)
+}
@@ -1,11 +1,31 @@
 package domain
 
-import "ems/app/model/response"
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+)
 
 type RoleService interface {
 	FetchRoles() ([]response.FetchRoles, error)
+	FetchPermissions() ([]response.FetchPermissions, error)
+	CreateRole(req *request.CreateRole) error
+	UpdateRole(roleID uint, req *request.UpdateRole) error
+	UpdateRoleTwoFactor(roleID uint, req *request.UpdateRoleTwoFactor) error
+	RemoveRole(roleID uint) error
 }
 
 type RoleRepository interface {
 	FetchRoles() ([]response.FetchRoles, error)
+	FetchPermissions() ([]response.FetchPermissions, error)
+	GetRoleByID(roleID uint) (*schema.Role, error)
+	IsRoleNameExists(name string) (bool, error)
+	IsRoleNameExistsExceptID(roleID uint, name string) (bool, error)
+	IsRoleAssigned(roleID uint) (bool, error)
+	CreateRole(req *request.CreateRole, permissionIDs []uint) error
+	UpdateRole(roleID uint, req *request.UpdateRole, permissionIDs []uint) error
+	UpdateRoleTwoFactor(roleID uint, requireTwoFactor bool) error
+	RemoveRole(roleID uint) error
+	FetchRolePermissions(roleID uint) ([]string, error)
+	HasPermission(roleID uint, permission string) (bool, error)
 }
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+)
+
+// SCIMService provisions users and departments for identity providers through SCIM 2.0.
+// Users are SCIM Users and departments SCIM Groups.
+type SCIMService interface {
+	FetchServiceProviderConfig() *response.SCIMServiceProviderConfig
+	FetchUsers(query *request.SCIMListQuery) (*response.SCIMListResponse, error)
+	FetchUser(userID uint) (*response.SCIMUser, error)
+	CreateUser(actor *request.AuditActor, req *request.SCIMUser) (*response.SCIMUser, error)
+	ReplaceUser(actor *request.AuditActor, userID uint, req *request.SCIMUser) (*response.SCIMUser, error)
+	PatchUser(actor *request.AuditActor, userID uint, req *request.SCIMPatch) (*response.SCIMUser, error)
+	DeactivateUser(actor *request.AuditActor, userID uint) error
+	FetchGroups(query *request.SCIMListQuery) (*response.SCIMListResponse, error)
+	FetchGroup(departmentID uint, withMembers bool) (*response.SCIMGroup, error)
+	CreateGroup(actor *request.AuditActor, req *request.SCIMGroup) (*response.SCIMGroup, error)
+	ReplaceGroup(actor *request.AuditActor, departmentID uint, req *request.SCIMGroup) (*response.SCIMGroup, error)
+	PatchGroup(actor *request.AuditActor, departmentID uint, req *request.SCIMPatch) (*response.SCIMGroup, error)
+	RemoveGroup(actor *request.AuditActor, departmentID uint) error
+}
+
+// SCIMRepository reads and writes the SCIM view of users and departments. Filters are SCIM
+// filter expressions, an empty one matching everything.
+type SCIMRepository interface {
+	FetchSCIMUsers(filter string, offset, limit int) ([]response.SCIMUserRecord, int, error)
+	FetchSCIMUser(userID uint) (*response.SCIMUserRecord, error)
+	IsSCIMEmailTaken(userID uint, email string) (bool, error)
+	CreateSCIMUser(actor *request.AuditActor, req *request.SaveSCIMUser, roleID uint, hashedPassword string) (uint, error)
+	UpdateSCIMUser(actor *request.AuditActor, userID uint, req *request.SaveSCIMUser) error
+	ReactivateUser(actor *request.AuditActor, userID uint) error
+	FetchSCIMGroups(filter string, offset, limit int) ([]response.SCIMGroupRecord, int, error)
+	FetchSCIMGroup(departmentID uint) (*response.SCIMGroupRecord, error)
+	FetchSCIMGroupMembers(departmentIDs []uint) ([]response.SCIMGroupMember, error)
+	FetchSCIMMembershipUsers(userIDs []uint) ([]response.SCIMMembershipUser, error)
+	GetDepartmentIDByName(name string) (uint, error)
+	UpdateSCIMGroup(actor *request.AuditActor, departmentID uint, name string, externalID *string) error
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"time"
+)
+
+type ServiceAccountService interface {
+	FetchServiceAccounts() ([]response.FetchServiceAccounts, error)
+	FetchAPIKeyScopes() []string
+	CreateServiceAccount(req *request.CreateServiceAccount, createdBy uint) error
+	RemoveServiceAccount(serviceAccountID uint) error
+	CreateAPIKey(serviceAccountID uint, req *request.CreateAPIKey, createdBy uint) (*response.CreatedAPIKey, error)
+	RevokeAPIKey(serviceAccountID, apiKeyID uint) error
+}
+
+type ServiceAccountRepository interface {
+	FetchServiceAccounts() ([]response.FetchServiceAccounts, error)
+	GetServiceAccountByID(serviceAccountID uint) (*schema.ServiceAccount, error)
+	IsServiceAccountNameExists(name string) (bool, error)
+	CreateServiceAccount(req *request.CreateServiceAccount, createdBy uint) error
+	RemoveServiceAccount(serviceAccountID uint) error
+	CreateAPIKey(serviceAccountID uint, name, prefix, keyHash string, scopes []string, expiresAt time.Time,
+		createdBy uint) (uint, error)
+	GetAPIKeyByID(serviceAccountID, apiKeyID uint) (*schema.APIKey, error)
+	GetAPIKeyByPrefix(prefix string) (*response.APIKey, error)
+	RevokeAPIKey(apiKeyID uint) error
+	TouchAPIKey(apiKeyID uint) error
+}
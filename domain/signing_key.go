@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"time"
+)
+
+type SigningKeyService interface {
+	LoadSigningKeys() error
+	RotateSigningKey(force bool) error
+	FetchJWKS() (*response.JWKS, error)
+}
+
+type SigningKeyRepository interface {
+	FetchSigningKeys() ([]schema.SigningKey, error)
+	RotateSigningKey(key *schema.SigningKey, previousExpiresAt time.Time) error
+}
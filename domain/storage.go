@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+// FileStorage stores uploaded files under slash separated keys such as "user-3/offer.pdf".
+type FileStorage interface {
+	Backend() string
+	Save(key string, content io.Reader, contentType string) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	Exists(key string) (bool, error)
+	SignedURL(key, fileName string, validity time.Duration) (string, error)
+}
+
+// FileScanner checks uploaded content for malware before it is stored. A clean file returns
+// infected false; an infected one also returns the matched signature name.
+type FileScanner interface {
+	Scan(content io.Reader) (infected bool, signature string, err error)
+}
@@ -8,29 +8,30 @@ import (
 )
 
 type UserService interface {
-	CreateUser(req *request.CreateUser) error
+	CreateUser(actor *request.AuditActor, req *request.CreateUser) error
 	FetchUsers(filters *request.FetchUsers) (*utils.PaginationResponse, error)
-	UpdateUser(userID uint, req *request.UpdateUser) error
+	UpdateUser(actor *request.AuditActor, userID uint, req *request.UpdateUser) error
 	FetchLastUserCode() (*response.FetchLastUserCode, error)
 	FetchUnmappedLeadUsers() ([]response.FetchUnmappedUsers, error)
 	FetchUnmappedLeadUserIncludeUserID(req *request.FetchUnmappedLeadUserIncludeUserID) ([]response.FetchUnmappedUsers, error)
 	FetchUnmappedUsers(req *request.FetchUnmappedUsersByDepartmentID) ([]response.FetchUnmappedUsers, error)
-	RemoveUser(userID uint) error
-	ResetPassword(userID uint, req *request.ResetPassword) error
-	UpdateUserDetails(req *request.UpdateUserDetails) error
-	FetchUserDetails(req *request.FetchUserDetails) (*response.FetchUserDetails, error)
-	UploadFiles(userID uint, filepaths []string) error
-	FetchFilePathsByUserID(userID uint) ([]response.FetchUploadedDocumentPaths, error)
-	RemoveFile(filePath string) error
-	ChangePassword(req *request.ChangePassword) error
+	RemoveUser(actor *request.AuditActor, userID uint) error
+	ResetPassword(actor *request.AuditActor, userID uint, req *request.ResetPassword) error
+	UpdateUserDetails(actor *request.AuditActor, req *request.UpdateUserDetails) error
+	FetchUserDetails(viewerID, viewerRoleID uint, req *request.FetchUserDetails) (*response.FetchUserDetails, error)
+	FetchUserFieldHistory(viewerID, viewerRoleID uint, filters *request.FetchUserFieldHistory) (*utils.PaginationResponse, error)
+	UploadFiles(actor *request.AuditActor, userID uint, documents []response.UploadedDocument) error
+	ChangePassword(actor *request.AuditActor, userID uint, req *request.ChangePassword) error
 	FetchUnmappedHRUsers() ([]response.FetchUnmappedUsers, error)
+	RotatePIIEncryptionKey() (*response.RotatePIIEncryptionKey, error)
 }
 
 type UserRepository interface {
 	GetUserByEmail(email string) (*response.FetchUserByEmail, error)
+	GetLoginUserByID(userID uint) (*response.FetchUserByEmail, error)
 	CreateOTP(data *schema.ForgotPasswordOtp) (bool, error)
 	GetOTPStatusByUserID(id uint) (*schema.ForgotPasswordOtp, error)
-	UpdateToken(userID uint, token string) error
+	IncrementOTPFailedAttempts(otpID uint) error
 	UpdateOTPStatus(userID uint) error
 	GetUserByID(id uint) (*response.FetchUserByID, error)
 	IsEmailExists(email string) (bool, error)
@@ -41,16 +42,18 @@ type UserRepository interface {
 	IsPanNumberExistsExceptID(id uint, panNumber string) (bool, error)
 	IsUserCodeExists(code string) (bool, error)
 	IsUserCodeExistsExceptID(id uint, code string) (bool, error)
-	CreateUser(req *request.CreateUser, hashedPassword string) error
+	GetUserIDByCode(code string) (uint, error)
+	CreateUser(actor *request.AuditActor, req *request.CreateUser, hashedPassword string) error
 	FetchUsers(filters *request.FetchUsers) (*utils.PaginationResponse, error)
-	UpdateUser(userID uint, req *request.UpdateUser) error
+	UpdateUser(actor *request.AuditActor, userID uint, req *request.UpdateUser) error
 	IsUserExists(userID uint) (bool, error)
+	IsRoleExists(roleID uint) (bool, error)
 	IsUnmappedLeadUser(userID uint) (bool, error)
 	IsUnmappedLeadUserIncludeUserID(userID uint) (bool, error)
 	IsUnmappedHRUserIncludeUserID(userID uint) (bool, error)
 	FetchLastUserCode() (*response.FetchLastUserCode, error)
-	UpdateTokenStatus(userID uint) error
-	UpdatePassword(userId uint, hashedPassword string) error
+	UpdatePassword(actor *request.AuditActor, userId uint, hashedPassword string) error
+	FetchPasswordHistory(userID uint, limit int) ([]string, error)
 	FetchUnmappedLeadUsers() ([]response.FetchUnmappedUsers, error)
 	FetchUnmappedLeadUserIncludeUserID(req *request.FetchUnmappedLeadUserIncludeUserID) ([]response.FetchUnmappedUsers, error)
 	FetchUnmappedHRUserIncludeUserID(req *request.FetchUnmappedLeadUserIncludeUserID) ([]response.FetchUnmappedUsers, error)
@@ -59,12 +62,14 @@ type UserRepository interface {
 	FetchUnmappedEmployeeUsers() ([]response.FetchUnmappedUsers, error)
 	FetchUnmappedHRUsers() ([]response.FetchUnmappedUsers, error)
 	IsDepartmentUserExists(userID uint) (*response.FetchDepartmentUserCountAndRoleID, error)
-	RemoveUser(userID uint) error
+	RemoveUser(actor *request.AuditActor, userID uint) error
 	IsMappedLeadUser(userID uint) (bool, error)
-	UpdateUserDetails(req *request.UpdateUserDetails) error
+	UpdateUserDetails(actor *request.AuditActor, req *request.UpdateUserDetails) error
 	FetchUserDetails(req *request.FetchUserDetails) (*response.FetchUserDetails, error)
-	UploadFiles(userID uint, filepaths []string) error
-	FetchFilePathsByUserID(userID uint) ([]response.FetchUploadedDocumentPaths, error)
-	RemoveFile(filePath string) error
+	UploadFiles(actor *request.AuditActor, userID uint, documents []response.UploadedDocument) error
 	GetUserCount() (int, error)
+	ReEncryptUserDetails() (int, error)
+	FetchUserFieldHistory(filters *request.FetchUserFieldHistory) (*utils.PaginationResponse, error)
+	ReEncryptUserFieldHistory() (int, error)
+	ReEncryptSealedColumns() (int, error)
 }
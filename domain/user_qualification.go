@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/utils"
+)
+
+type UserQualificationService interface {
+	FetchUserQualifications(userID uint) (*response.FetchUserQualifications, error)
+	CreateEducation(req *request.CreateEducation) error
+	UpdateEducation(educationID uint, req *request.UpdateEducation) error
+	RemoveEducation(educationID uint) error
+	CreateCertification(req *request.CreateCertification) error
+	UpdateCertification(certificationID uint, req *request.UpdateCertification) error
+	RemoveCertification(certificationID uint) error
+	CreateSkill(req *request.CreateSkill) error
+	UpdateSkill(skillID uint, req *request.UpdateSkill) error
+	RemoveSkill(skillID uint) error
+	SearchQualifications(filters *request.SearchQualifications) (*utils.PaginationResponse, error)
+}
+
+type UserQualificationRepository interface {
+	FetchEducations(userID uint) ([]response.FetchEducation, error)
+	FetchCertifications(userID uint) ([]response.FetchCertification, error)
+	FetchSkills(userID uint) ([]response.FetchSkill, error)
+	CreateEducation(req *request.CreateEducation) error
+	IsEducationExists(educationID uint) (bool, error)
+	UpdateEducation(educationID uint, req *request.UpdateEducation) error
+	RemoveEducation(educationID uint) error
+	CreateCertification(req *request.CreateCertification) error
+	IsCertificationExists(certificationID uint) (bool, error)
+	UpdateCertification(certificationID uint, req *request.UpdateCertification) error
+	RemoveCertification(certificationID uint) error
+	CreateSkill(req *request.CreateSkill) error
+	IsSkillExists(skillID uint) (bool, error)
+	IsSkillNameExists(userID uint, name string) (bool, error)
+	IsSkillNameExistsExceptID(skillID uint, name string) (bool, error)
+	UpdateSkill(skillID uint, req *request.UpdateSkill) error
+	RemoveSkill(skillID uint) error
+	SearchQualifications(filters *request.SearchQualifications) ([]response.SearchQualifications, uint, error)
+	FetchExpiringCertifications(days uint) ([]response.ExpiringCertification, error)
+	MarkCertificationReminderSent(certificationID uint) error
+}
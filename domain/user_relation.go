@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"ems/app/model/request"
+	"ems/app/model/response"
+)
+
+type UserRelationService interface {
+	FetchUserRelations(userID uint) (*response.FetchUserRelations, error)
+	CreateEmergencyContact(req *request.CreateEmergencyContact) error
+	UpdateEmergencyContact(contactID uint, req *request.UpdateEmergencyContact) error
+	RemoveEmergencyContact(contactID uint) error
+	CreateDependent(req *request.CreateDependent) error
+	UpdateDependent(dependentID uint, req *request.UpdateDependent) error
+	RemoveDependent(dependentID uint) error
+	UpdateNominees(req *request.UpdateNominees) error
+}
+
+type UserRelationRepository interface {
+	FetchEmergencyContacts(userID uint) ([]response.FetchEmergencyContact, error)
+	FetchDependents(userID uint) ([]response.FetchDependent, error)
+	FetchNominees(userID uint) ([]response.FetchNominee, error)
+	CreateEmergencyContact(req *request.CreateEmergencyContact) error
+	IsEmergencyContactExists(contactID uint) (bool, error)
+	UpdateEmergencyContact(contactID uint, req *request.UpdateEmergencyContact) error
+	RemoveEmergencyContact(contactID uint) error
+	CreateDependent(req *request.CreateDependent) error
+	IsDependentExists(dependentID uint) (bool, error)
+	UpdateDependent(dependentID uint, req *request.UpdateDependent) error
+	RemoveDependent(dependentID uint) error
+	ReplaceNominees(req *request.UpdateNominees) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/utils"
+	"time"
+)
+
+type WebhookService interface {
+	FetchWebhooks() ([]response.FetchWebhooks, error)
+	FetchEventTypes() []string
+	CreateWebhook(req *request.CreateWebhook, createdBy uint) (*response.CreatedWebhookSecret, error)
+	UpdateWebhook(webhookID uint, req *request.UpdateWebhook) error
+	RemoveWebhook(webhookID uint) error
+	RotateWebhookSecret(webhookID uint) (*response.CreatedWebhookSecret, error)
+	FetchWebhookDeliveries(filters *request.FetchWebhookDeliveries) (*utils.PaginationResponse, error)
+	FetchWebhookDelivery(deliveryID uint) (*response.FetchWebhookDelivery, error)
+	RedeliverWebhookDelivery(deliveryID uint) (*response.FetchWebhookDelivery, error)
+	QueueWebhookDeliveries(event *response.OutboxEvent) error
+	DeliverWebhooks() error
+}
+
+type WebhookRepository interface {
+	FetchWebhooks() ([]response.FetchWebhooks, error)
+	GetWebhookByID(webhookID uint) (*schema.Webhook, error)
+	IsWebhookNameExists(name string, excludeID uint) (bool, error)
+	CreateWebhook(req *request.CreateWebhook, secret string, createdBy uint) (uint, error)
+	UpdateWebhook(webhookID uint, req *request.UpdateWebhook) error
+	UpdateWebhookSecret(webhookID uint, secret string) error
+	RemoveWebhook(webhookID uint) error
+	FetchWebhookDeliveries(filters *request.FetchWebhookDeliveries) (*utils.PaginationResponse, error)
+	FetchWebhookDelivery(deliveryID uint) (*response.FetchWebhookDelivery, error)
+	CreateWebhookDeliveries(event *response.OutboxEvent) error
+	ClaimDueWebhookDeliveries(limit int, lease time.Duration) ([]response.WebhookDelivery, error)
+	ClaimWebhookDelivery(deliveryID uint, lease time.Duration) (*response.WebhookDelivery, error)
+	RecordWebhookDeliveryAttempt(deliveryID uint, attempts int, status constant.WebhookDeliveryStatus,
+		nextAttemptAt time.Time, responseStatus *int, lastError *string, duration time.Duration) error
+}
@@ -2,10 +2,13 @@ module ems
 
 go 1.21.0
 
-require github.com/gin-gonic/gin v1.10.0
+require (
+	github.com/gabriel-vasile/mimetype v1.4.3
+	github.com/gin-gonic/gin v1.10.0
+	github.com/google/uuid v1.4.0
+)
 
 require (
-	github.com/google/uuid v1.4.0 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/mattn/go-sqlite3 v1.14.22 // indirect
@@ -21,7 +24,6 @@ require (
 	github.com/cloudwego/iasm v0.2.0 // indirect
 	github.com/didip/tollbooth v4.0.2+incompatible
 	github.com/didip/tollbooth_gin v0.0.0-20170928041415-5752492be505
-	github.com/gabriel-vasile/mimetype v1.4.3 // indirect
 	github.com/gin-contrib/cors v1.7.2
 	github.com/gin-contrib/sse v0.1.0 // indirect
 	github.com/go-co-op/gocron v1.37.0
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,15 +14,127 @@ import (
 type Configuration struct {
 	Name                      string
 	Port                      string
+	TrustedProxies            []string
 	DbDsn                     string
 	JwtSecretKey              string
-	TokenDuration             time.Duration
+	JwtSigningAlgorithm       string
+	JwtKeyRotationInterval    time.Duration
+	JwtKeyPropagationDelay    time.Duration
+	AccessTokenDuration       time.Duration
+	RefreshTokenDuration      time.Duration
+	TwoFactorTokenDuration    time.Duration
+	TwoFactorIssuer           string
+	APIKeyMaxValidity         time.Duration
+	ImpersonationDuration     time.Duration
 	SmtpHost                  string
 	SmtpPort                  string
 	SmtpUserName              string
 	SmtpPassword              string
 	SmtpDisplayName           string
 	ForgotPasswordOTPValidity int64
+	MaxForgotPasswordAttempts int
+	Lockout                   LockoutConfiguration
+	PasswordPolicy            PasswordPolicyConfiguration
+	OIDC                      OIDCConfiguration
+	LDAP                      LDAPConfiguration
+	SCIM                      SCIMConfiguration
+	PiiEncryptionKeys         map[string][]byte
+	PiiActiveKeyID            string
+	PiiBlindIndexKey          []byte
+	StorageBackend            string
+	LocalStorageDir           string
+	SignedURLValidity         time.Duration
+	S3                        S3Configuration
+	MaxUploadFileSize         int64
+	UserStorageQuota          int64
+	MalwareScanner            string
+	ClamAVAddress             string
+}
+
+// LockoutConfiguration controls brute-force protection. An account or client IP is locked
+// after MaxAccountAttempts or MaxIPAttempts failures within AttemptWindow. Each lockout lasts
+// twice as long as the previous one, from BaseDuration up to MaxDuration.
+type LockoutConfiguration struct {
+	MaxAccountAttempts int
+	MaxIPAttempts      int
+	AttemptWindow      time.Duration
+	BaseDuration       time.Duration
+	MaxDuration        time.Duration
+}
+
+// PasswordPolicyConfiguration describes the passwords users may choose. DenyListFile is an
+// optional file of additional forbidden passwords, one per line. A MaxAge of zero disables
+// password expiry.
+type PasswordPolicyConfiguration struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DenyListFile     string
+	HistoryCount     int
+	MaxAge           time.Duration
+}
+
+// OIDCConfiguration enables single sign-on when IssuerURL is set. RedirectURL is the client
+// page the provider returns to, which posts the code and state to /auth/oidc/callback. With
+// JITProvisioning, unknown users are created with DefaultRoleID on their first sign in.
+type OIDCConfiguration struct {
+	IssuerURL       string
+	ClientID        string
+	ClientSecret    string
+	RedirectURL     string
+	Scopes          []string
+	JITProvisioning bool
+	DefaultRoleID   uint
+	StateValidity   time.Duration
+}
+
+// LDAPConfiguration enables directory sign in and group sync when URL is set. Users are found
+// by binding as BindDN and searching BaseDN with UserFilter, where %s is the escaped email,
+// and their password is checked by binding as them. GroupRoles and GroupDepartments are tried
+// in order and the first group the user belongs to wins.
+type LDAPConfiguration struct {
+	URL                string
+	StartTLS           bool
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	SyncFilter         string
+	EmailAttribute     string
+	FirstNameAttribute string
+	LastNameAttribute  string
+	GroupAttribute     string
+	GroupRoles         []GroupMapping
+	GroupDepartments   []GroupMapping
+	SyncInterval       time.Duration
+	SyncDryRun         bool
+	Timeout            time.Duration
+}
+
+// GroupMapping maps the members of a directory group to an EMS role or department.
+type GroupMapping struct {
+	GroupDN string
+	ID      uint
+}
+
+// SCIMConfiguration enables the SCIM provisioning endpoints when Token is set. Identity
+// providers authenticate with it as a bearer token. Provisioned users get DefaultRoleID, and
+// list responses hold at most MaxResults resources.
+type SCIMConfiguration struct {
+	Token         string
+	DefaultRoleID uint
+	MaxResults    int
+}
+
+type S3Configuration struct {
+	Endpoint       string
+	Region         string
+	Bucket         string
+	AccessKey      string
+	SecretKey      string
+	ForcePathStyle bool
 }
 
 var Config *Configuration
@@ -37,15 +151,114 @@ func Load() error {
 
 	Config = &Configuration{
 		Port:                      getEnvOrError("PORT"),
+		TrustedProxies:            getEnvAsList("TRUSTED_PROXIES"),
 		DbDsn:                     getEnvOrError("DATABASE_URL"),
 		JwtSecretKey:              getEnvOrError("SECRET_KEY"),
-		TokenDuration:             time.Hour * 24,
+		JwtSigningAlgorithm:       getEnvOrDefault("JWT_SIGNING_ALGORITHM", "RS256"),
+		JwtKeyRotationInterval:    time.Hour * 24 * time.Duration(getEnvAsIntOrDefault("JWT_KEY_ROTATION_DAYS", 30)),
+		JwtKeyPropagationDelay:    time.Minute * time.Duration(getEnvAsIntOrDefault("JWT_KEY_PROPAGATION_MINUTES", 5)),
+		AccessTokenDuration:       time.Minute * time.Duration(getEnvAsIntOrDefault("ACCESS_TOKEN_DURATION_MINUTES", 15)),
+		RefreshTokenDuration:      time.Hour * 24 * time.Duration(getEnvAsIntOrDefault("REFRESH_TOKEN_DURATION_DAYS", 30)),
+		TwoFactorTokenDuration:    time.Minute * 5,
+		TwoFactorIssuer:           getEnvOrDefault("TWO_FACTOR_ISSUER", "EMS"),
+		APIKeyMaxValidity:         time.Hour * 24 * time.Duration(getEnvAsIntOrDefault("API_KEY_MAX_VALIDITY_DAYS", 365)),
+		ImpersonationDuration:     time.Minute * time.Duration(getEnvAsIntOrDefault("IMPERSONATION_DURATION_MINUTES", 30)),
 		SmtpHost:                  getEnvOrError("SMTP_HOST"),
 		SmtpPort:                  getEnvOrError("SMTP_PORT"),
 		SmtpUserName:              getEnvOrError("SMTP_USERNAME"),
 		SmtpDisplayName:           getEnvOrError("SMTP_DISPLAY_NAME"),
 		SmtpPassword:              getEnvOrError("SMTP_PASSWORD"),
 		ForgotPasswordOTPValidity: getEnvAsInt("FORGOT_OTP_VALIDITY"),
+		MaxForgotPasswordAttempts: int(getEnvAsIntOrDefault("MAX_FORGOT_OTP_ATTEMPTS", 5)),
+		PiiEncryptionKeys:         getEnvAsKeyMap("PII_ENCRYPTION_KEYS"),
+		PiiActiveKeyID:            getEnvOrError("PII_ACTIVE_KEY_ID"),
+		PiiBlindIndexKey:          getEnvAsKey("PII_BLIND_INDEX_KEY"),
+		StorageBackend:            getEnvOrDefault("STORAGE_BACKEND", "local"),
+		LocalStorageDir:           getEnvOrDefault("LOCAL_STORAGE_DIR", "./uploads"),
+		SignedURLValidity:         time.Minute * 5,
+		MaxUploadFileSize:         getEnvAsIntOrDefault("MAX_UPLOAD_FILE_SIZE_MB", 10) << 20,
+		UserStorageQuota:          getEnvAsIntOrDefault("USER_STORAGE_QUOTA_MB", 200) << 20,
+		MalwareScanner:            getEnvOrDefault("MALWARE_SCANNER", "none"),
+		ClamAVAddress:             getEnvOrDefault("CLAMAV_ADDRESS", "unix:/var/run/clamav/clamd.ctl"),
+		Lockout: LockoutConfiguration{
+			MaxAccountAttempts: int(getEnvAsIntOrDefault("MAX_FAILED_LOGIN_ATTEMPTS", 5)),
+			MaxIPAttempts:      int(getEnvAsIntOrDefault("MAX_FAILED_IP_ATTEMPTS", 20)),
+			AttemptWindow:      time.Minute * time.Duration(getEnvAsIntOrDefault("FAILED_ATTEMPT_WINDOW_MINUTES", 15)),
+			BaseDuration:       time.Minute * time.Duration(getEnvAsIntOrDefault("LOCKOUT_BASE_DURATION_MINUTES", 1)),
+			MaxDuration:        time.Minute * time.Duration(getEnvAsIntOrDefault("LOCKOUT_MAX_DURATION_MINUTES", 1440)),
+		},
+		PasswordPolicy: PasswordPolicyConfiguration{
+			MinLength:        int(getEnvAsIntOrDefault("PASSWORD_MIN_LENGTH", 10)),
+			RequireUppercase: getEnvOrDefault("PASSWORD_REQUIRE_UPPERCASE", "true") == "true",
+			RequireLowercase: getEnvOrDefault("PASSWORD_REQUIRE_LOWERCASE", "true") == "true",
+			RequireDigit:     getEnvOrDefault("PASSWORD_REQUIRE_DIGIT", "true") == "true",
+			RequireSymbol:    getEnvOrDefault("PASSWORD_REQUIRE_SYMBOL", "false") == "true",
+			DenyListFile:     os.Getenv("PASSWORD_DENY_LIST_FILE"),
+			HistoryCount:     int(getEnvAsIntOrDefault("PASSWORD_HISTORY_COUNT", 5)),
+			MaxAge:           time.Hour * 24 * time.Duration(getEnvAsIntOrDefault("PASSWORD_MAX_AGE_DAYS", 90)),
+		},
+	}
+
+	// S3 settings are only needed when the backend is in use or a migration targets it.
+	if Config.StorageBackend == "s3" || os.Getenv("S3_BUCKET") != "" {
+		Config.S3 = S3Configuration{
+			Endpoint:       getEnvOrError("S3_ENDPOINT"),
+			Region:         getEnvOrError("S3_REGION"),
+			Bucket:         getEnvOrError("S3_BUCKET"),
+			AccessKey:      getEnvOrError("S3_ACCESS_KEY"),
+			SecretKey:      getEnvOrError("S3_SECRET_KEY"),
+			ForcePathStyle: getEnvOrDefault("S3_FORCE_PATH_STYLE", "true") == "true",
+		}
+	}
+
+	if os.Getenv("OIDC_ISSUER_URL") != "" {
+		Config.OIDC = OIDCConfiguration{
+			IssuerURL:       getEnvOrError("OIDC_ISSUER_URL"),
+			ClientID:        getEnvOrError("OIDC_CLIENT_ID"),
+			ClientSecret:    getEnvOrError("OIDC_CLIENT_SECRET"),
+			RedirectURL:     getEnvOrError("OIDC_REDIRECT_URL"),
+			Scopes:          strings.Fields(getEnvOrDefault("OIDC_SCOPES", "openid email profile")),
+			JITProvisioning: getEnvOrDefault("OIDC_JIT_PROVISIONING", "false") == "true",
+			DefaultRoleID:   uint(getEnvAsIntOrDefault("OIDC_DEFAULT_ROLE_ID", 5)), // 5 => Employee
+			StateValidity:   time.Minute * 10,
+		}
+	}
+
+	if os.Getenv("LDAP_URL") != "" {
+		Config.LDAP = LDAPConfiguration{
+			URL:                getEnvOrError("LDAP_URL"),
+			StartTLS:           getEnvOrDefault("LDAP_START_TLS", "false") == "true",
+			BindDN:             os.Getenv("LDAP_BIND_DN"),
+			BindPassword:       os.Getenv("LDAP_BIND_PASSWORD"),
+			BaseDN:             getEnvOrError("LDAP_BASE_DN"),
+			UserFilter:         getEnvOrDefault("LDAP_USER_FILTER", "(&(objectClass=person)(mail=%s))"),
+			SyncFilter:         getEnvOrDefault("LDAP_SYNC_FILTER", "(&(objectClass=person)(mail=*))"),
+			EmailAttribute:     getEnvOrDefault("LDAP_EMAIL_ATTRIBUTE", "mail"),
+			FirstNameAttribute: getEnvOrDefault("LDAP_FIRST_NAME_ATTRIBUTE", "givenName"),
+			LastNameAttribute:  getEnvOrDefault("LDAP_LAST_NAME_ATTRIBUTE", "sn"),
+			GroupAttribute:     getEnvOrDefault("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+			GroupRoles:         getEnvAsGroupMappings("LDAP_GROUP_ROLES"),
+			GroupDepartments:   getEnvAsGroupMappings("LDAP_GROUP_DEPARTMENTS"),
+			SyncInterval:       time.Minute * time.Duration(getEnvAsIntOrDefault("LDAP_SYNC_INTERVAL_MINUTES", 60)),
+			SyncDryRun:         getEnvOrDefault("LDAP_SYNC_DRY_RUN", "false") == "true",
+			Timeout:            time.Second * 10,
+		}
+	}
+
+	if os.Getenv("SCIM_TOKEN") != "" {
+		Config.SCIM = SCIMConfiguration{
+			Token:         getEnvOrError("SCIM_TOKEN"),
+			DefaultRoleID: uint(getEnvAsIntOrDefault("SCIM_DEFAULT_ROLE_ID", 5)), // 5 => Employee
+			MaxResults:    int(getEnvAsIntOrDefault("SCIM_MAX_RESULTS", 100)),
+		}
+	}
+
+	if Config.JwtSigningAlgorithm != "RS256" && Config.JwtSigningAlgorithm != "EdDSA" {
+		panic(fmt.Sprintf("JWT_SIGNING_ALGORITHM must be RS256 or EdDSA, got %s", Config.JwtSigningAlgorithm))
+	}
+
+	if _, ok := Config.PiiEncryptionKeys[Config.PiiActiveKeyID]; !ok {
+		panic(fmt.Sprintf("PII_ACTIVE_KEY_ID %s not found in PII_ENCRYPTION_KEYS", Config.PiiActiveKeyID))
 	}
 
 	return nil
@@ -59,6 +272,14 @@ func getEnvOrError(key string) string {
 	panic(fmt.Sprintf("Environment variable %s not set", key))
 }
 
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
 func getEnvAsInt(key string) int64 {
 	valueStr := getEnvOrError(key)
 	var value int64
@@ -69,3 +290,78 @@ func getEnvAsInt(key string) int64 {
 	}
 	return value
 }
+
+func getEnvAsIntOrDefault(key string, defaultValue int64) int64 {
+	if os.Getenv(key) == "" {
+		return defaultValue
+	}
+
+	return getEnvAsInt(key)
+}
+
+// getEnvAsKey decodes a base64 encoded 32 byte key.
+func getEnvAsKey(key string) []byte {
+	value, err := base64.StdEncoding.DecodeString(getEnvOrError(key))
+	if err != nil || len(value) != 32 {
+		panic(fmt.Sprintf("Environment variable %s must be a base64 encoded 32 byte key", key))
+	}
+	return value
+}
+
+// getEnvAsKeyMap parses a comma separated list of keyID:base64Key pairs,
+// e.g. "v1:<key>,v2:<key>". Old keys stay in the list until rotation completes.
+func getEnvAsKeyMap(key string) map[string][]byte {
+	keys := make(map[string][]byte)
+
+	for _, pair := range strings.Split(getEnvOrError(key), ",") {
+		id, encoded, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || id == "" {
+			panic(fmt.Sprintf("Environment variable %s has an invalid entry", key))
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(value) != 32 {
+			panic(fmt.Sprintf("Environment variable %s key %s must be a base64 encoded 32 byte key", key, id))
+		}
+		keys[id] = value
+	}
+
+	return keys
+}
+
+// getEnvAsList parses a comma separated list, e.g. "10.0.0.0/8,192.168.1.10". It is empty
+// when the variable is not set.
+func getEnvAsList(key string) []string {
+	var values []string
+
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// getEnvAsGroupMappings parses a semicolon separated list of groupDN=>ID pairs, e.g.
+// "cn=hr,ou=groups,dc=example,dc=com=>3". Order is kept, as the first matching group wins.
+func getEnvAsGroupMappings(key string) []GroupMapping {
+	var mappings []GroupMapping
+
+	for _, pair := range strings.Split(os.Getenv(key), ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		groupDN, id, found := strings.Cut(pair, "=>")
+
+		var value uint
+		if _, err := fmt.Sscanf(strings.TrimSpace(id), "%d", &value); !found || err != nil || value == 0 {
+			panic(fmt.Sprintf("Environment variable %s has an invalid entry %q", key, pair))
+		}
+
+		mappings = append(mappings, GroupMapping{GroupDN: strings.TrimSpace(groupDN), ID: value})
+	}
+
+	return mappings
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	"gorm.io/driver/sqlite"
@@ -44,10 +45,21 @@ func InitDB() (*gorm.DB, error) {
 }
 
 func migrateSchema(db *gorm.DB) error {
-	return db.AutoMigrate(&schema.Role{}, &schema.User{}, &schema.ForgotPasswordOtp{},
+	return db.AutoMigrate(&schema.Role{}, &schema.Permission{}, &schema.RolePermission{},
+		&schema.User{}, &schema.ForgotPasswordOtp{}, &schema.PasswordHistory{},
 		&schema.Department{}, &schema.DepartmentMember{}, &schema.UserNotice{},
 		&schema.UserDocument{}, &schema.DepartmentMemberLeaveRequest{}, &schema.UserDetails{},
-		&schema.DepartmentMemberLeaveRequestDate{}, &schema.DepartmentMemberPermissionRequest{})
+		&schema.DepartmentMemberLeaveRequestDate{}, &schema.DepartmentMemberPermissionRequest{},
+		&schema.ProfileChangeRequest{}, &schema.UserEmergencyContact{}, &schema.UserDependent{},
+		&schema.UserNominee{}, &schema.CustomField{}, &schema.UserCustomFieldValue{},
+		&schema.UserEducation{}, &schema.UserCertification{}, &schema.UserSkill{},
+		&schema.DocumentCategory{}, &schema.QuarantinedDocument{}, &schema.LetterTemplate{},
+		&schema.LetterTemplateVersion{}, &schema.UserSession{}, &schema.UserTwoFactor{},
+		&schema.UserRecoveryCode{}, &schema.LoginThrottle{}, &schema.AuthLog{},
+		&schema.UserIdentity{}, &schema.OIDCLoginState{}, &schema.ServiceAccount{}, &schema.APIKey{},
+		&schema.ImpersonatedRequest{}, &schema.SigningKey{},
+		&schema.AuditLog{}, &schema.UserFieldHistory{}, &schema.OutboxEvent{}, &schema.OutboxDelivery{},
+		&schema.Webhook{}, &schema.WebhookDelivery{}, &schema.WebhookDeliveryAttempt{})
 }
 
 func initData(db *gorm.DB) error {
@@ -55,14 +67,30 @@ func initData(db *gorm.DB) error {
 		return err
 	}
 
+	if err := initPermissions(db); err != nil {
+		return err
+	}
+
 	if err := initUsers(db); err != nil {
 		return err
 	}
 
+	if err := initPasswordChangedAt(db); err != nil {
+		return err
+	}
+
 	if err := initHRDepartment(db); err != nil {
 		return err
 	}
 
+	if err := initDocumentCategories(db); err != nil {
+		return err
+	}
+
+	if err := initLetterTemplates(db); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -76,14 +104,71 @@ func initRole(db *gorm.DB) error {
 		for _, role := range model.Roles {
 			if err := db.Exec(`
 				INSERT INTO [Role]
-				(CreatedAt, UpdatedAt, IsActive, [Name])
-				VALUES(?, ?, 1, ?)`, time.Now(), time.Now(), role).Error; err != nil {
+				(CreatedAt, UpdatedAt, IsActive, [Name], IsSystem)
+				VALUES(?, ?, 1, ?, 1)`, time.Now(), time.Now(), role).Error; err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	// Databases created before custom roles existed only hold the built-in roles.
+	return db.Exec(`
+		UPDATE [Role]
+		SET IsSystem = 1
+		WHERE ID <= ?`, len(model.Roles)).Error
+}
+
+// initPermissions adds permissions that are missing from the database and grants them to the
+// built-in roles by default, so permissions introduced by an upgrade reach existing roles
+// without overriding grants an admin has since changed.
+func initPermissions(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, permission := range model.Permissions {
+			var count int64
+
+			if err := tx.Raw(`
+				SELECT COUNT(*)
+				FROM Permission
+				WHERE [Name] = ?`, permission.Name).Scan(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				continue
+			}
+
+			if err := tx.Exec(`
+				INSERT INTO Permission
+				(CreatedAt, UpdatedAt, IsActive, [Name], Description)
+				VALUES(?, ?, 1, ?, ?)`, time.Now(), time.Now(), permission.Name, permission.Description).Error; err != nil {
+				return err
+			}
+
+			var permissionID uint
+
+			if err := tx.Raw(`
+				SELECT ID
+				FROM Permission
+				WHERE [Name] = ?`, permission.Name).Scan(&permissionID).Error; err != nil {
+				return err
+			}
+
+			for role, permissions := range model.RolePermissions {
+				if !slices.Contains(permissions, permission.Name) {
+					continue
+				}
+
+				if err := tx.Exec(`
+					INSERT INTO RolePermission
+					(CreatedAt, UpdatedAt, IsActive, RoleID, PermissionID)
+					VALUES(?, ?, 1, ?, ?)`, time.Now(), time.Now(), role, permissionID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
 }
 
 func initUsers(db *gorm.DB) error {
@@ -136,6 +221,14 @@ func initUsers(db *gorm.DB) error {
 	return nil
 }
 
+// initPasswordChangedAt starts the expiry clock for passwords set before it was tracked.
+func initPasswordChangedAt(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE [User]
+		SET PasswordChangedAt = ?
+		WHERE PasswordChangedAt IS NULL`, time.Now()).Error
+}
+
 func initHRDepartment(db *gorm.DB) error {
 	var count int64
 
@@ -174,3 +267,71 @@ func initHRDepartment(db *gorm.DB) error {
 
 	return nil
 }
+
+func initDocumentCategories(db *gorm.DB) error {
+	var count int64
+
+	if err := db.Raw(`SELECT COUNT(*) FROM DocumentCategory`).Scan(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		for _, category := range model.DocumentCategories {
+			if err := db.Exec(`
+				INSERT INTO DocumentCategory
+				(CreatedAt, UpdatedAt, IsActive, [Name], Code, IsRequired, HasExpiry, AllowedTypes)
+				VALUES(?, ?, 1, ?, ?, ?, ?, ?)`, time.Now(), time.Now(), category.Name, category.Code,
+				category.IsRequired, category.HasExpiry, category.AllowedTypes).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func initLetterTemplates(db *gorm.DB) error {
+	var count int64
+
+	if err := db.Raw(`SELECT COUNT(*) FROM LetterTemplate`).Scan(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return db.Transaction(func(tx *gorm.DB) error {
+			for _, letterTemplate := range model.LetterTemplates {
+				var categoryID uint
+
+				if err := tx.Raw(`
+				SELECT ID
+				FROM DocumentCategory
+				WHERE Code = ? AND IsActive = 1`, letterTemplate.CategoryCode).Scan(&categoryID).Error; err != nil {
+					return err
+				}
+
+				if categoryID == 0 {
+					continue
+				}
+
+				if err := tx.Exec(`
+				INSERT INTO LetterTemplate
+				(CreatedAt, UpdatedAt, IsActive, [Name], DocumentCategoryID, Version)
+				VALUES(?, ?, 1, ?, ?, 1)`, time.Now(), time.Now(), letterTemplate.Name, categoryID).Error; err != nil {
+					return err
+				}
+
+				if err := tx.Exec(`
+				INSERT INTO LetterTemplateVersion
+				(CreatedAt, UpdatedAt, IsActive, LetterTemplateID, Version, Title, Body, CreatedBy)
+				SELECT ?, ?, 1, MAX(ID), 1, ?, ?, ?
+				FROM LetterTemplate`, time.Now(), time.Now(), letterTemplate.Title, letterTemplate.Body,
+					4).Error; err != nil { // 4 => HR
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
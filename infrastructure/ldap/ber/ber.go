@@ -0,0 +1,270 @@
+// Package ber implements the subset of ASN.1 BER that LDAPv3 messages use: definite lengths
+// and single byte tags.
+package ber
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	ClassUniversal   byte = 0x00
+	ClassApplication byte = 0x40
+	ClassContext     byte = 0x80
+)
+
+const (
+	TagBoolean     byte = 0x01
+	TagInteger     byte = 0x02
+	TagOctetString byte = 0x04
+	TagNull        byte = 0x05
+	TagEnumerated  byte = 0x0a
+	TagSequence    byte = 0x10
+	TagSet         byte = 0x11
+)
+
+const (
+	constructedBit = 0x20
+	classMask      = 0xc0
+	tagMask        = 0x1f
+	// maxPacketSize bounds what ReadPacket accepts so a bad length cannot exhaust memory.
+	maxPacketSize = 16 << 20
+)
+
+var ErrInvalidPacket = errors.New("invalid BER packet")
+
+// Packet is one BER element. Primitive elements hold their content in Value and constructed
+// ones in Children.
+type Packet struct {
+	Class       byte
+	Constructed bool
+	Tag         byte
+	Value       []byte
+	Children    []*Packet
+}
+
+func NewConstructed(class, tag byte, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+func NewSequence(children ...*Packet) *Packet {
+	return NewConstructed(ClassUniversal, TagSequence, children...)
+}
+
+func NewSet(children ...*Packet) *Packet {
+	return NewConstructed(ClassUniversal, TagSet, children...)
+}
+
+func NewPrimitive(class, tag byte, value []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+func NewString(class, tag byte, value string) *Packet {
+	return NewPrimitive(class, tag, []byte(value))
+}
+
+func NewOctetString(value string) *Packet {
+	return NewString(ClassUniversal, TagOctetString, value)
+}
+
+// NewInteger encodes value in the shortest two's complement form, as INTEGER and ENUMERATED
+// both require.
+func NewInteger(class, tag byte, value int64) *Packet {
+	var content []byte
+
+	for {
+		content = append([]byte{byte(value)}, content...)
+		value >>= 8
+
+		if (value == 0 && content[0]&0x80 == 0) || (value == -1 && content[0]&0x80 != 0) {
+			break
+		}
+	}
+
+	return NewPrimitive(class, tag, content)
+}
+
+func NewBoolean(value bool) *Packet {
+	if value {
+		return NewPrimitive(ClassUniversal, TagBoolean, []byte{0xff})
+	}
+	return NewPrimitive(ClassUniversal, TagBoolean, []byte{0x00})
+}
+
+func (p *Packet) Append(children ...*Packet) *Packet {
+	p.Children = append(p.Children, children...)
+	return p
+}
+
+// Is reports whether the packet has the given class and tag.
+func (p *Packet) Is(class, tag byte) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+func (p *Packet) Int() int64 {
+	var value int64
+
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			value = -1
+		}
+		value = value<<8 | int64(b)
+	}
+
+	return value
+}
+
+func (p *Packet) Bool() bool {
+	return len(p.Value) > 0 && p.Value[0] != 0
+}
+
+// Child returns the i-th child, or an error when the packet is primitive or too short, so
+// that callers can walk a response without checking lengths at every step.
+func (p *Packet) Child(i int) (*Packet, error) {
+	if !p.Constructed || i >= len(p.Children) {
+		return nil, fmt.Errorf("%w: missing element %d", ErrInvalidPacket, i)
+	}
+
+	return p.Children[i], nil
+}
+
+func (p *Packet) Bytes() []byte {
+	content := p.Value
+
+	if p.Constructed {
+		content = nil
+		for _, child := range p.Children {
+			content = append(content, child.Bytes()...)
+		}
+	}
+
+	identifier := p.Class | p.Tag&tagMask
+	if p.Constructed {
+		identifier |= constructedBit
+	}
+
+	return append(append([]byte{identifier}, encodeLength(len(content))...), content...)
+}
+
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	var encoded []byte
+	for ; length > 0; length >>= 8 {
+		encoded = append([]byte{byte(length)}, encoded...)
+	}
+
+	return append([]byte{0x80 | byte(len(encoded))}, encoded...)
+}
+
+// ReadPacket reads one complete element from r.
+func ReadPacket(r io.Reader) (*Packet, error) {
+	var header [2]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	length := int(header[1])
+
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 4 {
+			return nil, fmt.Errorf("%w: unsupported length encoding", ErrInvalidPacket)
+		}
+
+		encoded := make([]byte, size)
+		if _, err := io.ReadFull(r, encoded); err != nil {
+			return nil, err
+		}
+
+		length = 0
+		for _, b := range encoded {
+			length = length<<8 | int(b)
+		}
+	}
+
+	if length > maxPacketSize {
+		return nil, fmt.Errorf("%w: %d bytes is too large", ErrInvalidPacket, length)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+
+	return decode(header[0], content)
+}
+
+// Decode parses a single element that takes up all of data.
+func Decode(data []byte) (*Packet, error) {
+	r := &byteReader{data: data}
+
+	packet, err := ReadPacket(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPacket, err)
+	}
+
+	if r.remaining() > 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidPacket)
+	}
+
+	return packet, nil
+}
+
+func decode(identifier byte, content []byte) (*Packet, error) {
+	if identifier&tagMask == tagMask {
+		return nil, fmt.Errorf("%w: multi byte tags are not supported", ErrInvalidPacket)
+	}
+
+	packet := &Packet{
+		Class:       identifier & classMask,
+		Constructed: identifier&constructedBit != 0,
+		Tag:         identifier & tagMask,
+	}
+
+	if !packet.Constructed {
+		packet.Value = content
+		return packet, nil
+	}
+
+	r := &byteReader{data: content}
+
+	for r.remaining() > 0 {
+		child, err := ReadPacket(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPacket, err)
+		}
+
+		packet.Children = append(packet.Children, child)
+	}
+
+	return packet, nil
+}
+
+type byteReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if r.offset >= len(r.data) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.data[r.offset:])
+	r.offset += n
+
+	return n, nil
+}
+
+func (r *byteReader) remaining() int {
+	return len(r.data) - r.offset
+}
@@ -0,0 +1,203 @@
+package ldap
+
+import (
+	"ems/infrastructure/ldap/ber"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Filter choices of RFC 4511 section 4.5.1.
+const (
+	filterAnd            byte = 0
+	filterOr             byte = 1
+	filterNot            byte = 2
+	filterEqualityMatch  byte = 3
+	filterSubstrings     byte = 4
+	filterGreaterOrEqual byte = 5
+	filterLessOrEqual    byte = 6
+	filterPresent        byte = 7
+	filterApproxMatch    byte = 8
+)
+
+// EscapeFilter escapes a value for use inside a search filter, so that an email like
+// "*)(uid=*" cannot widen the search.
+func EscapeFilter(value string) string {
+	var escaped strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '*', '(', ')', 0:
+			fmt.Fprintf(&escaped, "\\%02x", c)
+		default:
+			escaped.WriteByte(c)
+		}
+	}
+
+	return escaped.String()
+}
+
+// compileFilter converts the string form of a search filter (RFC 4515) to its BER encoding.
+// Extensible matches are not supported.
+func compileFilter(filter string) (*ber.Packet, error) {
+	packet, rest, err := parseFilter(strings.TrimSpace(filter))
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("invalid filter %q: unexpected %q", filter, rest)
+	}
+
+	return packet, nil
+}
+
+func parseFilter(filter string) (*ber.Packet, string, error) {
+	if !strings.HasPrefix(filter, "(") || len(filter) < 2 {
+		return nil, "", fmt.Errorf("expected (")
+	}
+
+	filter = filter[1:]
+
+	switch filter[0] {
+	case '&', '|':
+		tag := filterAnd
+		if filter[0] == '|' {
+			tag = filterOr
+		}
+
+		packet := ber.NewConstructed(ber.ClassContext, tag)
+		filter = filter[1:]
+
+		for strings.HasPrefix(filter, "(") {
+			child, rest, err := parseFilter(filter)
+			if err != nil {
+				return nil, "", err
+			}
+
+			packet.Append(child)
+			filter = rest
+		}
+
+		return closeFilter(packet, filter)
+	case '!':
+		child, rest, err := parseFilter(filter[1:])
+		if err != nil {
+			return nil, "", err
+		}
+
+		return closeFilter(ber.NewConstructed(ber.ClassContext, filterNot, child), rest)
+	}
+
+	end := strings.IndexByte(filter, ')')
+	if end < 0 {
+		return nil, "", fmt.Errorf("expected )")
+	}
+
+	packet, err := parseItem(filter[:end])
+	if err != nil {
+		return nil, "", err
+	}
+
+	return packet, filter[end+1:], nil
+}
+
+func closeFilter(packet *ber.Packet, rest string) (*ber.Packet, string, error) {
+	if !strings.HasPrefix(rest, ")") {
+		return nil, "", fmt.Errorf("expected )")
+	}
+
+	return packet, rest[1:], nil
+}
+
+func parseItem(item string) (*ber.Packet, error) {
+	attribute, value, found := strings.Cut(item, "=")
+	if !found || attribute == "" {
+		return nil, fmt.Errorf("expected attribute=value")
+	}
+
+	tag := filterEqualityMatch
+
+	switch attribute[len(attribute)-1] {
+	case '>':
+		tag = filterGreaterOrEqual
+	case '<':
+		tag = filterLessOrEqual
+	case '~':
+		tag = filterApproxMatch
+	case ':':
+		return nil, fmt.Errorf("extensible matches are not supported")
+	}
+
+	if tag != filterEqualityMatch {
+		attribute = attribute[:len(attribute)-1]
+	} else if value == "*" {
+		return ber.NewString(ber.ClassContext, filterPresent, attribute), nil
+	} else if strings.Contains(value, "*") {
+		return parseSubstrings(attribute, value)
+	}
+
+	assertion, err := unescapeFilter(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return ber.NewConstructed(ber.ClassContext, tag, ber.NewOctetString(attribute), ber.NewOctetString(assertion)), nil
+}
+
+func parseSubstrings(attribute, value string) (*ber.Packet, error) {
+	parts := strings.Split(value, "*")
+	substrings := ber.NewSequence()
+
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		unescaped, err := unescapeFilter(part)
+		if err != nil {
+			return nil, err
+		}
+
+		var tag byte = 1 // any
+		switch i {
+		case 0:
+			tag = 0 // initial
+		case len(parts) - 1:
+			tag = 2 // final
+		}
+
+		substrings.Append(ber.NewString(ber.ClassContext, tag, unescaped))
+	}
+
+	return ber.NewConstructed(ber.ClassContext, filterSubstrings, ber.NewOctetString(attribute), substrings), nil
+}
+
+func unescapeFilter(value string) (string, error) {
+	if !strings.Contains(value, "\\") {
+		return value, nil
+	}
+
+	var unescaped strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			unescaped.WriteByte(value[i])
+			continue
+		}
+
+		if i+3 > len(value) {
+			return "", fmt.Errorf("incomplete escape in %q", value)
+		}
+
+		decoded, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("invalid escape in %q", value)
+		}
+
+		unescaped.Write(decoded)
+		i += 2
+	}
+
+	return unescaped.String(), nil
+}
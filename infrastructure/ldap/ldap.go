@@ -0,0 +1,478 @@
+// Package ldap is a minimal LDAPv3 client for signing users in with a simple bind and reading
+// their group memberships.
+package ldap
+
+import (
+	"crypto/tls"
+	apperror "ems/app/model/app_error"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/infrastructure/ldap/ber"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Protocol operations of RFC 4511 section 4.2 onwards.
+const (
+	opBindRequest           byte = 0
+	opBindResponse          byte = 1
+	opUnbindRequest         byte = 2
+	opSearchRequest         byte = 3
+	opSearchResultEntry     byte = 4
+	opSearchResultDone      byte = 5
+	opSearchResultReference byte = 19
+	opExtendedRequest       byte = 23
+	opExtendedResponse      byte = 24
+)
+
+const (
+	resultSuccess            = 0
+	resultInvalidCredentials = 49
+
+	scopeWholeSubtree = 2
+	derefAlways       = 3
+
+	startTLSOID     = "1.3.6.1.4.1.1466.20037"
+	pagedResultsOID = "1.2.840.113556.1.4.319"
+	pageSize        = 500
+)
+
+// ResultError is an LDAP result other than success.
+type ResultError struct {
+	Code    int64
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("ldap result %d: %s", e.Code, e.Message)
+}
+
+type entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// first returns the first value of an attribute. Attribute names are case insensitive.
+func (e *entry) first(attribute string) string {
+	if values := e.Attributes[strings.ToLower(attribute)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type conn struct {
+	conn      net.Conn
+	messageID int64
+}
+
+type directory struct {
+	config config.LDAPConfiguration
+}
+
+func NewDirectory(ldapConfig config.LDAPConfiguration) domain.Directory {
+	return &directory{ldapConfig}
+}
+
+// Authenticate finds the user by email and checks the password by binding as them. It
+// returns apperror.ErrInvalidCredentials when the user is unknown or the password is wrong.
+func (d *directory) Authenticate(email, password string) (*response.DirectoryUser, error) {
+	// A simple bind with an empty password is an unauthenticated bind, which servers accept.
+	if password == "" {
+		return nil, apperror.ErrInvalidCredentials
+	}
+
+	c, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	entries, err := c.search(d.config.BaseDN, fmt.Sprintf(d.config.UserFilter, EscapeFilter(email)), d.attributes())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, apperror.ErrInvalidCredentials
+	}
+
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("%d directory entries match %s", len(entries), email)
+	}
+
+	if err := c.bind(entries[0].DN, password); err != nil {
+		return nil, err
+	}
+
+	return d.directoryUser(&entries[0]), nil
+}
+
+// FetchUsers returns every user matched by the sync filter that has an email.
+func (d *directory) FetchUsers() ([]response.DirectoryUser, error) {
+	c, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	entries, err := c.search(d.config.BaseDN, d.config.SyncFilter, d.attributes())
+	if err != nil {
+		return nil, err
+	}
+
+	var users []response.DirectoryUser
+
+	for i := range entries {
+		if user := d.directoryUser(&entries[i]); user.Email != "" {
+			users = append(users, *user)
+		}
+	}
+
+	return users, nil
+}
+
+func (d *directory) attributes() []string {
+	return []string{d.config.EmailAttribute, d.config.FirstNameAttribute, d.config.LastNameAttribute,
+		d.config.GroupAttribute}
+}
+
+func (d *directory) directoryUser(entry *entry) *response.DirectoryUser {
+	return &response.DirectoryUser{
+		DN:        entry.DN,
+		Email:     entry.first(d.config.EmailAttribute),
+		FirstName: entry.first(d.config.FirstNameAttribute),
+		LastName:  entry.first(d.config.LastNameAttribute),
+		Groups:    entry.Attributes[strings.ToLower(d.config.GroupAttribute)],
+	}
+}
+
+// connect opens a connection and binds as the service account, or anonymously without one.
+func (d *directory) connect() (*conn, error) {
+	serverURL, err := url.Parse(d.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP_URL: %w", err)
+	}
+
+	host := serverURL.Host
+	dialer := &net.Dialer{Timeout: d.config.Timeout}
+	tlsConfig := &tls.Config{ServerName: serverURL.Hostname()}
+
+	var netConn net.Conn
+
+	switch serverURL.Scheme {
+	case "ldap":
+		if serverURL.Port() == "" {
+			host = net.JoinHostPort(host, "389")
+		}
+		netConn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		if serverURL.Port() == "" {
+			host = net.JoinHostPort(host, "636")
+		}
+		netConn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	default:
+		return nil, fmt.Errorf("unsupported LDAP_URL scheme %s", serverURL.Scheme)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// One deadline covers the whole exchange, which only ever needs a few round trips.
+	if err := netConn.SetDeadline(time.Now().Add(d.config.Timeout)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	c := &conn{conn: netConn}
+
+	if d.config.StartTLS && serverURL.Scheme == "ldap" {
+		if err := c.startTLS(tlsConfig); err != nil {
+			c.conn.Close()
+			return nil, err
+		}
+	}
+
+	if d.config.BindDN != "" {
+		if err := c.bind(d.config.BindDN, d.config.BindPassword); err != nil {
+			c.close()
+			return nil, fmt.Errorf("service account bind failed: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (c *conn) send(op *ber.Packet, controls ...*ber.Packet) (int64, error) {
+	c.messageID++
+
+	message := ber.NewSequence(ber.NewInteger(ber.ClassUniversal, ber.TagInteger, c.messageID), op)
+	if len(controls) > 0 {
+		message.Append(ber.NewConstructed(ber.ClassContext, 0, controls...))
+	}
+
+	if _, err := c.conn.Write(message.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return c.messageID, nil
+}
+
+// receive reads the next message for messageID and returns its protocol operation and, when
+// present, its controls.
+func (c *conn) receive(messageID int64) (*ber.Packet, *ber.Packet, error) {
+	for {
+		message, err := ber.ReadPacket(c.conn)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		id, err := message.Child(0)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		op, err := message.Child(1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if id.Int() == 0 {
+			return nil, nil, fmt.Errorf("ldap server sent a notice of disconnection")
+		}
+
+		if id.Int() != messageID {
+			continue
+		}
+
+		var controls *ber.Packet
+		if len(message.Children) > 2 {
+			controls = message.Children[2]
+		}
+
+		return op, controls, nil
+	}
+}
+
+func (c *conn) bind(dn, password string) error {
+	messageID, err := c.send(ber.NewConstructed(ber.ClassApplication, opBindRequest,
+		ber.NewInteger(ber.ClassUniversal, ber.TagInteger, 3),
+		ber.NewOctetString(dn),
+		ber.NewString(ber.ClassContext, 0, password),
+	))
+	if err != nil {
+		return err
+	}
+
+	op, _, err := c.receive(messageID)
+	if err != nil {
+		return err
+	}
+
+	if !op.Is(ber.ClassApplication, opBindResponse) {
+		return fmt.Errorf("unexpected response to bind")
+	}
+
+	err = result(op)
+
+	var resultError *ResultError
+	if errors.As(err, &resultError) && resultError.Code == resultInvalidCredentials {
+		return apperror.ErrInvalidCredentials
+	}
+
+	return err
+}
+
+func (c *conn) startTLS(tlsConfig *tls.Config) error {
+	messageID, err := c.send(ber.NewConstructed(ber.ClassApplication, opExtendedRequest,
+		ber.NewString(ber.ClassContext, 0, startTLSOID)))
+	if err != nil {
+		return err
+	}
+
+	op, _, err := c.receive(messageID)
+	if err != nil {
+		return err
+	}
+
+	if !op.Is(ber.ClassApplication, opExtendedResponse) {
+		return fmt.Errorf("unexpected response to StartTLS")
+	}
+
+	if err := result(op); err != nil {
+		return fmt.Errorf("StartTLS failed: %w", err)
+	}
+
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+
+	c.conn = tlsConn
+
+	return nil
+}
+
+// search runs a subtree search, following the paged results control so that servers with a
+// size limit, like Active Directory, return every entry.
+func (c *conn) search(baseDN, filter string, attributes []string) ([]entry, error) {
+	compiled, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := ber.NewSequence()
+	for _, attribute := range attributes {
+		requested.Append(ber.NewOctetString(attribute))
+	}
+
+	var (
+		entries []entry
+		cookie  string
+	)
+
+	for {
+		messageID, err := c.send(ber.NewConstructed(ber.ClassApplication, opSearchRequest,
+			ber.NewOctetString(baseDN),
+			ber.NewInteger(ber.ClassUniversal, ber.TagEnumerated, scopeWholeSubtree),
+			ber.NewInteger(ber.ClassUniversal, ber.TagEnumerated, derefAlways),
+			ber.NewInteger(ber.ClassUniversal, ber.TagInteger, 0),
+			ber.NewInteger(ber.ClassUniversal, ber.TagInteger, 0),
+			ber.NewBoolean(false),
+			compiled,
+			requested,
+		), pagedResultsControl(cookie))
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			op, controls, err := c.receive(messageID)
+			if err != nil {
+				return nil, err
+			}
+
+			if op.Is(ber.ClassApplication, opSearchResultEntry) {
+				entry, err := parseEntry(op)
+				if err != nil {
+					return nil, err
+				}
+
+				entries = append(entries, *entry)
+				continue
+			}
+
+			if op.Is(ber.ClassApplication, opSearchResultReference) {
+				continue
+			}
+
+			if !op.Is(ber.ClassApplication, opSearchResultDone) {
+				return nil, fmt.Errorf("unexpected response to search")
+			}
+
+			if err := result(op); err != nil {
+				return nil, err
+			}
+
+			cookie = nextPageCookie(controls)
+			break
+		}
+
+		if cookie == "" {
+			return entries, nil
+		}
+	}
+}
+
+// close unbinds, which the server answers by closing the connection.
+func (c *conn) close() {
+	c.send(ber.NewPrimitive(ber.ClassApplication, opUnbindRequest, nil))
+	c.conn.Close()
+}
+
+func pagedResultsControl(cookie string) *ber.Packet {
+	value := ber.NewSequence(ber.NewInteger(ber.ClassUniversal, ber.TagInteger, pageSize), ber.NewOctetString(cookie))
+
+	return ber.NewSequence(ber.NewOctetString(pagedResultsOID), ber.NewBoolean(false),
+		ber.NewPrimitive(ber.ClassUniversal, ber.TagOctetString, value.Bytes()))
+}
+
+// nextPageCookie returns the cookie for the next page, or "" when this was the last one or
+// the server ignored the control.
+func nextPageCookie(controls *ber.Packet) string {
+	if controls == nil {
+		return ""
+	}
+
+	for _, control := range controls.Children {
+		if len(control.Children) < 2 || control.Children[0].String() != pagedResultsOID {
+			continue
+		}
+
+		value, err := ber.Decode(control.Children[len(control.Children)-1].Value)
+		if err != nil || len(value.Children) < 2 {
+			return ""
+		}
+
+		return value.Children[1].String()
+	}
+
+	return ""
+}
+
+func parseEntry(op *ber.Packet) (*entry, error) {
+	dn, err := op.Child(0)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := op.Child(1)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entry{DN: dn.String(), Attributes: make(map[string][]string)}
+
+	for _, attribute := range attributes.Children {
+		name, err := attribute.Child(0)
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := attribute.Child(1)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(name.String())
+		for _, value := range values.Children {
+			entry.Attributes[key] = append(entry.Attributes[key], value.String())
+		}
+	}
+
+	return entry, nil
+}
+
+// result turns an LDAPResult into an error unless it is success.
+func result(op *ber.Packet) error {
+	code, err := op.Child(0)
+	if err != nil {
+		return err
+	}
+
+	if code.Int() == resultSuccess {
+		return nil
+	}
+
+	var message string
+	if diagnostic, err := op.Child(2); err == nil {
+		message = diagnostic.String()
+	}
+
+	return &ResultError{Code: code.Int(), Message: message}
+}
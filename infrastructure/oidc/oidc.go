@@ -0,0 +1,322 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// keyRefreshInterval limits how often an unknown key ID makes us fetch the JWKS again.
+	keyRefreshInterval = time.Minute
+	// clockSkew is the leeway allowed when checking the ID token's timestamps.
+	clockSkew = time.Minute
+)
+
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// provider implements the OpenID Connect authorization code flow with PKCE. The discovery
+// document and signing keys are fetched on first use and cached.
+type provider struct {
+	config config.OIDCConfiguration
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func NewIdentityProvider(oidcConfig config.OIDCConfiguration) domain.IdentityProvider {
+	return &provider{
+		config: oidcConfig,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *provider) AuthorizationURL(state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the identity from the verified ID token.
+func (p *provider) Exchange(code, codeVerifier, nonce string) (*response.OIDCIdentity, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic, which every provider has to support, form encodes both values first.
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("token endpoint returned %s", res.Status)
+	}
+
+	if res.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned %s: %s %s", res.Status, token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("token endpoint returned no id token")
+	}
+
+	return p.verifyIDToken(token.IDToken, discovery.Issuer, nonce)
+}
+
+func (p *provider) verifyIDToken(idToken, issuer, nonce string) (*response.OIDCIdentity, error) {
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	claims := jwt.MapClaims{}
+
+	if _, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("unexpected signing algorithm %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		return p.getKey(kid)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	now := time.Now()
+
+	if iss, _ := claims["iss"].(string); iss != issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %s", ErrInvalidIDToken, iss)
+	}
+
+	if !hasAudience(claims["aud"], p.config.ClientID) {
+		return nil, fmt.Errorf("%w: not issued for this client", ErrInvalidIDToken)
+	}
+
+	if azp, ok := claims["azp"].(string); ok && azp != p.config.ClientID {
+		return nil, fmt.Errorf("%w: authorized party is %s", ErrInvalidIDToken, azp)
+	}
+
+	if exp, ok := claims["exp"].(float64); !ok || now.Add(-clockSkew).Unix() > int64(exp) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	}
+
+	if iat, ok := claims["iat"].(float64); ok && now.Add(clockSkew).Unix() < int64(iat) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce does not match", ErrInvalidIDToken)
+	}
+
+	identity := &response.OIDCIdentity{Issuer: issuer, EmailVerified: true}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.GivenName, _ = claims["given_name"].(string)
+	identity.FamilyName, _ = claims["family_name"].(string)
+	identity.Name, _ = claims["name"].(string)
+
+	// Some providers leave email_verified out for addresses they manage themselves.
+	if emailVerified, ok := claims["email_verified"].(bool); ok {
+		identity.EmailVerified = emailVerified
+	}
+
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return identity, nil
+}
+
+func (p *provider) getDiscovery() (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery discoveryDocument
+	if err := p.getJSON(strings.TrimSuffix(p.config.IssuerURL, "/")+discoveryPath, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to load the identity provider configuration: %w", err)
+	}
+
+	if discovery.Issuer != p.config.IssuerURL {
+		return nil, fmt.Errorf("identity provider issuer %s does not match %s", discovery.Issuer, p.config.IssuerURL)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksURI == "" {
+		return nil, fmt.Errorf("identity provider configuration is incomplete")
+	}
+
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+// getKey returns the signing key with the given ID, fetching the JWKS again when the provider
+// has rotated to a key we have not seen.
+func (p *provider) getKey(kid string) (*rsa.PublicKey, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := p.getJSON(discovery.JwksURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to load the identity provider keys: %w", err)
+	}
+
+	p.keys = make(map[string]*rsa.PublicKey)
+	p.keysFetchedAt = time.Now()
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := rsaPublicKey(jwk)
+		if err != nil {
+			return nil, err
+		}
+
+		p.keys[jwk.Kid] = key
+	}
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (p *provider) getJSON(url string, out interface{}) error {
+	res, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, res.Status)
+	}
+
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(out)
+}
+
+func rsaPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus for key %q", jwk.Kid)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("invalid exponent for key %q", jwk.Kid)
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+// hasAudience accepts the aud claim as a single string or a list.
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, value := range aud {
+			if value == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
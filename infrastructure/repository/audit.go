@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/utils"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditTables maps each audited entity to the table its rows are kept in.
+var auditTables = map[constant.AuditEntity]string{
+	constant.AuditUser:                "User",
+	constant.AuditUserDetails:         "UserDetails",
+	constant.AuditDepartment:          "Department",
+	constant.AuditDepartmentMember:    "DepartmentMember",
+	constant.AuditLeave:               "DepartmentMemberLeaveRequest",
+	constant.AuditPermission:          "DepartmentMemberPermissionRequest",
+	constant.AuditNotice:              "UserNotice",
+	constant.AuditDocumentCategory:    "DocumentCategory",
+	constant.AuditDocument:            "UserDocument",
+	constant.AuditQuarantinedDocument: "QuarantinedDocument",
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db}
+}
+
+func (r *auditRepository) FetchAuditLogs(filters *request.FetchAuditLogs) (*utils.PaginationResponse, error) {
+	var (
+		data         []response.FetchAuditLogs
+		search            = "%" + strings.TrimSpace(filters.Search) + "%"
+		itemsPerPage uint = 10
+		totalCount   uint = 0
+		query        strings.Builder
+		queryParams  []interface{}
+	)
+
+	query.WriteString(`
+		SELECT al.ID, al.CreatedAt createdAt, al.ActorType actorType, al.UserID userID,
+		(usr.FirstName || ' ' || usr.LastName) AS userName, al.ServiceAccountID serviceAccountID,
+		al.ImpersonatorID impersonatorID, al.IPAddress ipAddress, al.[Action], al.Entity,
+		al.EntityID entityID, al.Changes changes, COUNT(*) OVER (PARTITION BY 1) AS [count]
+		FROM AuditLog al
+		LEFT JOIN [User] usr ON usr.ID = al.UserID
+		WHERE al.IsActive = 1`)
+
+	if filters.UserID > 0 {
+		query.WriteString(` AND al.UserID = ?`)
+		queryParams = append(queryParams, filters.UserID)
+	}
+
+	if filters.Action != "" {
+		query.WriteString(` AND al.[Action] = ?`)
+		queryParams = append(queryParams, filters.Action)
+	}
+
+	if filters.Entity != "" {
+		query.WriteString(` AND al.Entity = ?`)
+		queryParams = append(queryParams, filters.Entity)
+	}
+
+	if filters.EntityID > 0 {
+		query.WriteString(` AND al.EntityID = ?`)
+		queryParams = append(queryParams, filters.EntityID)
+	}
+
+	if filters.FromDate != "" {
+		query.WriteString(` AND date(al.CreatedAt) >= ?`)
+		queryParams = append(queryParams, filters.FromDate)
+	}
+
+	if filters.ToDate != "" {
+		query.WriteString(` AND date(al.CreatedAt) <= ?`)
+		queryParams = append(queryParams, filters.ToDate)
+	}
+
+	if len(filters.Search) > 0 {
+		query.WriteString(` AND (al.Changes LIKE ? OR al.IPAddress LIKE ?)`)
+		queryParams = append(queryParams, search, search)
+	}
+
+	query.WriteString(` ORDER BY al.ID DESC`)
+
+	if filters.Page > 0 {
+		query.WriteString(` LIMIT ? OFFSET ?`)
+		queryParams = append(queryParams, itemsPerPage, (filters.Page-1)*itemsPerPage)
+	}
+
+	if err := r.db.Raw(query.String(), queryParams...).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range data {
+		if err := json.Unmarshal([]byte(data[i].ChangeList), &data[i].Changes); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(data) > 0 {
+		totalCount = data[0].Count
+	}
+
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
+}
+
+// FetchAuditLogChain returns the rows after afterID in chain order.
+func (r *auditRepository) FetchAuditLogChain(afterID uint, limit int) ([]schema.AuditLog, error) {
+	var data []schema.AuditLog
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM AuditLog
+		WHERE ID > ?
+		ORDER BY ID
+		LIMIT ?`, afterID, limit).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// auditTarget is a row that a change touches, found by keys. For a create, keys find the new row
+// once it is saved.
+type auditTarget struct {
+	action constant.AuditAction
+	entity constant.AuditEntity
+	keys   map[string]interface{}
+}
+
+// auditTransaction makes the change in a transaction and records it in the audit log in the same
+// transaction, so that the change is saved with its audit log rows or not at all. The targets are
+// read before and after the change, and the fields that differ are recorded.
+func auditTransaction(db *gorm.DB, actor *request.AuditActor, targets []auditTarget,
+	change func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// SQLite takes the write lock on a transaction's first write. Taking it before anything is
+		// read makes concurrent changes, from this or another instance, wait their turn instead of
+		// failing to upgrade their read lock, and holds the chain until this change is committed.
+		if err := tx.Exec(`UPDATE AuditLog SET ID = ID WHERE 1 = 0`).Error; err != nil {
+			return err
+		}
+
+		before := make([]map[string]interface{}, len(targets))
+
+		for i, target := range targets {
+			if target.action == constant.AuditCreate {
+				continue
+			}
+
+			snapshot, err := snapshotEntity(tx, target.entity, target.keys)
+
+			if err != nil {
+				return err
+			}
+
+			before[i] = snapshot
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		for i, target := range targets {
+			if err := recordAuditLog(tx, actor, target, before[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// recordAuditLog appends the change to the target to the audit log, together with the field
+// history of the change, if any. The row continues from the last one in the chain, which no other
+// writer can extend while the transaction holds the write lock.
+func recordAuditLog(tx *gorm.DB, actor *request.AuditActor, target auditTarget, before map[string]interface{}) error {
+	after, err := snapshotEntity(tx, target.entity, target.keys)
+
+	if err != nil {
+		return err
+	}
+
+	changes := auditChanges(before, after)
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changeList, err := json.Marshal(changes)
+
+	if err != nil {
+		return err
+	}
+
+	// Saves that insert the row when it is missing, such as user details, are recorded as creates.
+	action := target.action
+	if action == constant.AuditUpdate && before == nil {
+		action = constant.AuditCreate
+	}
+
+	log := &schema.AuditLog{
+		ActorType:        string(actor.Type),
+		UserID:           actor.UserID,
+		ServiceAccountID: actor.ServiceAccountID,
+		ImpersonatorID:   actor.ImpersonatorID,
+		Action:           string(action),
+		Entity:           string(target.entity),
+		EntityID:         auditSnapshotID("ID", after, before),
+		Changes:          string(changeList),
+	}
+
+	if actor.IPAddress != "" {
+		log.IPAddress = &actor.IPAddress
+	}
+
+	if err := tx.Raw(`
+		SELECT Hash
+		FROM AuditLog
+		ORDER BY ID DESC
+		LIMIT 1`).Scan(&log.PrevHash).Error; err != nil {
+		return err
+	}
+
+	log.CreatedAt = time.Now()
+	log.Hash = utils.AuditLogHash(log)
+
+	if err := tx.Exec(`
+		INSERT INTO AuditLog
+		(CreatedAt, UpdatedAt, IsActive, ActorType, UserID, ServiceAccountID, ImpersonatorID, IPAddress,
+		[Action], Entity, EntityID, Changes, PrevHash, Hash)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		log.CreatedAt, log.CreatedAt, constant.Active, log.ActorType, log.UserID, log.ServiceAccountID,
+		log.ImpersonatorID, log.IPAddress, log.Action, log.Entity, log.EntityID, log.Changes, log.PrevHash,
+		log.Hash).Error; err != nil {
+		return err
+	}
+
+	history := userFieldHistory(target.entity, before, after, changes)
+
+	if len(history) == 0 {
+		return nil
+	}
+
+	if err := tx.Raw(`
+		SELECT ID
+		FROM AuditLog
+		ORDER BY ID DESC LIMIT 1`).Scan(&log.ID).Error; err != nil {
+		return err
+	}
+
+	for _, entry := range history {
+		if err := tx.Exec(`
+			INSERT INTO UserFieldHistory
+			(CreatedAt, UpdatedAt, IsActive, AuditLogID, UserID, Field, OldValue, NewValue, ActorType,
+			ChangedBy, ServiceAccountID)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			log.CreatedAt, log.CreatedAt, constant.Active, log.ID, entry.UserID, entry.Field, entry.OldValue,
+			entry.NewValue, log.ActorType, log.UserID, log.ServiceAccountID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// snapshotEntity reads the latest row of the entity matching keys, removed rows included, or nil
+// when there is none. Keys are column names set by the repositories and never come from a request.
+// Leave requests also carry their active dates, which are kept in a table of their own.
+func snapshotEntity(tx *gorm.DB, entity constant.AuditEntity, keys map[string]interface{}) (map[string]interface{}, error) {
+	table, ok := auditTables[entity]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown audit entity %s", entity)
+	}
+
+	columns := make([]string, 0, len(keys))
+	for column := range keys {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var (
+		conditions []string
+		params     []interface{}
+	)
+
+	for _, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("[%s] = ?", column))
+		params = append(params, keys[column])
+	}
+
+	data := map[string]interface{}{}
+
+	if err := tx.Raw(fmt.Sprintf(`
+		SELECT *
+		FROM [%s]
+		WHERE %s
+		ORDER BY ID DESC
+		LIMIT 1`, table, strings.Join(conditions, " AND ")), params...).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if entity == constant.AuditLeave {
+		var dates []map[string]interface{}
+
+		if err := tx.Raw(`
+			SELECT strftime('%Y-%m-%d', [Date]) AS [date], IsFullDay isFullDay, SessionType sessionType
+			FROM DepartmentMemberLeaveRequestDate
+			WHERE DepartmentMemberLeaveRequestID = ? AND IsActive = 1
+			ORDER BY [Date]`, data["ID"]).Scan(&dates).Error; err != nil {
+			return nil, err
+		}
+
+		data["Dates"] = dates
+	}
+
+	return data, nil
+}
+
+// auditChanges lists the fields that differ between two snapshots. Encrypted PII is compared in
+// clear text but recorded masked, and password hashes are never recorded.
+func auditChanges(before, after map[string]interface{}) map[string]response.AuditChange {
+	fields := make(map[string]bool)
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		if field != "UpdatedAt" && !strings.HasSuffix(field, "Index") {
+			names = append(names, field)
+		}
+	}
+	sort.Strings(names)
+
+	changes := make(map[string]response.AuditChange)
+
+	for _, field := range names {
+		beforeValue, beforeMasked := auditValue(field, before)
+		afterValue, afterMasked := auditValue(field, after)
+
+		beforeJSON, _ := json.Marshal(beforeValue)
+		afterJSON, _ := json.Marshal(afterValue)
+
+		if string(beforeJSON) == string(afterJSON) {
+			continue
+		}
+
+		changes[field] = response.AuditChange{Before: beforeMasked, After: afterMasked}
+	}
+
+	return changes
+}
+
+// auditValue returns a field's value to compare and the value to record in its place.
+func auditValue(field string, snapshot map[string]interface{}) (interface{}, interface{}) {
+	value, ok := snapshot[field]
+
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	if bytes, ok := value.([]byte); ok {
+		value = string(bytes)
+	}
+
+	if field == "Password" {
+		return value, "[redacted]"
+	}
+
+	if text, ok := value.(string); ok && utils.IsEncryptedPII(text) {
+		plaintext, err := utils.DecryptPII(text)
+
+		if err != nil {
+			return text, "[encrypted]"
+		}
+
+		return plaintext, utils.MaskValue(plaintext, 4)
+	}
+
+	return value, value
+}
+
+// auditSnapshotID reads an ID column of the changed row from whichever snapshot has it.
+func auditSnapshotID(column string, snapshots ...map[string]interface{}) *uint {
+	for _, snapshot := range snapshots {
+		if id, ok := snapshot[column].(int64); ok {
+			entityID := uint(id)
+			return &entityID
+		}
+	}
+
+	return nil
+}
+
+// userHistoryIgnoredFields are the bookkeeping columns left out of a user's field history.
+var userHistoryIgnoredFields = map[string]bool{
+	"ID": true, "CreatedAt": true, "DeletedAt": true, "IsActive": true, "UserID": true, "Password": true,
+	"PasswordChangedAt": true, "MustChangePassword": true,
+}
+
+// userFieldHistory lists the changed fields of a user or their details with the values as they
+// are stored, so that PII stays encrypted. Other entities have no field history.
+func userFieldHistory(entity constant.AuditEntity, before, after map[string]interface{},
+	changes map[string]response.AuditChange) []schema.UserFieldHistory {
+	var userID *uint
+
+	switch entity {
+	case constant.AuditUser:
+		userID = auditSnapshotID("ID", after, before)
+	case constant.AuditUserDetails:
+		userID = auditSnapshotID("UserID", after, before)
+	}
+
+	if userID == nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		if !userHistoryIgnoredFields[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	history := make([]schema.UserFieldHistory, 0, len(fields))
+
+	for _, field := range fields {
+		history = append(history, schema.UserFieldHistory{
+			UserID:   *userID,
+			Field:    field,
+			OldValue: historyValue(before[field]),
+			NewValue: historyValue(after[field]),
+		})
+	}
+
+	return history
+}
+
+func historyValue(value interface{}) *string {
+	var text string
+
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		text = string(value)
+	case string:
+		text = value
+	case time.Time:
+		text = value.Format(time.RFC3339)
+	default:
+		text = fmt.Sprint(value)
+	}
+
+	return &text
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type customFieldRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldRepository(db *gorm.DB) domain.CustomFieldRepository {
+	return &customFieldRepository{db}
+}
+
+func (r *customFieldRepository) CreateCustomField(req *request.CreateCustomField) error {
+	return r.db.Exec(`
+		INSERT INTO CustomField
+		(CreatedAt, UpdatedAt, IsActive, [Name], [Key], FieldType, Options, IsRequired, Pattern,
+		MinValue, MaxValue, Visibility)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, req.Name, req.Key, req.FieldType,
+		customFieldOptions(req.Options), req.IsRequired, customFieldPattern(req.Pattern),
+		req.MinValue, req.MaxValue, req.Visibility).Error
+}
+
+func (r *customFieldRepository) IsCustomFieldKeyExists(key string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM CustomField
+		WHERE [Key] = ? AND IsActive = 1`, key).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *customFieldRepository) IsCustomFieldKeyExistsExceptID(id uint, key string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM CustomField
+		WHERE ID <> ? AND [Key] = ? AND IsActive = 1`, id, key).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *customFieldRepository) FetchCustomFields() ([]response.FetchCustomFields, error) {
+	var data []response.FetchCustomFields
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], [Key], FieldType fieldType, Options, IsRequired isRequired, Pattern,
+		MinValue minValue, MaxValue maxValue, Visibility, CreatedAt
+		FROM CustomField
+		WHERE IsActive = 1
+		ORDER BY CreatedAt`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *customFieldRepository) GetCustomFieldByID(id uint) (*schema.CustomField, error) {
+	var data *schema.CustomField
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM CustomField
+		WHERE ID = ? AND IsActive = 1`, id).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *customFieldRepository) GetCustomFieldByKey(key string) (*schema.CustomField, error) {
+	var data *schema.CustomField
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM CustomField
+		WHERE [Key] = ? AND IsActive = 1`, key).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *customFieldRepository) UpdateCustomField(id uint, req *request.UpdateCustomField) error {
+	return r.db.Exec(`
+		UPDATE CustomField
+		SET UpdatedAt = ?, [Name] = ?, [Key] = ?, FieldType = ?, Options = ?, IsRequired = ?,
+		Pattern = ?, MinValue = ?, MaxValue = ?, Visibility = ?
+		WHERE ID = ?`,
+		time.Now(), req.Name, req.Key, req.FieldType, customFieldOptions(req.Options), req.IsRequired,
+		customFieldPattern(req.Pattern), req.MinValue, req.MaxValue, req.Visibility, id).Error
+}
+
+func (r *customFieldRepository) RemoveCustomField(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE CustomField
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), id).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE UserCustomFieldValue
+			SET IsActive = ?, DeletedAt = ?
+			WHERE CustomFieldID = ?`, constant.Inactive, time.Now(), id).Error
+	})
+}
+
+func (r *customFieldRepository) FetchUserCustomFieldValues(userID uint, visibilities []uint) ([]response.FetchUserCustomFieldValue, error) {
+	var data []response.FetchUserCustomFieldValue
+
+	if err := r.db.Raw(`
+		SELECT cf.ID customFieldID, cf.[Key], cf.[Name], cf.FieldType fieldType, ucfv.[Value]
+		FROM UserCustomFieldValue ucfv
+		INNER JOIN CustomField cf ON cf.ID = ucfv.CustomFieldID AND cf.IsActive = 1
+		WHERE ucfv.UserID = ? AND ucfv.IsActive = 1 AND cf.Visibility IN ?
+		ORDER BY cf.CreatedAt`, userID, visibilities).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *customFieldRepository) UpdateUserCustomFieldValues(userID uint, values []request.CustomFieldValue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, value := range values {
+			if err := tx.Exec(`
+				UPDATE UserCustomFieldValue
+				SET IsActive = ?, DeletedAt = ?
+				WHERE UserID = ? AND CustomFieldID = ? AND IsActive = 1`,
+				constant.Inactive, time.Now(), userID, value.CustomFieldID).Error; err != nil {
+				return err
+			}
+
+			if value.Value == "" {
+				continue
+			}
+
+			if err := tx.Exec(`
+				INSERT INTO UserCustomFieldValue
+				(CreatedAt, UpdatedAt, IsActive, UserID, CustomFieldID, [Value])
+				VALUES(?, ?, ?, ?, ?, ?)`,
+				time.Now(), time.Now(), constant.Active, userID, value.CustomFieldID, value.Value).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func customFieldOptions(options []string) *string {
+	if len(options) == 0 {
+		return nil
+	}
+
+	joined := strings.Join(options, ",")
+	return &joined
+}
+
+func customFieldPattern(pattern string) *string {
+	if pattern == "" {
+		return nil
+	}
+	return &pattern
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"ems/app/model/constant"
+	"ems/app/model/event"
 	"ems/app/model/request"
 	"ems/app/model/response"
 	"ems/domain"
@@ -33,8 +34,13 @@ func (r *departmentRepository) IsDepartmentExists(id uint) (bool, error) {
 	return count > 0, nil
 }
 
-func (r *departmentRepository) CreateDepartment(req *request.CreateDepartment) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *departmentRepository) CreateDepartment(actor *request.AuditActor, req *request.CreateDepartment) error {
+	targets := []auditTarget{
+		{constant.AuditCreate, constant.AuditDepartment, map[string]interface{}{"Name": req.Name}},
+		{constant.AuditCreate, constant.AuditDepartmentMember, map[string]interface{}{"UserID": req.LeadID}},
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			INSERT INTO Department
 			(CreatedAt, UpdatedAt, IsActive, [Name])
@@ -57,7 +63,8 @@ func (r *departmentRepository) CreateDepartment(req *request.CreateDepartment) e
 			return err
 		}
 
-		return nil
+		return publishMembershipChanges(tx,
+			[]event.DepartmentMembershipChanged{{DepartmentID: departmentID, UserID: req.LeadID}}, true)
 	})
 }
 
@@ -141,8 +148,14 @@ func (r *departmentRepository) IsDepartmentNameExistsExceptID(id uint, name stri
 	return count > 0, nil
 }
 
-func (r *departmentRepository) UpdateDepartment(id uint, req *request.UpdateDepartment) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *departmentRepository) UpdateDepartment(actor *request.AuditActor, id uint, req *request.UpdateDepartment) error {
+	targets := []auditTarget{
+		{constant.AuditUpdate, constant.AuditDepartment, map[string]interface{}{"ID": id}},
+		{constant.AuditUpdate, constant.AuditDepartmentMember,
+			map[string]interface{}{"DepartmentID": id, "UserID": req.LeadID}},
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			UPDATE Department
 			SET UpdatedAt = ?, [Name] = ?
@@ -151,6 +164,12 @@ func (r *departmentRepository) UpdateDepartment(id uint, req *request.UpdateDepa
 			return err
 		}
 
+		memberships, err := activeMemberships(tx, "DepartmentID = ? AND UserID <> ?", id, req.LeadID)
+
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Exec(`
 			UPDATE DepartmentMember
 			SET isActive = 0, DeletedAt = ?
@@ -159,24 +178,38 @@ func (r *departmentRepository) UpdateDepartment(id uint, req *request.UpdateDepa
 			return err
 		}
 
-		if err := tx.Exec(`
+		if err := publishMembershipChanges(tx, memberships, false); err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
 			INSERT INTO DepartmentMember (CreatedAt, UpdatedAt, DepartmentID, UserID)
 			SELECT ?, ?, ?, ?
 			WHERE NOT EXISTS (
 				SELECT 1 FROM DepartmentMember WHERE isActive = 1 AND departmentID = ? AND userID = ?
 			)`,
-			time.Now(), time.Now(), id, req.LeadID, id, req.LeadID).Error; err != nil {
-			return err
+			time.Now(), time.Now(), id, req.LeadID, id, req.LeadID)
+
+		if result.Error != nil {
+			return result.Error
 		}
 
-		return nil
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return publishMembershipChanges(tx,
+			[]event.DepartmentMembershipChanged{{DepartmentID: id, UserID: req.LeadID}}, true)
 	})
 }
 
-func (r *departmentRepository) MappUsersToDepartment(departmentID uint, req *request.MappUsersToDepartment) error {
+func (r *departmentRepository) MappUsersToDepartment(actor *request.AuditActor, departmentID uint,
+	req *request.MappUsersToDepartment) error {
 	var (
 		placeholders []string
 		args         []interface{}
+		memberships  []event.DepartmentMembershipChanged
+		targets      []auditTarget
 		now          = time.Now()
 	)
 
@@ -185,15 +218,21 @@ func (r *departmentRepository) MappUsersToDepartment(departmentID uint, req *req
 	for _, userID := range req.UserIDs {
 		placeholders = append(placeholders, "(?, ?, ?, ?)")
 		args = append(args, now, now, departmentID, userID)
+		memberships = append(memberships,
+			event.DepartmentMembershipChanged{DepartmentID: departmentID, UserID: userID})
+		targets = append(targets, auditTarget{constant.AuditCreate, constant.AuditDepartmentMember,
+			map[string]interface{}{"DepartmentID": departmentID, "UserID": userID}})
 	}
 
 	query += strings.Join(placeholders, ", ")
 
-	if err := r.db.Exec(query, args...).Error; err != nil {
-		return err
-	}
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(query, args...).Error; err != nil {
+			return err
+		}
 
-	return nil
+		return publishMembershipChanges(tx, memberships, true)
+	})
 }
 
 func (r *departmentRepository) FetchDepartmentMembers(departmentID uint, filters *request.CommonRequest) (*utils.PaginationResponse, error) {
@@ -245,11 +284,26 @@ func (r *departmentRepository) FetchDepartmentMembers(departmentID uint, filters
 	return &response, nil
 }
 
-func (r *departmentRepository) UnMapUser(req *request.UnMapUser) error {
-	return r.db.Exec(`
-		UPDATE DepartmentMember
-		SET IsActive = 0, DeletedAt = ?
-		WHERE UserID = ?`, time.Now(), req.UserID).Error
+func (r *departmentRepository) UnMapUser(actor *request.AuditActor, req *request.UnMapUser) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditDepartmentMember,
+		map[string]interface{}{"UserID": req.UserID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		memberships, err := activeMemberships(tx, "UserID = ?", req.UserID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			UPDATE DepartmentMember
+			SET IsActive = 0, DeletedAt = ?
+			WHERE UserID = ?`, time.Now(), req.UserID).Error; err != nil {
+			return err
+		}
+
+		return publishMembershipChanges(tx, memberships, false)
+	})
 }
 
 func (r *departmentRepository) IsDepartmentMemberExists(id uint) (bool, error) {
@@ -265,8 +319,10 @@ func (r *departmentRepository) IsDepartmentMemberExists(id uint) (bool, error) {
 	return count > 0, nil
 }
 
-func (r *departmentRepository) RemoveDepartment(departmentID uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *departmentRepository) RemoveDepartment(actor *request.AuditActor, departmentID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditDepartment, map[string]interface{}{"ID": departmentID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			UPDATE Department
 			SET IsActive = ?, DeletedAt = ?
@@ -274,13 +330,20 @@ func (r *departmentRepository) RemoveDepartment(departmentID uint) error {
 			return err
 		}
 
+		memberships, err := activeMemberships(tx, "DepartmentID = ?", departmentID)
+
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Exec(`
 			UPDATE DepartmentMember
 			SET IsActive = ?, DeletedAt = ?
 			WHERE DepartmentID = ?`, constant.Inactive, time.Now(), departmentID).Error; err != nil {
 			return err
 		}
-		return nil
+
+		return publishMembershipChanges(tx, memberships, false)
 	})
 }
 
@@ -297,12 +360,19 @@ func (r *departmentRepository) GetDepartmentMemberCount(departmentID uint) (int,
 	return count, nil
 }
 
-func (r *departmentRepository) MapLeadToDepartment(departmentID uint, LeadID uint) error {
-	if err := r.db.Exec(`
+func (r *departmentRepository) MapLeadToDepartment(actor *request.AuditActor, departmentID uint, LeadID uint) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditDepartmentMember,
+		map[string]interface{}{"DepartmentID": departmentID, "UserID": LeadID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
 			INSERT INTO DepartmentMember (CreatedAt, UpdatedAt, DepartmentID, UserID)
 			VALUES(?, ?, ?, ?)`,
-		time.Now(), time.Now(), departmentID, LeadID).Error; err != nil {
-		return err
-	}
-	return nil
+			time.Now(), time.Now(), departmentID, LeadID).Error; err != nil {
+			return err
+		}
+
+		return publishMembershipChanges(tx,
+			[]event.DepartmentMembershipChanged{{DepartmentID: departmentID, UserID: LeadID}}, true)
+	})
 }
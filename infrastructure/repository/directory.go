@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/event"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type directoryRepository struct {
+	db *gorm.DB
+}
+
+func NewDirectoryRepository(db *gorm.DB) domain.DirectoryRepository {
+	return &directoryRepository{db}
+}
+
+func (r *directoryRepository) FetchDirectorySyncUsers() ([]response.DirectorySyncUser, error) {
+	var data []response.DirectorySyncUser
+
+	if err := r.db.Raw(`
+		SELECT usr.ID, usr.Email, usr.FirstName firstName, usr.LastName lastName, usr.RoleID roleID,
+		usr.AuthSource authSource, dm.DepartmentID departmentID
+		FROM [User] usr
+		LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+		WHERE usr.IsActive = 1`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *directoryRepository) FetchRoleNames() (map[uint]string, error) {
+	return r.fetchNames(`
+		SELECT ID, [Name]
+		FROM [Role]
+		WHERE IsActive = 1`)
+}
+
+func (r *directoryRepository) FetchDepartmentNames() (map[uint]string, error) {
+	return r.fetchNames(`
+		SELECT ID, [Name]
+		FROM Department
+		WHERE IsActive = 1`)
+}
+
+func (r *directoryRepository) fetchNames(query string) (map[uint]string, error) {
+	var rows []struct {
+		ID   uint
+		Name string
+	}
+
+	if err := r.db.Raw(query).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		names[row.ID] = row.Name
+	}
+
+	return names, nil
+}
+
+// CreateDirectoryUser creates a user found in the directory. They sign in with their directory
+// password, so the stored one is a random placeholder.
+func (r *directoryRepository) CreateDirectoryUser(actor *request.AuditActor, req *request.CreateUser,
+	hashedPassword string) (uint, error) {
+	var userID uint
+
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditUser, map[string]interface{}{"Code": req.Code}}}
+
+	err := auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO [User] (
+				CreatedAt, UpdatedAt, IsActive, ManagerID, FirstName, LastName, Email, Mobile,
+				Code, RoleID, [Password], PasswordChangedAt, MustChangePassword, AuthSource
+			)
+			VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?
+			)`,
+			time.Now(), time.Now(), constant.Active, 3, // 3 => Manager
+			req.FirstName, req.LastName, req.Email, req.Mobile, req.Code,
+			req.RoleID, hashedPassword, time.Now(), constant.DirectoryAuth).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM [User]
+			ORDER BY ID DESC LIMIT 1`).Scan(&userID).Error; err != nil {
+			return err
+		}
+
+		return publishUserCreated(tx, userID)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// UpdateDirectoryUser applies the directory's names and role and makes the directory the
+// source of the user's password.
+func (r *directoryRepository) UpdateDirectoryUser(actor *request.AuditActor, userID uint,
+	req *request.UpdateDirectoryUser) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditUser, map[string]interface{}{"ID": userID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE [User]
+			SET UpdatedAt = ?, FirstName = ?, LastName = ?, RoleID = ?, AuthSource = ?, MustChangePassword = 0
+			WHERE ID = ?`,
+			time.Now(), req.FirstName, req.LastName, req.RoleID, constant.DirectoryAuth, userID).Error
+	})
+}
+
+// SetUserDepartment moves the user from currentDepartmentID, if any, to the department, ending
+// their current membership.
+func (r *directoryRepository) SetUserDepartment(actor *request.AuditActor, userID uint, currentDepartmentID *uint,
+	departmentID uint) error {
+	var targets []auditTarget
+
+	if currentDepartmentID != nil {
+		targets = append(targets, auditTarget{constant.AuditDelete, constant.AuditDepartmentMember,
+			map[string]interface{}{"DepartmentID": *currentDepartmentID, "UserID": userID}})
+	}
+
+	targets = append(targets, auditTarget{constant.AuditCreate, constant.AuditDepartmentMember,
+		map[string]interface{}{"DepartmentID": departmentID, "UserID": userID}})
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		memberships, err := activeMemberships(tx, "UserID = ?", userID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			UPDATE DepartmentMember
+			SET IsActive = 0, DeletedAt = ?
+			WHERE UserID = ? AND IsActive = 1`, time.Now(), userID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO DepartmentMember (CreatedAt, UpdatedAt, DepartmentID, UserID)
+			VALUES(?, ?, ?, ?)`,
+			time.Now(), time.Now(), departmentID, userID).Error; err != nil {
+			return err
+		}
+
+		if err := publishMembershipChanges(tx, memberships, false); err != nil {
+			return err
+		}
+
+		return publishMembershipChanges(tx,
+			[]event.DepartmentMembershipChanged{{DepartmentID: departmentID, UserID: userID}}, true)
+	})
+}
@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/infrastructure/storage"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type documentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) domain.DocumentRepository {
+	return &documentRepository{db}
+}
+
+const fetchUserDocumentsQuery = `
+	SELECT ud.ID, ud.UserID userID, ud.DocumentCategoryID documentCategoryID, dc.[Name] AS category,
+	ud.FileName fileName, ud.FilePath filePath, ud.Checksum, ud.Version,
+	strftime('%Y-%m-%d', ud.ExpiryDate) AS expiryDate,
+	CASE WHEN ud.ExpiryDate IS NOT NULL AND date(ud.ExpiryDate) < date('now') THEN 1 ELSE 0 END AS isExpired,
+	(uploadedUser.FirstName || ' ' || uploadedUser.LastName) AS uploadedBy, ud.CreatedAt
+	FROM UserDocument ud
+	LEFT JOIN DocumentCategory dc ON dc.ID = ud.DocumentCategoryID
+	LEFT JOIN [User] uploadedUser ON uploadedUser.ID = ud.UploadedBy`
+
+func (r *documentRepository) CreateDocumentCategory(actor *request.AuditActor, req *request.CreateDocumentCategory) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditDocumentCategory,
+		map[string]interface{}{"Code": req.Code}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			INSERT INTO DocumentCategory
+			(CreatedAt, UpdatedAt, IsActive, [Name], Code, Description, IsRequired, HasExpiry, AllowedTypes)
+			VALUES(?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''))`,
+			time.Now(), time.Now(), constant.Active, req.Name, req.Code, req.Description,
+			req.IsRequired, req.HasExpiry, strings.Join(req.AllowedTypes, ",")).Error
+	})
+}
+
+func (r *documentRepository) FetchDocumentCategories() ([]response.FetchDocumentCategories, error) {
+	var data []response.FetchDocumentCategories
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Code, Description, IsRequired isRequired, HasExpiry hasExpiry,
+		AllowedTypes allowedTypes, CreatedAt
+		FROM DocumentCategory
+		WHERE IsActive = 1
+		ORDER BY [Name]`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) GetDocumentCategoryByID(categoryID uint) (*schema.DocumentCategory, error) {
+	var data *schema.DocumentCategory
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM DocumentCategory
+		WHERE ID = ? AND IsActive = 1`, categoryID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) IsDocumentCategoryCodeExists(code string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM DocumentCategory
+		WHERE Code = ? AND IsActive = 1`, code).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *documentRepository) IsDocumentCategoryCodeExistsExceptID(categoryID uint, code string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM DocumentCategory
+		WHERE ID <> ? AND Code = ? AND IsActive = 1`, categoryID, code).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *documentRepository) UpdateDocumentCategory(actor *request.AuditActor, categoryID uint,
+	req *request.UpdateDocumentCategory) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditDocumentCategory,
+		map[string]interface{}{"ID": categoryID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE DocumentCategory
+			SET UpdatedAt = ?, [Name] = ?, Code = ?, Description = NULLIF(?, ''), IsRequired = ?, HasExpiry = ?,
+			AllowedTypes = NULLIF(?, '')
+			WHERE ID = ?`, time.Now(), req.Name, req.Code, req.Description, req.IsRequired,
+			req.HasExpiry, strings.Join(req.AllowedTypes, ","), categoryID).Error
+	})
+}
+
+func (r *documentRepository) RemoveDocumentCategory(actor *request.AuditActor, categoryID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditDocumentCategory,
+		map[string]interface{}{"ID": categoryID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE DocumentCategory
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), categoryID).Error
+	})
+}
+
+func (r *documentRepository) GetLatestDocumentChecksum(userID, categoryID uint, fileName string) (*string, error) {
+	var checksum *string
+
+	if err := r.db.Raw(`
+		SELECT Checksum
+		FROM UserDocument
+		WHERE UserID = ? AND DocumentCategoryID = ? AND FileName = ? AND IsLatest = 1 AND IsActive = 1`,
+		userID, categoryID, fileName).Scan(&checksum).Error; err != nil {
+		return nil, err
+	}
+
+	return checksum, nil
+}
+
+// CreateDocumentVersions stores each file as the next version of the document with the same
+// user, category and file name. Earlier versions are kept for history.
+func (r *documentRepository) CreateDocumentVersions(actor *request.AuditActor, uploadedBy uint,
+	req *request.UploadDocument, documents []response.UploadedDocument) error {
+	targets := make([]auditTarget, 0, len(documents))
+	for _, document := range documents {
+		targets = append(targets, auditTarget{constant.AuditCreate, constant.AuditDocument,
+			map[string]interface{}{"UserID": req.UserID, "FilePath": document.FilePath}})
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		for _, document := range documents {
+			if err := createDocumentVersion(tx, uploadedBy, req, &document); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CreateGeneratedLetters stores every letter as the next version of the owner's document, all
+// in one transaction so a bulk run is never half recorded.
+func (r *documentRepository) CreateGeneratedLetters(actor *request.AuditActor, uploadedBy uint,
+	letters []response.RenderedLetter) error {
+	targets := make([]auditTarget, 0, len(letters))
+	for _, letter := range letters {
+		targets = append(targets, auditTarget{constant.AuditCreate, constant.AuditDocument,
+			map[string]interface{}{"UserID": letter.UserID, "FilePath": letter.Document.FilePath}})
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		for _, letter := range letters {
+			req := &request.UploadDocument{UserID: letter.UserID, DocumentCategoryID: letter.DocumentCategoryID}
+
+			if err := createDocumentVersion(tx, uploadedBy, req, &letter.Document); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func createDocumentVersion(tx *gorm.DB, uploadedBy uint, req *request.UploadDocument,
+	document *response.UploadedDocument) error {
+	var version uint
+
+	if err := tx.Raw(`
+		SELECT COALESCE(MAX(Version), 0)
+		FROM UserDocument
+		WHERE UserID = ? AND DocumentCategoryID = ? AND FileName = ? AND IsActive = 1`,
+		req.UserID, req.DocumentCategoryID, document.FileName).Scan(&version).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`
+		UPDATE UserDocument
+		SET UpdatedAt = ?, IsLatest = 0
+		WHERE UserID = ? AND DocumentCategoryID = ? AND FileName = ? AND IsLatest = 1`,
+		time.Now(), req.UserID, req.DocumentCategoryID, document.FileName).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		INSERT INTO UserDocument
+		(CreatedAt, UpdatedAt, IsActive, UserID, FilePath, DocumentCategoryID, FileName, Checksum,
+		Version, IsLatest, ExpiryDate, UploadedBy, StorageBackend, ContentType, FileSize,
+		LetterTemplateVersionID)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, 1, strftime('%Y-%m-%d', NULLIF(?, '')), ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, req.UserID, document.FilePath, req.DocumentCategoryID,
+		document.FileName, document.Checksum, version+1, req.ExpiryDate, uploadedBy,
+		config.Config.StorageBackend, document.ContentType, document.FileSize,
+		document.LetterTemplateVersionID).Error
+}
+
+func (r *documentRepository) FetchUserDocuments(req *request.FetchUserDocuments) ([]response.FetchUserDocument, error) {
+	var (
+		data        []response.FetchUserDocument
+		query       strings.Builder
+		queryParams = []interface{}{req.UserID}
+	)
+
+	query.WriteString(fetchUserDocumentsQuery + `
+		WHERE ud.UserID = ? AND ud.IsLatest = 1 AND ud.IsActive = 1`)
+
+	if req.DocumentCategoryID > 0 {
+		query.WriteString(` AND ud.DocumentCategoryID = ?`)
+		queryParams = append(queryParams, req.DocumentCategoryID)
+	}
+
+	query.WriteString(` ORDER BY dc.[Name], ud.CreatedAt DESC`)
+
+	if err := r.db.Raw(query.String(), queryParams...).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) IsDocumentExists(documentID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserDocument
+		WHERE ID = ? AND IsActive = 1`, documentID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *documentRepository) GetDocumentByID(documentID uint) (*response.DownloadDocument, error) {
+	var data *response.DownloadDocument
+
+	if err := r.db.Raw(`
+		SELECT ID, UserID userID, FilePath filePath, FileName fileName
+		FROM UserDocument
+		WHERE ID = ? AND IsActive = 1`, documentID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RemoveDocument soft deletes one version. When it was the latest, the newest remaining
+// version becomes the latest again.
+func (r *documentRepository) RemoveDocument(actor *request.AuditActor, documentID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditDocument, map[string]interface{}{"ID": documentID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE UserDocument
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), documentID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE UserDocument
+			SET UpdatedAt = ?, IsLatest = 1
+			WHERE ID = (
+				SELECT ud.ID
+				FROM UserDocument ud
+				INNER JOIN UserDocument removed ON removed.ID = ? AND removed.IsLatest = 1
+				WHERE ud.UserID = removed.UserID AND ud.DocumentCategoryID = removed.DocumentCategoryID
+				AND ud.FileName = removed.FileName AND ud.IsActive = 1
+				ORDER BY ud.Version DESC LIMIT 1
+			)`, time.Now(), documentID).Error
+	})
+}
+
+func (r *documentRepository) FetchDocumentVersions(documentID uint) ([]response.FetchUserDocument, error) {
+	var data []response.FetchUserDocument
+
+	if err := r.db.Raw(fetchUserDocumentsQuery+`
+		INNER JOIN UserDocument selected ON selected.ID = ?
+		WHERE ud.UserID = selected.UserID AND ud.DocumentCategoryID IS selected.DocumentCategoryID
+		AND ud.FileName IS selected.FileName AND ud.IsActive = 1
+		ORDER BY ud.Version DESC`, documentID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// FetchMissingDocuments lists users lacking a current, unexpired document in any required category.
+func (r *documentRepository) FetchMissingDocuments(filters *request.FetchMissingDocuments) ([]response.FetchMissingDocuments, uint, error) {
+	var (
+		data         []response.FetchMissingDocuments
+		search            = "%" + strings.TrimSpace(filters.Search) + "%"
+		itemsPerPage uint = 10
+		totalCount   uint = 0
+		query        strings.Builder
+		queryParams  []interface{}
+	)
+
+	query.WriteString(`
+		SELECT usr.ID userID, (usr.FirstName || ' ' || usr.LastName) AS userName, usr.Code,
+		CASE WHEN dm.UserID IS NOT NULL THEN dept.[Name] ELSE 'None' END AS Department,
+		group_concat(dc.[Name], ', ') AS missingCategories,
+		COUNT(*) OVER (PARTITION BY 1) AS [count]
+		FROM [User] usr
+		INNER JOIN DocumentCategory dc ON dc.IsRequired = 1 AND dc.IsActive = 1
+		LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+		LEFT JOIN Department dept ON dept.ID = dm.DepartmentID AND dept.IsActive = 1
+		WHERE usr.IsActive = 1 AND usr.RoleID <> ? AND NOT EXISTS (
+			SELECT 1
+			FROM UserDocument ud
+			WHERE ud.UserID = usr.ID AND ud.DocumentCategoryID = dc.ID AND ud.IsLatest = 1
+			AND ud.IsActive = 1 AND (ud.ExpiryDate IS NULL OR date(ud.ExpiryDate) >= date('now')))`)
+	queryParams = append(queryParams, constant.Admin)
+
+	if filters.DepartmentID > 0 {
+		query.WriteString(` AND dm.DepartmentID = ?`)
+		queryParams = append(queryParams, filters.DepartmentID)
+	}
+
+	if len(filters.Search) > 0 {
+		query.WriteString(` AND (usr.Email LIKE ? OR usr.FirstName LIKE ? OR usr.LastName LIKE ?)`)
+		queryParams = append(queryParams, search, search, search)
+	}
+
+	query.WriteString(` GROUP BY usr.ID ORDER BY usr.FirstName, usr.LastName`)
+
+	if filters.Page > 0 {
+		query.WriteString(` LIMIT ? OFFSET ?`)
+		queryParams = append(queryParams, itemsPerPage, (filters.Page-1)*itemsPerPage)
+	}
+
+	if err := r.db.Raw(query.String(), queryParams...).Scan(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(data) > 0 {
+		totalCount = data[0].Count
+	}
+
+	return data, totalCount, nil
+}
+
+func (r *documentRepository) FetchExpiringDocuments(days uint) ([]response.ExpiringDocument, error) {
+	var data []response.ExpiringDocument
+
+	if err := r.db.Raw(`
+		SELECT ud.ID, usr.Email, (usr.FirstName || ' ' || usr.LastName) AS userName,
+		dc.[Name] AS category, ud.ExpiryDate expiryDate
+		FROM UserDocument ud
+		INNER JOIN [User] usr ON usr.ID = ud.UserID AND usr.IsActive = 1
+		INNER JOIN DocumentCategory dc ON dc.ID = ud.DocumentCategoryID
+		WHERE ud.IsActive = 1 AND ud.IsLatest = 1 AND ud.ReminderSentAt IS NULL
+		AND ud.ExpiryDate IS NOT NULL
+		AND date(ud.ExpiryDate) BETWEEN date('now') AND date('now', '+' || ? || ' days')`,
+		days).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) MarkDocumentReminderSent(documentID uint) error {
+	return r.db.Exec(`
+		UPDATE UserDocument
+		SET ReminderSentAt = ?
+		WHERE ID = ?`, time.Now(), documentID).Error
+}
+
+// FetchDocumentsByStorageBackend includes soft deleted rows so a migration moves every file.
+func (r *documentRepository) FetchDocumentsByStorageBackend(backend string) ([]response.StoredDocument, error) {
+	var data []response.StoredDocument
+
+	if err := r.db.Raw(`
+		SELECT ID, FilePath filePath
+		FROM UserDocument
+		WHERE COALESCE(StorageBackend, ?) = ?
+		ORDER BY ID`, storage.LocalBackend, backend).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) UpdateDocumentStorage(documentID uint, filePath, backend string) error {
+	return r.db.Exec(`
+		UPDATE UserDocument
+		SET UpdatedAt = ?, FilePath = ?, StorageBackend = ?
+		WHERE ID = ?`, time.Now(), filePath, backend, documentID).Error
+}
+
+func (r *documentRepository) FetchUserStorageUsage(userID uint) (int64, error) {
+	var usage int64
+
+	if err := r.db.Raw(`
+		SELECT COALESCE(SUM(FileSize), 0)
+		FROM UserDocument
+		WHERE UserID = ? AND IsActive = 1`, userID).Scan(&usage).Error; err != nil {
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+func (r *documentRepository) CreateQuarantinedDocument(actor *request.AuditActor, uploadedBy, userID uint,
+	document *response.UploadedDocument, signature string) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditQuarantinedDocument,
+		map[string]interface{}{"UserID": userID, "FilePath": document.FilePath}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			INSERT INTO QuarantinedDocument
+			(CreatedAt, UpdatedAt, IsActive, UserID, FileName, FilePath, Checksum, ContentType, FileSize, Signature,
+			UploadedBy, StorageBackend)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, userID, document.FileName, document.FilePath, document.Checksum,
+			document.ContentType, document.FileSize, signature, uploadedBy, config.Config.StorageBackend).Error
+	})
+}
+
+func (r *documentRepository) FetchQuarantinedDocuments() ([]response.FetchQuarantinedDocument, error) {
+	var data []response.FetchQuarantinedDocument
+
+	if err := r.db.Raw(`
+		SELECT qd.ID, qd.UserID userID, (usr.FirstName || ' ' || usr.LastName) AS userName,
+		qd.FileName fileName, qd.Checksum, qd.ContentType contentType, qd.FileSize fileSize, qd.Signature,
+		(uploadedUser.FirstName || ' ' || uploadedUser.LastName) AS uploadedBy, qd.CreatedAt
+		FROM QuarantinedDocument qd
+		INNER JOIN [User] usr ON usr.ID = qd.UserID
+		INNER JOIN [User] uploadedUser ON uploadedUser.ID = qd.UploadedBy
+		WHERE qd.IsActive = 1
+		ORDER BY qd.CreatedAt DESC`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) GetQuarantinedDocumentByID(quarantineID uint) (*response.QuarantinedDocument, error) {
+	var data *response.QuarantinedDocument
+
+	if err := r.db.Raw(`
+		SELECT ID, FilePath filePath
+		FROM QuarantinedDocument
+		WHERE ID = ? AND IsActive = 1`, quarantineID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *documentRepository) RemoveQuarantinedDocument(actor *request.AuditActor, quarantineID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditQuarantinedDocument,
+		map[string]interface{}{"ID": quarantineID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE QuarantinedDocument
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), quarantineID).Error
+	})
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/event"
+	"ems/app/model/response"
+	"ems/domain"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type eventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) domain.EventRepository {
+	return &eventRepository{db}
+}
+
+// ClaimPendingEvents returns the events that are due, oldest first, and holds them for the
+// lease so that an overlapping dispatch does not pick them up as well.
+func (r *eventRepository) ClaimPendingEvents(limit int, lease time.Duration) ([]response.OutboxEvent, error) {
+	var data []response.OutboxEvent
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Raw(`
+			SELECT ID, CreatedAt, EventType, Payload, Attempts
+			FROM OutboxEvent
+			WHERE IsActive = 1 AND DeliveredAt IS NULL AND FailedAt IS NULL AND NextAttemptAt <= ?
+			ORDER BY ID
+			LIMIT ?`, now, limit).Scan(&data).Error; err != nil {
+			return err
+		}
+
+		if len(data) == 0 {
+			return nil
+		}
+
+		eventIDs := make([]uint, 0, len(data))
+		for _, outboxEvent := range data {
+			eventIDs = append(eventIDs, outboxEvent.ID)
+		}
+
+		return tx.Exec(`
+			UPDATE OutboxEvent
+			SET UpdatedAt = ?, NextAttemptAt = ?
+			WHERE ID IN ?`, now, now.Add(lease), eventIDs).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// FetchEventSubscribers returns the subscribers that have already handled the event.
+func (r *eventRepository) FetchEventSubscribers(eventID uint) ([]string, error) {
+	var subscribers []string
+
+	if err := r.db.Raw(`
+		SELECT Subscriber
+		FROM OutboxDelivery
+		WHERE OutboxEventID = ? AND IsActive = 1`, eventID).Scan(&subscribers).Error; err != nil {
+		return nil, err
+	}
+
+	return subscribers, nil
+}
+
+func (r *eventRepository) CreateEventDelivery(eventID uint, subscriber string) error {
+	return r.db.Exec(`
+		INSERT INTO OutboxDelivery (CreatedAt, UpdatedAt, IsActive, OutboxEventID, Subscriber)
+		VALUES(?, ?, ?, ?, ?)`, time.Now(), time.Now(), constant.Active, eventID, subscriber).Error
+}
+
+func (r *eventRepository) CompleteEvent(eventID uint) error {
+	return r.db.Exec(`
+		UPDATE OutboxEvent
+		SET UpdatedAt = ?, DeliveredAt = ?, LastError = NULL
+		WHERE ID = ?`, time.Now(), time.Now(), eventID).Error
+}
+
+func (r *eventRepository) RetryEvent(eventID uint, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.db.Exec(`
+		UPDATE OutboxEvent
+		SET UpdatedAt = ?, Attempts = ?, NextAttemptAt = ?, LastError = ?
+		WHERE ID = ?`, time.Now(), attempts, nextAttemptAt, lastError, eventID).Error
+}
+
+func (r *eventRepository) FailEvent(eventID uint, attempts int, lastError string) error {
+	return r.db.Exec(`
+		UPDATE OutboxEvent
+		SET UpdatedAt = ?, Attempts = ?, FailedAt = ?, LastError = ?
+		WHERE ID = ?`, time.Now(), attempts, time.Now(), lastError, eventID).Error
+}
+
+// publishEvent adds an event to the outbox. It is called with the transaction that makes the
+// change, so the event is kept exactly when the change is.
+func publishEvent(tx *gorm.DB, eventType constant.EventType, payload interface{}) error {
+	data, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		INSERT INTO OutboxEvent (CreatedAt, UpdatedAt, IsActive, EventType, Payload, Attempts, NextAttemptAt)
+		VALUES(?, ?, ?, ?, ?, 0, ?)`,
+		time.Now(), time.Now(), constant.Active, eventType, string(data), time.Now()).Error
+}
+
+// publishUserCreated publishes UserCreated for a user inserted in the transaction.
+func publishUserCreated(tx *gorm.DB, userID uint) error {
+	var payload event.UserCreated
+
+	if err := tx.Raw(`
+		SELECT ID UserID, FirstName, LastName, Email, Code, RoleID
+		FROM [User]
+		WHERE ID = ?`, userID).Scan(&payload).Error; err != nil {
+		return err
+	}
+
+	return publishEvent(tx, constant.UserCreated, payload)
+}
+
+// activeMemberships returns the current memberships matching the condition, to publish them as
+// left once they are ended.
+func activeMemberships(tx *gorm.DB, condition string, params ...interface{}) ([]event.DepartmentMembershipChanged, error) {
+	var memberships []event.DepartmentMembershipChanged
+
+	if err := tx.Raw(`
+		SELECT DepartmentID, UserID
+		FROM DepartmentMember
+		WHERE IsActive = 1 AND `+condition, params...).Scan(&memberships).Error; err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+// publishMembershipChanges publishes DepartmentMembershipChanged for each user, as joined or
+// left.
+func publishMembershipChanges(tx *gorm.DB, memberships []event.DepartmentMembershipChanged, joined bool) error {
+	for _, membership := range memberships {
+		membership.Joined = joined
+
+		if err := publishEvent(tx, constant.DepartmentMembershipChanged, membership); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// departmentMemberUserID returns the user of a department membership.
+func departmentMemberUserID(tx *gorm.DB, departmentMemberID uint) (uint, error) {
+	var userID uint
+
+	if err := tx.Raw(`
+		SELECT UserID
+		FROM DepartmentMember
+		WHERE ID = ?`, departmentMemberID).Scan(&userID).Error; err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"ems/app/model/constant"
+	"ems/app/model/event"
 	"ems/app/model/request"
 	"ems/app/model/response"
 	"ems/domain"
@@ -20,8 +21,11 @@ func NewLeaveRepository(db *gorm.DB) domain.LeaveRepository {
 	return &leaveRepository{db}
 }
 
-func (r *leaveRepository) RequestLeave(departmentMemberID uint, req *request.RequestLeave) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *leaveRepository) RequestLeave(actor *request.AuditActor, departmentMemberID uint, req *request.RequestLeave) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditLeave,
+		map[string]interface{}{"DepartmentMemberID": departmentMemberID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			INSERT INTO DepartmentMemberLeaveRequest
 			(CreatedAt, UpdatedAt, DepartmentMemberID, Reason)
@@ -38,6 +42,12 @@ func (r *leaveRepository) RequestLeave(departmentMemberID uint, req *request.Req
 			return err
 		}
 
+		payload := event.LeaveRequested{
+			LeaveID:            departmentMemberLeaveRequestID,
+			DepartmentMemberID: departmentMemberID,
+			Reason:             req.Reason,
+		}
+
 		for _, date := range req.Dates {
 			if err := tx.Exec(`
 			INSERT INTO DepartmentMemberLeaveRequestDate
@@ -46,9 +56,19 @@ func (r *leaveRepository) RequestLeave(departmentMemberID uint, req *request.Req
 				date.Date, date.IsFullDay, date.SessionType).Error; err != nil {
 				return err
 			}
+
+			payload.Dates = append(payload.Dates, event.LeaveDate(date))
 		}
 
-		return nil
+		userID, err := departmentMemberUserID(tx, departmentMemberID)
+
+		if err != nil {
+			return err
+		}
+
+		payload.UserID = userID
+
+		return publishEvent(tx, constant.LeaveRequested, payload)
 	})
 }
 
@@ -153,11 +173,30 @@ func (r *leaveRepository) FetchDepartmentMemberLeaves(departmentID uint, filters
 	return &response, nil
 }
 
-func (r *leaveRepository) UpdateLeaveStatus(leaveID, approvedBy uint, req *request.UpdateLeaveStatus) error {
-	return r.db.Exec(`
-		UPDATE DepartmentMemberLeaveRequest 
-		SET UpdatedAt = ?, IsApproved = ?, ApprovedAt = ?, ApprovedBy = ?
-		WHERE ID = ?`, time.Now(), req.IsApproved, time.Now(), approvedBy, leaveID).Error
+func (r *leaveRepository) UpdateLeaveStatus(actor *request.AuditActor, leaveID, approvedBy uint,
+	req *request.UpdateLeaveStatus) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditLeave, map[string]interface{}{"ID": leaveID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE DepartmentMemberLeaveRequest 
+			SET UpdatedAt = ?, IsApproved = ?, ApprovedAt = ?, ApprovedBy = ?
+			WHERE ID = ?`, time.Now(), req.IsApproved, time.Now(), approvedBy, leaveID).Error; err != nil {
+			return err
+		}
+
+		var payload event.LeaveStatusChanged
+
+		if err := tx.Raw(`
+			SELECT lr.ID LeaveID, lr.DepartmentMemberID, dm.UserID, lr.IsApproved, lr.ApprovedBy
+			FROM DepartmentMemberLeaveRequest lr
+			INNER JOIN DepartmentMember dm ON dm.ID = lr.DepartmentMemberID
+			WHERE lr.ID = ?`, leaveID).Scan(&payload).Error; err != nil {
+			return err
+		}
+
+		return publishEvent(tx, constant.LeaveStatusChanged, payload)
+	})
 }
 
 func (r *leaveRepository) IsLeaveExistsWithoutApproval(departmentMemberID uint) (bool, error) {
@@ -227,8 +266,10 @@ func (r *leaveRepository) FetchLeadAndHRLeaves(filters *request.CommonRequestWit
 	return &response, nil
 }
 
-func (r *leaveRepository) UpdateLeaveRequest(leaveID uint, req *request.RequestLeave) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *leaveRepository) UpdateLeaveRequest(actor *request.AuditActor, leaveID uint, req *request.RequestLeave) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditLeave, map[string]interface{}{"ID": leaveID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			UPDATE DepartmentMemberLeaveRequest
 			SET UpdatedAt = ?, Reason = ?
@@ -271,8 +312,10 @@ func (r *leaveRepository) IsLeaveExistsWithID(leaveID uint) (bool, error) {
 	return count > 0, nil
 }
 
-func (r *leaveRepository) RemoveLeaveRequest(leaveID uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *leaveRepository) RemoveLeaveRequest(actor *request.AuditActor, leaveID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditLeave, map[string]interface{}{"ID": leaveID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			UPDATE DepartmentMemberLeaveRequest
 			SET IsActive = ?, DeletedAt = ?
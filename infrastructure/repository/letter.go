@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type letterRepository struct {
+	db *gorm.DB
+}
+
+func NewLetterRepository(db *gorm.DB) domain.LetterRepository {
+	return &letterRepository{db}
+}
+
+func (r *letterRepository) CreateLetterTemplate(createdBy uint, req *request.CreateLetterTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO LetterTemplate
+			(CreatedAt, UpdatedAt, IsActive, [Name], DocumentCategoryID, Version)
+			VALUES(?, ?, ?, ?, ?, 1)`,
+			time.Now(), time.Now(), constant.Active, req.Name, req.DocumentCategoryID).Error; err != nil {
+			return err
+		}
+
+		var letterTemplateID uint
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM LetterTemplate
+			ORDER BY ID DESC LIMIT 1`).Scan(&letterTemplateID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO LetterTemplateVersion
+			(CreatedAt, UpdatedAt, IsActive, LetterTemplateID, Version, Title, Body, CreatedBy)
+			VALUES(?, ?, ?, ?, 1, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, letterTemplateID, req.Title, req.Body, createdBy).Error
+	})
+}
+
+func (r *letterRepository) FetchLetterTemplates() ([]response.FetchLetterTemplates, error) {
+	var data []response.FetchLetterTemplates
+
+	if err := r.db.Raw(`
+		SELECT lt.ID, lt.[Name], lt.DocumentCategoryID documentCategoryID, dc.[Name] AS category, lt.Version,
+		ltv.Title, ltv.Body, (usr.FirstName || ' ' || usr.LastName) AS updatedBy, ltv.CreatedAt AS updatedAt
+		FROM LetterTemplate lt
+		INNER JOIN LetterTemplateVersion ltv ON ltv.LetterTemplateID = lt.ID AND ltv.Version = lt.Version
+		INNER JOIN DocumentCategory dc ON dc.ID = lt.DocumentCategoryID
+		INNER JOIN [User] usr ON usr.ID = ltv.CreatedBy
+		WHERE lt.IsActive = 1
+		ORDER BY lt.[Name]`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *letterRepository) GetLetterTemplateByID(letterTemplateID uint) (*response.LetterTemplate, error) {
+	var data *response.LetterTemplate
+
+	if err := r.db.Raw(`
+		SELECT lt.ID, lt.[Name], lt.DocumentCategoryID documentCategoryID, ltv.ID AS versionID,
+		ltv.Title, ltv.Body
+		FROM LetterTemplate lt
+		INNER JOIN LetterTemplateVersion ltv ON ltv.LetterTemplateID = lt.ID AND ltv.Version = lt.Version
+		WHERE lt.ID = ? AND lt.IsActive = 1`, letterTemplateID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *letterRepository) IsLetterTemplateNameExists(name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM LetterTemplate
+		WHERE [Name] = ? AND IsActive = 1`, name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *letterRepository) IsLetterTemplateNameExistsExceptID(letterTemplateID uint, name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM LetterTemplate
+		WHERE ID <> ? AND [Name] = ? AND IsActive = 1`, letterTemplateID, name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UpdateLetterTemplate saves the edited text as a new version and points the template at it.
+// Earlier versions stay untouched for letters that were generated from them.
+func (r *letterRepository) UpdateLetterTemplate(letterTemplateID, updatedBy uint, req *request.UpdateLetterTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE LetterTemplate
+			SET UpdatedAt = ?, [Name] = ?, DocumentCategoryID = ?, Version = Version + 1
+			WHERE ID = ?`, time.Now(), req.Name, req.DocumentCategoryID, letterTemplateID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO LetterTemplateVersion
+			(CreatedAt, UpdatedAt, IsActive, LetterTemplateID, Version, Title, Body, CreatedBy)
+			SELECT ?, ?, ?, ID, Version, ?, ?, ?
+			FROM LetterTemplate
+			WHERE ID = ?`, time.Now(), time.Now(), constant.Active, req.Title, req.Body, updatedBy,
+			letterTemplateID).Error
+	})
+}
+
+func (r *letterRepository) RemoveLetterTemplate(letterTemplateID uint) error {
+	return r.db.Exec(`
+		UPDATE LetterTemplate
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), letterTemplateID).Error
+}
+
+func (r *letterRepository) FetchLetterTemplateVersions(letterTemplateID uint) ([]response.FetchLetterTemplateVersions, error) {
+	var data []response.FetchLetterTemplateVersions
+
+	if err := r.db.Raw(`
+		SELECT ltv.ID, ltv.Version, ltv.Title, ltv.Body,
+		(usr.FirstName || ' ' || usr.LastName) AS createdBy, ltv.CreatedAt
+		FROM LetterTemplateVersion ltv
+		INNER JOIN [User] usr ON usr.ID = ltv.CreatedBy
+		WHERE ltv.LetterTemplateID = ? AND ltv.IsActive = 1
+		ORDER BY ltv.Version DESC`, letterTemplateID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *letterRepository) GetLetterData(userID uint) (*response.LetterData, error) {
+	var data *response.LetterData
+
+	if err := r.db.Raw(`
+		SELECT usr.ID userID, usr.FirstName firstName, usr.LastName lastName, usr.Code, usr.Email, usr.Mobile,
+		ud.Designation, strftime('%Y-%m-%d', ud.DateOfJoining) AS dateOfJoining, ud.Experience,
+		ud.[Address], ud.City, dept.[Name] AS Department,
+		(mgr.FirstName || ' ' || mgr.LastName) AS Manager,
+		(
+			SELECT strftime('%Y-%m-%d', un.NoticeEndDate)
+			FROM UserNotice un
+			WHERE un.DepartmentMemberID = dm.ID AND un.IsApproved = 1 AND un.IsActive = 1
+			ORDER BY un.CreatedAt DESC LIMIT 1
+		) AS lastWorkingDate
+		FROM [User] usr
+		LEFT JOIN UserDetails ud ON ud.UserID = usr.ID AND ud.IsActive = 1
+		LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+		LEFT JOIN Department dept ON dept.ID = dm.DepartmentID AND dept.IsActive = 1
+		LEFT JOIN [User] mgr ON mgr.ID = usr.ManagerID
+		WHERE usr.ID = ? AND usr.IsActive = 1`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *letterRepository) FetchDepartmentUserIDs(departmentID uint) ([]uint, error) {
+	var data []uint
+
+	if err := r.db.Raw(`
+		SELECT dm.UserID
+		FROM DepartmentMember dm
+		INNER JOIN [User] usr ON usr.ID = dm.UserID AND usr.IsActive = 1
+		WHERE dm.DepartmentID = ? AND dm.IsActive = 1
+		ORDER BY usr.Code`, departmentID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
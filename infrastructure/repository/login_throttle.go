@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) domain.LoginThrottleRepository {
+	return &loginThrottleRepository{db}
+}
+
+func (r *loginThrottleRepository) GetLoginThrottle(subject string) (*schema.LoginThrottle, error) {
+	var data *schema.LoginThrottle
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM LoginThrottle
+		WHERE Subject = ?`, subject).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RecordFailedAttempt counts a failure and returns the updated counters. Failures before
+// windowStart no longer count, and the lockout count starts over when the last failure was
+// before lockoutResetBefore.
+func (r *loginThrottleRepository) RecordFailedAttempt(subject string, windowStart,
+	lockoutResetBefore time.Time) (*schema.LoginThrottle, error) {
+	var data *schema.LoginThrottle
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE LoginThrottle
+			SET UpdatedAt = ?,
+			LockoutCount = CASE WHEN LastFailedAt < ? THEN 0 ELSE LockoutCount END,
+			FailedAttempts = CASE WHEN LastFailedAt < ? THEN 1 ELSE FailedAttempts + 1 END,
+			LastFailedAt = ?
+			WHERE Subject = ?`, time.Now(), lockoutResetBefore, windowStart, time.Now(), subject)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			if err := tx.Exec(`
+				INSERT INTO LoginThrottle
+				(CreatedAt, UpdatedAt, IsActive, Subject, FailedAttempts, LockoutCount, LastFailedAt)
+				VALUES(?, ?, ?, ?, 1, 0, ?)`,
+				time.Now(), time.Now(), constant.Active, subject, time.Now()).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Raw(`
+			SELECT *
+			FROM LoginThrottle
+			WHERE Subject = ?`, subject).Scan(&data).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *loginThrottleRepository) LockSubject(throttleID uint, lockedUntil time.Time) error {
+	return r.db.Exec(`
+		UPDATE LoginThrottle
+		SET UpdatedAt = ?, LockedUntil = ?, LockoutCount = LockoutCount + 1, FailedAttempts = 0
+		WHERE ID = ?`, time.Now(), lockedUntil, throttleID).Error
+}
+
+func (r *loginThrottleRepository) ResetLoginThrottle(subject string) error {
+	return r.db.Exec(`
+		UPDATE LoginThrottle
+		SET UpdatedAt = ?, LockedUntil = NULL, LockoutCount = 0, FailedAttempts = 0
+		WHERE Subject = ?`, time.Now(), subject).Error
+}
+
+func (r *loginThrottleRepository) CreateAuthLog(data *schema.AuthLog) error {
+	return r.db.Exec(`
+		INSERT INTO AuthLog
+		(CreatedAt, UpdatedAt, IsActive, UserID, Email, IPAddress, Event, Detail)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, data.UserID, data.Email, data.IPAddress, data.Event,
+		data.Detail).Error
+}
+
+func (r *loginThrottleRepository) FetchUserAuthLogs(userID uint, limit int) ([]response.FetchAuthLogs, error) {
+	var data []response.FetchAuthLogs
+
+	if err := r.db.Raw(`
+		SELECT ID, Event, IPAddress ipAddress, Detail, CreatedAt createdAt
+		FROM AuthLog
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY ID DESC LIMIT ?`, userID, limit).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"ems/app/model/constant"
+	"ems/app/model/event"
 	"ems/app/model/request"
 	"ems/app/model/response"
 	"ems/domain"
@@ -20,12 +21,38 @@ func NewNoticeRepository(db *gorm.DB) domain.NoticeRepository {
 	return &noticeRepository{db}
 }
 
-func (r *noticeRepository) ApplyNotice(departmentMemberID uint, req *request.ApplyNotice) error {
-	return r.db.Exec(`
+func (r *noticeRepository) ApplyNotice(actor *request.AuditActor, departmentMemberID uint, req *request.ApplyNotice) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditNotice,
+		map[string]interface{}{"DepartmentMemberID": departmentMemberID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
 			INSERT INTO UserNotice
 			(CreatedAt, UpdatedAt, DepartmentMemberID, Remarks)
 			VALUES(?, ?, ?, ?)`,
-		time.Now(), time.Now(), departmentMemberID, req.Remarks).Error
+			time.Now(), time.Now(), departmentMemberID, req.Remarks).Error; err != nil {
+			return err
+		}
+
+		payload := event.NoticeApplied{DepartmentMemberID: departmentMemberID, Remarks: req.Remarks}
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM UserNotice
+			ORDER BY ID DESC LIMIT 1`).Scan(&payload.NoticeID).Error; err != nil {
+			return err
+		}
+
+		userID, err := departmentMemberUserID(tx, departmentMemberID)
+
+		if err != nil {
+			return err
+		}
+
+		payload.UserID = userID
+
+		return publishEvent(tx, constant.NoticeApplied, payload)
+	})
 }
 
 func (r *noticeRepository) FetchActiveUserNotices(roleID uint, filters *request.CommonRequest) (*utils.PaginationResponse, error) {
@@ -120,7 +147,8 @@ func (r *noticeRepository) FetchNotice(departmentMemberID uint) (*response.Fetch
 	return data, nil
 }
 
-func (r *noticeRepository) ApproveNotice(departmentMemberID, approvedBy uint, req *request.ApproveNotice) error {
+func (r *noticeRepository) ApproveNotice(actor *request.AuditActor, departmentMemberID, approvedBy uint,
+	req *request.ApproveNotice) error {
 	var appliedDate time.Time
 
 	if err := r.db.Raw(`
@@ -131,12 +159,42 @@ func (r *noticeRepository) ApproveNotice(departmentMemberID, approvedBy uint, re
 	}
 
 	noticeEndDate := appliedDate.AddDate(0, 0, req.ServeDays)
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditNotice,
+		map[string]interface{}{"DepartmentMemberID": departmentMemberID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE UserNotice
+			SET UpdatedAt = ?, NoticeEndDate = ?, IsApproved = 1, ApprovedBy = ?
+			WHERE DepartmentMemberID = ?`,
+			time.Now(), noticeEndDate, approvedBy, departmentMemberID).Error; err != nil {
+			return err
+		}
+
+		payload := event.NoticeApproved{
+			DepartmentMemberID: departmentMemberID,
+			NoticeEndDate:      noticeEndDate,
+			ApprovedBy:         approvedBy,
+		}
 
-	return r.db.Exec(`
-		UPDATE UserNotice
-		SET UpdatedAt = ?, NoticeEndDate = ?, IsApproved = 1, ApprovedBy = ?
-		WHERE DepartmentMemberID = ?`,
-		time.Now(), noticeEndDate, approvedBy, departmentMemberID).Error
+		if err := tx.Raw(`
+			SELECT ID
+			FROM UserNotice
+			WHERE DepartmentMemberID = ?
+			ORDER BY ID DESC LIMIT 1`, departmentMemberID).Scan(&payload.NoticeID).Error; err != nil {
+			return err
+		}
+
+		userID, err := departmentMemberUserID(tx, departmentMemberID)
+
+		if err != nil {
+			return err
+		}
+
+		payload.UserID = userID
+
+		return publishEvent(tx, constant.NoticeApproved, payload)
+	})
 }
 
 func (r *noticeRepository) IsApproveExistsByUser(departmentMemberID uint) (bool, error) {
@@ -151,3 +209,72 @@ func (r *noticeRepository) IsApproveExistsByUser(departmentMemberID uint) (bool,
 
 	return count > 0, nil
 }
+
+// RemoveServedNoticeUsers removes the users whose notice period ended before now, together with
+// their department membership and notice.
+func (r *noticeRepository) RemoveServedNoticeUsers(actor *request.AuditActor, now time.Time) error {
+	// Fetch users whose notice period has ended
+	var servedUsers []struct {
+		DepartmentMemberID uint
+		UserID             uint
+	}
+
+	if err := r.db.Raw(`
+		SELECT un.DepartmentMemberID, dm.UserID
+		FROM UserNotice un
+		JOIN DepartmentMember dm ON dm.ID = un.DepartmentMemberID
+		WHERE un.IsActive = 1 AND un.NoticeEndDate < ?`, now).Scan(&servedUsers).Error; err != nil {
+		return err
+	}
+
+	if len(servedUsers) == 0 {
+		return nil
+	}
+
+	var departmentMemberIDs, userIDs []uint
+	var targets []auditTarget
+
+	for _, servedUser := range servedUsers {
+		departmentMemberIDs = append(departmentMemberIDs, servedUser.DepartmentMemberID)
+		userIDs = append(userIDs, servedUser.UserID)
+
+		targets = append(targets,
+			auditTarget{constant.AuditDelete, constant.AuditDepartmentMember,
+				map[string]interface{}{"ID": servedUser.DepartmentMemberID}},
+			auditTarget{constant.AuditDelete, constant.AuditUser, map[string]interface{}{"ID": servedUser.UserID}},
+			auditTarget{constant.AuditDelete, constant.AuditNotice,
+				map[string]interface{}{"DepartmentMemberID": servedUser.DepartmentMemberID}})
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		memberships, err := activeMemberships(tx, "ID IN ?", departmentMemberIDs)
+
+		if err != nil {
+			return err
+		}
+
+		// Mark the corresponding department members as inactive and set DeletedAt
+		if err := tx.Exec(`
+			UPDATE DepartmentMember 
+			SET IsActive = 0, DeletedAt = ? 
+			WHERE ID IN ?`, now, departmentMemberIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			UPDATE [User] 
+			SET IsActive = 0, DeletedAt = ? 
+			WHERE ID IN ?`, now, userIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			UPDATE UserNotice
+			SET IsActive = 0, DeletedAt = ?
+			WHERE DepartmentMemberID IN ?`, time.Now(), departmentMemberIDs).Error; err != nil {
+			return err
+		}
+
+		return publishMembershipChanges(tx, memberships, false)
+	})
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/schema"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) domain.OIDCRepository {
+	return &oidcRepository{db}
+}
+
+func (r *oidcRepository) CreateOIDCLoginState(data *schema.OIDCLoginState) error {
+	return r.db.Exec(`
+		INSERT INTO OIDCLoginState
+		(CreatedAt, UpdatedAt, IsActive, State, Nonce, CodeVerifier, ExpiresAt)
+		VALUES(?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, data.State, data.Nonce, data.CodeVerifier,
+		data.ExpiresAt).Error
+}
+
+// UseOIDCLoginState marks the state as used and returns it, or nil when it is unknown, expired
+// or was already used, so that a callback can only be completed once.
+func (r *oidcRepository) UseOIDCLoginState(state string) (*schema.OIDCLoginState, error) {
+	var data *schema.OIDCLoginState
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE OIDCLoginState
+			SET UpdatedAt = ?, UsedAt = ?
+			WHERE IsActive = 1 AND State = ? AND UsedAt IS NULL AND ExpiresAt > ?`,
+			time.Now(), time.Now(), state, time.Now())
+
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return tx.Raw(`
+			SELECT *
+			FROM OIDCLoginState
+			WHERE State = ?`, state).Scan(&data).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *oidcRepository) GetUserIDByIdentity(issuer, subject string) (uint, error) {
+	var userID uint
+
+	if err := r.db.Raw(`
+		SELECT ui.UserID
+		FROM UserIdentity ui
+		INNER JOIN [User] usr ON usr.ID = ui.UserID AND usr.IsActive = 1
+		WHERE ui.IsActive = 1 AND ui.Issuer = ? AND ui.Subject = ?`,
+		issuer, subject).Scan(&userID).Error; err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *oidcRepository) GetUserIdentity(userID uint, issuer string) (*schema.UserIdentity, error) {
+	var data *schema.UserIdentity
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM UserIdentity
+		WHERE IsActive = 1 AND UserID = ? AND Issuer = ?`, userID, issuer).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *oidcRepository) CreateUserIdentity(data *schema.UserIdentity) error {
+	return createUserIdentity(r.db, data)
+}
+
+// CreateProvisionedUser creates a user signing in through single sign-on for the first time,
+// together with the link to their identity. The password is random and never handed out, so
+// they are not asked to change it.
+func (r *oidcRepository) CreateProvisionedUser(actor *request.AuditActor, req *request.CreateUser,
+	hashedPassword string, identity *schema.UserIdentity) (uint, error) {
+	var userID uint
+
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditUser, map[string]interface{}{"Code": req.Code}}}
+
+	err := auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO [User] (
+				CreatedAt, UpdatedAt, IsActive, ManagerID, FirstName, LastName, Email, Mobile,
+				Code, RoleID, [Password], PasswordChangedAt, MustChangePassword
+			)
+			VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0
+			)`,
+			time.Now(), time.Now(), constant.Active, 3, // 3 => Manager
+			req.FirstName, req.LastName, req.Email, req.Mobile, req.Code,
+			req.RoleID, hashedPassword, time.Now()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM [User]
+			ORDER BY ID DESC LIMIT 1`).Scan(&userID).Error; err != nil {
+			return err
+		}
+
+		identity.UserID = userID
+
+		if err := createUserIdentity(tx, identity); err != nil {
+			return err
+		}
+
+		return publishUserCreated(tx, userID)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func createUserIdentity(tx *gorm.DB, data *schema.UserIdentity) error {
+	return tx.Exec(`
+		INSERT INTO UserIdentity
+		(CreatedAt, UpdatedAt, IsActive, UserID, Issuer, Subject, Email)
+		VALUES(?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, data.UserID, data.Issuer, data.Subject, data.Email).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"ems/app/model/constant"
+	"ems/app/model/event"
 	"ems/app/model/request"
 	"ems/app/model/response"
 	"ems/domain"
@@ -20,12 +21,45 @@ func NewPermissionRepository(db *gorm.DB) domain.PermissionRepository {
 	return &permissionRepository{db}
 }
 
-func (r *permissionRepository) RequestPermission(departmentMemberID uint, req *request.RequestPermission) error {
-	return r.db.Exec(`
+func (r *permissionRepository) RequestPermission(actor *request.AuditActor, departmentMemberID uint,
+	req *request.RequestPermission) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditPermission,
+		map[string]interface{}{"DepartmentMemberID": departmentMemberID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
 			INSERT INTO DepartmentMemberPermissionRequest
 			(CreatedAt, UpdatedAt, DepartmentMemberID, [Date], FromTime, ToTime, Reason)
 			VALUES(?, ?, ?, ?, ?, ?, ?)`, time.Now(), time.Now(),
-		departmentMemberID, req.Date, req.FromTime, req.ToTime, req.Reason).Error
+			departmentMemberID, req.Date, req.FromTime, req.ToTime, req.Reason).Error; err != nil {
+			return err
+		}
+
+		payload := event.PermissionRequested{
+			DepartmentMemberID: departmentMemberID,
+			Date:               req.Date,
+			FromTime:           req.FromTime,
+			ToTime:             req.ToTime,
+			Reason:             req.Reason,
+		}
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM DepartmentMemberPermissionRequest
+			ORDER BY ID DESC LIMIT 1`).Scan(&payload.PermissionID).Error; err != nil {
+			return err
+		}
+
+		userID, err := departmentMemberUserID(tx, departmentMemberID)
+
+		if err != nil {
+			return err
+		}
+
+		payload.UserID = userID
+
+		return publishEvent(tx, constant.PermissionRequested, payload)
+	})
 }
 
 func (r *permissionRepository) FetchOwnPermissions(departmentMemberID uint, filters *request.CommonRequestWithDateFilter) (*utils.PaginationResponse, error) {
@@ -126,11 +160,30 @@ func (r *permissionRepository) FetchDepartmentMemberPermissions(departmentID uin
 	return &response, nil
 }
 
-func (r *permissionRepository) UpdatePermissionStatus(permissionID, approvedBy uint, req *request.UpdatePermissionStatus) error {
-	return r.db.Exec(`
-		UPDATE DepartmentMemberPermissionRequest
-		SET UpdatedAt = ?, IsApproved = ?, ApprovedAt = ?, ApprovedBy = ?
-		WHERE ID = ?`, time.Now(), req.IsApproved, time.Now(), approvedBy, permissionID).Error
+func (r *permissionRepository) UpdatePermissionStatus(actor *request.AuditActor, permissionID, approvedBy uint,
+	req *request.UpdatePermissionStatus) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditPermission, map[string]interface{}{"ID": permissionID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE DepartmentMemberPermissionRequest
+			SET UpdatedAt = ?, IsApproved = ?, ApprovedAt = ?, ApprovedBy = ?
+			WHERE ID = ?`, time.Now(), req.IsApproved, time.Now(), approvedBy, permissionID).Error; err != nil {
+			return err
+		}
+
+		var payload event.PermissionStatusChanged
+
+		if err := tx.Raw(`
+			SELECT pr.ID PermissionID, pr.DepartmentMemberID, dm.UserID, pr.IsApproved, pr.ApprovedBy
+			FROM DepartmentMemberPermissionRequest pr
+			INNER JOIN DepartmentMember dm ON dm.ID = pr.DepartmentMemberID
+			WHERE pr.ID = ?`, permissionID).Scan(&payload).Error; err != nil {
+			return err
+		}
+
+		return publishEvent(tx, constant.PermissionStatusChanged, payload)
+	})
 }
 
 func (r *permissionRepository) IsPermissionExistWithoutApproval(departmentMemberID uint) (bool, error) {
@@ -160,18 +213,27 @@ func (r *permissionRepository) IsPermissionExistWithID(id uint) (bool, error) {
 	return count > 0, nil
 }
 
-func (r *permissionRepository) UpdatePermissionRequest(permissionID uint, req *request.RequestPermission) error {
-	return r.db.Exec(`
-		UPDATE DepartmentMemberPermissionRequest
-		SET UpdatedAt = ?, Reason = ?, [Date] = ?, FromTime = ?, ToTime = ?
-		WHERE ID = ?`, time.Now(), req.Reason, req.Date, req.FromTime, req.ToTime, permissionID).Error
+func (r *permissionRepository) UpdatePermissionRequest(actor *request.AuditActor, permissionID uint,
+	req *request.RequestPermission) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditPermission, map[string]interface{}{"ID": permissionID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE DepartmentMemberPermissionRequest
+			SET UpdatedAt = ?, Reason = ?, [Date] = ?, FromTime = ?, ToTime = ?
+			WHERE ID = ?`, time.Now(), req.Reason, req.Date, req.FromTime, req.ToTime, permissionID).Error
+	})
 }
 
-func (r *permissionRepository) RemovePermissionRequest(permissionID uint) error {
-	return r.db.Exec(`
-		UPDATE DepartmentMemberPermissionRequest
-		SET IsActive = ?, DeletedAt = ?
-		WHERE ID = ?`, constant.Inactive, time.Now(), permissionID).Error
+func (r *permissionRepository) RemovePermissionRequest(actor *request.AuditActor, permissionID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditPermission, map[string]interface{}{"ID": permissionID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE DepartmentMemberPermissionRequest
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), permissionID).Error
+	})
 }
 
 func (r *permissionRepository) IsPermissionExistsWithApproval(permissionID uint) (bool, error) {
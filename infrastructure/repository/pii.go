@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"ems/utils"
+
+	"gorm.io/gorm"
+)
+
+// sealedColumns are the columns other than the user details and their history that are
+// encrypted with the PII key. They are re-encrypted with the same rotation, as they cannot be
+// read once the key they were sealed with is retired.
+var sealedColumns = []struct {
+	Table  string
+	Column string
+}{
+	{"ProfileChangeRequest", "Changes"},
+	{"UserTwoFactor", "Secret"},
+	{"SigningKey", "PrivateKey"},
+	{"Webhook", "Secret"},
+}
+
+// reEncryptColumn seals the column's encrypted values with the active key, leaving the values
+// already sealed with it alone.
+func reEncryptColumn(db *gorm.DB, table, column string) (int, error) {
+	var (
+		rows []struct {
+			ID    uint
+			Value string
+		}
+		updated int
+	)
+
+	if err := db.Raw(`
+		SELECT ID, [` + column + `] Value
+		FROM [` + table + `]
+		WHERE [` + column + `] LIKE 'enc:%'`).Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		if utils.IsEncryptedWithActiveKey(row.Value) {
+			continue
+		}
+
+		plaintext, err := utils.DecryptPII(row.Value)
+		if err != nil {
+			return updated, err
+		}
+
+		value, err := utils.EncryptPII(plaintext)
+		if err != nil {
+			return updated, err
+		}
+
+		if err := db.Exec(`
+			UPDATE [`+table+`]
+			SET [`+column+`] = ?
+			WHERE ID = ?`, value, row.ID).Error; err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type profileChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileChangeRepository(db *gorm.DB) domain.ProfileChangeRepository {
+	return &profileChangeRepository{db}
+}
+
+func (r *profileChangeRepository) RequestProfileChange(userID uint, changes string, document *response.UploadedDocument) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var userDocumentID *uint
+
+		if document != nil {
+			if err := tx.Exec(`
+				INSERT INTO UserDocument
+				(CreatedAt, UpdatedAt, UserID, FilePath, FileName, Checksum, UploadedBy, StorageBackend,
+				ContentType, FileSize)
+				VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, time.Now(), time.Now(), userID, document.FilePath,
+				document.FileName, document.Checksum, userID, config.Config.StorageBackend,
+				document.ContentType, document.FileSize).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Raw(`
+				SELECT ID
+				FROM UserDocument
+				ORDER BY ID DESC LIMIT 1`).Scan(&userDocumentID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`
+			INSERT INTO ProfileChangeRequest
+			(CreatedAt, UpdatedAt, IsActive, UserID, Changes, UserDocumentID)
+			VALUES(?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, userID, changes, userDocumentID).Error
+	})
+}
+
+func (r *profileChangeRepository) IsProfileChangeExistsWithoutApproval(userID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM ProfileChangeRequest
+		WHERE UserID = ? AND IsApproved IS NULL AND IsActive = 1`, userID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *profileChangeRepository) GetProfileChangeRequestByID(requestID uint) (*schema.ProfileChangeRequest, error) {
+	var data *schema.ProfileChangeRequest
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM ProfileChangeRequest
+		WHERE ID = ? AND IsActive = 1`, requestID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *profileChangeRepository) FetchOwnProfileChangeRequests(userID uint, filters *request.CommonRequest) ([]response.FetchProfileChangeRequests, uint, error) {
+	return r.fetchProfileChangeRequests(` AND pcr.UserID = ?`, []interface{}{userID}, filters)
+}
+
+func (r *profileChangeRepository) FetchPendingProfileChangeRequests(filters *request.CommonRequest) ([]response.FetchProfileChangeRequests, uint, error) {
+	return r.fetchProfileChangeRequests(` AND pcr.IsApproved IS NULL`, nil, filters)
+}
+
+func (r *profileChangeRepository) fetchProfileChangeRequests(condition string, params []interface{},
+	filters *request.CommonRequest) ([]response.FetchProfileChangeRequests, uint, error) {
+	var (
+		data         []response.FetchProfileChangeRequests
+		itemsPerPage uint = 10
+		totalCount   uint = 0
+		query             = `
+		SELECT pcr.ID, pcr.UserID userID, pcr.Changes, pcr.UserDocumentID userDocumentID,
+		pcr.IsApproved isApproved, pcr.ApprovedAt approvedAt, pcr.Remarks, pcr.CreatedAt,
+		(usr.FirstName || ' ' || usr.LastName) AS userName,
+		(approvedUser.FirstName || ' ' || approvedUser.LastName) AS approvedBy,
+		COUNT(*) OVER (PARTITION BY 1) AS [count]
+		FROM ProfileChangeRequest pcr
+		INNER JOIN [User] usr ON usr.ID = pcr.UserID AND usr.IsActive = 1
+		LEFT JOIN [User] approvedUser ON approvedUser.ID = pcr.ApprovedBy AND approvedUser.IsActive = 1
+		WHERE pcr.IsActive = 1` + condition + `
+		ORDER BY pcr.CreatedAt DESC`
+	)
+
+	if filters.Page > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		params = append(params, itemsPerPage, (filters.Page-1)*itemsPerPage)
+	}
+
+	if err := r.db.Raw(query, params...).Scan(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(data) > 0 {
+		totalCount = data[0].Count
+	}
+
+	return data, totalCount, nil
+}
+
+// UpdateProfileChangeStatus reviews a request that is still pending and reports whether it was,
+// so that two reviewers cannot both act on the same request. The approved changes are applied in
+// the same transaction, so that the request is never approved without them or the other way round.
+func (r *profileChangeRepository) UpdateProfileChangeStatus(actor *request.AuditActor, requestID, approvedBy uint,
+	req *request.UpdateProfileChangeStatus, changes *request.ApplyProfileChange) (bool, error) {
+	var targets []auditTarget
+
+	if changes != nil && changes.User != nil {
+		targets = append(targets, auditTarget{constant.AuditUpdate, constant.AuditUser,
+			map[string]interface{}{"ID": changes.UserID}})
+	}
+
+	if changes != nil && changes.Details != nil {
+		targets = append(targets, auditTarget{constant.AuditUpdate, constant.AuditUserDetails,
+			map[string]interface{}{"UserID": changes.UserID}})
+	}
+
+	isUpdated := false
+
+	err := auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE ProfileChangeRequest
+			SET UpdatedAt = ?, IsApproved = ?, ApprovedAt = ?, ApprovedBy = ?, Remarks = ?
+			WHERE ID = ? AND IsApproved IS NULL`, time.Now(), req.IsApproved, time.Now(), approvedBy, req.Remarks, requestID)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		isUpdated = result.RowsAffected == 1
+
+		if !isUpdated || changes == nil {
+			return nil
+		}
+
+		if changes.User != nil {
+			if err := updateUser(tx, changes.UserID, changes.User); err != nil {
+				return err
+			}
+		}
+
+		if changes.Details != nil {
+			return saveUserDetails(tx, changes.Details)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return isUpdated, nil
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"ems/app/model/constant"
+	"ems/app/model/request"
 	"ems/app/model/response"
+	"ems/app/model/schema"
 	"ems/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -20,11 +23,214 @@ func (r *roleRepository) FetchRoles() ([]response.FetchRoles, error) {
 	var data []response.FetchRoles
 
 	if err := r.db.Raw(`
-		SELECT ID, [Name]
+		SELECT ID, [Name], Description, IsSystem isSystem, RequireTwoFactor requireTwoFactor
 		FROM [Role]
-		WHERE ID <> ?`, constant.Admin).Scan(&data).Error; err != nil {
+		WHERE IsActive = 1 AND ID <> ?
+		ORDER BY ID`, constant.Admin).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	var grants []struct {
+		RoleID     uint   `gorm:"column:roleID"`
+		Permission string `gorm:"column:permission"`
+	}
+
+	if err := r.db.Raw(`
+		SELECT rp.RoleID roleID, p.[Name] permission
+		FROM RolePermission rp
+		INNER JOIN Permission p ON p.ID = rp.PermissionID AND p.IsActive = 1
+		WHERE rp.IsActive = 1
+		ORDER BY p.[Name]`).Scan(&grants).Error; err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[uint][]string)
+	for _, grant := range grants {
+		permissions[grant.RoleID] = append(permissions[grant.RoleID], grant.Permission)
+	}
+
+	for i := range data {
+		data[i].Permissions = permissions[data[i].ID]
+		if data[i].Permissions == nil {
+			data[i].Permissions = []string{}
+		}
+	}
+
+	return data, nil
+}
+
+func (r *roleRepository) FetchPermissions() ([]response.FetchPermissions, error) {
+	var data []response.FetchPermissions
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Description
+		FROM Permission
+		WHERE IsActive = 1
+		ORDER BY [Name]`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *roleRepository) GetRoleByID(roleID uint) (*schema.Role, error) {
+	var data *schema.Role
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM [Role]
+		WHERE ID = ? AND IsActive = 1`, roleID).Scan(&data).Error; err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
+
+func (r *roleRepository) IsRoleNameExists(name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM [Role]
+		WHERE [Name] = ? AND IsActive = 1`, name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *roleRepository) IsRoleNameExistsExceptID(roleID uint, name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM [Role]
+		WHERE ID <> ? AND [Name] = ? AND IsActive = 1`, roleID, name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *roleRepository) IsRoleAssigned(roleID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM [User]
+		WHERE RoleID = ? AND IsActive = 1`, roleID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *roleRepository) CreateRole(req *request.CreateRole, permissionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO [Role]
+			(CreatedAt, UpdatedAt, IsActive, [Name], Description, IsSystem)
+			VALUES(?, ?, ?, ?, ?, 0)`,
+			time.Now(), time.Now(), constant.Active, req.Name, req.Description).Error; err != nil {
+			return err
+		}
+
+		var roleID uint
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM [Role]
+			ORDER BY ID DESC LIMIT 1`).Scan(&roleID).Error; err != nil {
+			return err
+		}
+
+		return grantPermissions(tx, roleID, permissionIDs)
+	})
+}
+
+// UpdateRole replaces the role's grants with permissionIDs.
+func (r *roleRepository) UpdateRole(roleID uint, req *request.UpdateRole, permissionIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE [Role]
+			SET UpdatedAt = ?, [Name] = ?, Description = ?
+			WHERE ID = ?`, time.Now(), req.Name, req.Description, roleID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			UPDATE RolePermission
+			SET IsActive = ?, DeletedAt = ?
+			WHERE RoleID = ? AND IsActive = 1`, constant.Inactive, time.Now(), roleID).Error; err != nil {
+			return err
+		}
+
+		return grantPermissions(tx, roleID, permissionIDs)
+	})
+}
+
+func (r *roleRepository) UpdateRoleTwoFactor(roleID uint, requireTwoFactor bool) error {
+	return r.db.Exec(`
+		UPDATE [Role]
+		SET UpdatedAt = ?, RequireTwoFactor = ?
+		WHERE ID = ?`, time.Now(), requireTwoFactor, roleID).Error
+}
+
+func (r *roleRepository) RemoveRole(roleID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE [Role]
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), roleID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE RolePermission
+			SET IsActive = ?, DeletedAt = ?
+			WHERE RoleID = ? AND IsActive = 1`, constant.Inactive, time.Now(), roleID).Error
+	})
+}
+
+func (r *roleRepository) FetchRolePermissions(roleID uint) ([]string, error) {
+	var data []string
+
+	if err := r.db.Raw(`
+		SELECT p.[Name]
+		FROM RolePermission rp
+		INNER JOIN Permission p ON p.ID = rp.PermissionID AND p.IsActive = 1
+		WHERE rp.RoleID = ? AND rp.IsActive = 1
+		ORDER BY p.[Name]`, roleID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *roleRepository) HasPermission(roleID uint, permission string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM RolePermission rp
+		INNER JOIN Permission p ON p.ID = rp.PermissionID AND p.IsActive = 1
+		WHERE rp.RoleID = ? AND rp.IsActive = 1 AND p.[Name] = ?`, roleID, permission).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func grantPermissions(tx *gorm.DB, roleID uint, permissionIDs []uint) error {
+	for _, permissionID := range permissionIDs {
+		if err := tx.Exec(`
+			INSERT INTO RolePermission
+			(CreatedAt, UpdatedAt, IsActive, RoleID, PermissionID)
+			VALUES(?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, roleID, permissionID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,482 @@
+package repository
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"ems/infrastructure/scim"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type scimRepository struct {
+	db *gorm.DB
+}
+
+func NewSCIMRepository(db *gorm.DB) domain.SCIMRepository {
+	return &scimRepository{db}
+}
+
+type scimColumnKind int
+
+const (
+	scimString scimColumnKind = iota
+	scimID
+	scimBool
+	scimTime
+	scimMember
+)
+
+type scimColumn struct {
+	expr string
+	kind scimColumnKind
+}
+
+var enterpriseAttr = strings.ToLower(constant.SCIMEnterpriseUserSchema) + ":"
+
+// scimUserColumns maps the filterable User attributes to their columns. EMS keeps a single
+// email and phone number, so their type and primary sub-attributes are constants.
+var scimUserColumns = map[string]scimColumn{
+	"id":                              {"usr.ID", scimID},
+	"username":                        {"usr.Email", scimString},
+	"emails":                          {"usr.Email", scimString},
+	"emails.value":                    {"usr.Email", scimString},
+	"emails.type":                     {"'work'", scimString},
+	"emails.primary":                  {"1", scimBool},
+	"externalid":                      {"usr.ExternalID", scimString},
+	"name.givenname":                  {"usr.FirstName", scimString},
+	"name.familyname":                 {"usr.LastName", scimString},
+	"name.formatted":                  {"(usr.FirstName || ' ' || usr.LastName)", scimString},
+	"displayname":                     {"(usr.FirstName || ' ' || usr.LastName)", scimString},
+	"phonenumbers":                    {"NULLIF(usr.Mobile, '')", scimString},
+	"phonenumbers.value":              {"NULLIF(usr.Mobile, '')", scimString},
+	"phonenumbers.type":               {"'mobile'", scimString},
+	"title":                           {"ud.Designation", scimString},
+	"active":                          {"usr.IsActive", scimBool},
+	"groups":                          {"dept.ID", scimID},
+	"groups.value":                    {"dept.ID", scimID},
+	"groups.display":                  {"dept.[Name]", scimString},
+	enterpriseAttr + "employeenumber": {"usr.Code", scimString},
+	enterpriseAttr + "department":     {"dept.[Name]", scimString},
+	"meta.created":                    {"usr.CreatedAt", scimTime},
+	"meta.lastmodified":               {"usr.UpdatedAt", scimTime},
+}
+
+var scimGroupColumns = map[string]scimColumn{
+	"id":                {"dept.ID", scimID},
+	"displayname":       {"dept.[Name]", scimString},
+	"externalid":        {"dept.ExternalID", scimString},
+	"members":           {"", scimMember},
+	"members.value":     {"", scimMember},
+	"meta.created":      {"dept.CreatedAt", scimTime},
+	"meta.lastmodified": {"dept.UpdatedAt", scimTime},
+}
+
+const scimUserQuery = `
+	SELECT usr.ID, usr.ExternalID, usr.FirstName, usr.LastName, usr.Email, usr.Mobile, usr.Code,
+	usr.RoleID, usr.IsActive, usr.CreatedAt, usr.UpdatedAt, ud.Designation, dept.ID DepartmentID,
+	dept.[Name] DepartmentName
+	FROM [User] usr
+	LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+	LEFT JOIN Department dept ON dept.ID = dm.DepartmentID AND dept.IsActive = 1
+	LEFT JOIN UserDetails ud ON ud.UserID = usr.ID AND ud.IsActive = 1`
+
+// FetchSCIMUsers lists users matching the filter. Removed users are included, as identity
+// providers expect to see the users they deactivated.
+func (r *scimRepository) FetchSCIMUsers(filter string, offset, limit int) ([]response.SCIMUserRecord, int, error) {
+	var data []response.SCIMUserRecord
+
+	where, params, err := compileSCIMFilter(filter, scimUserColumns)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := scimUserQuery + ` WHERE ` + where + ` ORDER BY usr.ID LIMIT ? OFFSET ?`
+
+	if err := r.db.Raw(query, append(params, limit, offset)...).Scan(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+
+	if err := r.db.Raw(`SELECT COUNT(*) FROM (`+scimUserQuery+` WHERE `+where+`)`, params...).
+		Scan(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, totalCount, nil
+}
+
+func (r *scimRepository) FetchSCIMUser(userID uint) (*response.SCIMUserRecord, error) {
+	var data *response.SCIMUserRecord
+
+	if err := r.db.Raw(scimUserQuery+` WHERE usr.ID = ?`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// IsSCIMEmailTaken checks the email against every other user, removed ones included, so that
+// a provider re-provisioning a removed user reactivates them rather than creating a copy.
+func (r *scimRepository) IsSCIMEmailTaken(userID uint, email string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM [User]
+		WHERE Email = ? COLLATE NOCASE AND ID <> ?`, email, userID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CreateSCIMUser creates a provisioned user. They sign in through single sign-on or set a
+// password with forgot password, so the stored one is a random placeholder.
+func (r *scimRepository) CreateSCIMUser(actor *request.AuditActor, req *request.SaveSCIMUser, roleID uint,
+	hashedPassword string) (uint, error) {
+	var userID uint
+
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditUser, map[string]interface{}{"Code": req.Code}}}
+
+	err := auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO [User] (
+				CreatedAt, UpdatedAt, IsActive, ManagerID, FirstName, LastName, Email, Mobile,
+				Code, RoleID, [Password], PasswordChangedAt, MustChangePassword, ExternalID
+			)
+			VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?
+			)`,
+			time.Now(), time.Now(), constant.Active, 3, // 3 => Manager
+			req.FirstName, req.LastName, req.Email, req.Mobile, req.Code,
+			roleID, hashedPassword, time.Now(), req.ExternalID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM [User]
+			ORDER BY ID DESC LIMIT 1`).Scan(&userID).Error; err != nil {
+			return err
+		}
+
+		return publishUserCreated(tx, userID)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// UpdateSCIMUser writes the provider's attributes. The title is kept as the designation in the
+// user's details, which HR fills in, so it is only written once those exist.
+func (r *scimRepository) UpdateSCIMUser(actor *request.AuditActor, userID uint, req *request.SaveSCIMUser) error {
+	targets := []auditTarget{
+		{constant.AuditUpdate, constant.AuditUser, map[string]interface{}{"ID": userID}},
+		{constant.AuditUpdate, constant.AuditUserDetails, map[string]interface{}{"UserID": userID}},
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE [User]
+			SET UpdatedAt = ?, FirstName = ?, LastName = ?, Email = ?, Mobile = ?, Code = ?, ExternalID = ?
+			WHERE ID = ?`,
+			time.Now(), req.FirstName, req.LastName, req.Email, req.Mobile, req.Code, req.ExternalID,
+			userID).Error; err != nil {
+			return err
+		}
+
+		if req.Title == nil {
+			return nil
+		}
+
+		return tx.Exec(`
+			UPDATE UserDetails
+			SET UpdatedAt = ?, Designation = ?
+			WHERE UserID = ? AND IsActive = 1`,
+			time.Now(), *req.Title, userID).Error
+	})
+}
+
+// ReactivateUser restores a user removed by RemoveUser. Their department membership is not
+// restored.
+func (r *scimRepository) ReactivateUser(actor *request.AuditActor, userID uint) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditUser, map[string]interface{}{"ID": userID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE [User]
+			SET UpdatedAt = ?, IsActive = ?, DeletedAt = NULL
+			WHERE ID = ?`, time.Now(), constant.Active, userID).Error
+	})
+}
+
+const scimGroupQuery = `
+	SELECT dept.ID, dept.ExternalID, dept.[Name], dept.CreatedAt, dept.UpdatedAt
+	FROM Department dept`
+
+func (r *scimRepository) FetchSCIMGroups(filter string, offset, limit int) ([]response.SCIMGroupRecord, int, error) {
+	var data []response.SCIMGroupRecord
+
+	where, params, err := compileSCIMFilter(filter, scimGroupColumns)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where = `dept.IsActive = 1 AND ` + where
+	query := scimGroupQuery + ` WHERE ` + where + ` ORDER BY dept.ID LIMIT ? OFFSET ?`
+
+	if err := r.db.Raw(query, append(params, limit, offset)...).Scan(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+
+	if err := r.db.Raw(`SELECT COUNT(*) FROM Department dept WHERE `+where, params...).
+		Scan(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, totalCount, nil
+}
+
+func (r *scimRepository) FetchSCIMGroup(departmentID uint) (*response.SCIMGroupRecord, error) {
+	var data *response.SCIMGroupRecord
+
+	if err := r.db.Raw(scimGroupQuery+` WHERE dept.ID = ? AND dept.IsActive = 1`, departmentID).
+		Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *scimRepository) FetchSCIMGroupMembers(departmentIDs []uint) ([]response.SCIMGroupMember, error) {
+	var data []response.SCIMGroupMember
+
+	if len(departmentIDs) == 0 {
+		return data, nil
+	}
+
+	if err := r.db.Raw(`
+		SELECT dm.DepartmentID, usr.ID UserID, usr.FirstName, usr.LastName, usr.RoleID
+		FROM DepartmentMember dm
+		INNER JOIN [User] usr ON usr.ID = dm.UserID AND usr.IsActive = 1
+		WHERE dm.IsActive = 1 AND dm.DepartmentID IN ?
+		ORDER BY dm.DepartmentID, usr.ID`, departmentIDs).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *scimRepository) FetchSCIMMembershipUsers(userIDs []uint) ([]response.SCIMMembershipUser, error) {
+	var data []response.SCIMMembershipUser
+
+	if len(userIDs) == 0 {
+		return data, nil
+	}
+
+	if err := r.db.Raw(`
+		SELECT usr.ID, usr.RoleID, dm.DepartmentID
+		FROM [User] usr
+		LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+		WHERE usr.ID IN ? AND usr.IsActive = 1`, userIDs).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *scimRepository) GetDepartmentIDByName(name string) (uint, error) {
+	var departmentID uint
+
+	if err := r.db.Raw(`
+		SELECT ID
+		FROM Department
+		WHERE [Name] = ? AND IsActive = 1
+		ORDER BY ID DESC LIMIT 1`, name).Scan(&departmentID).Error; err != nil {
+		return 0, err
+	}
+
+	return departmentID, nil
+}
+
+func (r *scimRepository) UpdateSCIMGroup(actor *request.AuditActor, departmentID uint, name string,
+	externalID *string) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditDepartment, map[string]interface{}{"ID": departmentID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return tx.Exec(`
+			UPDATE Department
+			SET UpdatedAt = ?, [Name] = ?, ExternalID = ?
+			WHERE ID = ?`, time.Now(), name, externalID, departmentID).Error
+	})
+}
+
+// compileSCIMFilter turns a SCIM filter into an SQL condition over the given columns.
+func compileSCIMFilter(filter string, columns map[string]scimColumn) (string, []interface{}, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "1 = 1", nil, nil
+	}
+
+	parsed, err := scim.ParseFilter(filter)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	var params []interface{}
+
+	condition, err := compileSCIMCondition(parsed, columns, "", &params)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return condition, params, nil
+}
+
+func compileSCIMCondition(filter *scim.Filter, columns map[string]scimColumn, prefix string,
+	params *[]interface{}) (string, error) {
+	switch filter.Op {
+	case "and", "or":
+		left, err := compileSCIMCondition(filter.Left, columns, prefix, params)
+
+		if err != nil {
+			return "", err
+		}
+
+		right, err := compileSCIMCondition(filter.Right, columns, prefix, params)
+
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("(%s %s %s)", left, strings.ToUpper(filter.Op), right), nil
+	case "not":
+		operand, err := compileSCIMCondition(filter.Left, columns, prefix, params)
+
+		if err != nil {
+			return "", err
+		}
+
+		return "NOT " + operand, nil
+	case "[]":
+		return compileSCIMCondition(filter.Sub, columns, prefix+filter.Attr+".", params)
+	}
+
+	attr := prefix + filter.Attr
+	column, ok := columns[attr]
+
+	if !ok {
+		return "", apperror.InvalidFilterError(fmt.Sprintf("attribute %q cannot be filtered on", attr))
+	}
+
+	if filter.Op == "pr" {
+		switch column.kind {
+		case scimBool:
+			return "1 = 1", nil
+		case scimMember:
+			return `EXISTS (` + scimMemberQuery + `)`, nil
+		}
+
+		return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", column.expr, column.expr), nil
+	}
+
+	operators := map[string]string{"eq": "=", "ne": "<>", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+	operator, comparison := operators[filter.Op]
+
+	switch column.kind {
+	case scimString:
+		value, ok := filter.Value.(string)
+
+		if !ok {
+			return "", apperror.InvalidFilterError(attr + " needs a string value")
+		}
+
+		if comparison {
+			*params = append(*params, value)
+
+			if filter.Op == "ne" {
+				return fmt.Sprintf("(%s IS NULL OR %s <> ? COLLATE NOCASE)", column.expr, column.expr), nil
+			}
+
+			return fmt.Sprintf("%s %s ? COLLATE NOCASE", column.expr, operator), nil
+		}
+
+		pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+
+		switch filter.Op {
+		case "co":
+			pattern = "%" + pattern + "%"
+		case "sw":
+			pattern = pattern + "%"
+		case "ew":
+			pattern = "%" + pattern
+		}
+
+		*params = append(*params, pattern)
+
+		return fmt.Sprintf(`%s LIKE ? ESCAPE '\'`, column.expr), nil
+	case scimID, scimMember:
+		id, err := strconv.ParseUint(fmt.Sprint(filter.Value), 10, 64)
+
+		if !comparison || err != nil {
+			return "", apperror.InvalidFilterError(attr + " can only be compared with an ID")
+		}
+
+		*params = append(*params, id)
+
+		if column.kind == scimMember {
+			if filter.Op != "eq" {
+				return "", apperror.InvalidFilterError(attr + " only supports eq")
+			}
+
+			return `EXISTS (` + scimMemberQuery + ` AND dm.UserID = ?)`, nil
+		}
+
+		return fmt.Sprintf("%s %s ?", column.expr, operator), nil
+	case scimBool:
+		value, ok := filter.Value.(bool)
+
+		if !ok || (filter.Op != "eq" && filter.Op != "ne") {
+			return "", apperror.InvalidFilterError(attr + " can only be compared with true or false")
+		}
+
+		*params = append(*params, value)
+
+		return fmt.Sprintf("%s %s ?", column.expr, operator), nil
+	case scimTime:
+		value, ok := filter.Value.(string)
+		timestamp, err := time.Parse(time.RFC3339, value)
+
+		if !ok || !comparison || err != nil {
+			return "", apperror.InvalidFilterError(attr + " can only be compared with a date time")
+		}
+
+		*params = append(*params, timestamp.Local())
+
+		return fmt.Sprintf("%s %s ?", column.expr, operator), nil
+	}
+
+	return "", apperror.InvalidFilterError(fmt.Sprintf("attribute %q cannot be filtered on", attr))
+}
+
+const scimMemberQuery = `
+	SELECT 1
+	FROM DepartmentMember dm
+	INNER JOIN [User] mu ON mu.ID = dm.UserID AND mu.IsActive = 1
+	WHERE dm.DepartmentID = dept.ID AND dm.IsActive = 1`
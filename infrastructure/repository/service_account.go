@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type serviceAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceAccountRepository(db *gorm.DB) domain.ServiceAccountRepository {
+	return &serviceAccountRepository{db}
+}
+
+func (r *serviceAccountRepository) FetchServiceAccounts() ([]response.FetchServiceAccounts, error) {
+	var data []response.FetchServiceAccounts
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Description, CreatedAt createdAt
+		FROM ServiceAccount
+		WHERE IsActive = 1
+		ORDER BY [Name]`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	var keys []response.FetchAPIKeys
+
+	if err := r.db.Raw(`
+		SELECT k.ID, k.ServiceAccountID serviceAccountID, k.[Name], k.Prefix, k.Scopes scopes,
+		k.CreatedAt createdAt, k.ExpiresAt expiresAt, k.LastUsedAt lastUsedAt, k.RevokedAt revokedAt
+		FROM APIKey k
+		INNER JOIN ServiceAccount sa ON sa.ID = k.ServiceAccountID AND sa.IsActive = 1
+		WHERE k.IsActive = 1
+		ORDER BY k.CreatedAt DESC`).Scan(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	accountKeys := make(map[uint][]response.FetchAPIKeys)
+	for _, key := range keys {
+		key.Scopes = strings.Split(key.ScopeList, ",")
+		accountKeys[key.ServiceAccountID] = append(accountKeys[key.ServiceAccountID], key)
+	}
+
+	for i := range data {
+		data[i].APIKeys = accountKeys[data[i].ID]
+		if data[i].APIKeys == nil {
+			data[i].APIKeys = []response.FetchAPIKeys{}
+		}
+	}
+
+	return data, nil
+}
+
+func (r *serviceAccountRepository) GetServiceAccountByID(serviceAccountID uint) (*schema.ServiceAccount, error) {
+	var data *schema.ServiceAccount
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM ServiceAccount
+		WHERE ID = ? AND IsActive = 1`, serviceAccountID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *serviceAccountRepository) IsServiceAccountNameExists(name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM ServiceAccount
+		WHERE [Name] = ? AND IsActive = 1`, name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *serviceAccountRepository) CreateServiceAccount(req *request.CreateServiceAccount, createdBy uint) error {
+	return r.db.Exec(`
+		INSERT INTO ServiceAccount
+		(CreatedAt, UpdatedAt, IsActive, [Name], Description, CreatedBy)
+		VALUES(?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, req.Name, req.Description, createdBy).Error
+}
+
+// RemoveServiceAccount also revokes the account's keys, so that they stay revoked in the
+// key listing of any audit.
+func (r *serviceAccountRepository) RemoveServiceAccount(serviceAccountID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE ServiceAccount
+			SET IsActive = ?, DeletedAt = ?
+			WHERE ID = ?`, constant.Inactive, time.Now(), serviceAccountID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE APIKey
+			SET UpdatedAt = ?, RevokedAt = ?
+			WHERE ServiceAccountID = ? AND RevokedAt IS NULL`,
+			time.Now(), time.Now(), serviceAccountID).Error
+	})
+}
+
+func (r *serviceAccountRepository) CreateAPIKey(serviceAccountID uint, name, prefix, keyHash string, scopes []string,
+	expiresAt time.Time, createdBy uint) (uint, error) {
+	var apiKeyID uint
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO APIKey
+			(CreatedAt, UpdatedAt, IsActive, ServiceAccountID, [Name], Prefix, KeyHash, Scopes, ExpiresAt, CreatedBy)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, serviceAccountID, name, prefix, keyHash,
+			strings.Join(scopes, ","), expiresAt, createdBy).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+			SELECT ID
+			FROM APIKey
+			WHERE Prefix = ?`, prefix).Scan(&apiKeyID).Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return apiKeyID, nil
+}
+
+func (r *serviceAccountRepository) GetAPIKeyByID(serviceAccountID, apiKeyID uint) (*schema.APIKey, error) {
+	var data *schema.APIKey
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM APIKey
+		WHERE ID = ? AND ServiceAccountID = ? AND IsActive = 1`, apiKeyID, serviceAccountID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetAPIKeyByPrefix only finds keys of service accounts that have not been removed.
+func (r *serviceAccountRepository) GetAPIKeyByPrefix(prefix string) (*response.APIKey, error) {
+	var data *response.APIKey
+
+	if err := r.db.Raw(`
+		SELECT k.ID, k.ServiceAccountID serviceAccountID, k.KeyHash keyHash, k.Scopes scopes,
+		k.ExpiresAt expiresAt, k.LastUsedAt lastUsedAt, k.RevokedAt revokedAt
+		FROM APIKey k
+		INNER JOIN ServiceAccount sa ON sa.ID = k.ServiceAccountID AND sa.IsActive = 1
+		WHERE k.Prefix = ? AND k.IsActive = 1`, prefix).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *serviceAccountRepository) RevokeAPIKey(apiKeyID uint) error {
+	return r.db.Exec(`
+		UPDATE APIKey
+		SET UpdatedAt = ?, RevokedAt = ?
+		WHERE ID = ? AND RevokedAt IS NULL`, time.Now(), time.Now(), apiKeyID).Error
+}
+
+func (r *serviceAccountRepository) TouchAPIKey(apiKeyID uint) error {
+	return r.db.Exec(`
+		UPDATE APIKey
+		SET LastUsedAt = ?
+		WHERE ID = ?`, time.Now(), apiKeyID).Error
+}
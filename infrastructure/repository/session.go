@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) domain.SessionRepository {
+	return &sessionRepository{db}
+}
+
+func (r *sessionRepository) CreateSession(userID uint, refreshTokenHash string, authMethod constant.AuthMethod,
+	device *request.SessionDevice, expiresAt time.Time) (uint, error) {
+	var sessionID uint
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO UserSession
+			(CreatedAt, UpdatedAt, IsActive, UserID, RefreshTokenHash, UserAgent, IPAddress, LastUsedAt, ExpiresAt,
+			AuthMethod)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, userID, refreshTokenHash, device.UserAgent, device.IPAddress,
+			time.Now(), expiresAt, authMethod).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+			SELECT ID
+			FROM UserSession
+			WHERE RefreshTokenHash = ?`, refreshTokenHash).Scan(&sessionID).Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return sessionID, nil
+}
+
+// GetSessionByRefreshTokenHash matches the current or the previous refresh token, so the
+// caller can tell a valid refresh from a replayed one.
+func (r *sessionRepository) GetSessionByRefreshTokenHash(refreshTokenHash string) (*response.UserSession, error) {
+	var data *response.UserSession
+
+	if err := r.db.Raw(`
+		SELECT ID, UserID userID, RefreshTokenHash refreshTokenHash,
+		PreviousRefreshTokenHash previousRefreshTokenHash, ExpiresAt expiresAt, RevokedAt revokedAt
+		FROM UserSession
+		WHERE IsActive = 1 AND (RefreshTokenHash = ? OR PreviousRefreshTokenHash = ?)`,
+		refreshTokenHash, refreshTokenHash).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RotateRefreshToken only succeeds while refreshTokenHash is still current, so of two
+// concurrent refreshes with the same token only one wins.
+func (r *sessionRepository) RotateRefreshToken(sessionID uint, refreshTokenHash, newRefreshTokenHash string,
+	device *request.SessionDevice) (bool, error) {
+	result := r.db.Exec(`
+		UPDATE UserSession
+		SET UpdatedAt = ?, RefreshTokenHash = ?, PreviousRefreshTokenHash = ?, UserAgent = ?, IPAddress = ?,
+		LastUsedAt = ?
+		WHERE ID = ? AND RefreshTokenHash = ? AND RevokedAt IS NULL`,
+		time.Now(), newRefreshTokenHash, refreshTokenHash, device.UserAgent, device.IPAddress, time.Now(),
+		sessionID, refreshTokenHash)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *sessionRepository) GetActiveSession(sessionID, userID uint) (*response.UserSession, error) {
+	var data *response.UserSession
+
+	if err := r.db.Raw(`
+		SELECT ID, UserID userID, ExpiresAt expiresAt, AuthMethod authMethod, ImpersonatorID impersonatorID
+		FROM UserSession
+		WHERE IsActive = 1 AND RevokedAt IS NULL AND ID = ? AND UserID = ?`,
+		sessionID, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *sessionRepository) FetchUserSessions(userID uint) ([]response.FetchUserSessions, error) {
+	var data []response.FetchUserSessions
+
+	if err := r.db.Raw(`
+		SELECT ID, UserAgent userAgent, IPAddress ipAddress, CreatedAt createdAt, LastUsedAt lastUsedAt,
+		ExpiresAt expiresAt
+		FROM UserSession
+		WHERE IsActive = 1 AND RevokedAt IS NULL AND UserID = ? AND ExpiresAt > ?
+		ORDER BY LastUsedAt DESC`, userID, time.Now()).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *sessionRepository) GetSessionByID(sessionID uint) (*response.UserSession, error) {
+	var data *response.UserSession
+
+	if err := r.db.Raw(`
+		SELECT ID, UserID userID, RefreshTokenHash refreshTokenHash,
+		PreviousRefreshTokenHash previousRefreshTokenHash, ExpiresAt expiresAt, RevokedAt revokedAt,
+		ImpersonatorID impersonatorID
+		FROM UserSession
+		WHERE IsActive = 1 AND ID = ?`, sessionID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *sessionRepository) RevokeSession(sessionID uint) error {
+	return r.db.Exec(`
+		UPDATE UserSession
+		SET UpdatedAt = ?, RevokedAt = ?
+		WHERE ID = ? AND RevokedAt IS NULL`, time.Now(), time.Now(), sessionID).Error
+}
+
+func (r *sessionRepository) RevokeUserSessions(userID uint) error {
+	return r.db.Exec(`
+		UPDATE UserSession
+		SET UpdatedAt = ?, RevokedAt = ?
+		WHERE UserID = ? AND RevokedAt IS NULL`, time.Now(), time.Now(), userID).Error
+}
+
+// CreateImpersonationSession starts a session as userID on behalf of impersonatorID. The
+// refresh token hash is required by the table but the token is never handed out, so the
+// session ends at expiresAt.
+func (r *sessionRepository) CreateImpersonationSession(userID, impersonatorID uint, reason, refreshTokenHash string,
+	device *request.SessionDevice, expiresAt time.Time) (uint, error) {
+	var sessionID uint
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO UserSession
+			(CreatedAt, UpdatedAt, IsActive, UserID, RefreshTokenHash, UserAgent, IPAddress, LastUsedAt, ExpiresAt,
+			AuthMethod, ImpersonatorID, ImpersonationReason)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, userID, refreshTokenHash, device.UserAgent, device.IPAddress,
+			time.Now(), expiresAt, constant.Impersonation, impersonatorID, reason).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+			SELECT ID
+			FROM UserSession
+			WHERE RefreshTokenHash = ?`, refreshTokenHash).Scan(&sessionID).Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return sessionID, nil
+}
+
+func (r *sessionRepository) RecordImpersonatedRequest(sessionID, impersonatorID, userID uint, method, path string,
+	statusCode int) error {
+	return r.db.Exec(`
+		INSERT INTO ImpersonatedRequest
+		(CreatedAt, UpdatedAt, IsActive, SessionID, ImpersonatorID, UserID, Method, Path, StatusCode)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, sessionID, impersonatorID, userID, method, path, statusCode).Error
+}
+
+func (r *sessionRepository) FetchImpersonations() ([]response.FetchImpersonations, error) {
+	var data []response.FetchImpersonations
+
+	if err := r.db.Raw(`
+		SELECT s.ID, s.ImpersonatorID impersonatorID,
+		(impersonator.FirstName || ' ' || impersonator.LastName) impersonator, s.UserID userID,
+		(usr.FirstName || ' ' || usr.LastName) user, s.ImpersonationReason reason, s.CreatedAt createdAt,
+		s.ExpiresAt expiresAt, s.RevokedAt revokedAt,
+		(SELECT COUNT(*) FROM ImpersonatedRequest ir WHERE ir.SessionID = s.ID) requestCount
+		FROM UserSession s
+		INNER JOIN [User] impersonator ON impersonator.ID = s.ImpersonatorID
+		INNER JOIN [User] usr ON usr.ID = s.UserID
+		WHERE s.IsActive = 1 AND s.ImpersonatorID IS NOT NULL
+		ORDER BY s.CreatedAt DESC`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *sessionRepository) FetchImpersonatedRequests(sessionID uint) ([]response.FetchImpersonatedRequests, error) {
+	var data []response.FetchImpersonatedRequests
+
+	if err := r.db.Raw(`
+		SELECT ID, Method, Path, StatusCode statusCode, CreatedAt createdAt
+		FROM ImpersonatedRequest
+		WHERE SessionID = ?
+		ORDER BY ID`, sessionID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/schema"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) domain.SigningKeyRepository {
+	return &signingKeyRepository{db}
+}
+
+// FetchSigningKeys lists the keys that have not expired, pending ones included.
+func (r *signingKeyRepository) FetchSigningKeys() ([]schema.SigningKey, error) {
+	var data []schema.SigningKey
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM SigningKey
+		WHERE IsActive = 1 AND (ExpiresAt IS NULL OR ExpiresAt > ?)
+		ORDER BY ActivatesAt`, time.Now()).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RotateSigningKey adds the new key and sets previousExpiresAt on the keys it replaces.
+func (r *signingKeyRepository) RotateSigningKey(key *schema.SigningKey, previousExpiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE SigningKey
+			SET UpdatedAt = ?, ExpiresAt = ?
+			WHERE IsActive = 1 AND ExpiresAt IS NULL`, time.Now(), previousExpiresAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO SigningKey
+			(CreatedAt, UpdatedAt, IsActive, KeyID, Algorithm, PrivateKey, PublicKey, ActivatesAt)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, key.KeyID, key.Algorithm, key.PrivateKey, key.PublicKey,
+			key.ActivatesAt).Error
+	})
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/schema"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) domain.TwoFactorRepository {
+	return &twoFactorRepository{db}
+}
+
+func (r *twoFactorRepository) GetTwoFactor(userID uint) (*schema.UserTwoFactor, error) {
+	var data *schema.UserTwoFactor
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM UserTwoFactor
+		WHERE UserID = ? AND IsActive = 1`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// SaveTwoFactorSecret replaces any setup that was started but never confirmed.
+func (r *twoFactorRepository) SaveTwoFactorSecret(userID uint, secret string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE UserTwoFactor
+			SET IsActive = ?, DeletedAt = ?
+			WHERE UserID = ? AND IsActive = 1`, constant.Inactive, time.Now(), userID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO UserTwoFactor
+			(CreatedAt, UpdatedAt, IsActive, UserID, Secret, LastUsedStep)
+			VALUES(?, ?, ?, ?, ?, 0)`, time.Now(), time.Now(), constant.Active, userID, secret).Error
+	})
+}
+
+func (r *twoFactorRepository) EnableTwoFactor(userID uint, step int64, recoveryCodeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE UserTwoFactor
+			SET UpdatedAt = ?, EnabledAt = ?, LastUsedStep = ?
+			WHERE UserID = ? AND IsActive = 1`, time.Now(), time.Now(), step, userID).Error; err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+// UseTwoFactorStep records the time step of an accepted code. It fails when that step or a
+// later one was already used, so an intercepted code cannot be replayed.
+func (r *twoFactorRepository) UseTwoFactorStep(userID uint, step int64) (bool, error) {
+	result := r.db.Exec(`
+		UPDATE UserTwoFactor
+		SET UpdatedAt = ?, LastUsedStep = ?
+		WHERE UserID = ? AND IsActive = 1 AND LastUsedStep < ?`, time.Now(), step, userID, step)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID uint, recoveryCodeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.db.Exec(`
+		UPDATE UserRecoveryCode
+		SET UpdatedAt = ?, UsedAt = ?
+		WHERE UserID = ? AND CodeHash = ? AND IsActive = 1 AND UsedAt IS NULL`,
+		time.Now(), time.Now(), userID, codeHash)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *twoFactorRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserRecoveryCode
+		WHERE UserID = ? AND IsActive = 1 AND UsedAt IS NULL`, userID).Scan(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *twoFactorRepository) RemoveTwoFactor(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE UserTwoFactor
+			SET IsActive = ?, DeletedAt = ?
+			WHERE UserID = ? AND IsActive = 1`, constant.Inactive, time.Now(), userID).Error; err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, userID, nil)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, recoveryCodeHashes []string) error {
+	if err := tx.Exec(`
+		UPDATE UserRecoveryCode
+		SET IsActive = ?, DeletedAt = ?
+		WHERE UserID = ? AND IsActive = 1`, constant.Inactive, time.Now(), userID).Error; err != nil {
+		return err
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		if err := tx.Exec(`
+			INSERT INTO UserRecoveryCode
+			(CreatedAt, UpdatedAt, IsActive, UserID, CodeHash)
+			VALUES(?, ?, ?, ?, ?)`, time.Now(), time.Now(), constant.Active, userID, codeHash).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"ems/app/model/response"
 	"ems/app/model/schema"
 	"ems/domain"
+	"ems/infrastructure/config"
 	"ems/utils"
 	"strings"
 	"time"
@@ -33,27 +34,45 @@ func (r *userRepository) CreateOTP(data *schema.ForgotPasswordOtp) (bool, error)
 	return true, nil
 }
 
+// loginUserQuery selects a user with everything the login response and the two-factor checks
+// need. Callers append the condition identifying the user.
+const loginUserQuery = `
+	SELECT usr.ID, usr.FirstName, usr.LastName, usr.Email, usr.Mobile,
+	[Role].ID roleID, [Role].[Name] roleName, [Role].RequireTwoFactor requireTwoFactor, usr.CreatedAt, usr.IsActive,
+	Usr.[Password], usr.MustChangePassword mustChangePassword, usr.PasswordChangedAt passwordChangedAt,
+	usr.AuthSource authSource, usr.Code, dm.ID AS departmentMemberID, dept.ID AS departmentID,
+	(manager.FirstName || ' ' || manager.LastName) AS manager, manager.ID managerID,
+	dept.[Name] AS department, lead.ID AS leadID, (lead.FirstName || ' ' || lead.LastName) AS lead,
+	tf.ID IS NOT NULL AS twoFactorEnabled
+	FROM [User] usr
+	INNER JOIN [Role] ON [Role].ID = usr.RoleID AND [Role].IsActive = 1
+	LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+	LEFT JOIN Department dept ON dept.ID = dm.departmentID AND dept.IsActive = 1
+	LEFT JOIN (
+		SELECT leadDM.departmentID, lead.ID, lead.FirstName, lead.LastName
+		FROM DepartmentMember leadDM
+		INNER JOIN [User] lead ON lead.ID = leadDM.UserID
+		WHERE lead.RoleID = ? AND lead.IsActive = 1
+	) lead ON lead.departmentID = dept.ID
+	LEFT JOIN [User] manager ON manager.id = usr.managerID AND manager.isActive = 1
+	LEFT JOIN UserTwoFactor tf ON tf.UserID = usr.ID AND tf.IsActive = 1 AND tf.EnabledAt IS NOT NULL
+	WHERE usr.IsActive = 1`
+
 func (r *userRepository) GetUserByEmail(email string) (*response.FetchUserByEmail, error) {
 	var data *response.FetchUserByEmail
 
-	if err := r.db.Raw(`
-		SELECT usr.ID, usr.FirstName, usr.LastName, usr.Email, usr.Mobile, usr.token, 
-		[Role].ID roleID, [Role].[Name] roleName, usr.CreatedAt, usr.IsActive,
-		Usr.[Password], usr.Code, dm.ID AS departmentMemberID, dept.ID AS departmentID, 
-		(manager.FirstName || ' ' || manager.LastName) AS manager, manager.ID managerID,
-		dept.[Name] AS department, lead.ID AS leadID, (lead.FirstName || ' ' || lead.LastName) AS lead
-		FROM [User] usr
-		INNER JOIN [Role] ON [Role].ID = usr.RoleID AND [Role].IsActive = 1
-		LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
-		LEFT JOIN Department dept ON dept.ID = dm.departmentID AND dept.IsActive = 1
-		LEFT JOIN (
-			SELECT leadDM.departmentID, lead.ID, lead.FirstName, lead.LastName
-			FROM DepartmentMember leadDM
-			INNER JOIN [User] lead ON lead.ID = leadDM.UserID
-			WHERE lead.RoleID = ? AND lead.IsActive = 1
-		) lead ON lead.departmentID = dept.ID
-		LEFT JOIN [User] manager ON manager.id = usr.managerID AND manager.isActive = 1
-		WHERE usr.IsActive = 1 AND usr.Email = ?`, constant.DepartmentLead, email).
+	if err := r.db.Raw(loginUserQuery+` AND usr.Email = ?`, constant.DepartmentLead, email).
+		Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userRepository) GetLoginUserByID(userID uint) (*response.FetchUserByEmail, error) {
+	var data *response.FetchUserByEmail
+
+	if err := r.db.Raw(loginUserQuery+` AND usr.ID = ?`, constant.DepartmentLead, userID).
 		Scan(&data).Error; err != nil {
 		return nil, err
 	}
@@ -76,11 +95,11 @@ func (r *userRepository) GetOTPStatusByUserID(ID uint) (*schema.ForgotPasswordOt
 	return data, nil
 }
 
-func (r *userRepository) UpdateToken(userID uint, token string) error {
+func (r *userRepository) IncrementOTPFailedAttempts(otpID uint) error {
 	return r.db.Exec(`
-		UPDATE User 
-		SET token = ?
-		WHERE IsActive = 1 AND ID = ?`, token, userID).Error
+		UPDATE ForgotPasswordOtp
+		SET UpdatedAt = ?, FailedAttempts = FailedAttempts + 1
+		WHERE ID = ?`, time.Now(), otpID).Error
 }
 
 func (r *userRepository) UpdateOTPStatus(userID uint) error {
@@ -94,8 +113,9 @@ func (r *userRepository) GetUserByID(ID uint) (*response.FetchUserByID, error) {
 	var data *response.FetchUserByID
 
 	if err := r.db.Raw(`
-		SELECT user.ID userID, user.token token, user.RoleID roleID, 
-		dm.ID departmentMemberID, dm.DepartmentID departmentID
+		SELECT user.ID userID, user.RoleID roleID, 
+		dm.ID departmentMemberID, dm.DepartmentID departmentID,
+		user.MustChangePassword mustChangePassword, user.PasswordChangedAt passwordChangedAt
 		FROM User user
 		LEFT JOIN DepartmentMember dm ON dm.UserID = User.ID AND dm.IsActive = 1
 		WHERE user.IsActive = 1 AND user.ID = ?`, ID).Scan(&data).Error; err != nil {
@@ -157,6 +177,19 @@ func (r *userRepository) IsMobileNumberExistsExceptID(id uint, mobile string) (b
 	return count > 0, nil
 }
 
+func (r *userRepository) IsRoleExists(roleID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM [Role]
+		WHERE ID = ? AND IsActive = 1`, roleID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (r *userRepository) IsUserExists(id uint) (bool, error) {
 	var count int64
 
@@ -183,6 +216,19 @@ func (r *userRepository) IsUserCodeExists(code string) (bool, error) {
 	return count > 0, nil
 }
 
+func (r *userRepository) GetUserIDByCode(code string) (uint, error) {
+	var userID uint
+
+	if err := r.db.Raw(`
+		SELECT ID
+		FROM User
+		WHERE Code = ? AND IsActive = 1`, code).Scan(&userID).Error; err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
 func (r *userRepository) IsUserCodeExistsExceptID(id uint, code string) (bool, error) {
 	var count int64
 
@@ -196,18 +242,41 @@ func (r *userRepository) IsUserCodeExistsExceptID(id uint, code string) (bool, e
 	return count > 0, nil
 }
 
-func (r *userRepository) CreateUser(req *request.CreateUser, hashedPassword string) error {
-	return r.db.Exec(`
-		INSERT INTO [User] (
-			CreatedAt, UpdatedAt, IsActive, ManagerID, FirstName, LastName, Email, Mobile, 
-			Code, RoleID, [Password]
-		)
-		VALUES (
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-		)`,
-		time.Now(), time.Now(), constant.Active, 3, // 3 => Manager
-		req.FirstName, req.LastName, req.Email, req.Mobile, req.Code,
-		req.RoleID, hashedPassword).Error
+// CreateUser stores the initial password chosen by HR, which the user must change when they
+// first sign in.
+func (r *userRepository) CreateUser(actor *request.AuditActor, req *request.CreateUser, hashedPassword string) error {
+	targets := []auditTarget{{constant.AuditCreate, constant.AuditUser, map[string]interface{}{"Code": req.Code}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO [User] (
+				CreatedAt, UpdatedAt, IsActive, ManagerID, FirstName, LastName, Email, Mobile, 
+				Code, RoleID, [Password], PasswordChangedAt, MustChangePassword
+			)
+			VALUES (
+				?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1
+			)`,
+			time.Now(), time.Now(), constant.Active, 3, // 3 => Manager
+			req.FirstName, req.LastName, req.Email, req.Mobile, req.Code,
+			req.RoleID, hashedPassword, time.Now()).Error; err != nil {
+			return err
+		}
+
+		var userID uint
+
+		if err := tx.Raw(`
+			SELECT ID
+			FROM [User]
+			ORDER BY ID DESC LIMIT 1`).Scan(&userID).Error; err != nil {
+			return err
+		}
+
+		if err := addPasswordHistory(tx, userID, hashedPassword); err != nil {
+			return err
+		}
+
+		return publishUserCreated(tx, userID)
+	})
 }
 
 func (r *userRepository) FetchUsers(filters *request.FetchUsers) (*utils.PaginationResponse, error) {
@@ -250,6 +319,15 @@ func (r *userRepository) FetchUsers(filters *request.FetchUsers) (*utils.Paginat
 		queryParams = append(queryParams, search, search, search)
 	}
 
+	if filters.CustomFieldID > 0 {
+		query.WriteString(` AND EXISTS (
+			SELECT 1
+			FROM UserCustomFieldValue ucfv
+			WHERE ucfv.UserID = [User].ID AND ucfv.CustomFieldID = ? AND ucfv.IsActive = 1
+			AND ucfv.[Value] = ?)`)
+		queryParams = append(queryParams, filters.CustomFieldID, filters.CustomFieldValue)
+	}
+
 	query.WriteString(` ORDER BY [User].CreatedAt DESC`)
 
 	if filters.Page > 0 {
@@ -270,11 +348,19 @@ func (r *userRepository) FetchUsers(filters *request.FetchUsers) (*utils.Paginat
 	return &response, nil
 }
 
-func (r *userRepository) UpdateUser(userID uint, req *request.UpdateUser) error {
-	return r.db.Exec(`
-				UPDATE [User]
-				SET UpdatedAt = ?, FirstName = ?, LastName = ?, Code = ?, Email = ?, Mobile = ?
-				WHERE ID = ?`,
+func (r *userRepository) UpdateUser(actor *request.AuditActor, userID uint, req *request.UpdateUser) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditUser, map[string]interface{}{"ID": userID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return updateUser(tx, userID, req)
+	})
+}
+
+func updateUser(tx *gorm.DB, userID uint, req *request.UpdateUser) error {
+	return tx.Exec(`
+		UPDATE [User]
+		SET UpdatedAt = ?, FirstName = ?, LastName = ?, Code = ?, Email = ?, Mobile = ?
+		WHERE ID = ?`,
 		time.Now(), req.FirstName, req.LastName, req.Code,
 		req.Email, req.Mobile, userID).
 		Error
@@ -348,18 +434,41 @@ func (r *userRepository) FetchLastUserCode() (*response.FetchLastUserCode, error
 	return data, nil
 }
 
-func (r *userRepository) UpdateTokenStatus(userID uint) error {
-	return r.db.Exec(`
-		UPDATE [User]
-		SET Token = NULL
-		WHERE ID = ?`, userID).Error
-}
+func (r *userRepository) UpdatePassword(actor *request.AuditActor, userId uint, hashedPassword string) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditUser, map[string]interface{}{"ID": userId}}}
 
-func (r *userRepository) UpdatePassword(userId uint, hashedPassword string) error {
-	return r.db.Exec(`
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		if err := tx.Exec(`
 			UPDATE User
-			SET UpdatedAt = ?, [Password] = ?
-			WHERE ID = ?`, time.Now(), hashedPassword, userId).Error
+			SET UpdatedAt = ?, [Password] = ?, PasswordChangedAt = ?, MustChangePassword = 0
+			WHERE ID = ?`, time.Now(), hashedPassword, time.Now(), userId).Error; err != nil {
+			return err
+		}
+
+		return addPasswordHistory(tx, userId, hashedPassword)
+	})
+}
+
+// FetchPasswordHistory returns the hashes of the user's most recent passwords, newest first.
+func (r *userRepository) FetchPasswordHistory(userID uint, limit int) ([]string, error) {
+	var data []string
+
+	if err := r.db.Raw(`
+		SELECT PasswordHash
+		FROM PasswordHistory
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY ID DESC LIMIT ?`, userID, limit).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func addPasswordHistory(tx *gorm.DB, userID uint, hashedPassword string) error {
+	return tx.Exec(`
+		INSERT INTO PasswordHistory
+		(CreatedAt, UpdatedAt, IsActive, UserID, PasswordHash)
+		VALUES(?, ?, ?, ?, ?)`, time.Now(), time.Now(), constant.Active, userID, hashedPassword).Error
 }
 
 func (r *userRepository) FetchUnmappedLeadUsers() ([]response.FetchUnmappedUsers, error) {
@@ -436,8 +545,10 @@ func (r *userRepository) IsDepartmentUserExists(userID uint) (*response.FetchDep
 	return data, nil
 }
 
-func (r *userRepository) RemoveUser(userID uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *userRepository) RemoveUser(actor *request.AuditActor, userID uint) error {
+	targets := []auditTarget{{constant.AuditDelete, constant.AuditUser, map[string]interface{}{"ID": userID}}}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
 		if err := tx.Exec(`
 			UPDATE User
 			SET IsActive = ?, DeletedAt = ?
@@ -445,13 +556,20 @@ func (r *userRepository) RemoveUser(userID uint) error {
 			return err
 		}
 
+		memberships, err := activeMemberships(tx, "UserID = ?", userID)
+
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Exec(`
 			UPDATE DepartmentMember
 			SET IsActive = ?, DeletedAt = ?
 			WHERE UserID = ?`, constant.Inactive, time.Now(), userID).Error; err != nil {
 			return err
 		}
-		return nil
+
+		return publishMembershipChanges(tx, memberships, false)
 	})
 }
 
@@ -470,51 +588,75 @@ func (r *userRepository) IsMappedLeadUser(userID uint) (bool, error) {
 	return count > 0, nil
 }
 
-func (r *userRepository) UpdateUserDetails(req *request.UpdateUserDetails) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		layout := "2006-01-02"
-		dob := req.DOB.Format(layout)
-		doj := req.DateOfJoining.Format(layout)
+func (r *userRepository) UpdateUserDetails(actor *request.AuditActor, req *request.UpdateUserDetails) error {
+	targets := []auditTarget{{constant.AuditUpdate, constant.AuditUserDetails,
+		map[string]interface{}{"UserID": req.UserID}}}
 
-		var count int64
-		if err := tx.Raw(`
-				SELECT COUNT(*) 
-				FROM UserDetails
-				WHERE IsActive = 1 AND UserID = ?`, req.UserID).Scan(&count).Error; err != nil {
-			return err
-		}
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		return saveUserDetails(tx, req)
+	})
+}
 
-		if count > 0 {
-			if err := tx.Exec(`
-				UPDATE UserDetails
-				SET UpdatedAt = ?, DateOfJoining = strftime('%Y-%m-%d', ?), Experience = ?, Designation = ?, 
-				DOB = strftime('%Y-%m-%d', ?), PanNumber = ?, AadharNumber = ?, 
-				BankAccountNumber = ?, IfscCode = ?, City = ?, [Address] = ?, 
-				Degree = ?, College = ?
-				WHERE UserID = ?`,
-				time.Now(), doj, req.Experience, req.Designation, dob, req.PanNumber,
-				req.AadharNumber, req.BankAccountNumber, req.IfscCode,
-				req.City, req.Address, req.Degree, req.College, req.UserID).
-				Error; err != nil {
-				return err
-			}
-		} else {
-			if err := tx.Exec(`
-				INSERT INTO UserDetails
-				(CreatedAt, UpdatedAt, UserID, IsActive, DateOfJoining, DOB, Experience, Designation,
-				 PanNumber, AadharNumber, BankAccountNumber, IfscCode, City, [Address], 
-				Degree, College)
-				VALUES(?, ?, ?, ?, strftime('%Y-%m-%d', ?), strftime('%Y-%m-%d', ?), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-				time.Now(), time.Now(), req.UserID, constant.Active, doj, dob, req.Experience,
-				req.Designation, req.PanNumber, req.AadharNumber, req.BankAccountNumber,
-				req.IfscCode, req.City, req.Address, req.Degree, req.College).
-				Error; err != nil {
-				return err
-			}
-		}
+// saveUserDetails updates the user's details, or inserts them when HR has not filled them in yet.
+func saveUserDetails(tx *gorm.DB, req *request.UpdateUserDetails) error {
+	layout := "2006-01-02"
+	doj := req.DateOfJoining.Format(layout)
 
-		return nil
-	})
+	dob, err := utils.EncryptPII(req.DOB.Format(layout))
+	if err != nil {
+		return err
+	}
+
+	aadharNumber, err := utils.EncryptPII(req.AadharNumber)
+	if err != nil {
+		return err
+	}
+
+	panNumber, err := utils.EncryptPII(req.PanNumber)
+	if err != nil {
+		return err
+	}
+
+	bankAccountNumber, err := utils.EncryptPII(req.BankAccountNumber)
+	if err != nil {
+		return err
+	}
+
+	aadharNumberIndex := utils.BlindIndex(req.AadharNumber)
+	panNumberIndex := utils.BlindIndex(req.PanNumber)
+
+	var count int64
+	if err := tx.Raw(`
+		SELECT COUNT(*) 
+		FROM UserDetails
+		WHERE IsActive = 1 AND UserID = ?`, req.UserID).Scan(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return tx.Exec(`
+			UPDATE UserDetails
+			SET UpdatedAt = ?, DateOfJoining = strftime('%Y-%m-%d', ?), Experience = ?, Designation = ?, 
+			DOB = ?, PanNumber = ?, PanNumberIndex = ?, AadharNumber = ?, AadharNumberIndex = ?,
+			BankAccountNumber = ?, IfscCode = ?, City = ?, [Address] = ?, 
+			Degree = ?, College = ?
+			WHERE UserID = ?`,
+			time.Now(), doj, req.Experience, req.Designation, dob, panNumber, panNumberIndex,
+			aadharNumber, aadharNumberIndex, bankAccountNumber, req.IfscCode,
+			req.City, req.Address, req.Degree, req.College, req.UserID).
+			Error
+	}
+
+	return tx.Exec(`
+		INSERT INTO UserDetails
+		(CreatedAt, UpdatedAt, UserID, IsActive, DateOfJoining, DOB, Experience, Designation,
+		 PanNumber, PanNumberIndex, AadharNumber, AadharNumberIndex, BankAccountNumber, IfscCode, 
+		 City, [Address], Degree, College)
+		VALUES(?, ?, ?, ?, strftime('%Y-%m-%d', ?), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), req.UserID, constant.Active, doj, dob, req.Experience,
+		req.Designation, panNumber, panNumberIndex, aadharNumber, aadharNumberIndex,
+		bankAccountNumber, req.IfscCode, req.City, req.Address, req.Degree, req.College).
+		Error
 }
 
 func (r *userRepository) IsAadharNumberExistsExceptID(id uint, aadharNumber string) (bool, error) {
@@ -523,7 +665,7 @@ func (r *userRepository) IsAadharNumberExistsExceptID(id uint, aadharNumber stri
 	if err := r.db.Raw(`
 		SELECT COUNT(*)
 		FROM UserDetails
-		WHERE UserID <> ? AND AadharNumber = ?`, id, aadharNumber).Scan(&count).Error; err != nil {
+		WHERE UserID <> ? AND AadharNumberIndex = ?`, id, utils.BlindIndex(aadharNumber)).Scan(&count).Error; err != nil {
 		return false, err
 	}
 
@@ -536,7 +678,7 @@ func (r *userRepository) IsPanNumberExistsExceptID(id uint, panNumber string) (b
 	if err := r.db.Raw(`
 		SELECT COUNT(*)
 		FROM UserDetails
-		WHERE UserID <> ? AND PanNumber = ?`, id, panNumber).Scan(&count).Error; err != nil {
+		WHERE UserID <> ? AND PanNumberIndex = ?`, id, utils.BlindIndex(panNumber)).Scan(&count).Error; err != nil {
 		return false, err
 	}
 
@@ -547,8 +689,8 @@ func (r *userRepository) FetchUserDetails(req *request.FetchUserDetails) (*respo
 	var data *response.FetchUserDetails
 
 	if err := r.db.Raw(`
-		SELECT usr.ID, usr.FirstName, usr.LastName, usr.Email, usr.Mobile, 
-		strftime('%Y-%m-%d', DOB) AS DOB, strftime('%Y-%m-%d', DateOfJoining) AS DateOfJoining,
+		SELECT usr.ID userID, usr.FirstName, usr.LastName, usr.Code, usr.Email, usr.Mobile, 
+		DOB, strftime('%Y-%m-%d', DateOfJoining) AS DateOfJoining,
 		PanNumber, AadharNumber, Experience, Designation,
 		BankAccountNumber, IfscCode, City, [Address], Degree, College
 		FROM [User] usr
@@ -558,17 +700,228 @@ func (r *userRepository) FetchUserDetails(req *request.FetchUserDetails) (*respo
 		return nil, err
 	}
 
+	if data == nil {
+		return nil, nil
+	}
+
+	for _, field := range []*string{data.DOB, data.AadharNumber, data.PanNumber, data.BankAccountNumber} {
+		if field == nil {
+			continue
+		}
+
+		plaintext, err := utils.DecryptPII(*field)
+		if err != nil {
+			return nil, err
+		}
+		*field = plaintext
+	}
+
 	return data, nil
+}
+
+func (r *userRepository) ReEncryptUserDetails() (int, error) {
+	var (
+		rows    []schema.UserDetails
+		updated int
+	)
+
+	if err := r.db.Raw(`
+		SELECT ID, DOB, AadharNumber, PanNumber, BankAccountNumber
+		FROM UserDetails`).Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		if utils.IsEncryptedWithActiveKey(row.DOB) && utils.IsEncryptedWithActiveKey(row.AadharNumber) &&
+			utils.IsEncryptedWithActiveKey(row.PanNumber) && utils.IsEncryptedWithActiveKey(row.BankAccountNumber) {
+			continue
+		}
+
+		values := make([]string, 4)
+		for i, field := range []string{row.DOB, row.AadharNumber, row.PanNumber, row.BankAccountNumber} {
+			plaintext, err := utils.DecryptPII(field)
+			if err != nil {
+				return updated, err
+			}
+
+			// Legacy rows hold DOB as a date column value, normalise it before sealing.
+			if i == 0 && len(plaintext) > 10 {
+				plaintext = plaintext[:10]
+			}
+
+			if values[i], err = utils.EncryptPII(plaintext); err != nil {
+				return updated, err
+			}
+
+			switch i {
+			case 1:
+				row.AadharNumberIndex = utils.BlindIndex(plaintext)
+			case 2:
+				row.PanNumberIndex = utils.BlindIndex(plaintext)
+			}
+		}
+
+		if err := r.db.Exec(`
+			UPDATE UserDetails
+			SET DOB = ?, AadharNumber = ?, AadharNumberIndex = ?, PanNumber = ?, PanNumberIndex = ?,
+			BankAccountNumber = ?
+			WHERE ID = ?`, values[0], values[1], row.AadharNumberIndex, values[2],
+			row.PanNumberIndex, values[3], row.ID).Error; err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+func (r *userRepository) FetchUserFieldHistory(filters *request.FetchUserFieldHistory) (*utils.PaginationResponse, error) {
+	var (
+		data         []response.FetchUserFieldHistory
+		itemsPerPage uint = 10
+		totalCount   uint = 0
+		query        strings.Builder
+		queryParams  = []interface{}{filters.UserID}
+	)
+
+	query.WriteString(`
+		SELECT ufh.ID, ufh.CreatedAt changedAt, ufh.Field, ufh.OldValue oldValue, ufh.NewValue newValue,
+		ufh.ActorType actorType, ufh.ChangedBy changedBy, (usr.FirstName || ' ' || usr.LastName) AS changedByName,
+		ufh.ServiceAccountID serviceAccountID, COUNT(*) OVER (PARTITION BY 1) AS [count]
+		FROM UserFieldHistory ufh
+		LEFT JOIN [User] usr ON usr.ID = ufh.ChangedBy
+		WHERE ufh.IsActive = 1 AND ufh.UserID = ?`)
+
+	if filters.Field != "" {
+		query.WriteString(` AND ufh.Field = ?`)
+		queryParams = append(queryParams, filters.Field)
+	}
+
+	if filters.FromDate != "" {
+		query.WriteString(` AND date(ufh.CreatedAt) >= ?`)
+		queryParams = append(queryParams, filters.FromDate)
+	}
+
+	if filters.ToDate != "" {
+		query.WriteString(` AND date(ufh.CreatedAt) <= ?`)
+		queryParams = append(queryParams, filters.ToDate)
+	}
+
+	query.WriteString(` ORDER BY ufh.ID DESC`)
+
+	if filters.Page > 0 {
+		query.WriteString(` LIMIT ? OFFSET ?`)
+		queryParams = append(queryParams, itemsPerPage, (filters.Page-1)*itemsPerPage)
+	}
+
+	if err := r.db.Raw(query.String(), queryParams...).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range data {
+		for _, value := range []*string{data[i].OldValue, data[i].NewValue} {
+			if value == nil {
+				continue
+			}
+
+			plaintext, err := utils.DecryptPII(*value)
+			if err != nil {
+				return nil, err
+			}
+			*value = plaintext
+		}
+	}
+
+	if len(data) > 0 {
+		totalCount = data[0].Count
+	}
 
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
 }
 
-func (r *userRepository) UploadFiles(userID uint, filepaths []string) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		for _, filepath := range filepaths {
+// ReEncryptSealedColumns seals the other columns encrypted with the PII key, such as two-factor
+// secrets and signing keys, with the active key.
+func (r *userRepository) ReEncryptSealedColumns() (int, error) {
+	var updated int
+
+	for _, sealed := range sealedColumns {
+		count, err := reEncryptColumn(r.db, sealed.Table, sealed.Column)
+		updated += count
+
+		if err != nil {
+			return updated, err
+		}
+	}
+
+	return updated, nil
+}
+
+// ReEncryptUserFieldHistory seals the history's PII values with the active key, as
+// ReEncryptUserDetails does for the details themselves.
+func (r *userRepository) ReEncryptUserFieldHistory() (int, error) {
+	var (
+		rows    []schema.UserFieldHistory
+		updated int
+	)
+
+	if err := r.db.Raw(`
+		SELECT ID, OldValue, NewValue
+		FROM UserFieldHistory
+		WHERE OldValue LIKE 'enc:%' OR NewValue LIKE 'enc:%'`).Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		values := []*string{row.OldValue, row.NewValue}
+		changed := false
+
+		for _, value := range values {
+			if value == nil || !utils.IsEncryptedPII(*value) || utils.IsEncryptedWithActiveKey(*value) {
+				continue
+			}
+
+			plaintext, err := utils.DecryptPII(*value)
+			if err != nil {
+				return updated, err
+			}
+
+			if *value, err = utils.EncryptPII(plaintext); err != nil {
+				return updated, err
+			}
+			changed = true
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err := r.db.Exec(`
+			UPDATE UserFieldHistory
+			SET OldValue = ?, NewValue = ?
+			WHERE ID = ?`, row.OldValue, row.NewValue, row.ID).Error; err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+func (r *userRepository) UploadFiles(actor *request.AuditActor, userID uint, documents []response.UploadedDocument) error {
+	targets := make([]auditTarget, 0, len(documents))
+	for _, document := range documents {
+		targets = append(targets, auditTarget{constant.AuditCreate, constant.AuditDocument,
+			map[string]interface{}{"UserID": userID, "FilePath": document.FilePath}})
+	}
+
+	return auditTransaction(r.db, actor, targets, func(tx *gorm.DB) error {
+		for _, document := range documents {
 			if err := tx.Exec(`
 				INSERT INTO UserDocument
-				(CreatedAt, UpdatedAt, UserID, FilePath)
-				VALUES(?, ?, ?, ?)`, time.Now(), time.Now(), userID, filepath).Error; err != nil {
+				(CreatedAt, UpdatedAt, UserID, FilePath, FileName, Checksum, StorageBackend, ContentType, FileSize)
+				VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`, time.Now(), time.Now(), userID, document.FilePath,
+				document.FileName, document.Checksum, config.Config.StorageBackend, document.ContentType,
+				document.FileSize).Error; err != nil {
 				return err
 			}
 		}
@@ -621,27 +974,6 @@ func (r *userRepository) GetUnmappedHRsCount(userIDs []uint) (int, error) {
 	return count, nil
 }
 
-func (r *userRepository) FetchFilePathsByUserID(userID uint) ([]response.FetchUploadedDocumentPaths, error) {
-	var data []response.FetchUploadedDocumentPaths
-
-	if err := r.db.Raw(`
-		SELECT ud.UserID, GROUP_CONCAT(filePath) AS filePaths
-		FROM UserDocument ud
-		WHERE ud.UserID = ? AND ud.IsActive = 1
-		GROUP BY ud.userID`, userID).Scan(&data).Error; err != nil {
-		return nil, err
-	}
-
-	return data, nil
-}
-
-func (r *userRepository) RemoveFile(filePath string) error {
-	return r.db.Exec(`
-		UPDATE UserDocument
-		SET IsActive = ?, DeletedAt = ?
-		WHERE FilePath = ?`, constant.Inactive, time.Now(), filePath).Error
-}
-
 func (r *userRepository) GetUserCount() (int, error) {
 	var count int
 
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userQualificationRepository struct {
+	db *gorm.DB
+}
+
+func NewUserQualificationRepository(db *gorm.DB) domain.UserQualificationRepository {
+	return &userQualificationRepository{db}
+}
+
+func (r *userQualificationRepository) FetchEducations(userID uint) ([]response.FetchEducation, error) {
+	var data []response.FetchEducation
+
+	if err := r.db.Raw(`
+		SELECT ID, Degree, Institution, FieldOfStudy fieldOfStudy, StartYear startYear,
+		EndYear endYear, Grade
+		FROM UserEducation
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY StartYear DESC`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userQualificationRepository) FetchCertifications(userID uint) ([]response.FetchCertification, error) {
+	var data []response.FetchCertification
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Issuer, CredentialID credentialID,
+		strftime('%Y-%m-%d', IssuedDate) AS issuedDate, strftime('%Y-%m-%d', ExpiryDate) AS expiryDate,
+		CASE WHEN ExpiryDate IS NOT NULL AND date(ExpiryDate) < date('now') THEN 1 ELSE 0 END AS isExpired
+		FROM UserCertification
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY IssuedDate DESC`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userQualificationRepository) FetchSkills(userID uint) ([]response.FetchSkill, error) {
+	var data []response.FetchSkill
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Proficiency
+		FROM UserSkill
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY Proficiency DESC, [Name]`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userQualificationRepository) CreateEducation(req *request.CreateEducation) error {
+	return r.db.Exec(`
+		INSERT INTO UserEducation
+		(CreatedAt, UpdatedAt, IsActive, UserID, Degree, Institution, FieldOfStudy, StartYear, EndYear, Grade)
+		VALUES(?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''))`,
+		time.Now(), time.Now(), constant.Active, req.UserID, req.Degree, req.Institution,
+		req.FieldOfStudy, req.StartYear, req.EndYear, req.Grade).Error
+}
+
+func (r *userQualificationRepository) IsEducationExists(educationID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserEducation
+		WHERE ID = ? AND IsActive = 1`, educationID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *userQualificationRepository) UpdateEducation(educationID uint, req *request.UpdateEducation) error {
+	return r.db.Exec(`
+		UPDATE UserEducation
+		SET UpdatedAt = ?, Degree = ?, Institution = ?, FieldOfStudy = NULLIF(?, ''), StartYear = ?,
+		EndYear = ?, Grade = NULLIF(?, '')
+		WHERE ID = ?`, time.Now(), req.Degree, req.Institution, req.FieldOfStudy, req.StartYear,
+		req.EndYear, req.Grade, educationID).Error
+}
+
+func (r *userQualificationRepository) RemoveEducation(educationID uint) error {
+	return r.db.Exec(`
+		UPDATE UserEducation
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), educationID).Error
+}
+
+func (r *userQualificationRepository) CreateCertification(req *request.CreateCertification) error {
+	return r.db.Exec(`
+		INSERT INTO UserCertification
+		(CreatedAt, UpdatedAt, IsActive, UserID, [Name], Issuer, CredentialID, IssuedDate, ExpiryDate)
+		VALUES(?, ?, ?, ?, ?, ?, NULLIF(?, ''), strftime('%Y-%m-%d', ?), strftime('%Y-%m-%d', NULLIF(?, '')))`,
+		time.Now(), time.Now(), constant.Active, req.UserID, req.Name, req.Issuer, req.CredentialID,
+		req.IssuedDate, req.ExpiryDate).Error
+}
+
+func (r *userQualificationRepository) IsCertificationExists(certificationID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserCertification
+		WHERE ID = ? AND IsActive = 1`, certificationID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UpdateCertification clears ReminderSentAt so a renewed certificate is reminded again.
+func (r *userQualificationRepository) UpdateCertification(certificationID uint, req *request.UpdateCertification) error {
+	return r.db.Exec(`
+		UPDATE UserCertification
+		SET UpdatedAt = ?, [Name] = ?, Issuer = ?, CredentialID = NULLIF(?, ''),
+		IssuedDate = strftime('%Y-%m-%d', ?), ExpiryDate = strftime('%Y-%m-%d', NULLIF(?, '')),
+		ReminderSentAt = NULL
+		WHERE ID = ?`, time.Now(), req.Name, req.Issuer, req.CredentialID, req.IssuedDate,
+		req.ExpiryDate, certificationID).Error
+}
+
+func (r *userQualificationRepository) RemoveCertification(certificationID uint) error {
+	return r.db.Exec(`
+		UPDATE UserCertification
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), certificationID).Error
+}
+
+func (r *userQualificationRepository) CreateSkill(req *request.CreateSkill) error {
+	return r.db.Exec(`
+		INSERT INTO UserSkill
+		(CreatedAt, UpdatedAt, IsActive, UserID, [Name], Proficiency)
+		VALUES(?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, req.UserID, req.Name, req.Proficiency).Error
+}
+
+func (r *userQualificationRepository) IsSkillExists(skillID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserSkill
+		WHERE ID = ? AND IsActive = 1`, skillID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *userQualificationRepository) IsSkillNameExists(userID uint, name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserSkill
+		WHERE UserID = ? AND lower([Name]) = lower(?) AND IsActive = 1`, userID, name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *userQualificationRepository) IsSkillNameExistsExceptID(skillID uint, name string) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserSkill
+		WHERE UserID = (SELECT UserID FROM UserSkill WHERE ID = ?)
+		AND lower([Name]) = lower(?) AND ID <> ? AND IsActive = 1`, skillID, name, skillID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *userQualificationRepository) UpdateSkill(skillID uint, req *request.UpdateSkill) error {
+	return r.db.Exec(`
+		UPDATE UserSkill
+		SET UpdatedAt = ?, [Name] = ?, Proficiency = ?
+		WHERE ID = ?`, time.Now(), req.Name, req.Proficiency, skillID).Error
+}
+
+func (r *userQualificationRepository) RemoveSkill(skillID uint) error {
+	return r.db.Exec(`
+		UPDATE UserSkill
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), skillID).Error
+}
+
+// SearchQualifications returns users holding every requested skill and every requested
+// certification. Expired certifications do not count.
+func (r *userQualificationRepository) SearchQualifications(filters *request.SearchQualifications) ([]response.SearchQualifications, uint, error) {
+	var (
+		data         []response.SearchQualifications
+		search            = "%" + strings.TrimSpace(filters.Search) + "%"
+		itemsPerPage uint = 10
+		totalCount   uint = 0
+		query        strings.Builder
+		queryParams  []interface{}
+	)
+
+	query.WriteString(`
+		SELECT usr.ID, usr.FirstName, usr.LastName, usr.Code, usr.Email, ud.Designation,
+		CASE WHEN dm.UserID IS NOT NULL THEN dept.[Name] ELSE 'None' END AS Department,
+		(SELECT group_concat(us.[Name], ', ') FROM UserSkill us
+			WHERE us.UserID = usr.ID AND us.IsActive = 1) AS Skills,
+		(SELECT group_concat(uc.[Name], ', ') FROM UserCertification uc
+			WHERE uc.UserID = usr.ID AND uc.IsActive = 1
+			AND (uc.ExpiryDate IS NULL OR date(uc.ExpiryDate) >= date('now'))) AS Certifications,
+		COUNT(*) OVER (PARTITION BY 1) AS [count]
+		FROM [User] usr
+		LEFT JOIN UserDetails ud ON ud.UserID = usr.ID AND ud.IsActive = 1
+		LEFT JOIN DepartmentMember dm ON dm.UserID = usr.ID AND dm.IsActive = 1
+		LEFT JOIN Department dept ON dept.ID = dm.DepartmentID AND dept.IsActive = 1
+		WHERE usr.IsActive = 1`)
+
+	for _, skill := range splitSearchTerms(filters.Skills) {
+		query.WriteString(` AND EXISTS (
+			SELECT 1
+			FROM UserSkill us
+			WHERE us.UserID = usr.ID AND us.IsActive = 1 AND lower(us.[Name]) = lower(?)
+			AND us.Proficiency >= ?)`)
+		queryParams = append(queryParams, skill, filters.MinProficiency)
+	}
+
+	for _, certification := range splitSearchTerms(filters.Certifications) {
+		query.WriteString(` AND EXISTS (
+			SELECT 1
+			FROM UserCertification uc
+			WHERE uc.UserID = usr.ID AND uc.IsActive = 1 AND uc.[Name] LIKE ?
+			AND (uc.ExpiryDate IS NULL OR date(uc.ExpiryDate) >= date('now')))`)
+		queryParams = append(queryParams, "%"+certification+"%")
+	}
+
+	if len(filters.Degree) > 0 {
+		query.WriteString(` AND EXISTS (
+			SELECT 1
+			FROM UserEducation ue
+			WHERE ue.UserID = usr.ID AND ue.IsActive = 1 AND ue.Degree LIKE ?)`)
+		queryParams = append(queryParams, "%"+strings.TrimSpace(filters.Degree)+"%")
+	}
+
+	if filters.DepartmentID > 0 {
+		query.WriteString(` AND dm.DepartmentID = ?`)
+		queryParams = append(queryParams, filters.DepartmentID)
+	}
+
+	if len(filters.Search) > 0 {
+		query.WriteString(` AND (usr.Email LIKE ? OR usr.FirstName LIKE ? OR usr.LastName LIKE ?)`)
+		queryParams = append(queryParams, search, search, search)
+	}
+
+	query.WriteString(` ORDER BY usr.FirstName, usr.LastName`)
+
+	if filters.Page > 0 {
+		query.WriteString(` LIMIT ? OFFSET ?`)
+		queryParams = append(queryParams, itemsPerPage, (filters.Page-1)*itemsPerPage)
+	}
+
+	if err := r.db.Raw(query.String(), queryParams...).Scan(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(data) > 0 {
+		totalCount = data[0].Count
+	}
+
+	return data, totalCount, nil
+}
+
+func (r *userQualificationRepository) FetchExpiringCertifications(days uint) ([]response.ExpiringCertification, error) {
+	var data []response.ExpiringCertification
+
+	if err := r.db.Raw(`
+		SELECT uc.ID, uc.UserID userID, usr.Email, (usr.FirstName || ' ' || usr.LastName) AS userName,
+		uc.[Name], uc.ExpiryDate expiryDate
+		FROM UserCertification uc
+		INNER JOIN [User] usr ON usr.ID = uc.UserID AND usr.IsActive = 1
+		WHERE uc.IsActive = 1 AND uc.ReminderSentAt IS NULL AND uc.ExpiryDate IS NOT NULL
+		AND date(uc.ExpiryDate) BETWEEN date('now') AND date('now', '+' || ? || ' days')`,
+		days).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userQualificationRepository) MarkCertificationReminderSent(certificationID uint) error {
+	return r.db.Exec(`
+		UPDATE UserCertification
+		SET ReminderSentAt = ?
+		WHERE ID = ?`, time.Now(), certificationID).Error
+}
+
+func splitSearchTerms(value string) []string {
+	var terms []string
+
+	for _, term := range strings.Split(value, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userRelationRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRelationRepository(db *gorm.DB) domain.UserRelationRepository {
+	return &userRelationRepository{db}
+}
+
+func (r *userRelationRepository) FetchEmergencyContacts(userID uint) ([]response.FetchEmergencyContact, error) {
+	var data []response.FetchEmergencyContact
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Relationship, Mobile, [Address]
+		FROM UserEmergencyContact
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY CreatedAt`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userRelationRepository) FetchDependents(userID uint) ([]response.FetchDependent, error) {
+	var data []response.FetchDependent
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Relationship, strftime('%Y-%m-%d', DOB) AS dob, Gender, IsInsured isInsured
+		FROM UserDependent
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY CreatedAt`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userRelationRepository) FetchNominees(userID uint) ([]response.FetchNominee, error) {
+	var data []response.FetchNominee
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], Relationship, SharePercentage sharePercentage, [Address]
+		FROM UserNominee
+		WHERE UserID = ? AND IsActive = 1
+		ORDER BY CreatedAt`, userID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userRelationRepository) CreateEmergencyContact(req *request.CreateEmergencyContact) error {
+	return r.db.Exec(`
+		INSERT INTO UserEmergencyContact
+		(CreatedAt, UpdatedAt, IsActive, UserID, [Name], Relationship, Mobile, [Address])
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now(), time.Now(), constant.Active, req.UserID, req.Name, req.Relationship,
+		req.Mobile, req.Address).Error
+}
+
+func (r *userRelationRepository) IsEmergencyContactExists(contactID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserEmergencyContact
+		WHERE ID = ? AND IsActive = 1`, contactID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *userRelationRepository) UpdateEmergencyContact(contactID uint, req *request.UpdateEmergencyContact) error {
+	return r.db.Exec(`
+		UPDATE UserEmergencyContact
+		SET UpdatedAt = ?, [Name] = ?, Relationship = ?, Mobile = ?, [Address] = ?
+		WHERE ID = ?`, time.Now(), req.Name, req.Relationship, req.Mobile, req.Address, contactID).Error
+}
+
+func (r *userRelationRepository) RemoveEmergencyContact(contactID uint) error {
+	return r.db.Exec(`
+		UPDATE UserEmergencyContact
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), contactID).Error
+}
+
+func (r *userRelationRepository) CreateDependent(req *request.CreateDependent) error {
+	return r.db.Exec(`
+		INSERT INTO UserDependent
+		(CreatedAt, UpdatedAt, IsActive, UserID, [Name], Relationship, DOB, Gender, IsInsured)
+		VALUES(?, ?, ?, ?, ?, ?, strftime('%Y-%m-%d', ?), ?, ?)`,
+		time.Now(), time.Now(), constant.Active, req.UserID, req.Name, req.Relationship,
+		req.DOB, req.Gender, req.IsInsured).Error
+}
+
+func (r *userRelationRepository) IsDependentExists(dependentID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM UserDependent
+		WHERE ID = ? AND IsActive = 1`, dependentID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *userRelationRepository) UpdateDependent(dependentID uint, req *request.UpdateDependent) error {
+	return r.db.Exec(`
+		UPDATE UserDependent
+		SET UpdatedAt = ?, [Name] = ?, Relationship = ?, DOB = strftime('%Y-%m-%d', ?), Gender = ?,
+		IsInsured = ?
+		WHERE ID = ?`, time.Now(), req.Name, req.Relationship, req.DOB, req.Gender,
+		req.IsInsured, dependentID).Error
+}
+
+func (r *userRelationRepository) RemoveDependent(dependentID uint) error {
+	return r.db.Exec(`
+		UPDATE UserDependent
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), dependentID).Error
+}
+
+func (r *userRelationRepository) ReplaceNominees(req *request.UpdateNominees) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE UserNominee
+			SET IsActive = ?, DeletedAt = ?
+			WHERE UserID = ? AND IsActive = 1`, constant.Inactive, time.Now(), req.UserID).Error; err != nil {
+			return err
+		}
+
+		for _, nominee := range req.Nominees {
+			if err := tx.Exec(`
+				INSERT INTO UserNominee
+				(CreatedAt, UpdatedAt, IsActive, UserID, [Name], Relationship, SharePercentage, [Address])
+				VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+				time.Now(), time.Now(), constant.Active, req.UserID, nominee.Name, nominee.Relationship,
+				nominee.SharePercentage, nominee.Address).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
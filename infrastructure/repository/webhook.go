@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/model/response"
+	"ems/app/model/schema"
+	"ems/domain"
+	"ems/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) domain.WebhookRepository {
+	return &webhookRepository{db}
+}
+
+func (r *webhookRepository) FetchWebhooks() ([]response.FetchWebhooks, error) {
+	var data []response.FetchWebhooks
+
+	if err := r.db.Raw(`
+		SELECT ID, [Name], URL url, EventTypes eventTypes, IsEnabled isEnabled, CreatedAt createdAt
+		FROM Webhook
+		WHERE IsActive = 1
+		ORDER BY [Name]`).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range data {
+		data[i].EventTypes = strings.Split(data[i].EventTypeList, ",")
+	}
+
+	return data, nil
+}
+
+func (r *webhookRepository) GetWebhookByID(webhookID uint) (*schema.Webhook, error) {
+	var data *schema.Webhook
+
+	if err := r.db.Raw(`
+		SELECT *
+		FROM Webhook
+		WHERE ID = ? AND IsActive = 1`, webhookID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *webhookRepository) IsWebhookNameExists(name string, excludeID uint) (bool, error) {
+	var count int64
+
+	if err := r.db.Raw(`
+		SELECT COUNT(*)
+		FROM Webhook
+		WHERE [Name] = ? AND ID != ? AND IsActive = 1`, name, excludeID).Scan(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CreateWebhook stores the webhook with its secret encrypted, as the secret is needed in clear
+// to sign each delivery.
+func (r *webhookRepository) CreateWebhook(req *request.CreateWebhook, secret string, createdBy uint) (uint, error) {
+	encryptedSecret, err := utils.EncryptPII(secret)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var webhookID uint
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO Webhook
+			(CreatedAt, UpdatedAt, IsActive, [Name], URL, Secret, EventTypes, IsEnabled, CreatedBy)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			time.Now(), time.Now(), constant.Active, req.Name, req.URL, encryptedSecret,
+			strings.Join(req.EventTypes, ","), true, createdBy).Error; err != nil {
+			return err
+		}
+
+		return tx.Raw(`
+			SELECT ID
+			FROM Webhook
+			ORDER BY ID DESC
+			LIMIT 1`).Scan(&webhookID).Error
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return webhookID, nil
+}
+
+func (r *webhookRepository) UpdateWebhook(webhookID uint, req *request.UpdateWebhook) error {
+	return r.db.Exec(`
+		UPDATE Webhook
+		SET UpdatedAt = ?, [Name] = ?, URL = ?, EventTypes = ?, IsEnabled = ?
+		WHERE ID = ? AND IsActive = 1`,
+		time.Now(), req.Name, req.URL, strings.Join(req.EventTypes, ","), req.IsEnabled, webhookID).Error
+}
+
+func (r *webhookRepository) UpdateWebhookSecret(webhookID uint, secret string) error {
+	encryptedSecret, err := utils.EncryptPII(secret)
+
+	if err != nil {
+		return err
+	}
+
+	return r.db.Exec(`
+		UPDATE Webhook
+		SET UpdatedAt = ?, Secret = ?
+		WHERE ID = ? AND IsActive = 1`, time.Now(), encryptedSecret, webhookID).Error
+}
+
+func (r *webhookRepository) RemoveWebhook(webhookID uint) error {
+	return r.db.Exec(`
+		UPDATE Webhook
+		SET IsActive = ?, DeletedAt = ?
+		WHERE ID = ?`, constant.Inactive, time.Now(), webhookID).Error
+}
+
+// FetchWebhookDeliveries returns the deliveries of a webhook, or of every webhook when none is
+// given, latest first.
+func (r *webhookRepository) FetchWebhookDeliveries(filters *request.FetchWebhookDeliveries) (*utils.PaginationResponse, error) {
+	var (
+		data         []response.FetchWebhookDeliveries
+		itemsPerPage uint = 10
+		totalCount   uint = 0
+		query        strings.Builder
+		queryParams  []interface{}
+	)
+
+	query.WriteString(`
+		SELECT wd.ID, wd.WebhookID webhookID, w.[Name] webhookName, wd.OutboxEventID outboxEventID,
+		wd.EventType eventType, wd.Status, wd.Attempts, wd.NextAttemptAt nextAttemptAt,
+		wd.LastAttemptAt lastAttemptAt, wd.ResponseStatus responseStatus, wd.LastError lastError,
+		wd.DeliveredAt deliveredAt, wd.CreatedAt createdAt, COUNT(*) OVER (PARTITION BY 1) AS [count]
+		FROM WebhookDelivery wd
+		INNER JOIN Webhook w ON w.ID = wd.WebhookID AND w.IsActive = 1
+		WHERE wd.IsActive = 1`)
+
+	if filters.WebhookID > 0 {
+		query.WriteString(` AND wd.WebhookID = ?`)
+		queryParams = append(queryParams, filters.WebhookID)
+	}
+
+	if filters.Status != "" {
+		query.WriteString(` AND wd.Status = ?`)
+		queryParams = append(queryParams, filters.Status)
+	}
+
+	if filters.EventType != "" {
+		query.WriteString(` AND wd.EventType = ?`)
+		queryParams = append(queryParams, filters.EventType)
+	}
+
+	query.WriteString(` ORDER BY wd.ID DESC`)
+
+	if filters.Page > 0 {
+		query.WriteString(` LIMIT ? OFFSET ?`)
+		queryParams = append(queryParams, itemsPerPage, (filters.Page-1)*itemsPerPage)
+	}
+
+	if err := r.db.Raw(query.String(), queryParams...).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		totalCount = data[0].Count
+	}
+
+	return utils.PaginatedResponse(totalCount, filters.Page, data), nil
+}
+
+// FetchWebhookDelivery returns a delivery with the event's payload and the log of its attempts.
+func (r *webhookRepository) FetchWebhookDelivery(deliveryID uint) (*response.FetchWebhookDelivery, error) {
+	var data *response.FetchWebhookDelivery
+
+	if err := r.db.Raw(`
+		SELECT wd.ID, wd.WebhookID webhookID, w.[Name] webhookName, wd.OutboxEventID outboxEventID,
+		wd.EventType eventType, wd.Status, wd.Attempts, wd.NextAttemptAt nextAttemptAt,
+		wd.LastAttemptAt lastAttemptAt, wd.ResponseStatus responseStatus, wd.LastError lastError,
+		wd.DeliveredAt deliveredAt, wd.CreatedAt createdAt, oe.Payload
+		FROM WebhookDelivery wd
+		INNER JOIN Webhook w ON w.ID = wd.WebhookID AND w.IsActive = 1
+		INNER JOIN OutboxEvent oe ON oe.ID = wd.OutboxEventID
+		WHERE wd.ID = ? AND wd.IsActive = 1`, deliveryID).Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	if err := r.db.Raw(`
+		SELECT ID, ResponseStatus responseStatus, Error, DurationMs durationMs, CreatedAt createdAt
+		FROM WebhookDeliveryAttempt
+		WHERE WebhookDeliveryID = ? AND IsActive = 1
+		ORDER BY ID DESC`, deliveryID).Scan(&data.AttemptLog).Error; err != nil {
+		return nil, err
+	}
+
+	if data.AttemptLog == nil {
+		data.AttemptLog = []response.FetchWebhookDeliveryAttempts{}
+	}
+
+	return data, nil
+}
+
+// CreateWebhookDeliveries queues the event for each enabled webhook subscribed to its type. It
+// skips the webhooks that already have it, so that the event can be handled again safely.
+func (r *webhookRepository) CreateWebhookDeliveries(event *response.OutboxEvent) error {
+	return r.db.Exec(`
+		INSERT OR IGNORE INTO WebhookDelivery
+		(CreatedAt, UpdatedAt, IsActive, WebhookID, OutboxEventID, EventType, Status, Attempts, NextAttemptAt)
+		SELECT ?, ?, ?, ID, ?, ?, ?, 0, ?
+		FROM Webhook
+		WHERE IsActive = 1 AND IsEnabled = 1 AND ',' || EventTypes || ',' LIKE '%,' || ? || ',%'`,
+		time.Now(), time.Now(), constant.Active, event.ID, event.EventType, constant.WebhookDeliveryPending,
+		time.Now(), event.EventType).Error
+}
+
+// ClaimDueWebhookDeliveries returns the pending deliveries that are due, oldest first, and holds
+// them for the lease so that an overlapping run does not send them as well. Deliveries of
+// disabled webhooks wait until the webhook is enabled again.
+func (r *webhookRepository) ClaimDueWebhookDeliveries(limit int, lease time.Duration) ([]response.WebhookDelivery, error) {
+	var data []response.WebhookDelivery
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Raw(`
+			SELECT wd.ID
+			FROM WebhookDelivery wd
+			INNER JOIN Webhook w ON w.ID = wd.WebhookID AND w.IsActive = 1 AND w.IsEnabled = 1
+			WHERE wd.IsActive = 1 AND wd.Status = ? AND wd.NextAttemptAt <= ?
+			ORDER BY wd.ID
+			LIMIT ?`, constant.WebhookDeliveryPending, now, limit).Scan(&data).Error; err != nil {
+			return err
+		}
+
+		if len(data) == 0 {
+			return nil
+		}
+
+		deliveryIDs := make([]uint, 0, len(data))
+		for _, delivery := range data {
+			deliveryIDs = append(deliveryIDs, delivery.ID)
+		}
+
+		if err := tx.Exec(`
+			UPDATE WebhookDelivery
+			SET UpdatedAt = ?, NextAttemptAt = ?
+			WHERE ID IN ?`, now, now.Add(lease), deliveryIDs).Error; err != nil {
+			return err
+		}
+
+		data = nil
+
+		return r.fetchWebhookDeliveries(tx, deliveryIDs, &data)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ClaimWebhookDelivery makes a delivery pending again with its attempts reset, held for the
+// lease so that it is sent by the caller rather than by a scheduled run.
+func (r *webhookRepository) ClaimWebhookDelivery(deliveryID uint, lease time.Duration) (*response.WebhookDelivery, error) {
+	var data []response.WebhookDelivery
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE WebhookDelivery
+			SET UpdatedAt = ?, Status = ?, Attempts = 0, NextAttemptAt = ?, DeliveredAt = NULL
+			WHERE ID = ? AND IsActive = 1`,
+			time.Now(), constant.WebhookDeliveryPending, time.Now().Add(lease), deliveryID).Error; err != nil {
+			return err
+		}
+
+		return r.fetchWebhookDeliveries(tx, []uint{deliveryID}, &data)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return &data[0], nil
+}
+
+// fetchWebhookDeliveries loads what is needed to send the deliveries, with the secrets decrypted.
+func (r *webhookRepository) fetchWebhookDeliveries(tx *gorm.DB, deliveryIDs []uint, data *[]response.WebhookDelivery) error {
+	if err := tx.Raw(`
+		SELECT wd.ID, wd.WebhookID webhookID, w.URL url, w.Secret secret, wd.OutboxEventID outboxEventID,
+		wd.EventType eventType, oe.Payload payload, wd.Attempts attempts, oe.CreatedAt eventAt
+		FROM WebhookDelivery wd
+		INNER JOIN Webhook w ON w.ID = wd.WebhookID
+		INNER JOIN OutboxEvent oe ON oe.ID = wd.OutboxEventID
+		WHERE wd.ID IN ?
+		ORDER BY wd.ID`, deliveryIDs).Scan(data).Error; err != nil {
+		return err
+	}
+
+	for i := range *data {
+		secret, err := utils.DecryptPII((*data)[i].Secret)
+
+		if err != nil {
+			return err
+		}
+
+		(*data)[i].Secret = secret
+	}
+
+	return nil
+}
+
+// RecordWebhookDeliveryAttempt logs an attempt and moves the delivery on to its next state.
+func (r *webhookRepository) RecordWebhookDeliveryAttempt(deliveryID uint, attempts int,
+	status constant.WebhookDeliveryStatus, nextAttemptAt time.Time, responseStatus *int, lastError *string,
+	duration time.Duration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Exec(`
+			INSERT INTO WebhookDeliveryAttempt
+			(CreatedAt, UpdatedAt, IsActive, WebhookDeliveryID, ResponseStatus, Error, DurationMs)
+			VALUES(?, ?, ?, ?, ?, ?, ?)`,
+			now, now, constant.Active, deliveryID, responseStatus, lastError,
+			duration.Milliseconds()).Error; err != nil {
+			return err
+		}
+
+		var deliveredAt *time.Time
+		if status == constant.WebhookDeliveryDelivered {
+			deliveredAt = &now
+		}
+
+		return tx.Exec(`
+			UPDATE WebhookDelivery
+			SET UpdatedAt = ?, Status = ?, Attempts = ?, NextAttemptAt = ?, LastAttemptAt = ?,
+			ResponseStatus = ?, LastError = ?, DeliveredAt = ?
+			WHERE ID = ?`,
+			now, status, attempts, nextAttemptAt, now, responseStatus, lastError, deliveredAt,
+			deliveryID).Error
+	})
+}
@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"bufio"
+	"ems/domain"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	clamAVTimeout   = time.Minute
+	clamAVChunkSize = 32 * 1024
+)
+
+// clamAVScanner streams files to clamd with the INSTREAM command.
+type clamAVScanner struct {
+	network string
+	address string
+}
+
+// NewClamAVScanner takes the clamd address as "unix:/path/to/clamd.sock" or "tcp:host:3310".
+func NewClamAVScanner(clamAVAddress string) (domain.FileScanner, error) {
+	network, address, found := strings.Cut(clamAVAddress, ":")
+
+	if !found || (network != "unix" && network != "tcp") || address == "" {
+		return nil, fmt.Errorf("invalid clamav address %s", clamAVAddress)
+	}
+
+	return &clamAVScanner{network, address}, nil
+}
+
+func (s *clamAVScanner) Scan(content io.Reader) (bool, string, error) {
+	conn, err := net.DialTimeout(s.network, s.address, clamAVTimeout)
+	if err != nil {
+		return false, "", fmt.Errorf("clamav: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(clamAVTimeout)); err != nil {
+		return false, "", err
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return false, "", fmt.Errorf("clamav: %w", err)
+	}
+
+	// Each chunk is prefixed with its length as a 4 byte big endian integer and a zero
+	// length chunk ends the stream.
+	chunk := make([]byte, 4+clamAVChunkSize)
+	for {
+		n, readErr := content.Read(chunk[4:])
+
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				return false, "", fmt.Errorf("clamav: %w", err)
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+
+		if readErr != nil {
+			return false, "", readErr
+		}
+	}
+
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return false, "", fmt.Errorf("clamav: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return false, "", fmt.Errorf("clamav: %w", err)
+	}
+
+	// Replies look like "stream: OK" or "stream: Eicar-Signature FOUND".
+	reply = strings.TrimPrefix(strings.TrimSuffix(reply, "\x00"), "stream: ")
+
+	switch {
+	case reply == "OK":
+		return false, "", nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return true, strings.TrimSuffix(reply, " FOUND"), nil
+	}
+
+	return false, "", fmt.Errorf("clamav: %s", reply)
+}
@@ -0,0 +1,32 @@
+package scanner
+
+import (
+	"ems/domain"
+	"ems/infrastructure/config"
+	"fmt"
+	"io"
+)
+
+const (
+	NoScanner     = "none"
+	ClamAVScanner = "clamav"
+)
+
+// NewScanner returns the scanner selected by MALWARE_SCANNER.
+func NewScanner() (domain.FileScanner, error) {
+	switch config.Config.MalwareScanner {
+	case NoScanner:
+		return &noopScanner{}, nil
+	case ClamAVScanner:
+		return NewClamAVScanner(config.Config.ClamAVAddress)
+	}
+
+	return nil, fmt.Errorf("unknown malware scanner %s", config.Config.MalwareScanner)
+}
+
+// noopScanner accepts every file. It is meant for development setups without a scanner.
+type noopScanner struct{}
+
+func (s *noopScanner) Scan(content io.Reader) (bool, string, error) {
+	return false, "", nil
+}
@@ -0,0 +1,512 @@
+package scim
+
+import (
+	apperror "ems/app/model/app_error"
+	"ems/app/model/constant"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter is a parsed filter expression (RFC 7644 section 3.4.2.2). Logical nodes hold their
+// operands in Left and Right, with not using Left only. Comparisons hold the attribute path,
+// normalised by NormalizeAttr, and a string, float64, bool or nil Value. A value path such as
+// emails[type eq "work"] has Op "[]" and filters the attribute's values with Sub.
+type Filter struct {
+	Op    string
+	Left  *Filter
+	Right *Filter
+	Attr  string
+	Value interface{}
+	Sub   *Filter
+}
+
+var comparisonOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// NormalizeAttr lower-cases an attribute path and drops the core schema URN, which may
+// prefix any core attribute. Extension attributes keep their schema URN.
+func NormalizeAttr(attr string) string {
+	attr = strings.ToLower(strings.TrimSpace(attr))
+
+	for _, schema := range []string{constant.SCIMUserSchema, constant.SCIMGroupSchema} {
+		if prefix := strings.ToLower(schema) + ":"; strings.HasPrefix(attr, prefix) {
+			return strings.TrimPrefix(attr, prefix)
+		}
+	}
+
+	return attr
+}
+
+// SplitAttr splits a normalised attribute path into its segments. An extension schema URN,
+// which contains dots of its own, is kept as the first segment.
+func SplitAttr(attr string) []string {
+	if schema := strings.ToLower(constant.SCIMEnterpriseUserSchema); strings.HasPrefix(attr, schema) {
+		if rest := strings.TrimPrefix(strings.TrimPrefix(attr, schema), ":"); rest != "" {
+			return append([]string{schema}, strings.Split(rest, ".")...)
+		}
+
+		return []string{schema}
+	}
+
+	if strings.HasPrefix(attr, "urn:") {
+		if i := strings.LastIndex(attr, ":"); i > 0 {
+			return append([]string{attr[:i]}, strings.Split(attr[i+1:], ".")...)
+		}
+	}
+
+	return strings.Split(attr, ".")
+}
+
+// ParseFilter parses a filter expression.
+func ParseFilter(expression string) (*Filter, error) {
+	tokens, err := tokenize(expression)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens}
+	filter, err := p.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, apperror.InvalidFilterError(fmt.Sprintf("unexpected %q", p.tokens[p.pos].text))
+	}
+
+	return filter, nil
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, token{text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(expression) && expression[end] != '"'; end++ {
+				if expression[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(expression) {
+				return nil, apperror.InvalidFilterError("unterminated string")
+			}
+
+			var value string
+			if err := json.Unmarshal([]byte(expression[i:end+1]), &value); err != nil {
+				return nil, apperror.InvalidFilterError("malformed string " + expression[i:end+1])
+			}
+
+			tokens = append(tokens, token{text: value, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(expression) && !strings.ContainsRune(" \t\n\r()[]\"", rune(expression[end])) {
+				end++
+			}
+
+			tokens = append(tokens, token{text: expression[i:end]})
+			i = end
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, apperror.InvalidFilterError("empty filter")
+	}
+
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) next() (token, error) {
+	t, ok := p.peek()
+
+	if !ok {
+		return token{}, apperror.InvalidFilterError("unexpected end of filter")
+	}
+
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) isKeyword(keyword string) bool {
+	t, ok := p.peek()
+	return ok && !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+func (p *filterParser) expect(text string) error {
+	t, err := p.next()
+
+	if err != nil {
+		return err
+	}
+
+	if t.quoted || t.text != text {
+		return apperror.InvalidFilterError(fmt.Sprintf("expected %q, found %q", text, t.text))
+	}
+
+	return nil
+}
+
+func (p *filterParser) parseOr() (*Filter, error) {
+	left, err := p.parseAnd()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("or") {
+		p.pos++
+
+		right, err := p.parseAnd()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Filter{Op: "or", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (*Filter, error) {
+	left, err := p.parseFactor()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") {
+		p.pos++
+
+		right, err := p.parseFactor()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Filter{Op: "and", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseFactor() (*Filter, error) {
+	if p.isKeyword("not") {
+		p.pos++
+
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		operand, err := p.parseGroup()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Filter{Op: "not", Left: operand}, nil
+	}
+
+	t, err := p.next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.quoted && t.text == "(" {
+		return p.parseGroup()
+	}
+
+	if t.quoted {
+		return nil, apperror.InvalidFilterError(fmt.Sprintf("expected an attribute, found %q", t.text))
+	}
+
+	attr := NormalizeAttr(t.text)
+
+	if next, ok := p.peek(); ok && !next.quoted && next.text == "[" {
+		p.pos++
+
+		sub, err := p.parseOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+
+		return &Filter{Op: "[]", Attr: attr, Sub: sub}, nil
+	}
+
+	operator, err := p.next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	op := strings.ToLower(operator.text)
+
+	if !operator.quoted && op == "pr" {
+		return &Filter{Op: op, Attr: attr}, nil
+	}
+
+	if operator.quoted || !comparisonOperators[op] {
+		return nil, apperror.InvalidFilterError(fmt.Sprintf("unknown operator %q", operator.text))
+	}
+
+	operand, err := p.next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseValue(operand)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{Op: op, Attr: attr, Value: value}, nil
+}
+
+func (p *filterParser) parseGroup() (*Filter, error) {
+	filter, err := p.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func parseValue(t token) (interface{}, error) {
+	if t.quoted {
+		return t.text, nil
+	}
+
+	switch strings.ToLower(t.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(t.text, 64)
+
+	if err != nil {
+		return nil, apperror.InvalidFilterError(fmt.Sprintf("invalid value %q", t.text))
+	}
+
+	return number, nil
+}
+
+// Match reports whether the resource, held as decoded JSON, satisfies the filter. Attribute
+// names are compared case insensitively and string comparisons ignore case. A multi-valued
+// attribute matches when any of its values does.
+func (f *Filter) Match(resource map[string]interface{}) bool {
+	switch f.Op {
+	case "and":
+		return f.Left.Match(resource) && f.Right.Match(resource)
+	case "or":
+		return f.Left.Match(resource) || f.Right.Match(resource)
+	case "not":
+		return !f.Left.Match(resource)
+	case "[]":
+		for _, value := range flatten(lookup(resource, SplitAttr(f.Attr))) {
+			if element, ok := value.(map[string]interface{}); ok && f.Sub.Match(element) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	values := flatten(lookup(resource, SplitAttr(f.Attr)))
+
+	if f.Op == "pr" {
+		for _, value := range values {
+			if value != nil && value != "" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, value := range values {
+		if compare(f.Op, value, f.Value) {
+			return true
+		}
+	}
+
+	// ne also holds for an attribute that has no value at all.
+	return f.Op == "ne" && len(values) == 0
+}
+
+// lookup follows the path through nested objects. Multi-valued attributes along the way are
+// expanded, so emails.value yields the value of every email.
+func lookup(value interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		return []interface{}{value}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		child, ok := getKey(v, path[0])
+
+		if !ok {
+			return nil
+		}
+
+		return lookup(child, path[1:])
+	case []interface{}:
+		var values []interface{}
+		for _, element := range v {
+			values = append(values, lookup(element, path)...)
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+func flatten(values []interface{}) []interface{} {
+	var flat []interface{}
+
+	for _, value := range values {
+		if list, ok := value.([]interface{}); ok {
+			flat = append(flat, list...)
+		} else {
+			flat = append(flat, value)
+		}
+	}
+
+	return flat
+}
+
+func compare(op string, actual, expected interface{}) bool {
+	switch expectedValue := expected.(type) {
+	case string:
+		actualValue, ok := actual.(string)
+
+		if !ok {
+			return op == "ne"
+		}
+
+		a, e := strings.ToLower(actualValue), strings.ToLower(expectedValue)
+
+		switch op {
+		case "eq":
+			return a == e
+		case "ne":
+			return a != e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case float64:
+		actualValue, ok := actual.(float64)
+
+		if !ok {
+			return op == "ne"
+		}
+
+		switch op {
+		case "eq":
+			return actualValue == expectedValue
+		case "ne":
+			return actualValue != expectedValue
+		case "gt":
+			return actualValue > expectedValue
+		case "ge":
+			return actualValue >= expectedValue
+		case "lt":
+			return actualValue < expectedValue
+		case "le":
+			return actualValue <= expectedValue
+		}
+	default:
+		switch op {
+		case "eq":
+			return actual == expected
+		case "ne":
+			return actual != expected
+		}
+	}
+
+	return false
+}
+
+// getKey finds a key of the object case insensitively.
+func getKey(object map[string]interface{}, name string) (interface{}, bool) {
+	if key, ok := findKey(object, name); ok {
+		return object[key], true
+	}
+
+	return nil, false
+}
+
+func findKey(object map[string]interface{}, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
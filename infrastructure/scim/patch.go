@@ -0,0 +1,332 @@
+package scim
+
+import (
+	apperror "ems/app/model/app_error"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Path is a parsed PATCH path (RFC 7644 section 3.5.2): an attribute, optionally narrowed to
+// the values matching Filter and to their Sub attribute, as in emails[type eq "work"].value.
+type Path struct {
+	Attr   string
+	Filter *Filter
+	Sub    string
+}
+
+func ParsePath(path string) (*Path, error) {
+	open := strings.Index(path, "[")
+
+	if open < 0 {
+		if strings.TrimSpace(path) == "" {
+			return nil, apperror.InvalidPathError(path)
+		}
+
+		return &Path{Attr: NormalizeAttr(path)}, nil
+	}
+
+	closing, quoted := -1, false
+	for i := open + 1; i < len(path) && closing < 0; i++ {
+		switch {
+		case path[i] == '\\' && quoted:
+			i++
+		case path[i] == '"':
+			quoted = !quoted
+		case path[i] == ']' && !quoted:
+			closing = i
+		}
+	}
+
+	if closing < 0 {
+		return nil, apperror.InvalidPathError(path)
+	}
+
+	filter, err := ParseFilter(path[open+1 : closing])
+
+	if err != nil {
+		return nil, apperror.InvalidPathError(path)
+	}
+
+	parsed := &Path{Attr: NormalizeAttr(path[:open]), Filter: filter}
+
+	if rest := path[closing+1:]; rest != "" {
+		if !strings.HasPrefix(rest, ".") || len(rest) == 1 {
+			return nil, apperror.InvalidPathError(path)
+		}
+
+		parsed.Sub = strings.ToLower(rest[1:])
+	}
+
+	return parsed, nil
+}
+
+// ApplyOperation applies one PatchOp operation to a resource held as decoded JSON. Without a
+// path the value is an object whose attributes are each applied in turn. Complex values are
+// merged into the existing ones, while multi-valued attributes are appended to by add and
+// replaced by replace. A remove on a multi-valued attribute with a value removes just the
+// listed values, as some providers send member removals that way.
+func ApplyOperation(resource map[string]interface{}, op, path string, value interface{}) error {
+	op = strings.ToLower(op)
+
+	if op != "add" && op != "replace" && op != "remove" {
+		return apperror.InvalidValueError(fmt.Sprintf("unknown operation %q", op))
+	}
+
+	if path == "" {
+		object, ok := value.(map[string]interface{})
+
+		if op == "remove" || !ok {
+			return apperror.InvalidValueError(op + " without a path needs an object value")
+		}
+
+		for key, attrValue := range object {
+			if err := ApplyOperation(resource, op, key, attrValue); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	parsed, err := ParsePath(path)
+
+	if err != nil {
+		return err
+	}
+
+	segments := SplitAttr(parsed.Attr)
+
+	if parsed.Filter == nil {
+		return applyToAttr(resource, op, segments, value)
+	}
+
+	current, _ := getPath(resource, segments)
+	elements, _ := current.([]interface{})
+	matched := false
+
+	var kept []interface{}
+
+	for _, element := range elements {
+		object, ok := element.(map[string]interface{})
+
+		if !ok || !parsed.Filter.Match(object) {
+			kept = append(kept, element)
+			continue
+		}
+
+		matched = true
+
+		switch {
+		case op == "remove" && parsed.Sub == "":
+			continue
+		case op == "remove":
+			if key, ok := findKey(object, parsed.Sub); ok {
+				delete(object, key)
+			}
+		case parsed.Sub == "":
+			update, ok := value.(map[string]interface{})
+
+			if !ok {
+				return apperror.InvalidValueError(path + " needs an object value")
+			}
+
+			merge(object, update)
+		default:
+			setPath(object, []string{parsed.Sub}, value)
+		}
+
+		kept = append(kept, element)
+	}
+
+	if !matched && op != "remove" {
+		element, err := newElement(parsed, value)
+
+		if err != nil {
+			return err
+		}
+
+		kept = append(kept, element)
+	}
+
+	if kept == nil {
+		kept = []interface{}{}
+	}
+
+	setPath(resource, segments, kept)
+
+	return nil
+}
+
+func applyToAttr(resource map[string]interface{}, op string, segments []string, value interface{}) error {
+	current, exists := getPath(resource, segments)
+
+	if op == "remove" {
+		existing, isList := current.([]interface{})
+		removals, hasValues := value.([]interface{})
+
+		if !exists || !isList || !hasValues {
+			deletePath(resource, segments)
+			return nil
+		}
+
+		var kept []interface{}
+		for _, element := range existing {
+			if !containsValue(removals, element) {
+				kept = append(kept, element)
+			}
+		}
+
+		if kept == nil {
+			kept = []interface{}{}
+		}
+
+		setPath(resource, segments, kept)
+		return nil
+	}
+
+	switch update := value.(type) {
+	case []interface{}:
+		existing, isList := current.([]interface{})
+
+		if op == "add" && isList {
+			for _, element := range update {
+				if !containsValue(existing, element) {
+					existing = append(existing, element)
+				}
+			}
+
+			setPath(resource, segments, existing)
+			return nil
+		}
+	case map[string]interface{}:
+		if existing, ok := current.(map[string]interface{}); ok {
+			merge(existing, update)
+			return nil
+		}
+	}
+
+	setPath(resource, segments, value)
+
+	return nil
+}
+
+// newElement builds the value an add or replace creates when its filter matched none, which
+// is only possible for a plain equality filter such as type eq "work".
+func newElement(path *Path, value interface{}) (map[string]interface{}, error) {
+	filter := path.Filter
+
+	if filter.Op != "eq" || strings.Contains(filter.Attr, ".") {
+		return nil, apperror.InvalidPathError(path.Attr + " filter matched no value")
+	}
+
+	element := map[string]interface{}{filter.Attr: filter.Value}
+
+	if path.Sub != "" {
+		element[path.Sub] = value
+		return element, nil
+	}
+
+	update, ok := value.(map[string]interface{})
+
+	if !ok {
+		return nil, apperror.InvalidValueError(path.Attr + " needs an object value")
+	}
+
+	merge(element, update)
+
+	return element, nil
+}
+
+// containsValue reports whether a multi-valued attribute holds the element. Elements with a
+// value sub-attribute, such as members, are compared on it alone.
+func containsValue(list []interface{}, element interface{}) bool {
+	for _, existing := range list {
+		if reflect.DeepEqual(existing, element) {
+			return true
+		}
+
+		a, aOK := existing.(map[string]interface{})
+		b, bOK := element.(map[string]interface{})
+
+		if !aOK || !bOK {
+			continue
+		}
+
+		aValue, aHas := getKey(a, "value")
+		bValue, bHas := getKey(b, "value")
+
+		if aHas && bHas && fmt.Sprint(aValue) == fmt.Sprint(bValue) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func merge(target, update map[string]interface{}) {
+	for key, value := range update {
+		setPath(target, SplitAttr(NormalizeAttr(key)), value)
+	}
+}
+
+func getPath(resource map[string]interface{}, segments []string) (interface{}, bool) {
+	var current interface{} = resource
+
+	for _, segment := range segments {
+		object, ok := current.(map[string]interface{})
+
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = getKey(object, segment); !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func setPath(resource map[string]interface{}, segments []string, value interface{}) {
+	object := resource
+
+	for _, segment := range segments[:len(segments)-1] {
+		key, ok := findKey(object, segment)
+
+		if !ok {
+			key = segment
+		}
+
+		child, ok := object[key].(map[string]interface{})
+
+		if !ok {
+			child = map[string]interface{}{}
+			object[key] = child
+		}
+
+		object = child
+	}
+
+	last := segments[len(segments)-1]
+
+	if key, ok := findKey(object, last); ok {
+		last = key
+	}
+
+	object[last] = value
+}
+
+func deletePath(resource map[string]interface{}, segments []string) {
+	parent, ok := getPath(resource, segments[:len(segments)-1])
+
+	if !ok {
+		return
+	}
+
+	if object, ok := parent.(map[string]interface{}); ok {
+		if key, ok := findKey(object, segments[len(segments)-1]); ok {
+			delete(object, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"ems/domain"
+	"ems/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type localStorage struct {
+	baseDir string
+}
+
+func NewLocalStorage(baseDir string) domain.FileStorage {
+	return &localStorage{baseDir}
+}
+
+func (s *localStorage) Backend() string {
+	return LocalBackend
+}
+
+func (s *localStorage) Save(key string, content io.Reader, contentType string) error {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		os.Remove(filePath)
+		return err
+	}
+
+	return nil
+}
+
+func (s *localStorage) Open(key string) (io.ReadCloser, error) {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(filePath)
+}
+
+func (s *localStorage) Delete(key string) error {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *localStorage) Exists(key string) (bool, error) {
+	filePath, err := s.resolve(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SignedURL points at the application's own file route, which verifies the signature.
+func (s *localStorage) SignedURL(key, fileName string, validity time.Duration) (string, error) {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.SignFileURL(key, fileName, time.Now().Add(validity)), nil
+}
+
+func (s *localStorage) resolve(key string) (string, error) {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.baseDir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/utils"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// s3Storage talks to any S3 compatible service (AWS, MinIO) with Signature Version 4.
+type s3Storage struct {
+	config config.S3Configuration
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Storage(s3Config config.S3Configuration) domain.FileStorage {
+	return &s3Storage{
+		config: s3Config,
+		client: &http.Client{Timeout: time.Minute},
+		now:    time.Now,
+	}
+}
+
+func (s *s3Storage) Backend() string {
+	return S3Backend
+}
+
+func (s *s3Storage) Save(key string, content io.Reader, contentType string) error {
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	res, err := s.do(http.MethodPut, key, header, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return s3Error(res, http.StatusOK)
+}
+
+func (s *s3Storage) Open(key string) (io.ReadCloser, error) {
+	res, err := s.do(http.MethodGet, key, http.Header{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s3Error(res, http.StatusOK); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+func (s *s3Storage) Delete(key string) error {
+	res, err := s.do(http.MethodDelete, key, http.Header{}, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return s3Error(res, http.StatusNoContent, http.StatusOK)
+}
+
+func (s *s3Storage) Exists(key string) (bool, error) {
+	res, err := s.do(http.MethodHead, key, http.Header{}, nil)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if err := s3Error(res, http.StatusOK); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SignedURL returns a presigned GET URL so the client downloads straight from the bucket.
+// The response headers are overridden so the browser saves the file under its original name.
+func (s *s3Storage) SignedURL(key, fileName string, validity time.Duration) (string, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(validity.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	query.Set("response-content-type", utils.ContentType(key))
+	query.Set("response-content-disposition", utils.ContentDisposition(key, fileName))
+	objectURL.RawQuery = canonicalQuery(query)
+
+	header := http.Header{}
+	header.Set("Host", objectURL.Host)
+
+	signature := s.signature(now, http.MethodGet, objectURL, header, s3UnsignedPayload)
+
+	objectURL.RawQuery += "&X-Amz-Signature=" + signature
+
+	return objectURL.String(), nil
+}
+
+func (s *s3Storage) do(method, key string, header http.Header, body []byte) (*http.Response, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	payloadHash := sha256Hex(body)
+
+	req.Header = header
+	req.Header.Set("Host", objectURL.Host)
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signature := s.signature(now, method, objectURL, req.Header, payloadHash)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKey, s.scope(now), signedHeaders(req.Header), signature))
+	req.Header.Del("Host")
+	req.Host = objectURL.Host
+
+	return s.client.Do(req)
+}
+
+func (s *s3Storage) objectURL(key string) (*url.URL, error) {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.ForcePathStyle {
+		endpoint.Path = "/" + s.config.Bucket + "/" + key
+	} else {
+		endpoint.Host = s.config.Bucket + "." + endpoint.Host
+		endpoint.Path = "/" + key
+	}
+	endpoint.RawPath = s3EscapePath(endpoint.Path)
+
+	return endpoint, nil
+}
+
+func (s *s3Storage) scope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+func (s *s3Storage) signature(now time.Time, method string, objectURL *url.URL, header http.Header, payloadHash string) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3EscapePath(objectURL.Path),
+		objectURL.RawQuery,
+		canonicalHeaders(header),
+		signedHeaders(header),
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		s.scope(now),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretKey), now.Format(s3DateFormat))
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+func canonicalHeaders(header http.Header) string {
+	var builder strings.Builder
+
+	for _, name := range sortedHeaderNames(header) {
+		builder.WriteString(name + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+
+	return builder.String()
+}
+
+func signedHeaders(header http.Header) string {
+	return strings.Join(sortedHeaderNames(header), ";")
+}
+
+func sortedHeaderNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+
+	for name := range header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(query.Get(key), true))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape percent-encodes everything except the RFC 3986 unreserved characters,
+// keeping "/" when encoding an object path.
+func s3Escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+
+	return builder.String()
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:])
+}
+
+func s3Error(res *http.Response, expected ...int) error {
+	for _, status := range expected {
+		if res.StatusCode == status {
+			return nil
+		}
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	return fmt.Errorf("s3 request failed with status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"ems/domain"
+	"ems/infrastructure/config"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	LocalBackend = "local"
+	S3Backend    = "s3"
+)
+
+var ErrInvalidKey = errors.New("invalid file key")
+
+// NewStorage returns the backend selected by STORAGE_BACKEND.
+func NewStorage() (domain.FileStorage, error) {
+	return NewStorageForBackend(config.Config.StorageBackend)
+}
+
+func NewStorageForBackend(backend string) (domain.FileStorage, error) {
+	switch backend {
+	case LocalBackend:
+		return NewLocalStorage(config.Config.LocalStorageDir), nil
+	case S3Backend:
+		if config.Config.S3.Bucket == "" {
+			return nil, fmt.Errorf("s3 storage is not configured")
+		}
+		return NewS3Storage(config.Config.S3), nil
+	}
+
+	return nil, fmt.Errorf("unknown storage backend %s", backend)
+}
+
+// NormalizeKey converts a stored FilePath into a storage key. Rows written before storage
+// backends existed hold paths like "./uploads/user-3/a.pdf", which map to "user-3/a.pdf".
+func NormalizeKey(key string) (string, error) {
+	key = path.Clean(strings.ReplaceAll(key, "\\", "/"))
+	key = strings.TrimPrefix(key, "/")
+	key = strings.TrimPrefix(key, "uploads/")
+
+	if key == "." || key == ".." || strings.HasPrefix(key, "../") {
+		return "", ErrInvalidKey
+	}
+
+	return key, nil
+}
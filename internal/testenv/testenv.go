@@ -0,0 +1,80 @@
+// Package testenv starts EMS against a fresh database for tests that go through the real
+// configuration, migrations and seed data.
+package testenv
+
+import (
+	"bytes"
+	"ems/infrastructure/config"
+	"ems/infrastructure/database"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// Setup configures EMS with a database and upload directory of its own in a temporary
+// directory, together with the test's own variables in env, and returns the migrated and
+// seeded database.
+func Setup(t *testing.T, env map[string]string) *gorm.DB {
+	dir := t.TempDir()
+
+	for name, value := range map[string]string{
+		"PORT":                ":0",
+		"DATABASE_URL":        dir + "/ems.db",
+		"LOCAL_STORAGE_DIR":   dir + "/uploads",
+		"SECRET_KEY":          "secret",
+		"SMTP_HOST":           "localhost",
+		"SMTP_PORT":           "25",
+		"SMTP_USERNAME":       "ems",
+		"SMTP_DISPLAY_NAME":   "EMS",
+		"SMTP_PASSWORD":       "ems",
+		"FORGOT_OTP_VALIDITY": "5",
+		"PII_ENCRYPTION_KEYS": "v1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+		"PII_ACTIVE_KEY_ID":   "v1",
+		"PII_BLIND_INDEX_KEY": "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=",
+	} {
+		t.Setenv(name, value)
+	}
+
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := database.InitDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// Call sends a JSON request, with the token as a bearer token when there is one, and returns
+// the status code and the data of the response.
+func Call(handler http.Handler, method, path, token string, body interface{}) (int, map[string]interface{}) {
+	var content []byte
+	if body != nil {
+		content, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(content))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	var out struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	json.Unmarshal(res.Body.Bytes(), &out)
+
+	return res.Code, out.Data
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"ems/api/routes"
+	"ems/app/model/constant"
+	"ems/app/service"
 	"ems/infrastructure/config"
 	"ems/infrastructure/database"
+	"ems/infrastructure/repository"
 	"ems/scheduler"
 
 	"github.com/didip/tollbooth"
@@ -46,6 +49,23 @@ func loadConfiguration() {
 	}
 }
 
+/**
+ * @Function: setupTrustedProxies
+ * @Description: Trusts X-Forwarded-For only from the configured proxies, so that clients cannot
+ *               choose the IP address used for lockouts and the audit log.
+ *
+ * @Params:
+ *    - routes: The Gin Engine whose trusted proxies are set.
+ *
+ * @Returns:
+ *    - None
+ */
+func setupTrustedProxies(router *gin.Engine) {
+	if err := router.SetTrustedProxies(config.Config.TrustedProxies); err != nil {
+		panic(err)
+	}
+}
+
 /**
  * @Function: setupRateLimiter
  * @Description: Configures rate limiting for the given routes.
@@ -113,14 +133,20 @@ func setupServer() {
 		panic(err)
 	}
 
+	//Initialize Event Bus
+	eventBus := service.NewEventBus(repository.NewEventRepository(db))
+	eventBus.Subscribe(constant.WebhookSubscriber,
+		service.NewWebhookService(repository.NewWebhookRepository(db)).QueueWebhookDeliveries, constant.EventTypes...)
+
 	//Initialize Schedular
-	scheduler := scheduler.Scheduler{DB: db}
+	scheduler := scheduler.Scheduler{DB: db, EventBus: eventBus}
 	scheduler.InitScheduler()
 
 	// Initialize Gin routes
 	router := gin.Default()
 
 	// Setup various server configurations
+	setupTrustedProxies(router)
 	setupRateLimiter(router)
 	setupCors(router)
 	setupRoutes(router, db)
@@ -1,16 +1,30 @@
 package scheduler
 
 import (
+	"ems/app/model/constant"
+	"ems/app/model/request"
+	"ems/app/service"
+	"ems/domain"
+	"ems/infrastructure/config"
+	"ems/infrastructure/ldap"
+	"ems/infrastructure/repository"
+	"ems/utils"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron"
 	"gorm.io/gorm"
 )
 
+// systemActor records the changes made by the scheduled jobs, which have no request user, in
+// the audit log.
+var systemActor = &request.AuditActor{Type: constant.SystemActor}
+
 type Scheduler struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	EventBus domain.EventBus
 }
 
 func (s *Scheduler) InitScheduler() {
@@ -23,55 +37,193 @@ func (s *Scheduler) InitScheduler() {
 		log.Fatalf("Failed to schedule job: %v", err)
 	}
 
+	// Encrypt legacy plaintext PII and re-encrypt rows and secrets sealed with a retired key
+	s.reEncryptUserDetails()
+
+	_, err = scheduler.Every(1).Day().At("01:00").Do(s.reEncryptUserDetails)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// Remind employees about certifications nearing expiry
+	_, err = scheduler.Every(1).Day().At("09:00").Do(s.remindExpiringCertifications)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// Remind employees about documents nearing expiry
+	_, err = scheduler.Every(1).Day().At("09:00").Do(s.remindExpiringDocuments)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// Sync roles and departments from the LDAP directory
+	if config.Config.LDAP.URL != "" && config.Config.LDAP.SyncInterval > 0 {
+		_, err = scheduler.Every(config.Config.LDAP.SyncInterval).Do(s.syncDirectory)
+		if err != nil {
+			log.Fatalf("Failed to schedule job: %v", err)
+		}
+	}
+
+	// Pick up signing keys rotated by other instances. Both signing key jobs wait for their
+	// first interval, as the keys are loaded when the routes are set up.
+	_, err = scheduler.Every(1).Minute().WaitForSchedule().Do(s.loadSigningKeys)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// Rotate the token signing key once it is older than the rotation interval
+	_, err = scheduler.Every(1).Hour().WaitForSchedule().Do(s.rotateSigningKey)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// Deliver the domain events published to the outbox
+	_, err = scheduler.Every(5).Seconds().SingletonMode().WaitForSchedule().Do(s.dispatchEvents)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
+	// Send the webhook deliveries that are due
+	_, err = scheduler.Every(10).Seconds().SingletonMode().WaitForSchedule().Do(s.deliverWebhooks)
+	if err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+
 	// Start the scheduler asynchronously
 	scheduler.StartAsync()
 }
 
 func (s *Scheduler) removeUsers() {
-	now := time.Now()
-
-	err := s.DB.Transaction(func(tx *gorm.DB) error {
-		// Fetch users whose notice period has ended
-		var departmentMemberIDs []uint
-		if err := tx.Raw(`
-			SELECT DepartmentMemberID 
-			FROM UserNotice 
-			WHERE IsActive = 1 AND NoticeEndDate < ?`, now).Scan(&departmentMemberIDs).Error; err != nil {
-			return err
+	err := repository.NewNoticeRepository(s.DB).RemoveServedNoticeUsers(systemActor, time.Now())
+
+	if err != nil {
+		log.Fatalf("Transaction failed: %v", err)
+	} else {
+		fmt.Println("Users removed successfully")
+	}
+}
+
+func (s *Scheduler) reEncryptUserDetails() {
+	userRepository := repository.NewUserRepository(s.DB)
+
+	count, err := userRepository.ReEncryptUserDetails()
+	if err != nil {
+		log.Printf("User details re-encryption failed: %v", err)
+		return
+	}
+
+	if count > 0 {
+		fmt.Printf("User details re-encrypted: %d\n", count)
+	}
+
+	count, err = userRepository.ReEncryptUserFieldHistory()
+	if err != nil {
+		log.Printf("User field history re-encryption failed: %v", err)
+		return
+	}
+
+	if count > 0 {
+		fmt.Printf("User field history re-encrypted: %d\n", count)
+	}
+
+	count, err = userRepository.ReEncryptSealedColumns()
+	if err != nil {
+		log.Printf("Secrets re-encryption failed: %v", err)
+		return
+	}
+
+	if count > 0 {
+		fmt.Printf("Secrets re-encrypted: %d\n", count)
+	}
+}
+
+func (s *Scheduler) remindExpiringCertifications() {
+	userQualificationRepository := repository.NewUserQualificationRepository(s.DB)
+
+	certifications, err := userQualificationRepository.FetchExpiringCertifications(constant.CertificationExpiryReminderDays)
+	if err != nil {
+		log.Printf("Failed to fetch expiring certifications: %v", err)
+		return
+	}
+
+	for _, certification := range certifications {
+		if err := utils.SendCertificationExpiryMail(certification.Email, certification.UserName,
+			certification.Name, certification.ExpiryDate); err != nil {
+			log.Printf("Failed to send certification expiry reminder to %s: %v", certification.Email, err)
+			continue
 		}
 
-		if len(departmentMemberIDs) > 0 {
-			// Mark the corresponding department members as inactive and set DeletedAt
-			if err := tx.Exec(`
-				UPDATE DepartmentMember 
-				SET IsActive = 0, DeletedAt = ? 
-				WHERE ID IN ?`, now, departmentMemberIDs).Error; err != nil {
-				return err
-			}
-
-			if err := tx.Exec(`
-				UPDATE [User] 
-				SET IsActive = 0, DeletedAt = ? 
-				WHERE ID IN (
-					SELECT UserID FROM DepartmentMember WHERE ID IN ?
-				)`, now, departmentMemberIDs).Error; err != nil {
-				return err
-			}
-
-			if err := tx.Exec(`
-				UPDATE UserNotice
-				SET IsActive = 0, DeletedAt = ?
-				WHERE DepartmentMemberID IN ?`, time.Now(), departmentMemberIDs).Error; err != nil {
-				return err
-			}
+		if err := userQualificationRepository.MarkCertificationReminderSent(certification.ID); err != nil {
+			log.Printf("Failed to mark certification reminder as sent: %v", err)
 		}
+	}
+}
 
-		return nil
-	})
+func (s *Scheduler) remindExpiringDocuments() {
+	documentRepository := repository.NewDocumentRepository(s.DB)
 
+	documents, err := documentRepository.FetchExpiringDocuments(constant.DocumentExpiryReminderDays)
 	if err != nil {
-		log.Fatalf("Transaction failed: %v", err)
-	} else {
-		fmt.Println("Users removed successfully")
+		log.Printf("Failed to fetch expiring documents: %v", err)
+		return
+	}
+
+	for _, document := range documents {
+		if err := utils.SendDocumentExpiryMail(document.Email, document.UserName, document.Category,
+			document.ExpiryDate); err != nil {
+			log.Printf("Failed to send document expiry reminder to %s: %v", document.Email, err)
+			continue
+		}
+
+		if err := documentRepository.MarkDocumentReminderSent(document.ID); err != nil {
+			log.Printf("Failed to mark document reminder as sent: %v", err)
+		}
+	}
+}
+
+func (s *Scheduler) syncDirectory() {
+	directorySyncService := service.NewDirectorySyncService(repository.NewDirectoryRepository(s.DB),
+		ldap.NewDirectory(config.Config.LDAP))
+
+	report, err := directorySyncService.SyncDirectory(systemActor, config.Config.LDAP.SyncDryRun)
+	if err != nil {
+		log.Printf("Directory sync failed: %v", err)
+		return
+	}
+
+	// A dry run only reports, so print what the sync would have done.
+	if report.DryRun {
+		for _, change := range append(report.Created, report.Updated...) {
+			log.Printf("Directory sync would change %s: %s", change.Email, strings.Join(change.Changes, ", "))
+		}
+	}
+
+	for _, skipped := range report.Skipped {
+		log.Printf("Directory sync skipped %s: %s", skipped.Email, skipped.Reason)
+	}
+}
+
+func (s *Scheduler) loadSigningKeys() {
+	if err := service.NewSigningKeyService(repository.NewSigningKeyRepository(s.DB)).LoadSigningKeys(); err != nil {
+		log.Printf("Failed to load signing keys: %v", err)
+	}
+}
+
+func (s *Scheduler) rotateSigningKey() {
+	if err := service.NewSigningKeyService(repository.NewSigningKeyRepository(s.DB)).RotateSigningKey(false); err != nil {
+		log.Printf("Signing key rotation failed: %v", err)
+	}
+}
+
+func (s *Scheduler) dispatchEvents() {
+	if err := s.EventBus.DispatchEvents(); err != nil {
+		log.Printf("Event dispatch failed: %v", err)
+	}
+}
+
+func (s *Scheduler) deliverWebhooks() {
+	if err := service.NewWebhookService(repository.NewWebhookRepository(s.DB)).DeliverWebhooks(); err != nil {
+		log.Printf("Webhook delivery failed: %v", err)
 	}
 }
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"ems/app/model/schema"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+/**
+ * @function: AuditLogHash
+ * @description: hashes an audit log row together with the hash of the row before it
+ * @param: log *schema.AuditLog
+ * @returns: hex encoded SHA-256 hash
+ */
+func AuditLogHash(log *schema.AuditLog) string {
+	fields, _ := json.Marshal([]interface{}{log.PrevHash, log.CreatedAt.UTC().Format(time.RFC3339Nano),
+		log.ActorType, log.UserID, log.ServiceAccountID, log.ImpersonatorID, log.IPAddress, log.Action,
+		log.Entity, log.EntityID, log.Changes})
+
+	hash := sha256.Sum256(fields)
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"ems/infrastructure/config"
+	"encoding/hex"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+)
+
+const signedFileURLPath = "/api/files"
+
+/**
+ * @function: SignFileURL
+ * @description: builds a download URL for a locally stored file that stays valid until expiresAt
+ * @param: key, fileName string, expiresAt time.Time
+ * @returns: relative URL with key, name, expires and signature query parameters
+ */
+func SignFileURL(key, fileName string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("key", key)
+	query.Set("name", fileName)
+	query.Set("expires", expires)
+	query.Set("signature", fileURLSignature(key, fileName, expires))
+
+	return signedFileURLPath + "?" + query.Encode()
+}
+
+/**
+ * @function: VerifyFileURL
+ * @description: checks the signature and expiry of a URL produced by SignFileURL
+ * @param: key, fileName, expires, signature string
+ * @returns: true if the URL is authentic and not expired
+ */
+func VerifyFileURL(key, fileName, expires, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(fileURLSignature(key, fileName, expires)))
+}
+
+func fileURLSignature(key, fileName, expires string) string {
+	mac := hmac.New(sha256.New, []byte("file-url:"+config.Config.JwtSecretKey))
+	mac.Write([]byte(key + "\n" + fileName + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ContentType derives the media type of a stored file from its key's extension.
+func ContentType(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
+
+// ContentDisposition builds an attachment header carrying the original file name,
+// falling back to the key's base name for rows stored without one.
+func ContentDisposition(key, fileName string) string {
+	if fileName == "" {
+		fileName = path.Base(key)
+	}
+
+	if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName}); disposition != "" {
+		return disposition
+	}
+
+	return "attachment"
+}
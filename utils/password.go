@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bufio"
+	apperror "ems/app/model/app_error"
+	"ems/infrastructure/config"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// bcryptMaxLength is the number of bytes bcrypt uses; anything longer is silently ignored.
+const bcryptMaxLength = 72
+
+// commonPasswords is always denied, on top of PASSWORD_DENY_LIST_FILE.
+var commonPasswords = []string{
+	"password", "password1", "password123", "passw0rd", "p@ssw0rd", "p@ssword1", "welcome", "welcome1",
+	"welcome123", "qwerty", "qwerty123", "qwertyuiop", "abc123", "abcd1234", "letmein", "admin",
+	"admin123", "administrator", "changeme", "iloveyou", "monkey", "dragon", "football", "sunshine",
+	"princess", "trustno1", "superman", "123456", "12345678", "123456789", "1234567890", "111111",
+	"000000", "employee", "company", "summer2024", "winter2024", "spring2025", "autumn2025",
+}
+
+var (
+	passwordDenyList     map[string]bool
+	passwordDenyListOnce sync.Once
+)
+
+// ValidatePassword checks a new password against the configured policy. personalInfo holds
+// the user's name, email and mobile, none of which may appear in the password.
+func ValidatePassword(password string, personalInfo ...string) error {
+	policy := config.Config.PasswordPolicy
+
+	if len([]rune(password)) < policy.MinLength {
+		return apperror.WeakPasswordError(fmt.Sprintf("must be at least %d characters long", policy.MinLength))
+	}
+
+	if len(password) > bcryptMaxLength {
+		return apperror.WeakPasswordError(fmt.Sprintf("must be at most %d bytes long", bcryptMaxLength))
+	}
+
+	var hasUppercase, hasLowercase, hasDigit, hasSymbol bool
+
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUppercase = true
+		case unicode.IsLower(char):
+			hasLowercase = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case policy.RequireUppercase && !hasUppercase:
+		return apperror.WeakPasswordError("must contain an uppercase letter")
+	case policy.RequireLowercase && !hasLowercase:
+		return apperror.WeakPasswordError("must contain a lowercase letter")
+	case policy.RequireDigit && !hasDigit:
+		return apperror.WeakPasswordError("must contain a digit")
+	case policy.RequireSymbol && !hasSymbol:
+		return apperror.WeakPasswordError("must contain a symbol")
+	}
+
+	passwordDenyListOnce.Do(loadPasswordDenyList)
+
+	lowerPassword := strings.ToLower(password)
+
+	if passwordDenyList[lowerPassword] {
+		return apperror.WeakPasswordError("is too common")
+	}
+
+	for _, info := range personalInfo {
+		info = strings.ToLower(strings.TrimSpace(strings.Split(info, "@")[0]))
+
+		// Very short values such as initials would reject too many good passwords.
+		if len(info) >= 3 && strings.Contains(lowerPassword, info) {
+			return apperror.WeakPasswordError("must not contain your name, email or mobile number")
+		}
+	}
+
+	return nil
+}
+
+// IsPasswordExpired reports whether a password set at changedAt is older than the policy
+// allows. Passwords with no recorded change time never expire.
+func IsPasswordExpired(changedAt *time.Time) bool {
+	maxAge := config.Config.PasswordPolicy.MaxAge
+
+	return maxAge > 0 && changedAt != nil && time.Since(*changedAt) > maxAge
+}
+
+func loadPasswordDenyList() {
+	passwordDenyList = make(map[string]bool, len(commonPasswords))
+
+	for _, password := range commonPasswords {
+		passwordDenyList[password] = true
+	}
+
+	path := config.Config.PasswordPolicy.DenyListFile
+
+	if path == "" {
+		return
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		log.Printf("Failed to load password deny list %s: %v", path, err)
+		return
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if password := strings.ToLower(strings.TrimSpace(scanner.Text())); password != "" {
+			passwordDenyList[password] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Printf("Failed to read password deny list %s: %v", path, err)
+	}
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pdfPageWidth  = 595 // A4 in points
+	pdfPageHeight = 842
+	pdfMargin     = 72
+	pdfTitleSize  = 16
+	pdfBodySize   = 11
+	pdfLeading    = 1.45
+)
+
+// helveticaWidths holds the glyph widths of Helvetica for the printable ASCII range, in
+// thousandths of the font size, as published in the standard font metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+type pdfLine struct {
+	text []byte
+	bold bool
+	size float64
+}
+
+// RenderPDF lays out a bold title followed by a plain text body on A4 pages. It only uses
+// the standard Helvetica fonts, so nothing has to be embedded. Newlines in the body start a
+// new line; blank lines separate paragraphs.
+func RenderPDF(title, body string) []byte {
+	maxWidth := float64(pdfPageWidth - 2*pdfMargin)
+
+	var lines []pdfLine
+	for _, text := range wrapPDFText(title, pdfTitleSize, true, maxWidth) {
+		lines = append(lines, pdfLine{text, true, pdfTitleSize})
+	}
+	lines = append(lines, pdfLine{nil, false, pdfBodySize})
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		for _, text := range wrapPDFText(paragraph, pdfBodySize, false, maxWidth) {
+			lines = append(lines, pdfLine{text, false, pdfBodySize})
+		}
+	}
+
+	var pages []*bytes.Buffer
+	var page *bytes.Buffer
+	y := 0.0
+
+	for _, line := range lines {
+		height := line.size * pdfLeading
+
+		if page == nil || y-height < pdfMargin {
+			page = &bytes.Buffer{}
+			pages = append(pages, page)
+			y = pdfPageHeight - pdfMargin
+		}
+
+		y -= height
+
+		if len(line.text) == 0 {
+			continue
+		}
+
+		font := "F1"
+		if line.bold {
+			font = "F2"
+		}
+
+		fmt.Fprintf(page, "BT /%s %g Tf %d %.2f Td (%s) Tj ET\n", font, line.size, pdfMargin, y,
+			escapePDFText(line.text))
+	}
+
+	return writePDF(pages)
+}
+
+// writePDF assembles the catalog, page tree, fonts and one content stream per page, followed
+// by the cross reference table that points at each object's byte offset.
+func writePDF(pages []*bytes.Buffer) []byte {
+	var objects []string
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+
+	for i, page := range pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// wrapPDFText breaks text into lines that fit maxWidth, splitting words that are longer than
+// a whole line.
+func wrapPDFText(text string, size float64, bold bool, maxWidth float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+
+	for _, word := range strings.Fields(text) {
+		encoded := encodeWinAnsi(word)
+
+		candidate := append(append([]byte{}, line...), encoded...)
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), encoded...)
+		}
+
+		if pdfTextWidth(candidate, size, bold) <= maxWidth {
+			line = candidate
+			continue
+		}
+
+		if len(line) > 0 {
+			lines = append(lines, line)
+			line = nil
+		}
+
+		for pdfTextWidth(encoded, size, bold) > maxWidth {
+			split := 1
+			for split < len(encoded) && pdfTextWidth(encoded[:split+1], size, bold) <= maxWidth {
+				split++
+			}
+			lines = append(lines, encoded[:split])
+			encoded = encoded[split:]
+		}
+
+		line = encoded
+	}
+
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func pdfTextWidth(text []byte, size float64, bold bool) float64 {
+	width := 0
+	for _, c := range text {
+		if c >= 32 && c <= 126 {
+			width += helveticaWidths[c-32]
+		} else {
+			width += 556
+		}
+	}
+
+	// Helvetica-Bold is slightly wider; scaling the regular metrics keeps the title in bounds.
+	if bold {
+		width = width * 11 / 10
+	}
+
+	return float64(width) * size / 1000
+}
+
+// winAnsiPunctuation maps the typographic characters WinAnsi places below Latin-1.
+var winAnsiPunctuation = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// encodeWinAnsi maps text to the single byte WinAnsi encoding used by the standard fonts.
+// Characters it cannot represent are replaced with a question mark.
+func encodeWinAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			encoded = append(encoded, byte(r))
+		case winAnsiPunctuation[r] != 0:
+			encoded = append(encoded, winAnsiPunctuation[r])
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+func escapePDFText(text []byte) []byte {
+	escaped := make([]byte, 0, len(text))
+	for _, c := range text {
+		if c == '\\' || c == '(' || c == ')' {
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, c)
+	}
+	return escaped
+}
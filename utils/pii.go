@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"ems/infrastructure/config"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const piiCipherPrefix = "enc:"
+
+/**
+ * @function: EncryptPII
+ * @description: encrypts a PII value with the active AES-GCM key
+ * @param: plaintext string
+ * @returns: "enc:<keyID>:<base64(nonce|ciphertext)>", error
+ */
+func EncryptPII(plaintext string) (string, error) {
+	keyID := config.Config.PiiActiveKeyID
+
+	gcm, err := newPiiCipher(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(keyID))
+
+	return piiCipherPrefix + keyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+/**
+ * @function: DecryptPII
+ * @description: decrypts a value produced by EncryptPII with the key it was sealed with.
+ * Values without the cipher prefix are legacy plaintext and are returned unchanged.
+ * @param: value string
+ * @returns: plaintext string, error
+ */
+func DecryptPII(value string) (string, error) {
+	if !IsEncryptedPII(value) {
+		return value, nil
+	}
+
+	keyID, encoded, found := strings.Cut(strings.TrimPrefix(value, piiCipherPrefix), ":")
+	if !found {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	gcm, err := newPiiCipher(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func IsEncryptedPII(value string) bool {
+	return strings.HasPrefix(value, piiCipherPrefix)
+}
+
+// IsEncryptedWithActiveKey reports whether a stored value is already sealed with the
+// active key, i.e. it does not need to be re-encrypted during key rotation.
+func IsEncryptedWithActiveKey(value string) bool {
+	return strings.HasPrefix(value, piiCipherPrefix+config.Config.PiiActiveKeyID+":")
+}
+
+/**
+ * @function: BlindIndex
+ * @description: deterministic keyed hash used to look up encrypted values for equality
+ * @param: value string
+ * @returns: hex encoded HMAC-SHA256
+ */
+func BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, config.Config.PiiBlindIndexKey)
+	mac.Write([]byte(strings.ToUpper(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+/**
+ * @function: MaskValue
+ * @description: masks all but the last visible characters of a value
+ * @param: value string, visible int
+ * @returns: masked string
+ */
+func MaskValue(value string, visible int) string {
+	runes := []rune(value)
+	if len(runes) <= visible {
+		return strings.Repeat("X", len(runes))
+	}
+	return strings.Repeat("X", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+// MaskAadharNumber formats an aadhar number as XXXX-XXXX-1234.
+func MaskAadharNumber(value string) string {
+	digits := strings.ReplaceAll(value, "-", "")
+	if len(digits) < 4 {
+		return MaskValue(digits, 0)
+	}
+	return "XXXX-XXXX-" + digits[len(digits)-4:]
+}
+
+func newPiiCipher(keyID string) (cipher.AEAD, error) {
+	key, ok := config.Config.PiiEncryptionKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %s not configured", keyID)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
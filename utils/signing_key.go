@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Token signing algorithms.
+const (
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// SigningKey is a parsed token signing key. Keys sign from ActivatesAt and verify until
+// ExpiresAt, which is nil while the key is still current.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	PrivateKey  crypto.Signer
+	PublicKey   crypto.PublicKey
+	ActivatesAt time.Time
+	ExpiresAt   *time.Time
+}
+
+var signingKeys struct {
+	sync.RWMutex
+	keys []SigningKey
+}
+
+/**
+ * @function: SetSigningKeys
+ * @description: replaces the keys used to sign and verify tokens
+ * @param: keys []SigningKey
+ * @returns: None
+ */
+func SetSigningKeys(keys []SigningKey) {
+	signingKeys.Lock()
+	defer signingKeys.Unlock()
+
+	signingKeys.keys = keys
+}
+
+/**
+ * @function: SigningKeys
+ * @description: lists the keys tokens can currently be verified with, including keys that
+ * have been published but do not sign yet
+ * @param: None
+ * @returns: []SigningKey
+ */
+func SigningKeys() []SigningKey {
+	signingKeys.RLock()
+	defer signingKeys.RUnlock()
+
+	var keys []SigningKey
+	for _, key := range signingKeys.keys {
+		if key.ExpiresAt == nil || time.Now().Before(*key.ExpiresAt) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// signToken signs the claims with the most recently activated key and names it in the kid header.
+func signToken(claims jwt.MapClaims) (string, error) {
+	var current *SigningKey
+
+	keys := SigningKeys()
+	for i, key := range keys {
+		if key.ActivatesAt.After(time.Now()) || key.PrivateKey == nil {
+			continue
+		}
+
+		if current == nil || key.ActivatesAt.After(current.ActivatesAt) {
+			current = &keys[i]
+		}
+	}
+
+	if current == nil {
+		return "", errors.New("no token signing key is active")
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(current.Algorithm), claims)
+	token.Header["kid"] = current.ID
+
+	return token.SignedString(current.PrivateKey)
+}
+
+/**
+ * @function: ParseToken
+ * @description: verifies a token against the key named in its kid header. The key's own
+ * algorithm is enforced, so a token cannot pick a weaker one.
+ * @param: tokenValue string
+ * @returns: jwt.MapClaims, error
+ */
+func ParseToken(tokenValue string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenValue, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		for _, key := range SigningKeys() {
+			if key.ID == keyID {
+				if token.Method.Alg() != key.Algorithm {
+					return nil, jwt.ErrSignatureInvalid
+				}
+
+				return key.PublicKey, nil
+			}
+		}
+
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+/**
+ * @function: GenerateSigningKey
+ * @description: generates a key pair for the algorithm, a 2048 bit RSA key for RS256 or an
+ * Ed25519 key for EdDSA
+ * @param: algorithm string
+ * @returns: random key ID, PEM encoded PKCS #8 private key, PEM encoded PKIX public key, error
+ */
+func GenerateSigningKey(algorithm string) (string, string, string, error) {
+	keyID := make([]byte, 8)
+	if _, err := cryptorand.Read(keyID); err != nil {
+		return "", "", "", err
+	}
+
+	var privateKey crypto.Signer
+
+	switch algorithm {
+	case RS256:
+		key, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+		if err != nil {
+			return "", "", "", err
+		}
+		privateKey = key
+	case EdDSA:
+		_, key, err := ed25519.GenerateKey(cryptorand.Reader)
+		if err != nil {
+			return "", "", "", err
+		}
+		privateKey = key
+	default:
+		return "", "", "", fmt.Errorf("unsupported signing algorithm %s", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return hex.EncodeToString(keyID), string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})), nil
+}
+
+/**
+ * @function: ParseSigningKey
+ * @description: parses the PEM encoded keys produced by GenerateSigningKey
+ * @param: privatePEM, publicPEM string
+ * @returns: crypto.Signer, crypto.PublicKey, error
+ */
+func ParseSigningKey(privatePEM, publicPEM string) (crypto.Signer, crypto.PublicKey, error) {
+	privateBlock, _ := pem.Decode([]byte(privatePEM))
+	publicBlock, _ := pem.Decode([]byte(publicPEM))
+
+	if privateBlock == nil || publicBlock == nil {
+		return nil, nil, errors.New("malformed signing key")
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New("unsupported signing key type")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(publicBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return signer, publicKey, nil
+}
@@ -1,7 +1,13 @@
 package utils
 
 import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"ems/app/model/constant"
 	"ems/infrastructure/config"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
@@ -15,12 +21,127 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func GenerateToken(userID int) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"userID": userID,
-		"exp":    time.Now().Add(config.Config.TokenDuration).Unix(),
+const twoFactorTokenPurpose = "twoFactor"
+
+// GenerateToken issues a short lived access token bound to a session, so revoking the
+// session invalidates the token before it expires.
+func GenerateToken(userID int, sessionID uint) (string, error) {
+	return GenerateTokenUntil(userID, sessionID, time.Now().Add(config.Config.AccessTokenDuration))
+}
+
+// GenerateTokenUntil issues an access token that expires at expiresAt, for sessions that
+// cannot be refreshed.
+func GenerateTokenUntil(userID int, sessionID uint, expiresAt time.Time) (string, error) {
+	return signToken(jwt.MapClaims{
+		"userID":    userID,
+		"sessionID": sessionID,
+		"exp":       expiresAt.Unix(),
+	})
+}
+
+// GenerateTwoFactorToken issues the interim token handed out after the password check. It
+// carries no session, so it is only accepted by the two-factor endpoints.
+func GenerateTwoFactorToken(userID int) (string, error) {
+	return signToken(jwt.MapClaims{
+		"userID":  userID,
+		"purpose": twoFactorTokenPurpose,
+		"exp":     time.Now().Add(config.Config.TwoFactorTokenDuration).Unix(),
 	})
-	return token.SignedString([]byte(config.Config.JwtSecretKey))
+}
+
+func ValidateTwoFactorToken(tokenValue string) (uint, error) {
+	claims, err := ParseToken(tokenValue)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if claims["purpose"] != twoFactorTokenPurpose {
+		return 0, errors.New("invalid two-factor token")
+	}
+
+	userID, ok := claims["userID"].(float64)
+
+	if !ok || userID == 0 {
+		return 0, errors.New("invalid two-factor token")
+	}
+
+	return uint(userID), nil
+}
+
+// GenerateRefreshToken returns an opaque random token. Only its hash is stored.
+func GenerateRefreshToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := cryptorand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// GenerateAPIKey returns a new API key and its prefix. The prefix is stored in clear so that
+// the key can be looked up, and shown to tell keys apart.
+func GenerateAPIKey() (string, string, error) {
+	id := make([]byte, 6)
+	if _, err := cryptorand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret, err := GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix := hex.EncodeToString(id)
+	return constant.APIKeyPrefix + prefix + "_" + secret, prefix, nil
+}
+
+// GenerateWebhookSecret returns a new secret for signing a webhook's payloads.
+func GenerateWebhookSecret() (string, error) {
+	secret, err := GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	return constant.WebhookSecretPrefix + secret, nil
+}
+
+// SignWebhookPayload returns the signature header of a webhook payload: the time it was signed
+// and the HMAC-SHA256 of the time and the body, so that a receiver can reject replays.
+func SignWebhookPayload(secret string, signedAt time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseAPIKey returns the prefix of an API key, or false when the token is not one.
+func ParseAPIKey(token string) (string, bool) {
+	rest, ok := strings.CutPrefix(token, constant.APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+// PKCEChallenge derives the S256 code challenge sent to the identity provider for a verifier.
+func PKCEChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// HashToken hashes high entropy secrets such as refresh tokens and recovery codes for storage.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
 }
 
 /**
@@ -46,17 +167,65 @@ func GenerateOTP() string {
  * @returns: error if mail not sent
  */
 func SendFogotPasswordMail(to, otp string, requestedAt time.Time) error {
+	subject := "EMS OTP"
+
+	body := fmt.Sprintf(`<p>Your EMS OTP is <b>%s</b> requested at: <b>%s</b></p>`, otp, time.Now().Format("15:04:05 2006-01-02"))
+
+	return sendMail(to, subject, body)
+}
+
+/**
+ * @function: SendCertificationExpiryMail
+ * @description: function used to remind an employee that a certification is about to expire
+ * @param: to, userName, certificationName string, expiryDate time.Time
+ * @returns: error if mail not sent
+ */
+func SendCertificationExpiryMail(to, userName, certificationName string, expiryDate time.Time) error {
+	subject := "EMS Certification Expiry Reminder"
+
+	body := fmt.Sprintf(`<p>Hi %s,</p><p>Your certification <b>%s</b> expires on <b>%s</b>. Please renew it and share the updated certificate with HR.</p>`,
+		userName, certificationName, expiryDate.Format("2006-01-02"))
+
+	return sendMail(to, subject, body)
+}
+
+/**
+ * @function: SendDocumentExpiryMail
+ * @description: function used to remind an employee that an uploaded document is about to expire
+ * @param: to, userName, category string, expiryDate time.Time
+ * @returns: error if mail not sent
+ */
+func SendDocumentExpiryMail(to, userName, category string, expiryDate time.Time) error {
+	subject := "EMS Document Expiry Reminder"
 
+	body := fmt.Sprintf(`<p>Hi %s,</p><p>Your <b>%s</b> document expires on <b>%s</b>. Please share an updated copy with HR.</p>`,
+		userName, category, expiryDate.Format("2006-01-02"))
+
+	return sendMail(to, subject, body)
+}
+
+/**
+ * @function: SendImpersonationMail
+ * @description: function used to tell an employee that an admin has signed in as them
+ * @param: to, userName, impersonator, reason string, expiresAt time.Time
+ * @returns: error if mail not sent
+ */
+func SendImpersonationMail(to, userName, impersonator, reason string, expiresAt time.Time) error {
+	subject := "EMS Account Accessed By Support"
+
+	body := fmt.Sprintf(`<p>Hi %s,</p><p><b>%s</b> signed in to EMS as you until <b>%s</b> for the following reason: %s</p><p>If you did not expect this, please contact HR.</p>`,
+		userName, impersonator, expiresAt.Format("15:04:05 2006-01-02"), reason)
+
+	return sendMail(to, subject, body)
+}
+
+func sendMail(to, subject, body string) error {
 	displayName := config.Config.SmtpDisplayName
 	from := config.Config.SmtpUserName
 	password := config.Config.SmtpPassword
 	smtpHost := config.Config.SmtpHost
 	smtpPort := config.Config.SmtpPort
 
-	subject := "EMS OTP"
-
-	body := fmt.Sprintf(`<p>Your EMS OTP is <b>%s</b> requested at: <b>%s</b></p>`, otp, time.Now().Format("15:04:05 2006-01-02"))
-
 	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s", from, to, subject, body)
 
 	auth := smtp.PlainAuth(displayName, from, password, smtpHost)